@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/trknhr/agenticode/internal/agent"
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply <staging-dir>",
+	Short: "Review the changes of a dry run and apply them file by file",
+	Long: `Show the diff of each file a --staging-dir dry run changed and ask whether to
+apply it: y applies the file, n skips it, a applies it and all remaining files,
+q stops without applying anything more. A summary of what was written and
+skipped is printed at the end. Staged deletions are not applied.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+		return runApply(cmd.InOrStdin(), cmd.OutOrStdout(), args[0], root)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+}
+
+// runApply walks the user through the staged files and reports the outcome
+func runApply(in io.Reader, out io.Writer, stagingDir, root string) error {
+	files, err := agent.ListStagedFiles(stagingDir, root)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(out, "No staged changes to apply")
+		return nil
+	}
+
+	reader := bufio.NewReader(in)
+	diff := agent.NewDiffGenerator()
+	result := agent.ApplyStagedFiles(files, func(file agent.StagedFile) agent.ApplyChoice {
+		name := displayPath(file.Path, root)
+		if file.IsNew {
+			fmt.Fprintf(out, "\n📄 New file %s (%d lines)\n", name, strings.Count(file.New, "\n"))
+		} else {
+			fmt.Fprintf(out, "\n📝 %s\n", name)
+		}
+		fmt.Fprintln(out, diff.GenerateColoredDiff(file.Original, file.New, name))
+		return promptApplyChoice(reader, out)
+	})
+
+	writeApplySummary(out, result, root)
+	if result.Err != nil {
+		return result.Err
+	}
+	return nil
+}
+
+// promptApplyChoice asks until it gets a valid answer; end of input aborts
+func promptApplyChoice(reader *bufio.Reader, out io.Writer) agent.ApplyChoice {
+	for {
+		fmt.Fprint(out, "Apply this file? [y]es / [n]o / [a]ll / [q]uit: ")
+		line, err := reader.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "y", "yes":
+			return agent.ApplyFile
+		case "n", "no":
+			return agent.SkipFile
+		case "a", "all":
+			return agent.ApplyAll
+		case "q", "quit":
+			return agent.AbortApply
+		}
+		if err != nil {
+			fmt.Fprintln(out)
+			return agent.AbortApply
+		}
+	}
+}
+
+func writeApplySummary(out io.Writer, result agent.ApplyResult, root string) {
+	fmt.Fprintf(out, "\nApplied %d, skipped %d, not applied %d\n", len(result.Applied), len(result.Skipped), len(result.NotApplied))
+	for _, path := range result.Applied {
+		fmt.Fprintf(out, "  ✅ %s\n", displayPath(path, root))
+	}
+	for _, path := range result.Skipped {
+		fmt.Fprintf(out, "  ⏭️  %s\n", displayPath(path, root))
+	}
+	if result.Failed != "" {
+		fmt.Fprintf(out, "  ❌ %s: %v\n", displayPath(result.Failed, root), result.Err)
+	}
+	for _, path := range result.NotApplied {
+		fmt.Fprintf(out, "  ⏸️  %s\n", displayPath(path, root))
+	}
+}
+
+// displayPath shows project files relative to root
+func displayPath(path, root string) string {
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
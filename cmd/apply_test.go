@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyAsksPerFile(t *testing.T) {
+	root := t.TempDir()
+	staging := t.TempDir()
+	write := func(dir, name, content string) {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(root, "a.go", "package a\n")
+	write(root, "b.go", "package b\n")
+	write(root, "same.go", "package same\n")
+	write(staging, "a.go", "package a\n\nfunc A() {}\n")
+	write(staging, "b.go", "package b\n\nfunc B() {}\n")
+	write(staging, "same.go", "package same\n")
+	write(staging, "pkg/c.go", "package c\n")
+	write(staging, "pkg/d.go", "package d\n")
+
+	// Skip a.go, apply b.go after an invalid answer, then apply the rest
+	var out strings.Builder
+	if err := runApply(strings.NewReader("n\nmaybe\ny\na\n"), &out, staging, root); err != nil {
+		t.Fatal(err)
+	}
+
+	read := func(name string) string {
+		data, _ := os.ReadFile(filepath.Join(root, name))
+		return string(data)
+	}
+	if read("a.go") != "package a\n" {
+		t.Error("expected the skipped file to be left alone")
+	}
+	if !strings.Contains(read("b.go"), "func B()") || read("pkg/c.go") != "package c\n" || read("pkg/d.go") != "package d\n" {
+		t.Errorf("expected the approved files written, got:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "same.go") {
+		t.Error("unchanged files should not be offered")
+	}
+	if !strings.Contains(out.String(), "Applied 3, skipped 1, not applied 0") {
+		t.Errorf("unexpected summary:\n%s", out.String())
+	}
+}
+
+func TestApplyQuitLeavesRemainingFiles(t *testing.T) {
+	root := t.TempDir()
+	staging := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		os.WriteFile(filepath.Join(staging, name), []byte(name), 0644)
+	}
+
+	var out strings.Builder
+	if err := runApply(strings.NewReader("y\nq\n"), &out, staging, root); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "a.txt")); err != nil {
+		t.Errorf("expected a.txt to be applied: %v", err)
+	}
+	for _, name := range []string{"b.txt", "c.txt"} {
+		if _, err := os.Stat(filepath.Join(root, name)); !os.IsNotExist(err) {
+			t.Errorf("expected %s not to be applied", name)
+		}
+	}
+	if !strings.Contains(out.String(), "Applied 1, skipped 0, not applied 2") {
+		t.Errorf("unexpected summary:\n%s", out.String())
+	}
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/trknhr/agenticode/internal/redact"
+	"gopkg.in/yaml.v3"
+)
+
+// projectConfigName is the per-project config file merged over the user config
+// when --project-config is given
+const projectConfigName = ".agenticode.yaml"
+
+// projectConfigKeys are the settings a project config may change. Anything
+// that affects approvals, credentials, endpoints or commands run on startup
+// (providers, approval, permissions, security, hooks, mcp, format, ...) stays
+// in the user config, so a cloned repository cannot loosen them.
+var projectConfigKeys = []string{
+	"general",
+	"context",
+	"prompts",
+	"pinned_files",
+	"disabled_tools",
+	"tools.read",
+	"tools.read_many_files",
+	"tools.web_fetch",
+	"tools.preserve_encoding",
+}
+
+// projectConfigAllowed reports whether key may be set by a project config
+func projectConfigAllowed(key string) bool {
+	for _, allowed := range projectConfigKeys {
+		if key == allowed || strings.HasPrefix(key, allowed+".") {
+			return true
+		}
+	}
+	return false
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the agenticode configuration",
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration after merging all layers",
+	Long: `Print the configuration agenticode actually uses, in YAML: the user config
+(~/.agenticode.yaml or --config), with the project's .agenticode.yaml merged
+over it when --project-config is given, and environment overrides applied.
+Secrets are redacted.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, err := renderEffectiveConfig(viper.GetViper(), projectConfigFile)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(cmd.OutOrStdout(), out)
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configShowCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// mergeProjectConfig merges the allowed settings of dir/.agenticode.yaml over
+// the config already in v. It returns the file's path, or "" when there is no
+// separate project file, and the keys that were ignored.
+func mergeProjectConfig(v *viper.Viper, dir string) (string, []string, error) {
+	path, err := filepath.Abs(filepath.Join(dir, projectConfigName))
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", nil, nil
+	}
+	if used, err := filepath.Abs(v.ConfigFileUsed()); err == nil && used == path {
+		return "", nil, nil // Already loaded as the user config
+	}
+
+	project := viper.New()
+	project.SetConfigFile(path)
+	if err := project.ReadInConfig(); err != nil {
+		return "", nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	allowed := viper.New()
+	var ignored []string
+	for _, key := range project.AllKeys() {
+		if projectConfigAllowed(key) {
+			allowed.Set(key, project.Get(key))
+		} else {
+			ignored = append(ignored, key)
+		}
+	}
+	sort.Strings(ignored)
+	if err := v.MergeConfigMap(allowed.AllSettings()); err != nil {
+		return "", nil, fmt.Errorf("failed to merge %s: %w", path, err)
+	}
+	return path, ignored, nil
+}
+
+// renderEffectiveConfig dumps the merged settings as YAML with secrets redacted
+func renderEffectiveConfig(v *viper.Viper, projectFile string) (string, error) {
+	settings := redactSecrets(v.AllSettings()).(map[string]interface{})
+
+	var b strings.Builder
+	b.WriteString("# Effective agenticode configuration\n")
+	if used := v.ConfigFileUsed(); used != "" {
+		fmt.Fprintf(&b, "# user config: %s\n", used)
+	}
+	if projectFile != "" {
+		fmt.Fprintf(&b, "# project config: %s\n", projectFile)
+	}
+
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(settings); err != nil {
+		return "", fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	return b.String(), nil
+}
+
+// secretKey matches setting names whose values must not be printed
+var secretKey = regexp.MustCompile(`(?i)(api_?key|token|secret|password|authorization)`)
+
+// redactSecrets copies a settings tree, masking values stored under secret
+// keys. Environment references such as $OPENAI_API_KEY are left readable.
+func redactSecrets(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if s, ok := item.(string); ok && secretKey.MatchString(key) {
+				out[key] = redact.Value(s)
+			} else {
+				out[key] = redactSecrets(item)
+			}
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = redactSecrets(item)
+		}
+		return out
+	default:
+		return value
+	}
+}
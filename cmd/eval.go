@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/trknhr/agenticode/internal/eval"
+	"github.com/trknhr/agenticode/internal/llm"
+)
+
+var (
+	evalVerbose       bool
+	evalDeterministic bool
+	evalKeepFailed    bool
+	evalUseGPT        bool
+	evalUpdateGolden  bool
+	evalSaveJSON      string
+	evalMaxSteps      int
+)
+
+var evalCmd = &cobra.Command{
+	Use:   "eval <test-case.yaml|dir>...",
+	Short: "Evaluate code generation against test cases (experimental)",
+	Long: `Run each test case's prompt through the agent in a fresh directory, then
+check the generated files, build or run them, and optionally score them
+against the case's criteria with the model. A directory runs every .yaml
+test case in it. The command fails if any test case fails.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runEval,
+}
+
+func init() {
+	evalCmd.Flags().BoolVar(&evalVerbose, "verbose", false, "Show errors, generated files and execution output for each test case")
+	evalCmd.Flags().BoolVar(&evalKeepFailed, "keep-failed", false, "Keep the output directory of failed test cases")
+	evalCmd.Flags().BoolVar(&evalUseGPT, "use-gpt", false, "Score the output against each test case's criteria with the model")
+	evalCmd.Flags().BoolVar(&evalUpdateGolden, "update-golden", false, "Rewrite the test cases' golden files from the generated output")
+	evalCmd.Flags().StringVar(&evalSaveJSON, "save-json", "", "Save the results as JSON to this file")
+	evalCmd.Flags().BoolVar(&evalDeterministic, "deterministic", true, "Sample with temperature 0 and a fixed seed where the provider supports it (--deterministic=false for default sampling)")
+	evalCmd.Flags().IntVar(&evalMaxSteps, "max-steps", 10, "Maximum agent steps per test case")
+	evalCmd.Flags().StringVarP(&modelSelection, "model", "m", "", "Model selection (e.g., 'default', 'fast', 'groq/llama3-8b')")
+	rootCmd.AddCommand(evalCmd)
+}
+
+func runEval(cmd *cobra.Command, args []string) error {
+	testCases, err := loadEvalTestCases(args)
+	if err != nil {
+		return err
+	}
+	if len(testCases) == 0 {
+		return fmt.Errorf("no test cases found in %v", args)
+	}
+
+	providersConfig, err := loadProvidersConfig(viper.GetViper())
+	if err != nil {
+		return err
+	}
+	selectedModel := modelSelection
+	if selectedModel == "" {
+		selectedModel = "default"
+	}
+	client, err := llm.NewClient(llm.Config{
+		ProvidersConfig: providersConfig,
+		ModelSelection:  selectedModel,
+	})
+	if err != nil {
+		return err
+	}
+
+	runner := eval.NewRunner(client, eval.RunnerConfig{
+		MaxSteps:      evalMaxSteps,
+		Deterministic: evalDeterministic,
+		UseGPT:        evalUseGPT,
+		KeepFailed:    evalKeepFailed,
+		UpdateGolden:  evalUpdateGolden,
+	})
+	var results []*eval.EvalResult
+	failed := 0
+	for _, tc := range testCases {
+		fmt.Printf("▶ %s\n", tc.Name)
+		result := runner.Run(context.Background(), tc)
+		if !result.Success {
+			failed++
+		}
+		results = append(results, result)
+	}
+
+	reporter := eval.NewReporter(evalVerbose)
+	reporter.Report(results)
+	if evalSaveJSON != "" {
+		if err := reporter.SaveJSON(results, evalSaveJSON); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d test cases failed", failed, len(results))
+	}
+	return nil
+}
+
+// loadEvalTestCases loads test case files and every test case in directories
+func loadEvalTestCases(paths []string) ([]*eval.TestCase, error) {
+	var testCases []*eval.TestCase
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			cases, err := eval.LoadTestCases(path)
+			if err != nil {
+				return nil, err
+			}
+			testCases = append(testCases, cases...)
+			continue
+		}
+		tc, err := eval.LoadTestCase(path)
+		if err != nil {
+			return nil, err
+		}
+		testCases = append(testCases, tc)
+	}
+	return testCases, nil
+}
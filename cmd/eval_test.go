@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadEvalTestCasesAcceptsFilesAndDirs(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("name: a\nprompt: make a\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "b.yaml"), []byte("name: b\nprompt: make b\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "notes.md"), []byte("not a test case\n"), 0644)
+
+	cases, err := loadEvalTestCases([]string{dir, filepath.Join(dir, "a.yaml")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) != 3 || cases[0].Name != "a" || cases[1].Name != "b" || cases[2].Name != "a" {
+		t.Errorf("expected a and b from the dir, then a, got %+v", cases)
+	}
+	if _, err := loadEvalTestCases([]string{filepath.Join(dir, "missing.yaml")}); err == nil {
+		t.Error("expected a missing path to fail")
+	}
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/agent"
+)
+
+// exportTranscript handles "/export <file.md>" in an interactive session. An
+// existing file is only replaced when confirm returns true.
+func exportTranscript(path string, conversation []openai.ChatCompletionMessage, outputs *agent.ToolOutputs, confirm func(question string) bool) (bool, error) {
+	if _, err := os.Stat(path); err == nil {
+		if !confirm(fmt.Sprintf("%s already exists. Overwrite it? [y/N]: ", path)) {
+			return false, nil
+		}
+	}
+	if err := writeTranscript(path, conversation, outputs); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// writeTranscript saves the conversation, with what each tool call showed,
+// as a Markdown document
+func writeTranscript(path string, conversation []openai.ChatCompletionMessage, outputs *agent.ToolOutputs) error {
+	if err := os.WriteFile(path, []byte(agent.RenderTranscript(conversation, outputs)), 0644); err != nil {
+		return fmt.Errorf("failed to write transcript: %w", err)
+	}
+	return nil
+}
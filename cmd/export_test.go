@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/agent"
+)
+
+func TestExportTranscriptAsksBeforeOverwriting(t *testing.T) {
+	conversation := []openai.ChatCompletionMessage{{Role: "user", Content: "fix the greeting"}}
+	path := filepath.Join(t.TempDir(), "README.md")
+	os.WriteFile(path, []byte("# Project\n"), 0644)
+
+	asked := 0
+	saved, err := exportTranscript(path, conversation, agent.NewToolOutputs(), func(string) bool { asked++; return false })
+	if err != nil || saved || asked != 1 {
+		t.Fatalf("expected a declined overwrite, got saved=%v asked=%d err=%v", saved, asked, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "# Project\n" {
+		t.Errorf("expected the existing file to be kept, got %q", data)
+	}
+
+	saved, err = exportTranscript(path, conversation, agent.NewToolOutputs(), func(string) bool { return true })
+	if err != nil || !saved {
+		t.Fatalf("expected a confirmed overwrite, got saved=%v err=%v", saved, err)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "fix the greeting") {
+		t.Errorf("expected the transcript, got %q", data)
+	}
+
+	// New files need no confirmation
+	fresh := filepath.Join(t.TempDir(), "session.md")
+	if saved, err := exportTranscript(fresh, conversation, agent.NewToolOutputs(), func(string) bool { t.Error("unexpected prompt"); return false }); err != nil || !saved {
+		t.Errorf("expected a new file to be written, got saved=%v err=%v", saved, err)
+	}
+}
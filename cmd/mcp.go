@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/trknhr/agenticode/internal/agent"
+	"github.com/trknhr/agenticode/internal/mcp"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Inspect the configured MCP servers",
+}
+
+var mcpStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Connect to each configured MCP server and report its state",
+	Long: `Start every MCP server in the configuration, as a session would, and print
+its state, tool count, connection time and last error. Use it to diagnose a
+server that fails to start or exposes no tools.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, _ := mcp.LoadMCPTools(context.Background(), agent.NewInteractiveApprover(), viper.GetViper())
+		if manager == nil {
+			fmt.Fprintln(cmd.OutOrStdout(), "No MCP servers configured")
+			return nil
+		}
+		defer manager.CloseAll()
+		writeMCPStatus(cmd.OutOrStdout(), manager.GetAllStates())
+		return nil
+	},
+}
+
+func init() {
+	mcpCmd.AddCommand(mcpStatusCmd)
+	rootCmd.AddCommand(mcpCmd)
+}
+
+// writeMCPStatus prints each server's state, tool count, connection time and
+// last error, sorted by name
+func writeMCPStatus(w io.Writer, states map[string]mcp.ClientInfo) {
+	if len(states) == 0 {
+		fmt.Fprintln(w, "No MCP servers configured")
+		return
+	}
+	names := make([]string, 0, len(states))
+	for name := range states {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		info := states[name]
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s (%s)\n", name, info.State)
+		if info.State == mcp.StateConnected {
+			fmt.Fprintf(w, "  Tools: %d\n", info.ToolCount)
+			fmt.Fprintf(w, "  Connected: %s\n", info.ConnectedAt.Format("2006-01-02 15:04:05"))
+		}
+		if info.Attempts > 1 {
+			fmt.Fprintf(w, "  Startup attempts: %d\n", info.Attempts)
+		}
+		if info.Error != nil {
+			fmt.Fprintf(w, "  Last error: %v\n", info.Error)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/trknhr/agenticode/internal/mcp"
+)
+
+func TestWriteMCPStatus(t *testing.T) {
+	connectedAt := time.Date(2026, 10, 16, 9, 30, 0, 0, time.Local)
+	var out bytes.Buffer
+	writeMCPStatus(&out, map[string]mcp.ClientInfo{
+		"github": {Name: "github", State: mcp.StateConnected, ToolCount: 12, ConnectedAt: connectedAt, Attempts: 1},
+		"broken": {Name: "broken", State: mcp.StateError, Error: errors.New("failed to start client broken: exec: \"srv\": not found"), Attempts: 3},
+	})
+
+	want := `broken (error)
+  Startup attempts: 3
+  Last error: failed to start client broken: exec: "srv": not found
+
+github (connected)
+  Tools: 12
+  Connected: 2026-10-16 09:30:00
+`
+	if got := out.String(); got != want {
+		t.Errorf("unexpected status:\n%s\nwant:\n%s", got, want)
+	}
+
+	out.Reset()
+	writeMCPStatus(&out, nil)
+	if !strings.Contains(out.String(), "No MCP servers configured") {
+		t.Errorf("expected a note when no servers are configured, got %q", out.String())
+	}
+}
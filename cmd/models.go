@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/trknhr/agenticode/internal/llm"
+)
+
+var modelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "List the configured providers, models and named selections",
+	Long: `List the model names -m/--model accepts: the named selections under models:
+and every provider/model pair under providers: in the configuration.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		providersConfig, err := loadProvidersConfig(viper.GetViper())
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(cmd.OutOrStdout(), providersConfig.ModelListing())
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(modelsCmd)
+}
+
+// loadProvidersConfig reads the providers and named model selections
+func loadProvidersConfig(v *viper.Viper) (*llm.ProvidersConfig, error) {
+	providersConfig := &llm.ProvidersConfig{
+		Providers: make(map[string]llm.ProviderConfig),
+		Models:    make(map[string]llm.ModelSelection),
+	}
+
+	if !v.IsSet("providers") {
+		return nil, fmt.Errorf("no providers configured: add a providers section to ~/.agenticode.yaml (see .agenticode.yaml.example)")
+	}
+	if err := v.UnmarshalKey("providers", &providersConfig.Providers); err != nil {
+		return nil, fmt.Errorf("failed to load providers configuration: %w", err)
+	}
+
+	if v.IsSet("models") {
+		if err := v.UnmarshalKey("models", &providersConfig.Models); err != nil {
+			return nil, fmt.Errorf("failed to load models configuration: %w", err)
+		}
+	}
+	return providersConfig, nil
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/agent"
+	"github.com/trknhr/agenticode/internal/tools"
+)
+
+// planAndConfirm runs the conversation in plan mode, shows the resulting
+// todos and asks whether to carry them out. When the user agrees it returns
+// the conversation with the approval appended, ready to execute.
+func planAndConfirm(ctx context.Context, a *agent.Agent, conversation []openai.ChatCompletionMessage, in io.Reader, out io.Writer) ([]openai.ChatCompletionMessage, bool, error) {
+	start := time.Now()
+	response, planned, err := a.PlanWithHistory(ctx, conversation)
+	if err != nil {
+		return nil, false, fmt.Errorf("error planning: %w", err)
+	}
+	writePlan(out, response.Message, plannedTodos(start))
+
+	fmt.Fprint(out, "Carry out this plan? [y/N]: ")
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	if choice := strings.ToLower(strings.TrimSpace(answer)); choice != "y" && choice != "yes" && choice != "go" {
+		fmt.Fprintln(out, "Plan not carried out.")
+		return planned, false, nil
+	}
+	return append(planned, openai.ChatCompletionMessage{Role: "user", Content: agent.PlanApprovedPrompt}), true, nil
+}
+
+// plannedTodos returns the todos recorded or updated since start, i.e. by the
+// planning turn, leaving out those of earlier tasks in the session
+func plannedTodos(start time.Time) []tools.TodoItem {
+	var todos []tools.TodoItem
+	for _, todo := range tools.GlobalTodoStore.ReadAll() {
+		if !todo.UpdatedAt.Before(start) {
+			todos = append(todos, todo)
+		}
+	}
+	sort.Slice(todos, func(i, j int) bool {
+		if !todos[i].CreatedAt.Equal(todos[j].CreatedAt) {
+			return todos[i].CreatedAt.Before(todos[j].CreatedAt)
+		}
+		return todos[i].ID < todos[j].ID
+	})
+	return todos
+}
+
+// writePlan prints the model's summary and the todos it recorded
+func writePlan(out io.Writer, summary string, todos []tools.TodoItem) {
+	if summary != "" {
+		fmt.Fprintf(out, "\n%s\n", summary)
+	}
+	if len(todos) == 0 {
+		fmt.Fprintln(out, "\n📋 No todos were recorded.")
+		return
+	}
+	fmt.Fprintln(out, "\n📋 Plan:")
+	for i, todo := range todos {
+		fmt.Fprintf(out, "  %d. %s\n", i+1, todo.Title)
+	}
+}
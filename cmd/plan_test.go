@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/trknhr/agenticode/internal/tools"
+)
+
+func TestPlannedTodosLeavesOutEarlierTasks(t *testing.T) {
+	tools.GlobalTodoStore.Clear()
+	defer tools.GlobalTodoStore.Clear()
+
+	tools.GlobalTodoStore.Upsert([]tools.TodoItem{{Title: "Old task", State: tools.TodoCompleted}})
+	time.Sleep(time.Millisecond)
+	start := time.Now()
+	tools.GlobalTodoStore.Upsert([]tools.TodoItem{
+		{Title: "Read the parser", State: tools.TodoPending},
+		{Title: "Split the lexer", State: tools.TodoPending},
+	})
+
+	todos := plannedTodos(start)
+	if len(todos) != 2 || todos[0].Title != "Read the parser" || todos[1].Title != "Split the lexer" {
+		t.Errorf("expected only this plan's todos in order, got %+v", todos)
+	}
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// resolvePrompt returns the prompt of a non-interactive run. "-p -" reads it
+// from stdin and --prompt-file from a file ("-" again meaning stdin), so long
+// multi-line prompts can be piped in; the text is used exactly as read.
+func resolvePrompt(prompt, promptFile string, stdin io.Reader) (string, error) {
+	if promptFile != "" && prompt != "" {
+		return "", fmt.Errorf("use either --prompt or --prompt-file, not both")
+	}
+
+	var data []byte
+	var err error
+	switch {
+	case promptFromStdin(prompt, promptFile):
+		data, err = io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read prompt from stdin: %w", err)
+		}
+	case promptFile != "":
+		data, err = os.ReadFile(promptFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read prompt file: %w", err)
+		}
+	default:
+		return prompt, nil
+	}
+
+	if strings.TrimSpace(string(data)) == "" {
+		return "", fmt.Errorf("the prompt is empty")
+	}
+	return string(data), nil
+}
+
+// promptFromStdin reports whether resolvePrompt reads the prompt from stdin
+func promptFromStdin(prompt, promptFile string) bool {
+	return promptFile == "-" || (promptFile == "" && prompt == "-")
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/trknhr/agenticode/internal/agent"
+)
+
+func TestPromptFromStdinIsKeptVerbatim(t *testing.T) {
+	input := "Refactor the parser:\n\n  1. split lexing\n  2. keep `-p` working\n\t- tabs stay\n"
+
+	prompt, err := resolvePrompt("-", "", strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	message, err := agent.NewUserMessage(prompt, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message.Role != "user" || message.Content != input {
+		t.Errorf("expected the user message to be the stdin prompt verbatim, got %q", message.Content)
+	}
+}
+
+func TestResolvePrompt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "task.md")
+	if err := os.WriteFile(path, []byte("# Task\nfix it\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if prompt, err := resolvePrompt("", path, strings.NewReader("")); err != nil || prompt != "# Task\nfix it\n" {
+		t.Errorf("expected the file content, got %q, %v", prompt, err)
+	}
+	if prompt, err := resolvePrompt("", "-", strings.NewReader("from stdin")); err != nil || prompt != "from stdin" {
+		t.Errorf("expected --prompt-file - to read stdin, got %q, %v", prompt, err)
+	}
+	if prompt, err := resolvePrompt("inline", "", strings.NewReader("ignored")); err != nil || prompt != "inline" {
+		t.Errorf("expected the flag value, got %q, %v", prompt, err)
+	}
+	if _, err := resolvePrompt("-", "", strings.NewReader(" \n")); err == nil {
+		t.Error("expected an error for an empty prompt")
+	}
+	if _, err := resolvePrompt("inline", path, strings.NewReader("")); err == nil {
+		t.Error("expected an error when both a prompt and a prompt file are given")
+	}
+}
+
+func TestPromptFromStdin(t *testing.T) {
+	for _, tc := range []struct {
+		prompt, promptFile string
+		want               bool
+	}{
+		{"-", "", true},
+		{"", "-", true},
+		{"fix it", "", false},
+		{"", "task.md", false},
+	} {
+		if got := promptFromStdin(tc.prompt, tc.promptFile); got != tc.want {
+			t.Errorf("promptFromStdin(%q, %q) = %v, want %v", tc.prompt, tc.promptFile, got, tc.want)
+		}
+	}
+}
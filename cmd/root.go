@@ -1,32 +1,55 @@
 package cmd
 
 import (
-	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/sashabaranov/go-openai"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/trknhr/agenticode/internal/agent"
+	"github.com/trknhr/agenticode/internal/commands"
 	"github.com/trknhr/agenticode/internal/hooks"
 	"github.com/trknhr/agenticode/internal/llm"
+	"github.com/trknhr/agenticode/internal/locale"
 	"github.com/trknhr/agenticode/internal/mcp"
+	"github.com/trknhr/agenticode/internal/telemetry"
 	"github.com/trknhr/agenticode/internal/tools"
+	"golang.org/x/term"
 )
 
 var (
-	cfgFile        string
-	debugMode      bool
-	promptStr      string
-	maxTurns       int
-	allowedTools   string
-	permissionMode string
-	dangerousSkip  bool
-	modelSelection string
+	cfgFile         string
+	debugMode       bool
+	promptStr       string
+	promptFile      string
+	maxTurns        int
+	allowedTools    string
+	denyTools       string
+	readOnly        bool
+	stagingDir      string
+	showReasoning   bool
+	quiet           bool
+	planMode        bool
+	permissionMode  string
+	dangerousSkip   bool
+	modelSelection  string
+	approvalTimeout int
+	diffStyle       string
+	maxTotalTokens  int
+	imagePaths      []string
+	transcriptPath  string
+	tracePath       string
+
+	projectConfigFile string // Project-level config merged over the user config, if any
+	useProjectConfig  bool   // Merge ./.agenticode.yaml over the user config
 )
 
 var rootCmd = &cobra.Command{
@@ -43,6 +66,7 @@ to quickly create React apps, propose changes, and more.
 Usage:
   agenticode                      # Interactive mode
   agenticode -p "prompt"          # Execute a single prompt
+  cat task.md | agenticode -p -   # Execute a prompt read from stdin
   agenticode code "description"   # Generate code from description`,
 	RunE: runInteractiveMode,
 }
@@ -58,13 +82,28 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.agenticode.yaml)")
+	rootCmd.PersistentFlags().BoolVar(&useProjectConfig, "project-config", false, "Merge ./.agenticode.yaml over the config (security settings in it are ignored)")
 	rootCmd.PersistentFlags().BoolVar(&debugMode, "debug", false, "Enable debug mode (pause before each LLM call)")
-	rootCmd.Flags().StringVarP(&promptStr, "prompt", "p", "", "Provide a prompt to execute (non-interactive mode)")
+	rootCmd.Flags().StringVarP(&promptStr, "prompt", "p", "", "Provide a prompt to execute (non-interactive mode); - reads it from stdin")
+	rootCmd.Flags().StringVar(&promptFile, "prompt-file", "", "Read the prompt to execute from this file (- for stdin)")
 	rootCmd.Flags().IntVar(&maxTurns, "max-turns", 20, "Maximum number of turns for non-interactive mode")
 	rootCmd.Flags().StringVar(&allowedTools, "allowedTools", "", "Comma-separated list of allowed tools")
+	rootCmd.Flags().StringVar(&denyTools, "deny-tools", "", "Comma-separated list of tools to remove (added to disabled_tools; wins over --allowedTools)")
+	rootCmd.Flags().BoolVar(&showReasoning, "show-reasoning", false, "Print the reasoning returned by reasoning models, dimmed, before each answer")
+	rootCmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "Print only the final result: no tool output, model narration or auto-approval notices")
+	rootCmd.Flags().BoolVar(&planMode, "plan", false, "With -p: have the agent record a todo plan using read-only tools, then ask before carrying it out")
+	rootCmd.Flags().StringVar(&transcriptPath, "transcript", "", "With -p: save the conversation, tool output and diffs as a Markdown transcript to this file")
+	rootCmd.Flags().StringVar(&tracePath, "trace", "", "Write a JSONL record of each LLM call, tool call, approval and usage report to this file")
+	rootCmd.Flags().StringVar(&stagingDir, "staging-dir", "", "Dry run: write file changes to this directory, mirroring the project layout, instead of the real files")
+	rootCmd.Flags().BoolVar(&readOnly, "read-only", false, "Only offer read-only tools (for exploring or reviewing code), auto-approving them")
 	rootCmd.Flags().StringVar(&permissionMode, "permission-mode", "", "Permission mode: bypassPermissions")
+	rootCmd.Flags().IntVar(&maxTotalTokens, "max-total-tokens", 0, "Stop once a run has used this many tokens in total (0 means unlimited)")
+	rootCmd.Flags().IntVar(&approvalTimeout, "approval-timeout", 0, "Seconds to wait for an approval choice before applying the default action (0 waits forever)")
+	rootCmd.Flags().StringVar(&diffStyle, "diff-style", "", "How file changes are shown for approval: unified or side-by-side (default from approval.diff_style)")
+	rootCmd.Flags().StringSliceVar(&imagePaths, "image", nil, "Attach an image to the prompt (repeatable; the model needs vision: true)")
 	rootCmd.Flags().BoolVar(&dangerousSkip, "dangerously-skip-permissions", false, "Skip all permission checks (use with caution)")
 	rootCmd.Flags().StringVarP(&modelSelection, "model", "m", "", "Model selection (e.g., 'default', 'fast', 'groq/llama3-8b')")
+	rootCmd.Flags().Bool("print-config", false, "Print the effective configuration and exit (same as 'agenticode config show')")
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
 
@@ -85,33 +124,48 @@ func initConfig() {
 	if err := viper.ReadInConfig(); err == nil {
 		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
+
+	// With --project-config, settings in the project's .agenticode.yaml take
+	// precedence, except those that affect security
+	if !useProjectConfig {
+		return
+	}
+	path, ignored, err := mergeProjectConfig(viper.GetViper(), ".")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Ignoring project config:", err)
+	} else if path != "" {
+		projectConfigFile = path
+		fmt.Fprintln(os.Stderr, "Using project config file:", path)
+		if len(ignored) > 0 {
+			fmt.Fprintf(os.Stderr, "Ignoring settings the project config may not change: %s\n", strings.Join(ignored, ", "))
+		}
+	}
 }
 
 func runInteractiveMode(cmd *cobra.Command, args []string) error {
-	// Try to load providers configuration first
-	var client llm.Client
-	var err error
-
-	// Check if providers configuration exists
-	providersConfig := &llm.ProvidersConfig{
-		Providers: make(map[string]llm.ProviderConfig),
-		Models:    make(map[string]llm.ModelSelection),
+	if printConfig, _ := cmd.Flags().GetBool("print-config"); printConfig {
+		return configShowCmd.RunE(cmd, args)
 	}
 
-	// Load providers from viper
-	if !viper.IsSet("providers") {
-		return fmt.Errorf("failed to see Providers. add providers on config see .agenticode.yaml")
+	// --plan asks for its confirmation on stdin, which a piped prompt uses up
+	if planMode && promptFromStdin(promptStr, promptFile) {
+		return fmt.Errorf("--plan reads its confirmation from stdin, so the prompt cannot come from stdin too; use --prompt-file <file> instead")
 	}
 
-	if err := viper.UnmarshalKey("providers", &providersConfig.Providers); err != nil {
-		return fmt.Errorf("failed to load providers configuration: %w", err)
+	// Long prompts can come from stdin or a file instead of the flag value
+	prompt, err := resolvePrompt(promptStr, promptFile, cmd.InOrStdin())
+	if err != nil {
+		return err
 	}
+	promptStr = prompt
 
-	// Load model selections
-	if viper.IsSet("models") {
-		if err := viper.UnmarshalKey("models", &providersConfig.Models); err != nil {
-			return fmt.Errorf("failed to load models configuration: %w", err)
-		}
+	// Verbose client and MCP logging may include secrets, so it is debug-only
+	llm.SetDebug(debugMode)
+	mcp.SetDebug(debugMode)
+
+	providersConfig, err := loadProvidersConfig(viper.GetViper())
+	if err != nil {
+		return err
 	}
 
 	// Determine which model to use
@@ -121,17 +175,41 @@ func runInteractiveMode(cmd *cobra.Command, args []string) error {
 		selectedModel = "default"
 	}
 
-	// Create client with multi-provider configuration
-	client, err = llm.NewClient(llm.Config{
+	// Create client with multi-provider configuration; an unknown model is
+	// reported together with the configured ones
+	client, err := llm.NewClient(llm.Config{
 		ProvidersConfig: providersConfig,
 		ModelSelection:  selectedModel,
 	})
-
 	if err != nil {
-		// If specific model selection failed, try legacy configuration
-		fmt.Printf("Warning: Failed to use multi-provider configuration: %v\n", err)
-		fmt.Println("Falling back to legacy configuration...")
+		return err
+	}
+
+	// Language of user-facing messages and injected guidance
+	if err := locale.SetLanguage(viper.GetString("general.language")); err != nil {
+		return fmt.Errorf("invalid general.language: %w", err)
+	}
+
+	// Custom variables for the system/developer prompt templates
+	agent.SetPromptVars(viper.GetStringMapString("prompts.vars"))
+
+	// A custom system prompt template replaces the built-in one
+	if templatePath := viper.GetString("prompts.system_template"); templatePath != "" {
+		content, err := os.ReadFile(os.ExpandEnv(templatePath))
+		if err != nil {
+			return fmt.Errorf("failed to read prompts.system_template: %w", err)
+		}
+		if err := agent.SetSystemPromptTemplate(string(content)); err != nil {
+			return err
+		}
+	}
+
+	// Few-shot tool call examples appended to the developer prompt
+	var toolExamples []agent.ToolExample
+	if err := viper.UnmarshalKey("prompts.tool_examples", &toolExamples); err != nil {
+		return fmt.Errorf("invalid prompts.tool_examples: %w", err)
 	}
+	agent.SetToolExamples(toolExamples)
 
 	// Create agent
 	maxSteps := viper.GetInt("general.max_steps")
@@ -150,14 +228,112 @@ func runInteractiveMode(cmd *cobra.Command, args []string) error {
 	// Configure approver based on command line flags
 	if dangerousSkip || permissionMode == "bypassPermissions" {
 		// Auto-approve all tools when permissions are bypassed
-		approver.SetAutoApprove([]string{"write_file", "run_shell", "edit", "read_file", "read", "list_files", "grep", "glob", "read_many_files", "todo_write", "todo_read"})
+		approver.SetAutoApprove([]string{"write_file", "run_shell", "run_tests", "edit", "read_file", "read", "list_files", "grep", "glob", "read_many_files", "watch_file", "read_bytes", "summarize_file", "todo_write", "todo_read", "memory_write", "memory_read", "pin_file", "git_diff", "git_commit", "delete_file"})
 	} else {
 		// Default: only auto-approve safe tools
-		approver.SetAutoApprove([]string{"read_file", "read", "list_files", "grep", "glob", "read_many_files", "todo_write", "todo_read"})
+		approver.SetAutoApprove([]string{"read_file", "read", "list_files", "grep", "glob", "read_many_files", "watch_file", "read_bytes", "summarize_file", "todo_write", "todo_read", "memory_write", "memory_read", "pin_file", "git_diff"})
+	}
+
+	// Everything under a trusted directory is auto-approved regardless of risk
+	approver.SetTrustedDirs(viper.GetStringSlice("permissions.trusted_dirs"))
+
+	// Rules auto-approve specific commands or paths for a tool
+	var approvalRules []agent.ApprovalRule
+	if err := viper.UnmarshalKey("approval.rules", &approvalRules); err != nil {
+		return fmt.Errorf("invalid approval.rules: %w", err)
+	}
+	if err := approver.SetApprovalRules(approvalRules); err != nil {
+		return fmt.Errorf("invalid approval.rules: %w", err)
 	}
 
+	style := diffStyle
+	if !cmd.Flags().Changed("diff-style") {
+		style = viper.GetString("approval.diff_style")
+	}
+	if err := approver.SetDiffStyle(style); err != nil {
+		return err
+	}
+
+	// Fall back to the default action if nobody answers the approval prompt
+	timeoutSeconds := approvalTimeout
+	if !cmd.Flags().Changed("approval-timeout") {
+		timeoutSeconds = viper.GetInt("approval.timeout")
+	}
+	approver.SetTimeout(time.Duration(timeoutSeconds)*time.Second, viper.GetBool("approval.default_approve"))
+	approver.SetQuiet(quiet)
+
 	// Get tools
+	tools.SetFormatOnWrite(viper.GetStringMapString("format.on_write"))
+	if viper.IsSet("tools.mask_dotenv") {
+		tools.SetDotenvMasking(viper.GetBool("tools.mask_dotenv"))
+	}
+	tools.SetReadMaxLines(viper.GetInt("tools.read.max_lines"))
+	tools.SetPreserveEncoding(viper.GetBool("tools.preserve_encoding"))
+	maxWrite, confirmWrite := int64(tools.DefaultMaxWriteBytes), int64(tools.DefaultConfirmWriteBytes)
+	if viper.IsSet("security.max_write_bytes") {
+		maxWrite = viper.GetInt64("security.max_write_bytes")
+	}
+	if viper.IsSet("security.confirm_write_bytes") {
+		confirmWrite = viper.GetInt64("security.confirm_write_bytes")
+	}
+	tools.SetWriteLimits(maxWrite, confirmWrite)
+	if err := tools.SetProtectedBranches(viper.GetStringSlice("git.protected_branches")); err != nil {
+		return err
+	}
+	if viper.IsSet("tools.run_shell.dangerous_patterns") {
+		if err := tools.SetDangerousShellPatterns(viper.GetStringSlice("tools.run_shell.dangerous_patterns")); err != nil {
+			return err
+		}
+	}
+	if err := tools.SetShellEnv(viper.GetStringSlice("tools.run_shell.env")); err != nil {
+		return err
+	}
 	availableTools := tools.GetDefaultTools()
+	for _, tool := range availableTools {
+		switch t := tool.(type) {
+		case *tools.ReadManyFilesTool:
+			t.SetMaxFiles(viper.GetInt("tools.read_many_files.max_files"))
+		case *tools.AskUserTool:
+			t.SetDefaultAnswer(viper.GetString("tools.ask_user.default_answer"))
+		}
+	}
+	for _, path := range viper.GetStringSlice("pinned_files") {
+		if _, err := tools.GlobalPinnedFiles.Pin(path); err != nil {
+			log.Printf("Failed to pin configured file: %v", err)
+		}
+	}
+
+	// web_fetch needs the LLM client, so it is only built here when configured
+	if viper.IsSet("tools.web_fetch") {
+		cleanOptions := tools.DefaultHTMLCleanOptions()
+		if viper.IsSet("tools.web_fetch.flatten_tables") {
+			cleanOptions.FlattenTables = viper.GetBool("tools.web_fetch.flatten_tables")
+		}
+		if viper.IsSet("tools.web_fetch.strip_boilerplate") {
+			cleanOptions.StripBoilerplate = viper.GetBool("tools.web_fetch.strip_boilerplate")
+		}
+		if viper.IsSet("tools.web_fetch.max_nesting_depth") {
+			cleanOptions.MaxNestingDepth = viper.GetInt("tools.web_fetch.max_nesting_depth")
+		}
+		webFetch := tools.NewWebFetchTool(agent.NewLLMAdapter(client))
+		webFetch.SetCleanOptions(cleanOptions)
+		availableTools = append(availableTools, webFetch)
+	}
+
+	// summarize_file uses the summarize model when one is configured; otherwise
+	// the agent builds it on the main client
+	if summarizeClient := newSummarizeClient(); summarizeClient != nil {
+		availableTools = append(availableTools, tools.NewSummarizeFileTool(agent.NewLLMAdapter(summarizeClient)))
+	}
+
+	// web_search is only offered when a search backend is configured
+	if providerName := viper.GetString("tools.web_search.provider"); providerName != "" {
+		provider, err := tools.NewSearchProvider(providerName, os.ExpandEnv(viper.GetString("tools.web_search.api_key")))
+		if err != nil {
+			return fmt.Errorf("invalid web_search configuration: %w", err)
+		}
+		availableTools = append(availableTools, tools.NewWebSearchTool(provider, viper.GetInt("tools.web_search.max_results")))
+	}
 	
 	// Load MCP tools if configured
 	ctx := context.Background()
@@ -172,24 +348,45 @@ func runInteractiveMode(cmd *cobra.Command, args []string) error {
 		defer mcpManager.CloseAll()
 	}
 
-	// Filter tools if allowedTools is specified
-	if allowedTools != "" {
-		allowedList := strings.Split(allowedTools, ",")
+	// Aliases expose existing tools under project-specific names with preset arguments
+	var toolAliases map[string]tools.ToolAliasConfig
+	if err := viper.UnmarshalKey("tool_aliases", &toolAliases); err != nil {
+		return fmt.Errorf("invalid tool_aliases: %w", err)
+	}
+	aliasTools, err := tools.BuildAliasTools(toolAliases, availableTools)
+	if err != nil {
+		return err
+	}
+	availableTools = append(availableTools, aliasTools...)
+
+	// Filter tools if allowedTools, denied tools or --read-only is specified
+	keepTool := toolFilter(allowedTools, append(viper.GetStringSlice("disabled_tools"), denyTools), readOnly)
+	if keepTool != nil {
 		filteredTools := []tools.Tool{}
 		for _, tool := range availableTools {
-			for _, allowed := range allowedList {
-				if tool.Name() == strings.TrimSpace(allowed) {
-					filteredTools = append(filteredTools, tool)
-					break
-				}
+			if keepTool(tool) {
+				filteredTools = append(filteredTools, tool)
 			}
 		}
 		availableTools = filteredTools
 	}
 
+	// Read-only sessions run exactly the remaining tools without prompting
+	if readOnly {
+		var names []string
+		for _, tool := range availableTools {
+			names = append(names, tool.Name())
+		}
+		approver.SetAutoApprove(names)
+	}
+
 	// Load hook configuration
 	projectDir, _ := os.Getwd()
 	sessionID := fmt.Sprintf("session_%d", os.Getpid()) // Simple session ID for now
+	tools.GlobalTrash.SetDir(filepath.Join(projectDir, ".agenticode", "trash", sessionID))
+	if err := tools.GlobalMemoryStore.Load(filepath.Join(projectDir, ".agenticode", "memory.json")); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Notes from earlier sessions are unavailable: %v\n", err)
+	}
 
 	var hookManager *hooks.Manager
 	if hookConfig, err := loadHooksFromViper(); err == nil && hookConfig != nil {
@@ -197,21 +394,139 @@ func runInteractiveMode(cmd *cobra.Command, args []string) error {
 		log.Printf("Loaded hook configuration with %d hook types", countHookTypes(hookConfig))
 	}
 
+	// Record why each tool call ran or was refused, for the approvals command
+	decisionLog := agent.NewDecisionLog(hooks.TranscriptPath(sessionID))
+
+	// Keep what tool calls showed for --transcript and the export command
+	toolOutputs := agent.NewToolOutputs()
+
 	// Build agent options
 	opts := []agent.Option{
 		agent.WithMaxSteps(maxSteps),
 		agent.WithApprover(approver),
 		agent.WithTools(availableTools),
+		agent.WithDecisionLog(decisionLog),
+		agent.WithToolOutputs(toolOutputs),
+	}
+	if keepTool != nil {
+		opts = append(opts, agent.WithToolFilter(keepTool))
+	}
+
+	if showReasoning {
+		opts = append(opts, agent.WithShowReasoning(true))
+	}
+	if quiet {
+		opts = append(opts, agent.WithQuiet(true))
+	}
+
+	// Interactive sessions show a spinner while slow tools such as grep run
+	if promptStr == "" && !quiet && term.IsTerminal(int(os.Stdout.Fd())) {
+		opts = append(opts, agent.WithProgressDisplay(agent.NewTerminalProgress(os.Stdout)))
+	}
+
+	// With a staging directory every run is a dry run whose changes land there
+	dryRun := stagingDir != ""
+	if dryRun {
+		opts = append(opts, agent.WithStagingDir(stagingDir))
+		if !quiet {
+			fmt.Printf("🧪 Dry run: file changes are written to %s (review them with `agenticode apply %s`)\n", stagingDir, stagingDir)
+		}
 	}
 
 	if debugMode {
 		opts = append(opts, agent.WithDebugger(agent.NewInteractiveDebugger()))
 	}
 
+	// Clarifying questions need someone at the terminal; -p runs use the default answer
+	if promptStr == "" {
+		opts = append(opts, agent.WithUserPrompter(approver))
+	}
+
+	if viper.GetBool("general.streaming") {
+		opts = append(opts, agent.WithStreaming(true))
+		if viper.IsSet("general.stream_fallback") {
+			opts = append(opts, agent.WithStreamFallback(viper.GetBool("general.stream_fallback")))
+		}
+	}
+
+	if viper.GetBool("general.read_before_edit") {
+		opts = append(opts, agent.WithReadBeforeEdit(true))
+	}
+	if maxReadBytes := viper.GetInt("general.max_read_bytes"); maxReadBytes > 0 {
+		opts = append(opts, agent.WithReadBudget(maxReadBytes))
+	}
+	if maxTurnBytes := viper.GetInt("general.max_turn_tool_bytes"); maxTurnBytes > 0 {
+		opts = append(opts, agent.WithTurnOutputBudget(maxTurnBytes))
+	}
+
+	// Repeated identical tool calls trigger corrective guidance
+	if viper.IsSet("general.repetition_threshold") || viper.IsSet("general.repetition_window") {
+		threshold, window := agent.DefaultRepeatThreshold, agent.DefaultRepeatWindow
+		if viper.IsSet("general.repetition_threshold") {
+			threshold = viper.GetInt("general.repetition_threshold")
+		}
+		if viper.IsSet("general.repetition_window") {
+			window = viper.GetInt("general.repetition_window")
+		}
+		opts = append(opts, agent.WithRepetitionDetection(threshold, window))
+	}
+
+	// Automatic compaction thresholds (sub-agents inherit unless overridden)
+	if maxContextTokens := viper.GetInt("general.auto_compact_tokens"); maxContextTokens > 0 {
+		opts = append(opts, agent.WithAutoCompact(maxContextTokens))
+	}
+	if subAgentContextTokens := viper.GetInt("general.subagent_auto_compact_tokens"); subAgentContextTokens > 0 {
+		opts = append(opts, agent.WithSubAgentAutoCompact(subAgentContextTokens))
+	}
+	contextStrategy, err := agent.ParseContextStrategy(viper.GetString("context.strategy"))
+	if err != nil {
+		return fmt.Errorf("invalid context.strategy: %w", err)
+	}
+	opts = append(opts, agent.WithContextStrategy(contextStrategy))
+	toolErrorPolicy, err := agent.ParseToolErrorPolicy(viper.GetString("general.tool_error_policy"))
+	if err != nil {
+		return fmt.Errorf("invalid general.tool_error_policy: %w", err)
+	}
+	opts = append(opts, agent.WithToolErrorPolicy(toolErrorPolicy))
+	if viper.IsSet("general.max_subagent_depth") {
+		opts = append(opts, agent.WithMaxSubAgentDepth(viper.GetInt("general.max_subagent_depth")))
+	}
+
+	// Token budget: the flag takes precedence over the config file
+	tokenBudget := maxTotalTokens
+	if !cmd.Flags().Changed("max-total-tokens") {
+		tokenBudget = viper.GetInt("general.max_total_tokens")
+	}
+	if tokenBudget > 0 {
+		opts = append(opts, agent.WithMaxTotalTokens(tokenBudget))
+	}
+
 	if hookManager != nil {
 		opts = append(opts, agent.WithHookManager(hookManager))
 	}
 
+	// Opt-in telemetry: metrics only go where the config points them
+	var telemetryConfig telemetry.Config
+	if err := viper.UnmarshalKey("telemetry", &telemetryConfig); err != nil {
+		return fmt.Errorf("invalid telemetry configuration: %w", err)
+	}
+	sink, err := telemetry.NewSink(telemetryConfig)
+	if err != nil {
+		return fmt.Errorf("failed to set up telemetry: %w", err)
+	}
+	if sink != nil {
+		defer sink.Close()
+		opts = append(opts, agent.WithTelemetry(sink))
+	}
+	if tracePath != "" {
+		tracer, err := agent.NewTracer(tracePath)
+		if err != nil {
+			return err
+		}
+		defer tracer.Close()
+		opts = append(opts, agent.WithTracer(tracer))
+	}
+
 	agentInstance := agent.NewAgent(client, opts...)
 
 	// Get model name for prompts
@@ -265,34 +580,75 @@ func runInteractiveMode(cmd *cobra.Command, args []string) error {
 			}
 		}
 
-		conversation = append(conversation, openai.ChatCompletionMessage{
-			Role:    "user",
-			Content: finalPrompt,
-		},
-		)
+		if len(imagePaths) > 0 && !supportsVision(client) {
+			return fmt.Errorf("--image needs a model configured with vision: true")
+		}
+		userMessage, err := agent.NewUserMessage(finalPrompt, imagePaths)
+		if err != nil {
+			return err
+		}
+		conversation = append(conversation, userMessage)
+
+		if !quiet {
+			fmt.Println(locale.T(locale.ExecutingPrompt, maxSteps))
+		}
 
-		fmt.Printf("🚀 Executing prompt with max %d turns...\n", maxSteps)
+		// Plan mode: review the agent's plan before it changes anything
+		if planMode {
+			planned, approved, err := planAndConfirm(ctx, agentInstance, conversation, os.Stdin, os.Stdout)
+			if err != nil || !approved {
+				return err
+			}
+			conversation = planned
+		}
 
-		response, _, err := agentInstance.ExecuteWithHistory(ctx, conversation, false)
+		runStart := time.Now()
+		response, finalConversation, err := agentInstance.ExecuteWithHistory(ctx, conversation, dryRun)
+		writeRunSummary(sessionID, client, response, err, time.Since(runStart))
+		if transcriptPath != "" {
+			if finalConversation == nil {
+				finalConversation = conversation
+			}
+			if err := writeTranscript(transcriptPath, finalConversation, toolOutputs); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}
 		if err != nil {
 			return fmt.Errorf("error executing prompt: %w", err)
 		}
 
+		// Quiet runs print just the answer; problems go to stderr
+		if quiet {
+			if !response.Success {
+				fmt.Fprintln(os.Stderr, locale.T(locale.TaskFailed))
+			}
+			if response.HadErrors {
+				fmt.Fprintln(os.Stderr, locale.T(locale.ToolErrorsUnresolved))
+			}
+			if response.Message != "" {
+				fmt.Println(response.Message)
+			}
+			return nil
+		}
+
 		// Display execution result
 		if response.Success {
-			fmt.Println("\n✅ Task completed successfully!")
+			fmt.Println("\n" + locale.T(locale.TaskSucceeded))
 		} else {
-			fmt.Println("\n⚠️  Task did not complete successfully")
+			fmt.Println("\n" + locale.T(locale.TaskFailed))
+		}
+		if response.HadErrors {
+			fmt.Println(locale.T(locale.ToolErrorsUnresolved))
 		}
 
 		// Display the response
 		if response.Message != "" {
-			fmt.Printf("\n💬 Final message: %s\n", response.Message)
+			fmt.Println("\n" + locale.T(locale.FinalMessage, response.Message))
 		}
 
 		// Show execution steps summary
 		if len(response.Steps) > 0 {
-			fmt.Printf("\n📊 Execution summary: %d steps taken\n", len(response.Steps))
+			fmt.Println("\n" + locale.T(locale.ExecutionSummary, len(response.Steps)))
 			for i, step := range response.Steps {
 				if step.ToolName != "" {
 					fmt.Printf("  %d. %s", i+1, step.ToolName)
@@ -306,7 +662,7 @@ func runInteractiveMode(cmd *cobra.Command, args []string) error {
 
 		// Show any generated files summary
 		if len(response.GeneratedFiles) > 0 {
-			fmt.Printf("\n📝 Generated %d file(s):\n", len(response.GeneratedFiles))
+			fmt.Println("\n" + locale.T(locale.GeneratedFiles, len(response.GeneratedFiles)))
 			for _, file := range response.GeneratedFiles {
 				fmt.Printf("  • %s\n", file.Path)
 			}
@@ -322,22 +678,133 @@ func runInteractiveMode(cmd *cobra.Command, args []string) error {
 	fmt.Println("Type 'compact' to compress conversation history into a summary")
 	fmt.Println("Type 'init' to generate or update AGENTIC.md documentation")
 	fmt.Println("Type 'history' to view conversation history")
+	fmt.Println("Type '/export <file.md>' to save the conversation, tool output and diffs as a Markdown transcript")
+	fmt.Println("Type 'edit-last' to revise your previous prompt and run it again, or 'retry' to rerun it unchanged")
 	fmt.Println("Type 'todos' to view the todo store")
+	fmt.Println("Type '/plan <task>' to have the agent plan a task as todos without changing anything, then '/go' to carry it out")
+	fmt.Println("Type 'approvals' to view why tool calls were approved or rejected")
+	fmt.Println("Type 'tools' to list the available tools and their parameters")
+	fmt.Println("Type 'mcp' to show the state of the configured MCP servers")
+	fmt.Println("Type '/tools disable <name>' or '/tools enable <name>' to switch a tool off or on for this session")
+	fmt.Println("Type '/image <path>' to attach a screenshot or other image to your next prompt (vision models only)")
+	fmt.Println("Type '/trash' to list files deleted this session and '/restore <path>' to bring one back")
+	fmt.Println("Type '/pin <file>' to show a file's current contents every turn, '/unpin [file]' to stop (all files if none given)")
+
+	// Load custom slash commands from .agenticode/commands
+	customCommands, err := commands.Load(commands.DefaultDirs(projectDir)...)
+	if err != nil {
+		log.Printf("Failed to load custom commands: %v", err)
+	}
+	if list := customCommands.List(); len(list) > 0 {
+		fmt.Println("Custom commands:")
+		for _, c := range list {
+			if c.Description != "" {
+				fmt.Printf("  /%s - %s\n", c.Name, c.Description)
+			} else {
+				fmt.Printf("  /%s\n", c.Name)
+			}
+		}
+	}
 	fmt.Println("---")
 
-	scanner := bufio.NewScanner(os.Stdin)
+	// Read prompts through the approver so it and the session share one reader
+	var inputErr error
+	readLine := func() (string, bool) {
+		line, err := approver.ReadLine(context.Background())
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				inputErr = err
+			}
+			return "", false
+		}
+		return line, true
+	}
+	var pendingImages []string               // Attached to the next prompt
+	var retryImages []openai.ChatMessagePart // Images of a prompt being rerun
+	planPending := false                     // A plan awaits 'go'
 
 	for {
 		fmt.Print("\n> ")
-		if !scanner.Scan() {
+		line, ok := readLine()
+		if !ok {
 			break
 		}
 
-		input := strings.TrimSpace(scanner.Text())
+		input := strings.TrimSpace(line)
 		if input == "" {
 			continue
 		}
 
+		if handleTrashCommand(input) {
+			continue
+		}
+		if handlePinCommand(input) {
+			continue
+		}
+		if handleToolToggleCommand(agentInstance, input) {
+			continue
+		}
+
+		// Attach an image to the next prompt
+		if fields := strings.Fields(input); len(fields) > 0 && fields[0] == "/image" {
+			if len(fields) != 2 {
+				fmt.Println("Usage: /image <path>")
+			} else if !supportsVision(client) {
+				fmt.Println("❌ The current model does not accept images; set vision: true in its model config")
+			} else if _, err := agent.NewUserMessage("", fields[1:]); err != nil {
+				fmt.Printf("❌ %v\n", err)
+			} else {
+				pendingImages = append(pendingImages, fields[1])
+				fmt.Printf("🖼️  %s will be attached to your next prompt\n", fields[1])
+			}
+			continue
+		}
+
+		// Save the session as a Markdown transcript
+		if fields := strings.Fields(input); len(fields) > 0 && fields[0] == "/export" {
+			if len(fields) != 2 {
+				fmt.Println("Usage: /export <file.md>")
+				continue
+			}
+			saved, err := exportTranscript(fields[1], conversation, toolOutputs, func(question string) bool {
+				fmt.Print(question)
+				answer, ok := readLine()
+				return ok && strings.EqualFold(strings.TrimSpace(answer), "y")
+			})
+			switch {
+			case err != nil:
+				fmt.Printf("❌ %v\n", err)
+			case saved:
+				fmt.Printf("📝 Transcript saved to %s\n", fields[1])
+			default:
+				fmt.Println("Transcript not saved.")
+			}
+			continue
+		}
+
+		// Drop the previous turn and run its prompt again, optionally revised
+		if lower := strings.ToLower(input); lower == "edit-last" || lower == "retry" {
+			rolledBack, lastPrompt, images, ok := rollbackLastTurn(conversation)
+			if !ok {
+				fmt.Println("No previous prompt to edit.")
+				continue
+			}
+			conversation = rolledBack
+			retryImages = images
+			fmt.Println(locale.T(locale.RemovedLastTurn, lastPrompt))
+			input = lastPrompt
+			if lower == "edit-last" {
+				fmt.Print("Revised prompt (empty to run it unchanged): ")
+				revised, ok := readLine()
+				if !ok {
+					break
+				}
+				if revised := strings.TrimSpace(revised); revised != "" {
+					input = revised
+				}
+			}
+		}
+
 		// Handle special commands
 		switch strings.ToLower(input) {
 		case "exit", "quit":
@@ -366,28 +833,8 @@ func runInteractiveMode(cmd *cobra.Command, args []string) error {
 			}
 
 			// Check if a summarization model is configured
-			var summarizeClient llm.Client
-			useSummarizeModel := false
-			
-			if viper.IsSet("models.summarize") {
-				// Try to create a client for the summarization model
-				summarizeConfig := &llm.ProvidersConfig{
-					Providers: make(map[string]llm.ProviderConfig),
-					Models:    make(map[string]llm.ModelSelection),
-				}
-				
-				if err := viper.UnmarshalKey("providers", &summarizeConfig.Providers); err == nil {
-					if err := viper.UnmarshalKey("models", &summarizeConfig.Models); err == nil {
-						if sumClient, err := llm.NewClient(llm.Config{
-							ProvidersConfig: summarizeConfig,
-							ModelSelection:  "summarize",
-						}); err == nil {
-							summarizeClient = sumClient
-							useSummarizeModel = true
-						}
-					}
-				}
-			}
+			summarizeClient := newSummarizeClient()
+			useSummarizeModel := summarizeClient != nil
 
 			// Perform summarization
 			result, err := agent.SummarizeConversation(
@@ -461,7 +908,7 @@ func runInteractiveMode(cmd *cobra.Command, args []string) error {
 
 			// Execute task with conversation history
 			ctx := context.Background()
-			response, updatedConversation, err := agentInstance.ExecuteWithHistory(ctx, conversation, false)
+			response, updatedConversation, err := agentInstance.ExecuteWithHistory(ctx, conversation, dryRun)
 			if err != nil {
 				fmt.Printf("❌ Error generating AGENTIC.md: %v\n", err)
 				// Remove the init prompt from conversation if it failed
@@ -527,6 +974,39 @@ func runInteractiveMode(cmd *cobra.Command, args []string) error {
 			}
 			fmt.Println("\n--- End of History ---")
 			continue
+		case "approvals":
+			decisions := decisionLog.Decisions()
+			fmt.Println("\n--- Approval Decisions ---")
+			if len(decisions) == 0 {
+				fmt.Println("No tool calls yet.")
+			}
+			for _, d := range decisions {
+				status := "✅ approved"
+				if !d.Approved {
+					status = "❌ rejected"
+				}
+				fmt.Printf("\n%s %s %s (%s)\n", d.Time.Format("15:04:05"), d.ToolName, status, d.Source)
+				fmt.Printf("   %s\n", d.Reason)
+			}
+			fmt.Println("\n--- End of Approval Decisions ---")
+			continue
+		case "tools":
+			fmt.Println("\n--- Tools ---")
+			writeToolList(os.Stdout, agentInstance.Tools())
+			if disabled := agentInstance.DisabledTools(); len(disabled) > 0 {
+				fmt.Printf("Disabled tools: %s\n", strings.Join(disabled, ", "))
+			}
+			fmt.Println("--- End of Tools ---")
+			continue
+		case "mcp":
+			fmt.Println("\n--- MCP Servers ---")
+			var states map[string]mcp.ClientInfo
+			if mcpManager != nil {
+				states = mcpManager.GetAllStates()
+			}
+			writeMCPStatus(os.Stdout, states)
+			fmt.Println("--- End of MCP Servers ---")
+			continue
 		case "todos":
 			todos := tools.GlobalTodoStore.ReadAll()
 			fmt.Println("\n--- Todo Store ---")
@@ -555,6 +1035,32 @@ func runInteractiveMode(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
+		// '/plan <task>' only plans; '/go' carries out the plan that was shown
+		planning := false
+		var planStart time.Time
+		if fields := strings.Fields(input); len(fields) > 0 && fields[0] == "/plan" {
+			if len(fields) == 1 {
+				fmt.Println("Usage: /plan <task>")
+				continue
+			}
+			planning = true
+			planStart = time.Now()
+			input = strings.TrimSpace(input[len(fields[0]):])
+		} else if input == "/go" {
+			if !planPending {
+				fmt.Println("No plan to carry out. Use /plan <task> first.")
+				continue
+			}
+			input = agent.PlanApprovedPrompt
+		}
+		planPending = false
+
+		// Expand custom slash commands into their prompt template
+		if c, args, ok := customCommands.Parse(input); ok {
+			input = c.Expand(args)
+			fmt.Printf("📝 Running /%s\n", c.Name)
+		}
+
 		// Execute UserPromptSubmit hooks
 		finalInput := input
 		ctx := context.Background()
@@ -592,18 +1098,35 @@ func runInteractiveMode(cmd *cobra.Command, args []string) error {
 			}
 		}
 
-		// Add user message to conversation
-		conversation = append(conversation, openai.ChatCompletionMessage{
-			Role:    "user",
-			Content: finalInput,
-		})
+		// Add user message to conversation, with any images attached for it
+		userMessage, err := agent.NewUserMessage(finalInput, pendingImages)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			continue
+		}
+		userMessage = attachImageParts(userMessage, retryImages)
+		pendingImages, retryImages = nil, nil
+		conversation = append(conversation, userMessage)
 
 		// Execute task with conversation history
-		response, updatedConversation, err := agentInstance.ExecuteWithHistory(ctx, conversation, false)
+		var response *agent.ExecutionResult
+		var updatedConversation []openai.ChatCompletionMessage
+		if planning {
+			response, updatedConversation, err = agentInstance.PlanWithHistory(ctx, conversation)
+		} else {
+			response, updatedConversation, err = agentInstance.ExecuteWithHistory(ctx, conversation, dryRun)
+		}
 		if err != nil {
 			fmt.Printf("Error: %v\n", err)
 			continue
 		}
+		if planning {
+			conversation = updatedConversation
+			writePlan(os.Stdout, response.Message, plannedTodos(planStart))
+			fmt.Println("\nType '/go' to carry out the plan, or describe what to change.")
+			planPending = true
+			continue
+		}
 
 		// Update our conversation with the agent's updated version
 		conversation = updatedConversation
@@ -620,8 +1143,8 @@ func runInteractiveMode(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error reading input: %w", err)
+	if inputErr != nil {
+		return fmt.Errorf("error reading input: %w", inputErr)
 	}
 
 	return nil
@@ -635,7 +1158,8 @@ func loadHooksFromViper() (*hooks.HookConfig, error) {
 	}
 
 	var config hooks.HookConfig
-	if err := viper.UnmarshalKey("hooks", &config); err != nil {
+	// Timeouts are written in seconds, which mapstructure alone would read as nanoseconds
+	if err := viper.UnmarshalKey("hooks", &config, viper.DecodeHook(hooks.TimeoutDecodeHook)); err != nil {
 		return nil, fmt.Errorf("failed to load hooks configuration: %w", err)
 	}
 
@@ -676,3 +1200,260 @@ func countHookTypes(config *hooks.HookConfig) int {
 	}
 	return count
 }
+
+// toolFilter builds the predicate for --allowedTools, --deny-tools (plus
+// disabled_tools) and --read-only, or nil when every tool is allowed. A
+// denied tool is dropped even if it is also allowed.
+func toolFilter(allowedTools string, deniedTools []string, readOnly bool) func(tools.Tool) bool {
+	allowed := make(map[string]bool)
+	for _, name := range strings.Split(allowedTools, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			allowed[name] = true
+		}
+	}
+	denied := make(map[string]bool)
+	for _, list := range deniedTools {
+		for _, name := range strings.Split(list, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				denied[name] = true
+			}
+		}
+	}
+	if len(allowed) == 0 && len(denied) == 0 && !readOnly {
+		return nil
+	}
+
+	return func(tool tools.Tool) bool {
+		if denied[tool.Name()] {
+			return false
+		}
+		if len(allowed) > 0 && !allowed[tool.Name()] {
+			return false
+		}
+		return !readOnly || tool.ReadOnly()
+	}
+}
+
+// handleToolToggleCommand handles "/tools disable <name>" and "/tools enable
+// <name>", reporting whether input was one of them
+func handleToolToggleCommand(a *agent.Agent, input string) bool {
+	fields := strings.Fields(input)
+	if len(fields) < 2 || fields[0] != "/tools" {
+		return false
+	}
+
+	var toggle func(string) error
+	var done string
+	verb := strings.ToLower(fields[1])
+	switch verb {
+	case "disable":
+		toggle, done = a.DisableTool, "Disabled"
+	case "enable":
+		toggle, done = a.EnableTool, "Enabled"
+	default:
+		return false
+	}
+	if len(fields) == 2 {
+		fmt.Printf("Usage: /tools %s <name>...\n", verb)
+		return true
+	}
+	for _, name := range fields[2:] {
+		if err := toggle(name); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			continue
+		}
+		fmt.Printf("🔧 %s %s\n", done, name)
+	}
+	if disabled := a.DisabledTools(); len(disabled) > 0 {
+		fmt.Printf("Disabled tools: %s\n", strings.Join(disabled, ", "))
+	}
+	return true
+}
+
+// supportsVision reports whether the client's model accepts images
+func supportsVision(client llm.Client) bool {
+	v, ok := client.(interface{ SupportsVision() bool })
+	return ok && v.SupportsVision()
+}
+
+// writeRunSummary appends the outcome of a non-interactive run to the
+// session transcript, with the cost when the model has pricing configured
+func writeRunSummary(sessionID string, client llm.Client, result *agent.ExecutionResult, runErr error, duration time.Duration) {
+	summary := agent.NewRunSummary(result, runErr, duration)
+	if priced, ok := client.(interface{ Cost(int, int) float64 }); ok {
+		summary.CostUSD = priced.Cost(summary.PromptTokens, summary.CompletionTokens)
+	}
+	if err := agent.WriteRunSummary(hooks.TranscriptPath(sessionID), summary); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write run summary to transcript: %v\n", err)
+	}
+}
+
+// rollbackLastTurn removes the most recent user prompt and everything the
+// agent added after it (assistant replies and tool results), so the
+// conversation stays well-formed. Hook context sent just before the prompt is
+// removed too, but never the system and developer prompts that open the
+// conversation. It returns the shortened conversation and the removed prompt's
+// text and attached images.
+func rollbackLastTurn(conversation []openai.ChatCompletionMessage) ([]openai.ChatCompletionMessage, string, []openai.ChatMessagePart, bool) {
+	last := -1
+	for i := len(conversation) - 1; i >= 0; i-- {
+		if conversation[i].Role == "user" {
+			last = i
+			break
+		}
+	}
+	if last < 0 {
+		return conversation, "", nil, false
+	}
+
+	opening := 0
+	for opening < len(conversation) && (conversation[opening].Role == "system" || conversation[opening].Role == "developer") {
+		opening++
+	}
+	cut := last
+	for cut > opening && conversation[cut-1].Role == "system" {
+		cut--
+	}
+	text, images := splitUserMessage(conversation[last])
+	return conversation[:cut], text, images, true
+}
+
+// splitUserMessage returns the text of a user message and its image parts
+func splitUserMessage(msg openai.ChatCompletionMessage) (string, []openai.ChatMessagePart) {
+	if len(msg.MultiContent) == 0 {
+		return msg.Content, nil
+	}
+	var texts []string
+	var images []openai.ChatMessagePart
+	for _, part := range msg.MultiContent {
+		switch part.Type {
+		case openai.ChatMessagePartTypeText:
+			texts = append(texts, part.Text)
+		case openai.ChatMessagePartTypeImageURL:
+			images = append(images, part)
+		}
+	}
+	return strings.Join(texts, "\n\n"), images
+}
+
+// attachImageParts adds images already encoded for an earlier prompt to a
+// user message
+func attachImageParts(msg openai.ChatCompletionMessage, images []openai.ChatMessagePart) openai.ChatCompletionMessage {
+	if len(images) == 0 {
+		return msg
+	}
+	if len(msg.MultiContent) == 0 {
+		msg.MultiContent = []openai.ChatMessagePart{{Type: openai.ChatMessagePartTypeText, Text: msg.Content}}
+		msg.Content = ""
+	}
+	msg.MultiContent = append(msg.MultiContent, images...)
+	return msg
+}
+
+// handlePinCommand handles /pin and /unpin, reporting whether input was one of them
+func handlePinCommand(input string) bool {
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return false
+	}
+
+	switch fields[0] {
+	case "/pin":
+		if len(fields) == 1 {
+			pinned := tools.GlobalPinnedFiles.List()
+			if len(pinned) == 0 {
+				fmt.Println("No files pinned. Usage: /pin <file>")
+			}
+			for _, path := range pinned {
+				fmt.Printf("📌 %s\n", path)
+			}
+			return true
+		}
+		for _, path := range fields[1:] {
+			pinned, err := tools.GlobalPinnedFiles.Pin(path)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				continue
+			}
+			fmt.Printf("📌 Pinned %s\n", pinned)
+		}
+		return true
+	case "/unpin":
+		if len(fields) == 1 {
+			tools.GlobalPinnedFiles.Clear()
+			fmt.Println("Unpinned all files.")
+			return true
+		}
+		for _, path := range fields[1:] {
+			if tools.GlobalPinnedFiles.Unpin(path) {
+				fmt.Printf("Unpinned %s\n", path)
+			} else {
+				fmt.Printf("%s is not pinned\n", path)
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// handleTrashCommand handles /trash and /restore, reporting whether input was one of them
+func handleTrashCommand(input string) bool {
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return false
+	}
+
+	switch fields[0] {
+	case "/trash":
+		entries := tools.GlobalTrash.List()
+		if len(entries) == 0 {
+			fmt.Println("Nothing was deleted in this session.")
+			return true
+		}
+		fmt.Println("Deleted in this session (/restore <path> to undo):")
+		for _, entry := range entries {
+			fmt.Printf("  %s  %s\n", entry.DeletedAt.Format("15:04:05"), entry.Path)
+		}
+		return true
+	case "/restore":
+		if len(fields) == 1 {
+			fmt.Println("Usage: /restore <path>")
+			return true
+		}
+		for _, path := range fields[1:] {
+			if _, err := tools.GlobalTrash.Restore(path); err != nil {
+				fmt.Printf("❌ %v\n", err)
+			} else {
+				fmt.Printf("♻️  Restored %s\n", path)
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// newSummarizeClient builds a client for models.summarize, or returns nil
+// when no summarize model is configured or it cannot be created
+func newSummarizeClient() llm.Client {
+	if !viper.IsSet("models.summarize") {
+		return nil
+	}
+	summarizeConfig := &llm.ProvidersConfig{
+		Providers: make(map[string]llm.ProviderConfig),
+		Models:    make(map[string]llm.ModelSelection),
+	}
+	if err := viper.UnmarshalKey("providers", &summarizeConfig.Providers); err != nil {
+		return nil
+	}
+	if err := viper.UnmarshalKey("models", &summarizeConfig.Models); err != nil {
+		return nil
+	}
+	client, err := llm.NewClient(llm.Config{
+		ProvidersConfig: summarizeConfig,
+		ModelSelection:  "summarize",
+	})
+	if err != nil {
+		return nil
+	}
+	return client
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/agent"
+	"github.com/trknhr/agenticode/internal/llm"
+)
+
+func TestReadOnlyRemovesWriteTools(t *testing.T) {
+	client := llm.NewOpenAIClient("test-key", "gpt-4o")
+	names := func(allowed string, readOnly bool) map[string]bool {
+		a := agent.NewAgent(client, agent.WithToolFilter(toolFilter(allowed, nil, readOnly)))
+		set := make(map[string]bool)
+		for _, name := range a.ToolNames() {
+			set[name] = true
+		}
+		return set
+	}
+
+	readOnlyTools := names("", true)
+	for _, name := range []string{"write_file", "edit", "run_shell", "make_directory", "git_commit", "agent_tool"} {
+		if readOnlyTools[name] {
+			t.Errorf("expected %s to be absent with --read-only", name)
+		}
+	}
+	for _, name := range []string{"read_file", "grep", "glob", "list_files"} {
+		if !readOnlyTools[name] {
+			t.Errorf("expected %s to be available with --read-only", name)
+		}
+	}
+
+	// --allowedTools narrows the read-only set further and never re-adds write tools
+	combined := names("grep, write_file", true)
+	if len(combined) != 1 || !combined["grep"] {
+		t.Errorf("expected only grep, got %v", combined)
+	}
+
+	if toolFilter("", nil, false) != nil {
+		t.Error("expected no filter without --allowedTools or --read-only")
+	}
+}
+
+func TestDeniedToolsAreRemoved(t *testing.T) {
+	client := llm.NewOpenAIClient("test-key", "gpt-4o")
+	names := func(allowed string, denied []string) map[string]bool {
+		a := agent.NewAgent(client, agent.WithToolFilter(toolFilter(allowed, denied, false)))
+		set := make(map[string]bool)
+		for _, name := range a.ToolNames() {
+			set[name] = true
+		}
+		return set
+	}
+
+	// disabled_tools entries and the --deny-tools list both apply
+	available := names("", []string{"run_shell", "delete_file,git_commit"})
+	for _, name := range []string{"run_shell", "delete_file", "git_commit"} {
+		if available[name] {
+			t.Errorf("expected %s to be absent from the agent's tools", name)
+		}
+	}
+	for _, name := range []string{"read_file", "write_file", "edit", "agent_tool"} {
+		if !available[name] {
+			t.Errorf("expected %s to stay available", name)
+		}
+	}
+
+	// Deny wins over the allowlist
+	combined := names("run_shell, grep", []string{"run_shell"})
+	if len(combined) != 1 || !combined["grep"] {
+		t.Errorf("expected only grep, got %v", combined)
+	}
+}
+
+func TestRollbackLastTurn(t *testing.T) {
+	conversation := []openai.ChatCompletionMessage{
+		{Role: "system", Content: "system prompt"},
+		{Role: "developer", Content: "developer prompt"},
+		{Role: "user", Content: "list the files"},
+		{Role: "assistant", Content: "here they are"},
+		{Role: "system", Content: "hook context"},
+		{Role: "user", Content: "fix teh bug"},
+		{Role: "assistant", ToolCalls: []openai.ToolCall{{ID: "call-1", Type: "function", Function: openai.FunctionCall{Name: "read_file"}}}},
+		{Role: "tool", ToolCallID: "call-1", Content: "file contents"},
+		{Role: "assistant", Content: "fixed"},
+	}
+
+	rolledBack, prompt, _, ok := rollbackLastTurn(conversation)
+	if !ok || prompt != "fix teh bug" {
+		t.Fatalf("expected the last prompt to be removed, got %q %v", prompt, ok)
+	}
+	if len(rolledBack) != 4 || rolledBack[3].Content != "here they are" {
+		t.Fatalf("expected the conversation to end with the previous reply, got %+v", rolledBack)
+	}
+
+	// Rolling back the first turn keeps the opening prompts
+	rolledBack, prompt, _, ok = rollbackLastTurn(rolledBack)
+	if !ok || prompt != "list the files" || len(rolledBack) != 2 || rolledBack[1].Role != "developer" {
+		t.Fatalf("unexpected rollback of the first turn: %q %+v", prompt, rolledBack)
+	}
+
+	if _, _, _, ok := rollbackLastTurn(rolledBack); ok {
+		t.Error("expected nothing to roll back without a user prompt")
+	}
+}
+
+func TestRollbackLastTurnKeepsImages(t *testing.T) {
+	image := openai.ChatMessagePart{Type: openai.ChatMessagePartTypeImageURL, ImageURL: &openai.ChatMessageImageURL{URL: "data:image/png;base64,AAAA"}}
+	conversation := []openai.ChatCompletionMessage{
+		{Role: "system", Content: "system prompt"},
+		{Role: "user", MultiContent: []openai.ChatMessagePart{{Type: openai.ChatMessagePartTypeText, Text: "what is wrong here?"}, image}},
+		{Role: "assistant", Content: "the button is misaligned"},
+	}
+
+	_, prompt, images, ok := rollbackLastTurn(conversation)
+	if !ok || prompt != "what is wrong here?" || len(images) != 1 {
+		t.Fatalf("expected the prompt text and its image, got %q %v %v", prompt, images, ok)
+	}
+
+	rerun := attachImageParts(openai.ChatCompletionMessage{Role: "user", Content: "what is misaligned?"}, images)
+	if rerun.Content != "" || len(rerun.MultiContent) != 2 || rerun.MultiContent[0].Text != "what is misaligned?" || rerun.MultiContent[1].ImageURL.URL != image.ImageURL.URL {
+		t.Errorf("expected the revised prompt with the original image, got %+v", rerun)
+	}
+}
+
+func TestToolToggleNeedsSlashPrefix(t *testing.T) {
+	a := agent.NewAgent(llm.NewOpenAIClient("test-key", "gpt-4o"))
+
+	if handleToolToggleCommand(a, "tools disable in this repo are confusing, explain them") {
+		t.Error("expected a prompt starting with 'tools disable' to reach the model")
+	}
+	if len(a.DisabledTools()) != 0 {
+		t.Errorf("expected no tools disabled by the prompt, got %v", a.DisabledTools())
+	}
+	if !handleToolToggleCommand(a, "/tools disable run_shell") {
+		t.Fatal("expected /tools disable to be handled")
+	}
+	if disabled := a.DisabledTools(); len(disabled) != 1 || disabled[0] != "run_shell" {
+		t.Errorf("expected run_shell to be disabled, got %v", disabled)
+	}
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/trknhr/agenticode/internal/agent"
+	"github.com/trknhr/agenticode/internal/mcp"
+	"github.com/trknhr/agenticode/internal/tools"
+)
+
+var toolsCmd = &cobra.Command{
+	Use:   "tools [name...]",
+	Short: "List the available tools and their parameters",
+	Long: `List every tool the agent can call, including tools from configured MCP
+servers, with its description, whether it is read-only and its parameter
+schema. Pass tool names to describe only those tools. The names are the ones
+--allowedTools accepts.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		mcpManager, mcpTools := mcp.LoadMCPTools(ctx, agent.NewInteractiveApprover(), viper.GetViper())
+		if mcpManager != nil {
+			defer mcpManager.CloseAll()
+		}
+
+		// The agent registers the built-in tools; no LLM calls are made
+		available := agent.NewAgent(nil, agent.WithTools(mcpTools)).Tools()
+		selected, err := selectTools(available, args)
+		if err != nil {
+			return err
+		}
+		writeToolList(cmd.OutOrStdout(), selected)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(toolsCmd)
+}
+
+// selectTools keeps the named tools, in the order given, or all of them when
+// no names are given
+func selectTools(available []tools.Tool, names []string) ([]tools.Tool, error) {
+	if len(names) == 0 {
+		return available, nil
+	}
+	byName := make(map[string]tools.Tool, len(available))
+	for _, tool := range available {
+		byName[tool.Name()] = tool
+	}
+	selected := make([]tools.Tool, 0, len(names))
+	for _, name := range names {
+		tool, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown tool: %s", name)
+		}
+		selected = append(selected, tool)
+	}
+	return selected, nil
+}
+
+// writeToolList prints each tool's name, access, description and parameters
+func writeToolList(w io.Writer, list []tools.Tool) {
+	for i, tool := range list {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		access := "read-write"
+		if tool.ReadOnly() {
+			access = "read-only"
+		}
+		fmt.Fprintf(w, "%s (%s)\n", tool.Name(), access)
+		fmt.Fprintf(w, "  %s\n", tool.Description())
+
+		params, err := json.MarshalIndent(tool.GetParameters(), "    ", "  ")
+		if err != nil {
+			fmt.Fprintf(w, "  Parameters: unavailable (%v)\n", err)
+			continue
+		}
+		fmt.Fprintf(w, "  Parameters:\n    %s\n", strings.TrimSpace(string(params)))
+	}
+}
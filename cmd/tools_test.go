@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/trknhr/agenticode/internal/tools"
+)
+
+func TestToolsCommandListsDefaultTools(t *testing.T) {
+	var out bytes.Buffer
+	toolsCmd.SetOut(&out)
+	defer toolsCmd.SetOut(nil)
+
+	if err := toolsCmd.RunE(toolsCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	listing := out.String()
+	for _, tool := range tools.GetDefaultTools() {
+		if !strings.Contains(listing, "\n"+tool.Name()+" (") && !strings.HasPrefix(listing, tool.Name()+" (") {
+			t.Errorf("expected %s in the listing", tool.Name())
+		}
+	}
+	if !strings.Contains(listing, "read_file (read-only)") || !strings.Contains(listing, "write_file (read-write)") {
+		t.Errorf("expected read-only status in the listing:\n%s", listing)
+	}
+	if !strings.Contains(listing, `"required": [`) {
+		t.Errorf("expected parameter schemas in the listing:\n%s", listing)
+	}
+
+	if err := toolsCmd.RunE(toolsCmd, []string{"no_such_tool"}); err == nil {
+		t.Error("expected an unknown tool name to fail")
+	}
+}
@@ -2,31 +2,127 @@ package agent
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/sashabaranov/go-openai"
 	"github.com/trknhr/agenticode/internal/hooks"
 	"github.com/trknhr/agenticode/internal/llm"
+	"github.com/trknhr/agenticode/internal/locale"
+	"github.com/trknhr/agenticode/internal/telemetry"
 	"github.com/trknhr/agenticode/internal/tools"
 )
 
 type Agent struct {
-	llmClient   llm.Client
-	tools       map[string]tools.Tool
-	maxSteps    int
-	approver    ToolApprover
-	debugger    Debugger
-	hookManager *hooks.Manager
+	llmClient      llm.Client
+	tools          map[string]tools.Tool
+	maxSteps       int
+	approver       ToolApprover
+	debugger       Debugger
+	hookManager    *hooks.Manager
+	streaming      bool
+	streamFallback bool
+	maxTotalTokens int // 0 means no token budget
+
+	// maxContextTokens triggers automatic compaction once the estimated
+	// conversation size exceeds it; subAgentContextTokens overrides it for
+	// sub-agents spawned by this agent (0 means inherit)
+	maxContextTokens      int
+	subAgentContextTokens int
+	contextStrategy       ContextStrategy // How to shrink the conversation; "" means summarize
+
+	telemetry telemetry.Sink // Optional metrics sink (nil disables telemetry)
+
+	// Repetition detection: the same tool call seen repeatThreshold times
+	// within the last repeatWindow steps triggers corrective guidance
+	repeatThreshold int
+	repeatWindow    int
+
+	// readTracker is shared across runs so reads earlier in the session count;
+	// it is only set when the read-before-edit policy is enabled
+	readTracker *readTracker
+
+	// readBudget bounds how much file content accumulates between
+	// compactions; nil disables it
+	readBudget *readBudget
+
+	// turnOutputBytes caps the tool results a single turn adds to the
+	// conversation; 0 means no cap
+	turnOutputBytes int
+
+	// tracer writes a structured record of every event; nil disables it
+	tracer *Tracer
+
+	decisionLog *DecisionLog // Optional audit trail of approval decisions
+
+	toolOutputs *ToolOutputs // Tool displays and diffs kept for transcripts
+
+	userPrompter UserPrompter // Answers ask_user; nil in non-interactive runs
+
+	// isSubAgent marks agents created by the agent tool; their stop hook
+	// (SubagentStop) is fired by the factory once the result is known
+	isSubAgent bool
+
+	// depth is how many agent_tool launches separate this agent from the top
+	// level agent; agents at maxSubAgentDepth cannot launch further sub-agents
+	depth            int
+	maxSubAgentDepth int
+
+	// disabledTools holds tools switched off mid-session so they can be
+	// enabled again; they are neither advertised nor executable
+	disabledTools map[string]tools.Tool
+
+	showReasoning bool // Print reasoning-model output, dimmed
+	quiet         bool // Print neither model text nor tool displays
+
+	progress ProgressDisplay // Shows tool progress; nil hides it
+
+	// stagingDir receives file changes when ExecuteWithHistory runs as a dry
+	// run; without it a dry run behaves like a normal run
+	stagingDir string
+
+	// dryRun is set while ExecuteWithHistory runs as a dry run, so the
+	// sub-agents it launches run as dry runs too
+	dryRun bool
+
+	// toolFilter, when set, decides which tools the agent keeps at all
+	toolFilter func(tools.Tool) bool
+
+	// toolErrorPolicy decides whether unrecovered tool failures at the end
+	// of a run are flagged or fail it
+	toolErrorPolicy ToolErrorPolicy
 }
 
+// ErrEmptyResponse is the failure reported in ExecutionResult.Message when the
+// model replies with neither content nor tool calls, even after being asked to
+// continue. It is not returned as an error, so callers keep the conversation.
+var ErrEmptyResponse = errors.New("model returned an empty response")
+
+// emptyResponseNudge asks the model to continue after an empty reply
+const emptyResponseNudge = "Your last response was empty. Continue the task: call a tool, or reply with your answer if you are done."
+
+// Default repetition detection settings: the same call twice in three steps
+const (
+	DefaultRepeatThreshold = 2
+	DefaultRepeatWindow    = 3
+)
+
 // NewAgentV2 creates a new event-driven agent
 func NewAgent(llmClient llm.Client, opts ...Option) *Agent {
 	a := &Agent{
-		llmClient: llmClient,
-		tools:     make(map[string]tools.Tool),
-		maxSteps:  10,
+		llmClient:       llmClient,
+		tools:           make(map[string]tools.Tool),
+		maxSteps:        10,
+		streamFallback:  true,
+		repeatThreshold: DefaultRepeatThreshold,
+		repeatWindow:    DefaultRepeatWindow,
+
+		maxSubAgentDepth: tools.DefaultMaxSubAgentDepth,
+		toolErrorPolicy:  ToolErrorsFlag,
 	}
 
 	for _, opt := range opts {
@@ -37,14 +133,51 @@ func NewAgent(llmClient llm.Client, opts ...Option) *Agent {
 	llmAdapter := NewLLMAdapter(llmClient)
 	defaultTools := tools.GetDefaultToolsWithLLM(llmAdapter)
 	for _, tool := range defaultTools {
-		a.tools[tool.Name()] = tool
+		// Tools passed with WithTools keep their configuration
+		if _, exists := a.tools[tool.Name()]; !exists {
+			a.tools[tool.Name()] = tool
+		}
 	}
 
 	// Add the agent tool using the factory adapter
 	agentFactory := NewAgentFactoryAdapter()
+	agentFactory.maxContextTokens = a.maxContextTokens
+	agentFactory.contextStrategy = a.contextStrategy
+	agentFactory.hookManager = a.hookManager
+	agentFactory.depth = a.depth
+	agentFactory.maxDepth = a.maxSubAgentDepth
+	agentFactory.toolFilter = a.toolFilter
+	agentFactory.quiet = a.quiet
+	agentFactory.turnOutputBytes = a.turnOutputBytes
+	agentFactory.tracer = a.tracer
+	agentFactory.stagingDir = a.stagingDir
+	agentFactory.dryRun = func() bool { return a.dryRun }
+	agentFactory.disabled = func(name string) bool {
+		_, disabled := a.disabledTools[name]
+		return disabled
+	}
+	agentFactory.readTracker = a.readTracker
+	agentFactory.telemetry = a.telemetry
+	agentFactory.decisionLog = a.decisionLog
+	agentFactory.toolErrorPolicy = a.toolErrorPolicy
+	if a.readBudget != nil {
+		agentFactory.readBudgetBytes = a.readBudget.limit
+	}
+	if a.subAgentContextTokens > 0 {
+		agentFactory.maxContextTokens = a.subAgentContextTokens
+	}
 	agentTool := agentFactory.CreateAgentTool(llmClient)
 	a.tools[agentTool.Name()] = agentTool
 
+	// Drop tools the caller excluded (e.g. --allowedTools or --read-only)
+	if a.toolFilter != nil {
+		for name, tool := range a.tools {
+			if !a.toolFilter(tool) {
+				delete(a.tools, name)
+			}
+		}
+	}
+
 	// Set default approver if not provided
 	if a.approver == nil {
 		a.approver = NewInteractiveApprover()
@@ -83,6 +216,57 @@ func WithTools(tools []tools.Tool) Option {
 	}
 }
 
+// WithToolFilter restricts the agent to tools for which keep returns true.
+// It applies to the built-in tools as well as those passed with WithTools.
+func WithToolFilter(keep func(tools.Tool) bool) Option {
+	return func(a *Agent) {
+		a.toolFilter = keep
+	}
+}
+
+// WithDeterministic asks the LLM client for reproducible sampling
+// (temperature 0 and a fixed seed) when it supports that. Eval runs enable
+// it so a test's result does not depend on sampling noise.
+func WithDeterministic(enabled bool) Option {
+	return func(a *Agent) {
+		if client, ok := a.llmClient.(interface{ SetDeterministic(bool) }); ok {
+			client.SetDeterministic(enabled)
+		}
+	}
+}
+
+// WithQuiet stops the agent printing the model's text and tool displays
+// while it works; the caller prints the final result. Sub-agents are quiet too.
+func WithQuiet(quiet bool) Option {
+	return func(a *Agent) {
+		a.quiet = quiet
+	}
+}
+
+// WithShowReasoning prints the reasoning returned by reasoning models before
+// each answer. Reasoning is captured in ExecutionResult either way.
+func WithShowReasoning(show bool) Option {
+	return func(a *Agent) {
+		a.showReasoning = show
+	}
+}
+
+// WithProgressDisplay shows the progress of long-running tools, such as grep
+// over a large tree, while they run
+func WithProgressDisplay(display ProgressDisplay) Option {
+	return func(a *Agent) {
+		a.progress = display
+	}
+}
+
+// WithStagingDir writes file changes made during dry runs to dir, mirroring
+// the project layout, instead of to the real paths
+func WithStagingDir(dir string) Option {
+	return func(a *Agent) {
+		a.stagingDir = dir
+	}
+}
+
 // WithApprover sets the tool approver
 func WithApprover(approver ToolApprover) Option {
 	return func(a *Agent) {
@@ -103,11 +287,160 @@ func WithHookManager(manager *hooks.Manager) Option {
 	}
 }
 
+// WithStreaming enables the streaming API for LLM calls
+func WithStreaming(enabled bool) Option {
+	return func(a *Agent) {
+		a.streaming = enabled
+	}
+}
+
+// WithStreamFallback controls whether a failed stream is retried as a
+// regular non-streaming request (enabled by default)
+func WithStreamFallback(enabled bool) Option {
+	return func(a *Agent) {
+		a.streamFallback = enabled
+	}
+}
+
+// WithMaxTotalTokens stops execution once the cumulative token usage of a
+// run reaches the given budget (0 disables the check)
+func WithMaxTotalTokens(tokens int) Option {
+	return func(a *Agent) {
+		a.maxTotalTokens = tokens
+	}
+}
+
+// WithAutoCompact summarizes the conversation automatically once its
+// estimated size exceeds maxTokens (0 disables auto-compaction)
+func WithAutoCompact(maxTokens int) Option {
+	return func(a *Agent) {
+		a.maxContextTokens = maxTokens
+	}
+}
+
+// WithContextStrategy sets how the conversation is shrunk once it exceeds
+// the WithAutoCompact threshold
+func WithContextStrategy(strategy ContextStrategy) Option {
+	return func(a *Agent) {
+		a.contextStrategy = strategy
+	}
+}
+
+// WithSubAgentAutoCompact sets the auto-compaction threshold for sub-agents.
+// Without it, sub-agents inherit the threshold from WithAutoCompact.
+func WithSubAgentAutoCompact(maxTokens int) Option {
+	return func(a *Agent) {
+		a.subAgentContextTokens = maxTokens
+	}
+}
+
+// WithMaxSubAgentDepth limits how deeply sub-agents may launch their own
+// sub-agents; 1 allows sub-agents but no nesting, 0 disables agent_tool
+func WithMaxSubAgentDepth(depth int) Option {
+	return func(a *Agent) {
+		a.maxSubAgentDepth = depth
+	}
+}
+
+// WithTelemetry records per-run metrics to the given sink
+func WithTelemetry(sink telemetry.Sink) Option {
+	return func(a *Agent) {
+		a.telemetry = sink
+	}
+}
+
+// WithRepetitionDetection configures how many identical tool calls within
+// the last window steps count as a loop. A threshold of 0 disables detection.
+func WithRepetitionDetection(threshold, window int) Option {
+	return func(a *Agent) {
+		a.repeatThreshold = threshold
+		a.repeatWindow = window
+	}
+}
+
+// WithToolErrorPolicy sets how tool calls that failed without the model
+// recovering affect the result of a run
+func WithToolErrorPolicy(policy ToolErrorPolicy) Option {
+	return func(a *Agent) {
+		a.toolErrorPolicy = policy
+	}
+}
+
+// WithTurnOutputBudget truncates the tool results of a turn once together
+// they exceed maxBytes, telling the model to be more targeted (0 disables it)
+func WithTurnOutputBudget(maxBytes int) Option {
+	return func(a *Agent) {
+		a.turnOutputBytes = maxBytes
+	}
+}
+
+// WithTracer writes a JSON record of each LLM call, tool call and result,
+// approval decision and usage report to tracer
+func WithTracer(tracer *Tracer) Option {
+	return func(a *Agent) {
+		a.tracer = tracer
+	}
+}
+
+// WithReadBudget compacts the conversation once more than maxBytes of file
+// contents have been read into it, and asks the model to stop re-reading
+// files (0 disables the budget)
+func WithReadBudget(maxBytes int) Option {
+	return func(a *Agent) {
+		if maxBytes > 0 {
+			a.readBudget = newReadBudget(maxBytes)
+		} else {
+			a.readBudget = nil
+		}
+	}
+}
+
+// WithReadBeforeEdit requires a file to be read earlier in the session before
+// edit or multi_edit may modify it
+func WithReadBeforeEdit(enabled bool) Option {
+	return func(a *Agent) {
+		if enabled {
+			a.readTracker = newReadTracker()
+		} else {
+			a.readTracker = nil
+		}
+	}
+}
+
+// WithDecisionLog records why each tool call was approved or refused
+func WithDecisionLog(log *DecisionLog) Option {
+	return func(a *Agent) {
+		a.decisionLog = log
+	}
+}
+
+// WithToolOutputs keeps what each tool call shows the user, for exporting
+// the session with RenderTranscript
+func WithToolOutputs(outputs *ToolOutputs) Option {
+	return func(a *Agent) {
+		a.toolOutputs = outputs
+	}
+}
+
+// WithUserPrompter lets the agent ask the user clarifying questions through
+// the ask_user tool. Without it ask_user falls back to its default answer.
+func WithUserPrompter(prompter UserPrompter) Option {
+	return func(a *Agent) {
+		a.userPrompter = prompter
+	}
+}
+
 type ExecutionResult struct {
-	Success        bool
-	Message        string
-	GeneratedFiles []GeneratedFile
-	Steps          []ExecutionStep
+	Success          bool
+	Message          string
+	GeneratedFiles   []GeneratedFile
+	Steps            []ExecutionStep
+	TokensUsed       int
+	PromptTokens     int
+	CompletionTokens int
+	FilesChanged     []string // Paths modified by successful tool calls
+	HadErrors        bool     // The last tools executed failed and the model did not recover
+	Reasoning        []string // Reasoning the model returned, one entry per turn that had any
 }
 
 type GeneratedFile struct {
@@ -125,6 +458,67 @@ type ExecutionStep struct {
 	Error      error
 }
 
+// DisableTool stops advertising and running a tool until EnableTool is
+// called. It takes effect from the next LLM request.
+func (a *Agent) DisableTool(name string) error {
+	tool, ok := a.tools[name]
+	if !ok {
+		if _, disabled := a.disabledTools[name]; disabled {
+			return fmt.Errorf("tool %s is already disabled", name)
+		}
+		return fmt.Errorf("unknown tool: %s", name)
+	}
+	if a.disabledTools == nil {
+		a.disabledTools = make(map[string]tools.Tool)
+	}
+	a.disabledTools[name] = tool
+	delete(a.tools, name)
+	return nil
+}
+
+// EnableTool restores a tool removed with DisableTool
+func (a *Agent) EnableTool(name string) error {
+	tool, ok := a.disabledTools[name]
+	if !ok {
+		if _, enabled := a.tools[name]; enabled {
+			return fmt.Errorf("tool %s is already enabled", name)
+		}
+		return fmt.Errorf("unknown tool: %s", name)
+	}
+	a.tools[name] = tool
+	delete(a.disabledTools, name)
+	return nil
+}
+
+// Tools returns the tools currently available to the agent, sorted by name
+func (a *Agent) Tools() []tools.Tool {
+	list := make([]tools.Tool, 0, len(a.tools))
+	for _, name := range a.ToolNames() {
+		list = append(list, a.tools[name])
+	}
+	return list
+}
+
+// ToolNames returns the names of the tools currently available to the agent
+func (a *Agent) ToolNames() []string {
+	names := make([]string, 0, len(a.tools))
+	for name := range a.tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DisabledTools returns the names of tools switched off with DisableTool
+func (a *Agent) DisabledTools() []string {
+	names := make([]string, 0, len(a.disabledTools))
+	for name := range a.disabledTools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (a *Agent) ExecuteWithHistory(ctx context.Context, conversation []openai.ChatCompletionMessage, dryrun bool) (*ExecutionResult, []openai.ChatCompletionMessage, error) {
 	result := &ExecutionResult{
 		Success:        false,
@@ -155,22 +549,79 @@ func (a *Agent) ExecuteWithHistory(ctx context.Context, conversation []openai.Ch
 	if a.hookManager != nil {
 		handler.SetHookManager(a.hookManager)
 	}
+	if a.readTracker != nil {
+		handler.SetReadTracker(a.readTracker)
+	}
+	if a.decisionLog != nil {
+		handler.SetDecisionLog(a.decisionLog)
+	}
+	if a.toolOutputs != nil {
+		handler.SetToolOutputs(a.toolOutputs)
+	}
+	if a.userPrompter != nil {
+		handler.SetUserPrompter(a.userPrompter)
+	}
+	handler.SetShowReasoning(a.showReasoning)
+	handler.SetQuiet(a.quiet)
+	handler.SetTurnOutputBudget(a.turnOutputBytes)
+	handler.SetTracer(a.tracer)
+	if a.progress != nil {
+		handler.SetProgressDisplay(a.progress)
+	}
+	if dryrun && a.stagingDir != "" {
+		staging, err := newStagingArea(a.stagingDir)
+		if err != nil {
+			return result, conversation, err
+		}
+		handler.SetStagingArea(staging)
+	}
+	a.dryRun = dryrun
+	defer func() { a.dryRun = false }()
+
+	if a.telemetry != nil {
+		defer a.recordRun(time.Now(), result, handler)
+	}
+
+	budgetExhausted := false
+	emptyRetried := false
+	stalled := false
 
 	// Main execution loop
 	for i := 0; i < a.maxSteps; i++ {
 		log.Printf("%sStarting turn %d/%d", logPrefix, i+1, a.maxSteps)
 
+		// Trim old tool results before the conversation outgrows the context
+		// window, and compact it if that is not enough
+		if a.contextStrategy == ContextTrim && a.needsCompaction(conversation) {
+			trimmed, count := trimConversation(conversation, a.maxContextTokens)
+			log.Printf("%sConversation exceeds %d tokens, trimmed %d old tool results", logPrefix, a.maxContextTokens, count)
+			conversation = trimmed
+		}
+		if a.needsCompaction(conversation) {
+			log.Printf("%sConversation exceeds %d tokens, compacting", logPrefix, a.maxContextTokens)
+			compacted, err := a.compactConversation(ctx, conversation)
+			if err != nil {
+				log.Printf("%s%v", logPrefix, err)
+			} else {
+				conversation = compacted
+			}
+		}
+
 		// detect repetitive
 		if a.detectRepetitiveActions(result.Steps) {
 			log.Printf("%sDetected repetitive actions, adding guidance", logPrefix)
 			conversation = append(conversation, openai.ChatCompletionMessage{
 				Role:    "system",
-				Content: "You seem to be repeating the same actions. Please review the previous results and try a different approach.",
+				Content: locale.T(locale.RepetitionGuidance),
 			})
 		}
 
 		// Create a new turn
 		turn := NewTurn(a.llmClient, a.tools, conversation, a.debugger)
+		turn.SetStreaming(a.streaming, a.streamFallback)
+		if pinned := tools.GlobalPinnedFiles.Render(); pinned != "" {
+			turn.SetContextMessages([]openai.ChatCompletionMessage{{Role: "system", Content: pinned}})
+		}
 
 		// Handle the turn
 		if err := handler.HandleTurn(ctx, turn); err != nil {
@@ -181,6 +632,12 @@ func (a *Agent) ExecuteWithHistory(ctx context.Context, conversation []openai.Ch
 
 		// Update conversation from turn (includes assistant response)
 		conversation = turn.GetConversation()
+		usage := handler.TotalUsage()
+		result.TokensUsed = usage.TotalTokens
+		result.PromptTokens = usage.PromptTokens
+		result.CompletionTokens = usage.CompletionTokens
+		result.FilesChanged = handler.ChangedFiles()
+		result.Reasoning = handler.Reasoning()
 
 		// Log assistant message with tool calls
 		if len(conversation) > 0 {
@@ -203,6 +660,25 @@ func (a *Agent) ExecuteWithHistory(ctx context.Context, conversation []openai.Ch
 
 		// Check if there were any pending calls
 		pendingCalls := turn.GetPendingCalls()
+		if len(pendingCalls) == 0 && isEmptyResponse(conversation) {
+			// Neither content nor tool calls: the model stalled rather than finished
+			conversation = conversation[:len(conversation)-1]
+			if !emptyRetried {
+				log.Printf("%sModel returned an empty response, retrying", logPrefix)
+				emptyRetried = true
+				conversation = append(conversation, openai.ChatCompletionMessage{
+					Role:    "system",
+					Content: emptyResponseNudge,
+				})
+				continue
+			}
+			log.Printf("%sModel returned an empty response again, giving up", logPrefix)
+			result.Success = false
+			result.Message = ErrEmptyResponse.Error()
+			stalled = true
+			break
+		}
+		emptyRetried = false // Each stall gets its own retry
 		if len(pendingCalls) == 0 {
 			// No tool calls means the agent is done
 			log.Printf("%sNo tool calls in this turn, task completed", logPrefix)
@@ -214,6 +690,13 @@ func (a *Agent) ExecuteWithHistory(ctx context.Context, conversation []openai.Ch
 					result.Message = lastMsg.Content
 				}
 			}
+			if failed := handler.UnresolvedToolErrors(); len(failed) > 0 && a.toolErrorPolicy != ToolErrorsIgnore {
+				log.Printf("%sRun ended after failed tool calls: %s", logPrefix, strings.Join(failed, ", "))
+				result.HadErrors = true
+				if a.toolErrorPolicy == ToolErrorsFail {
+					result.Success = false
+				}
+			}
 			break
 		}
 
@@ -242,16 +725,51 @@ func (a *Agent) ExecuteWithHistory(ctx context.Context, conversation []openai.Ch
 				}
 			}
 		}
+
+		// Too much file content has piled up: compact it away and steer the
+		// model towards summaries and notes
+		if a.readBudget != nil && a.readBudget.record(toolResponses) {
+			log.Printf("%sRead budget of %d bytes exceeded, compacting", logPrefix, a.readBudget.limit)
+			compacted, err := a.compactConversation(ctx, conversation)
+			if err != nil {
+				log.Printf("%s%v", logPrefix, err)
+				a.readBudget.reset() // Do not retry compaction on every turn
+			} else {
+				conversation = compacted
+			}
+			conversation = append(conversation, openai.ChatCompletionMessage{
+				Role:    "system",
+				Content: a.readBudget.guidance(err == nil),
+			})
+		}
+
+		// Stop before the next turn if the token budget is spent
+		if a.maxTotalTokens > 0 && result.TokensUsed >= a.maxTotalTokens {
+			log.Printf("%sToken budget exhausted: %d/%d tokens used", logPrefix, result.TokensUsed, a.maxTotalTokens)
+			budgetExhausted = true
+			result.Success = false
+			result.Message = fmt.Sprintf("Token budget exhausted: used %d of %d tokens", result.TokensUsed, a.maxTotalTokens)
+			break
+		}
 	}
 
-	if len(result.Steps) >= a.maxSteps {
+	if !budgetExhausted && !stalled && len(result.Steps) >= a.maxSteps {
 		log.Printf("%sWARNING: Maximum steps (%d) reached without completion", logPrefix, a.maxSteps)
 		result.Success = false
 		result.Message = "Maximum steps reached"
 	}
 
+	// Tell the user the task is done; sub-agents report to their parent instead
+	if a.hookManager != nil && !a.isSubAgent {
+		message := "Task completed"
+		if !result.Success {
+			message = fmt.Sprintf("Task stopped: %s", result.Message)
+		}
+		a.hookManager.Notify(hooks.NotificationComplete, message)
+	}
+
 	// Execute Stop or SubagentStop hooks
-	if a.hookManager != nil {
+	if a.hookManager != nil && !a.isSubAgent {
 		var hookEvent hooks.HookEvent
 		if subAgentID != "" {
 			hookEvent = hooks.SubagentStop
@@ -286,11 +804,45 @@ func (a *Agent) ExecuteWithHistory(ctx context.Context, conversation []openai.Ch
 	return result, conversation, nil
 }
 
+// isEmptyResponse reports whether the last message is an assistant reply
+// with no tool calls and only whitespace content
+func isEmptyResponse(conversation []openai.ChatCompletionMessage) bool {
+	if len(conversation) == 0 {
+		return false
+	}
+	last := conversation[len(conversation)-1]
+	return last.Role == "assistant" && len(last.ToolCalls) == 0 && strings.TrimSpace(last.Content) == ""
+}
+
+// recordRun reports metrics for a finished run to the telemetry sink
+func (a *Agent) recordRun(start time.Time, result *ExecutionResult, handler *TurnHandler) {
+	usage := handler.TotalUsage()
+	metrics := telemetry.RunMetrics{
+		Success:          result.Success,
+		Duration:         time.Since(start),
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+		ToolCalls:        make(map[string]int),
+	}
+	for _, step := range result.Steps {
+		if step.ToolName != "" {
+			metrics.ToolCalls[step.ToolName]++
+		}
+	}
+
+	if err := a.telemetry.Record(metrics); err != nil {
+		log.Printf("Failed to record telemetry: %v", err)
+	}
+}
+
 type LLMResponse struct {
-	Role      string
-	Content   string
-	ToolCalls []openai.ToolCall
-	Reasoning string
+	Role         string
+	Content      string
+	ToolCalls    []openai.ToolCall
+	Reasoning    string
+	Usage        openai.Usage
+	FinishReason openai.FinishReason
 }
 
 type Message struct {
@@ -329,26 +881,36 @@ func filterConversationForLLM(messages []openai.ChatCompletionMessage) []openai.
 	return messages
 }
 
+// detectRepetitiveActions reports whether the same tool call (same tool and
+// identical arguments) occurred repeatThreshold times within the last
+// repeatWindow steps, e.g. the same failing edit or the same read in a loop
 func (a *Agent) detectRepetitiveActions(steps []ExecutionStep) bool {
-	if len(steps) < 3 {
+	if a.repeatThreshold <= 0 || len(steps) < a.repeatThreshold {
 		return false
 	}
 
-	// 最後の3つのステップを確認
-	recent := steps[len(steps)-3:]
+	recent := steps
+	if a.repeatWindow > 0 && len(steps) > a.repeatWindow {
+		recent = steps[len(steps)-a.repeatWindow:]
+	}
 
-	// 同じコマンドが繰り返されているかチェック
-	commands := make(map[string]int)
+	calls := make(map[string]int)
 	for _, step := range recent {
-		if step.ToolName == "run_shell" {
-			if cmd, ok := step.ToolArgs["command"].(string); ok {
-				commands[cmd]++
-				if commands[cmd] >= 2 {
-					return true
-				}
-			}
+		if step.ToolName == "" {
+			continue
+		}
+		key := toolCallKey(step.ToolName, step.ToolArgs)
+		calls[key]++
+		if calls[key] >= a.repeatThreshold {
+			return true
 		}
 	}
 
 	return false
 }
+
+// toolCallKey identifies a tool call by its name and arguments.
+// json.Marshal sorts map keys, so equal arguments always produce the same key.
+func toolCallKey(toolName string, args map[string]interface{}) string {
+	return toolName + ":" + jsonString(args)
+}
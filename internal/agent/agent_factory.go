@@ -2,16 +2,38 @@ package agent
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"strings"
 
 	"github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/hooks"
 	"github.com/trknhr/agenticode/internal/llm"
+	"github.com/trknhr/agenticode/internal/telemetry"
 	"github.com/trknhr/agenticode/internal/tools"
 )
 
 // AgentFactoryAdapter adapts the agent package for use by the tools package
 type AgentFactoryAdapter struct {
-	systemPrompt    func(string) string
-	developerPrompt func() string
+	systemPrompt     func(string) string
+	developerPrompt  func() string
+	maxContextTokens int                   // Auto-compaction threshold for sub-agents (0 disables)
+	contextStrategy  ContextStrategy       // How sub-agents shrink their conversation
+	hookManager      *hooks.Manager        // Parent's hooks, applied inside sub-agents too
+	depth            int                   // Depth of the agent that owns the tool
+	maxDepth         int                   // Deepest sub-agent the tool may create
+	toolFilter       func(tools.Tool) bool // Parent's tool filter, so denied tools stay unavailable
+	quiet            bool                  // Parent's quiet mode
+	turnOutputBytes  int                   // Parent's per-turn tool result budget
+	tracer           *Tracer               // Parent's trace file, shared by sub-agents
+	stagingDir       string                // Parent's staging directory for dry runs
+	dryRun           func() bool           // Whether the parent is running a dry run
+	disabled         func(string) bool     // Whether the parent has the tool switched off right now
+	readTracker      *readTracker          // Parent's read-before-edit tracker, shared by sub-agents
+	readBudgetBytes  int                   // Parent's read budget, applied to each sub-agent
+	telemetry        telemetry.Sink        // Parent's metrics sink
+	decisionLog      *DecisionLog          // Parent's audit trail of approval decisions
+	toolErrorPolicy  ToolErrorPolicy       // Parent's handling of unrecovered tool failures
 }
 
 // NewAgentFactoryAdapter creates a new adapter
@@ -19,6 +41,8 @@ func NewAgentFactoryAdapter() *AgentFactoryAdapter {
 	return &AgentFactoryAdapter{
 		systemPrompt:    GetSystemPrompt,
 		developerPrompt: GetDeveloperPrompt,
+		maxDepth:        tools.DefaultMaxSubAgentDepth,
+		toolErrorPolicy: ToolErrorsFlag,
 	}
 }
 
@@ -57,7 +81,33 @@ func (afa *AgentFactoryAdapter) CreateAgentTool(llmClient interface{}) tools.Too
 		opts := []Option{
 			WithMaxSteps(maxSteps),
 			WithApprover(approver),
+			WithAutoCompact(afa.maxContextTokens),
+			WithContextStrategy(afa.contextStrategy),
+			WithQuiet(afa.quiet),
+			WithTurnOutputBudget(afa.turnOutputBytes),
+			WithTracer(afa.tracer),
+			WithMaxSubAgentDepth(afa.maxDepth),
+			WithStagingDir(afa.stagingDir),
+			WithReadBudget(afa.readBudgetBytes),
+			WithTelemetry(afa.telemetry),
+			WithDecisionLog(afa.decisionLog),
+			WithToolErrorPolicy(afa.toolErrorPolicy),
+			withReadTracker(afa.readTracker),
+			asSubAgent(afa.depth + 1),
+		}
+		if afa.hookManager != nil {
+			opts = append(opts, WithHookManager(afa.hookManager))
+		}
+
+		// Tools the parent filtered out or has switched off stay unavailable
+		parentFilter := afa.toolFilter
+		keep := func(tool tools.Tool) bool {
+			if afa.disabled != nil && afa.disabled(tool.Name()) {
+				return false
+			}
+			return parentFilter == nil || parentFilter(tool)
 		}
+		opts = append(opts, WithToolFilter(keep))
 
 		// For restricted agent types, only provide allowed tools
 		if agentType == "searcher" || agentType == "analyzer" {
@@ -75,6 +125,11 @@ func (afa *AgentFactoryAdapter) CreateAgentTool(llmClient interface{}) tools.Too
 				}
 			}
 			opts = append(opts, WithTools(filteredTools))
+
+			// Restricted agents never launch sub-agents of their own
+			opts = append(opts, WithToolFilter(func(tool tools.Tool) bool {
+				return tool.Name() != "agent_tool" && keep(tool)
+			}))
 		}
 
 		subAgent := NewAgent(client, opts...)
@@ -84,10 +139,13 @@ func (afa *AgentFactoryAdapter) CreateAgentTool(llmClient interface{}) tools.Too
 			agent:           subAgent,
 			systemPrompt:    afa.systemPrompt,
 			developerPrompt: afa.developerPrompt,
+			dryRun:          afa.dryRun != nil && afa.dryRun(),
 		}, nil
 	}
 
-	return tools.NewAgentTool(llmClient, agentFactory)
+	agentTool := tools.NewAgentTool(llmClient, agentFactory)
+	agentTool.SetDepthLimit(afa.depth, afa.maxDepth)
+	return agentTool
 }
 
 // agentInterfaceAdapter adapts our Agent to the tools.AgentInterface
@@ -95,6 +153,7 @@ type agentInterfaceAdapter struct {
 	agent           *Agent
 	systemPrompt    func(string) string
 	developerPrompt func() string
+	dryRun          bool // The parent was running a dry run when it launched the sub-agent
 }
 
 // ExecuteWithHistory adapts the agent's ExecuteWithHistory to use interface{} types
@@ -139,7 +198,7 @@ func (a *agentInterfaceAdapter) ExecuteWithHistory(ctx context.Context, conversa
 	}
 
 	// Execute with the real agent
-	result, updatedConv, err := a.agent.ExecuteWithHistory(ctx, openAIMessages, dryrun)
+	result, updatedConv, err := a.agent.ExecuteWithHistory(ctx, openAIMessages, dryrun || a.dryRun)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -148,6 +207,7 @@ func (a *agentInterfaceAdapter) ExecuteWithHistory(ctx context.Context, conversa
 	toolsResult := &tools.AgentExecutionResult{
 		Success:        result.Success,
 		Message:        result.Message,
+		Summary:        a.summarizeFindings(ctx, updatedConv),
 		GeneratedFiles: make([]tools.GeneratedFile, len(result.GeneratedFiles)),
 		Steps:          make([]tools.ExecutionStep, len(result.Steps)),
 	}
@@ -173,6 +233,8 @@ func (a *agentInterfaceAdapter) ExecuteWithHistory(ctx context.Context, conversa
 		}
 	}
 
+	a.runSubagentStopHooks(ctx, toolsResult)
+
 	// Convert updated conversation back to interface{}
 	updatedInterface := make([]interface{}, len(updatedConv))
 	for i, msg := range updatedConv {
@@ -182,6 +244,83 @@ func (a *agentInterfaceAdapter) ExecuteWithHistory(ctx context.Context, conversa
 	return toolsResult, updatedInterface, nil
 }
 
+// withReadTracker shares a read-before-edit tracker, so a sub-agent sees the
+// reads its parent made (and the other way round); nil keeps the agent's own
+func withReadTracker(tracker *readTracker) Option {
+	return func(a *Agent) {
+		if tracker != nil {
+			a.readTracker = tracker
+		}
+	}
+}
+
+// asSubAgent marks an agent as created by the agent tool at the given depth
+func asSubAgent(depth int) Option {
+	return func(a *Agent) {
+		a.isSubAgent = true
+		a.depth = depth
+	}
+}
+
+// runSubagentStopHooks fires SubagentStop with the sub-agent's result. A hook
+// that blocks (exit code 2 or decision "block") marks the result as failed
+// with its reason; other hook reasons are appended as annotations.
+func (a *agentInterfaceAdapter) runSubagentStopHooks(ctx context.Context, result *tools.AgentExecutionResult) {
+	if a.agent.hookManager == nil {
+		return
+	}
+
+	hookInput := hooks.HookInput{
+		ToolName: "agent_tool",
+		ToolResponse: map[string]interface{}{
+			"success": result.Success,
+			"message": result.Message,
+			"summary": result.Summary,
+		},
+	}
+
+	outputs, err := a.agent.hookManager.ExecuteHooks(ctx, hooks.SubagentStop, hookInput)
+	if err != nil {
+		log.Printf("SubagentStop hook error: %v", err)
+	}
+
+	for _, output := range outputs {
+		if output.Reason == "" {
+			continue
+		}
+		if output.Decision == "block" {
+			log.Printf("Sub-agent result blocked by hook: %s", output.Reason)
+			result.Success = false
+			result.Summary = fmt.Sprintf("Sub-agent result blocked by hook: %s", output.Reason)
+			continue
+		}
+		base := result.Summary
+		if base == "" {
+			base = result.Message
+		}
+		result.Summary = strings.TrimSpace(base + "\n\nHook note: " + output.Reason)
+	}
+}
+
+// subAgentSummaryPrompt closes a sub-agent run so only its findings reach the parent
+const subAgentSummaryPrompt = "Summarize your findings for the agent that delegated this task to you. Start with the direct answer, then list only the key facts, file paths and line numbers it needs. Be concise and do not repeat raw file contents or your step-by-step process."
+
+// summarizeFindings asks the sub-agent's model for a concise final answer.
+// It returns an empty string on failure so the caller falls back to the last message.
+func (a *agentInterfaceAdapter) summarizeFindings(ctx context.Context, conversation []openai.ChatCompletionMessage) string {
+	messages := append(append([]openai.ChatCompletionMessage(nil), conversation...), openai.ChatCompletionMessage{
+		Role:    "user",
+		Content: subAgentSummaryPrompt,
+	})
+
+	resp, err := a.agent.llmClient.Generate(ctx, messages, nil)
+	if err != nil || len(resp.Choices) == 0 {
+		log.Printf("Sub-agent summary failed, returning last message instead: %v", err)
+		return ""
+	}
+	return strings.TrimSpace(resp.Choices[0].Message.Content)
+}
+
 // SimpleAutoApprover automatically approves all tool calls for sub-agents
 type SimpleAutoApprover struct{}
 
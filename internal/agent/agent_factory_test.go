@@ -0,0 +1,273 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/hooks"
+	"github.com/trknhr/agenticode/internal/telemetry"
+	"github.com/trknhr/agenticode/internal/tools"
+)
+
+func TestSubAgentAutoCompactsWithTinyContextWindow(t *testing.T) {
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			toolCallResponse("call-1", "todo_read", `{}`),
+			textResponse("Read the todo list; nothing else done yet."), // summarization call
+			textResponse("finished"),
+			textResponse("The todo list is empty."), // closing findings summary
+		},
+	}
+
+	factory := NewAgentFactoryAdapter()
+	factory.maxContextTokens = 50
+	agentTool := factory.CreateAgentTool(client)
+
+	result, err := agentTool.Execute(map[string]interface{}{
+		"description": "inspect todos",
+		"prompt":      "Look at the todo list",
+	})
+	if err != nil {
+		t.Fatalf("agent tool failed: %v", err)
+	}
+	if !strings.Contains(result.LLMContent, "The todo list is empty.") {
+		t.Errorf("expected sub-agent findings after compaction, got %q", result.LLMContent)
+	}
+
+	if len(client.requests) != 4 {
+		t.Fatalf("expected 4 LLM calls (turn, compaction, turn, findings), got %d", len(client.requests))
+	}
+
+	summarizeRequest := client.requests[1]
+	if client.toolsSent[1] != nil {
+		t.Error("summarization request should not offer tools")
+	}
+	if last := summarizeRequest[len(summarizeRequest)-1]; !strings.Contains(last.Content, "summary of our conversation") {
+		t.Errorf("expected a summarization prompt, got %q", last.Content)
+	}
+
+	compacted := false
+	for _, msg := range client.requests[2] {
+		if strings.Contains(msg.Content, "[CONVERSATION SUMMARY]") {
+			compacted = true
+		}
+		if msg.Role == "tool" {
+			t.Error("tool messages should have been compacted away")
+		}
+	}
+	if !compacted {
+		t.Error("expected the turn after compaction to carry the summary")
+	}
+}
+
+func TestSubAgentReturnsBoundedSummary(t *testing.T) {
+	hugeFinal := strings.Repeat("raw file contents ", 2000)
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			toolCallResponse("call-1", "todo_read", `{}`),
+			textResponse(hugeFinal),
+			textResponse("Found the handler in internal/agent/handlers.go:42. " + strings.Repeat("detail ", 500)),
+		},
+	}
+
+	agentTool := NewAgentFactoryAdapter().CreateAgentTool(client)
+	result, err := agentTool.Execute(map[string]interface{}{
+		"description":       "find handler",
+		"prompt":            "Where are tool calls handled?",
+		"agent_type":        "searcher",
+		"max_result_tokens": float64(100),
+	})
+	if err != nil {
+		t.Fatalf("agent tool failed: %v", err)
+	}
+
+	if !strings.Contains(result.LLMContent, "internal/agent/handlers.go:42") {
+		t.Errorf("expected the summary in the result, got %q", result.LLMContent)
+	}
+	if strings.Contains(result.LLMContent, "raw file contents") {
+		t.Error("the raw final message should not reach the parent")
+	}
+	if len(result.LLMContent) > 100*4+300 {
+		t.Errorf("expected LLMContent bounded by max_result_tokens, got %d chars", len(result.LLMContent))
+	}
+
+	summaryRequest := client.requests[len(client.requests)-1]
+	if last := summaryRequest[len(summaryRequest)-1]; last.Content != subAgentSummaryPrompt {
+		t.Errorf("expected a closing summary request, got %q", last.Content)
+	}
+}
+
+func TestSubAgentInheritsPreToolUseHooks(t *testing.T) {
+	hookConfig := &hooks.HookConfig{
+		PreToolUse: []hooks.HookMatcher{{
+			Matcher: "todo_read",
+			Hooks:   []hooks.Hook{{Type: "command", Command: "echo 'todo access denied' >&2; exit 2"}},
+		}},
+	}
+	manager := hooks.NewManager(hookConfig, t.TempDir(), false, "test")
+
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			toolCallResponse("call-1", "todo_read", `{}`),
+			textResponse("could not read todos"),
+			textResponse("todo_read was blocked"),
+		},
+	}
+	parent := NewAgent(client, WithApprover(&SimpleAutoApprover{}), WithHookManager(manager))
+
+	if _, err := parent.tools["agent_tool"].Execute(map[string]interface{}{
+		"description": "read todos",
+		"prompt":      "Read the todo list",
+	}); err != nil {
+		t.Fatalf("agent tool failed: %v", err)
+	}
+
+	blocked := false
+	for _, msg := range client.requests[1] {
+		if msg.Role == "tool" && strings.Contains(msg.Content, "Tool execution blocked: todo access denied") {
+			blocked = true
+		}
+	}
+	if !blocked {
+		t.Error("expected the PreToolUse hook to block todo_read inside the sub-agent")
+	}
+}
+
+func TestSubagentStopHookCanBlockResult(t *testing.T) {
+	hookConfig := &hooks.HookConfig{
+		SubagentStop: []hooks.HookMatcher{{
+			Hooks: []hooks.Hook{{Type: "command", Command: "echo 'findings need human review' >&2; exit 2"}},
+		}},
+	}
+	manager := hooks.NewManager(hookConfig, t.TempDir(), false, "test")
+
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			textResponse("all good"),
+			textResponse("summary: all good"),
+		},
+	}
+	parent := NewAgent(client, WithApprover(&SimpleAutoApprover{}), WithHookManager(manager))
+
+	result, err := parent.tools["agent_tool"].Execute(map[string]interface{}{
+		"description": "check",
+		"prompt":      "Check things",
+	})
+	if err != nil {
+		t.Fatalf("agent tool failed: %v", err)
+	}
+	if !strings.Contains(result.LLMContent, "blocked by hook: findings need human review") {
+		t.Errorf("expected SubagentStop hook to block the result, got %q", result.LLMContent)
+	}
+	if strings.Contains(result.LLMContent, "summary: all good") {
+		t.Error("blocked summary should not reach the parent")
+	}
+}
+
+func TestSubAgentAtMaxDepthCannotSpawnAnother(t *testing.T) {
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			toolCallResponse("call-1", "agent_tool", `{"description":"nested","prompt":"Go deeper"}`),
+			textResponse("did it myself"),
+			textResponse("summary: did it myself"),
+		},
+	}
+	parent := NewAgent(client, WithApprover(&SimpleAutoApprover{}), WithMaxSubAgentDepth(1))
+
+	result, err := parent.tools["agent_tool"].Execute(map[string]interface{}{
+		"description": "outer",
+		"prompt":      "Do the task",
+	})
+	if err != nil {
+		t.Fatalf("agent tool failed: %v", err)
+	}
+	if !strings.Contains(result.LLMContent, "summary: did it myself") {
+		t.Errorf("expected the outer sub-agent to finish, got %q", result.LLMContent)
+	}
+
+	if len(client.requests) != 3 {
+		t.Fatalf("expected no nested sub-agent LLM calls, got %d requests", len(client.requests))
+	}
+	refused := false
+	for _, msg := range client.requests[1] {
+		if msg.Role == "tool" && strings.Contains(msg.Content, "maximum sub-agent depth of 1") {
+			refused = true
+		}
+	}
+	if !refused {
+		t.Error("expected the nested agent_tool call to be refused")
+	}
+}
+
+func TestSubAgentInheritsToolFilter(t *testing.T) {
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			textResponse("done"),
+			textResponse("Nothing to report."),
+		},
+	}
+
+	parent := NewAgent(client, WithToolFilter(func(tool tools.Tool) bool {
+		return tool.Name() != "run_shell"
+	}))
+	if _, err := parent.tools["agent_tool"].Execute(map[string]interface{}{
+		"description": "look around",
+		"prompt":      "Describe the project",
+	}); err != nil {
+		t.Fatalf("agent tool failed: %v", err)
+	}
+
+	for _, tool := range client.toolsSent[0] {
+		if tool.Function.Name == "run_shell" {
+			t.Error("expected a tool denied to the parent to be unavailable to the sub-agent")
+		}
+	}
+}
+
+// recordingSink keeps the metrics of every run it is given
+type recordingSink struct {
+	runs []telemetry.RunMetrics
+}
+
+func (s *recordingSink) Record(metrics telemetry.RunMetrics) error {
+	s.runs = append(s.runs, metrics)
+	return nil
+}
+
+func (s *recordingSink) Close() error { return nil }
+
+func TestSubAgentInheritsDisabledToolsAndAuditing(t *testing.T) {
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			toolCallResponse("sub-1", "todo_read", `{}`),
+			textResponse("done"),
+			textResponse("Nothing to report."),
+		},
+	}
+	sink := &recordingSink{}
+	decisions := NewDecisionLog("")
+	parent := NewAgent(client, WithTelemetry(sink), WithDecisionLog(decisions), WithReadBeforeEdit(true), WithReadBudget(4096))
+	if err := parent.DisableTool("run_shell"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := parent.tools["agent_tool"].Execute(map[string]interface{}{
+		"description": "look around",
+		"prompt":      "Describe the project",
+	}); err != nil {
+		t.Fatalf("agent tool failed: %v", err)
+	}
+
+	for _, tool := range client.toolsSent[0] {
+		if tool.Function.Name == "run_shell" {
+			t.Error("expected a tool disabled in the parent to be unavailable to the sub-agent")
+		}
+	}
+	if len(sink.runs) != 1 || sink.runs[0].ToolCalls["todo_read"] != 1 {
+		t.Errorf("expected the sub-agent's run in the parent's metrics, got %+v", sink.runs)
+	}
+	if got := decisions.Decisions(); len(got) != 1 || got[0].ToolName != "todo_read" {
+		t.Errorf("expected the sub-agent's decision in the parent's log, got %+v", got)
+	}
+}
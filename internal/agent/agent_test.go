@@ -0,0 +1,959 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/hooks"
+	"github.com/trknhr/agenticode/internal/llm"
+	"github.com/trknhr/agenticode/internal/telemetry"
+	"github.com/trknhr/agenticode/internal/tools"
+)
+
+func TestAgentStopsWhenTokenBudgetExhausted(t *testing.T) {
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			withUsage(toolCallResponse("call-1", "todo_read", `{}`), 4000, 1000),
+			withUsage(toolCallResponse("call-2", "todo_read", `{}`), 6000, 1000),
+			withUsage(toolCallResponse("call-3", "todo_read", `{}`), 8000, 1000),
+		},
+	}
+
+	a := NewAgent(client,
+		WithMaxSteps(10),
+		WithApprover(&SimpleAutoApprover{}),
+		WithMaxTotalTokens(10000),
+	)
+
+	result, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "loop forever"},
+	}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Success {
+		t.Error("expected the run to be marked unsuccessful")
+	}
+	if !strings.Contains(result.Message, "Token budget exhausted") {
+		t.Errorf("unexpected message: %q", result.Message)
+	}
+	if client.generateCalls != 2 {
+		t.Errorf("expected the agent to stop after 2 LLM calls, got %d", client.generateCalls)
+	}
+	if result.TokensUsed != 12000 {
+		t.Errorf("expected 12000 tokens used, got %d", result.TokensUsed)
+	}
+}
+
+func TestAgentWithoutTokenBudgetRunsToCompletion(t *testing.T) {
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			withUsage(toolCallResponse("call-1", "todo_read", `{}`), 50000, 1000),
+			withUsage(textResponse("all done"), 60000, 1000),
+		},
+	}
+
+	a := NewAgent(client, WithMaxSteps(10), WithApprover(&SimpleAutoApprover{}))
+
+	result, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "do it"},
+	}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Success || result.Message != "all done" {
+		t.Errorf("expected successful completion, got %+v", result)
+	}
+}
+
+func TestAgentRecordsTelemetryForRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.prom")
+	sink, err := telemetry.NewSink(telemetry.Config{Enabled: true, Format: "prometheus", Path: path})
+	if err != nil {
+		t.Fatalf("failed to create sink: %v", err)
+	}
+
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			withUsage(toolCallResponse("call-1", "todo_read", `{}`), 100, 10),
+			withUsage(textResponse("done"), 200, 20),
+		},
+	}
+	a := NewAgent(client, WithApprover(&SimpleAutoApprover{}), WithTelemetry(sink))
+
+	if _, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "check todos"},
+	}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("metrics were not exported: %v", err)
+	}
+	for _, want := range []string{
+		`agenticode_runs_total{status="success"} 1`,
+		`agenticode_tokens_total{type="prompt"} 300`,
+		`agenticode_tool_calls_total{tool="todo_read"} 1`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("metrics missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestDetectRepetitiveActions(t *testing.T) {
+	editArgs := func() map[string]interface{} {
+		return map[string]interface{}{"file_path": "main.go", "old_string": "foo", "new_string": "bar"}
+	}
+	readArgs := func(path string) map[string]interface{} {
+		return map[string]interface{}{"path": path}
+	}
+
+	tests := []struct {
+		name      string
+		threshold int
+		window    int
+		steps     []ExecutionStep
+		want      bool
+	}{
+		{
+			name:      "repeated identical edit",
+			threshold: 2,
+			window:    3,
+			steps: []ExecutionStep{
+				{ToolName: "edit", ToolArgs: editArgs()},
+				{ToolName: "edit", ToolArgs: editArgs()},
+			},
+			want: true,
+		},
+		{
+			name:      "repeated read loop",
+			threshold: 3,
+			window:    5,
+			steps: []ExecutionStep{
+				{ToolName: "read_file", ToolArgs: readArgs("a.go")},
+				{ToolName: "grep", ToolArgs: map[string]interface{}{"pattern": "x"}},
+				{ToolName: "read_file", ToolArgs: readArgs("a.go")},
+				{ToolName: "read_file", ToolArgs: readArgs("a.go")},
+			},
+			want: true,
+		},
+		{
+			name:      "reads of different files",
+			threshold: 2,
+			window:    3,
+			steps: []ExecutionStep{
+				{ToolName: "read_file", ToolArgs: readArgs("a.go")},
+				{ToolName: "read_file", ToolArgs: readArgs("b.go")},
+				{ToolName: "read_file", ToolArgs: readArgs("c.go")},
+			},
+			want: false,
+		},
+		{
+			name:      "repeat outside window",
+			threshold: 2,
+			window:    2,
+			steps: []ExecutionStep{
+				{ToolName: "write_file", ToolArgs: map[string]interface{}{"path": "x", "content": "y"}},
+				{ToolName: "grep", ToolArgs: map[string]interface{}{"pattern": "x"}},
+				{ToolName: "write_file", ToolArgs: map[string]interface{}{"path": "x", "content": "y"}},
+			},
+			want: false,
+		},
+		{
+			name:      "disabled",
+			threshold: 0,
+			window:    3,
+			steps: []ExecutionStep{
+				{ToolName: "edit", ToolArgs: editArgs()},
+				{ToolName: "edit", ToolArgs: editArgs()},
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Agent{}
+			WithRepetitionDetection(tt.threshold, tt.window)(a)
+			if got := a.detectRepetitiveActions(tt.steps); got != tt.want {
+				t.Errorf("detectRepetitiveActions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAgentInjectsGuidanceForRepeatedEdit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	args := `{"file_path":"` + path + `","old_string":"missing","new_string":"x"}`
+
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			toolCallResponse("call-1", "edit", args),
+			toolCallResponse("call-2", "edit", args),
+			textResponse("giving up"),
+		},
+	}
+	a := NewAgent(client, WithApprover(&SimpleAutoApprover{}))
+
+	if _, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "fix it"},
+	}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	last := client.requests[len(client.requests)-1]
+	found := false
+	for _, msg := range last {
+		if msg.Role == "system" && strings.Contains(msg.Content, "repeating the same actions") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected repetition guidance before the third LLM call")
+	}
+}
+
+func TestAgentRevisesChangeAfterRejectionFeedback(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "handler.go")
+	firstArgs := jsonString(map[string]interface{}{"path": target, "content": "func handle() {}\n"})
+	secondArgs := jsonString(map[string]interface{}{"path": target, "content": "func handle() { go work() }\n"})
+
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			toolCallResponse("call-1", "write_file", firstArgs),
+			toolCallResponse("call-2", "write_file", secondArgs),
+		},
+	}
+
+	// Reject the first write with feedback, accept the revised one
+	approver := NewInteractiveApproverWithInput(strings.NewReader("f\nmake the handler async\ny\n"))
+	a := NewAgent(client, WithApprover(approver))
+
+	result, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "write a handler"},
+	}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("expected the run to succeed, got %q", result.Message)
+	}
+
+	if client.generateCalls != 3 {
+		t.Fatalf("expected a new generation attempt after the feedback, got %d LLM calls", client.generateCalls)
+	}
+	feedbackSent := false
+	for _, msg := range client.requests[1] {
+		if msg.Role == "tool" && strings.Contains(msg.Content, "make the handler async") {
+			feedbackSent = true
+		}
+	}
+	if !feedbackSent {
+		t.Error("expected the feedback to be sent to the model with the rejection")
+	}
+
+	data, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatalf("expected the revised change to be written: %v", err)
+	}
+	if !strings.Contains(string(data), "go work()") {
+		t.Errorf("expected the revised content, got %q", data)
+	}
+}
+
+func TestAgentAsksUserAndUsesAnswer(t *testing.T) {
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			toolCallResponse("call-1", "ask_user", jsonString(map[string]interface{}{"question": "Which port should the server listen on?"})),
+			textResponse("Using port 9090."),
+		},
+	}
+
+	approver := NewInteractiveApproverWithInput(strings.NewReader("9090\n"))
+	a := NewAgent(client, WithApprover(approver), WithUserPrompter(approver))
+
+	result, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "start a server"},
+	}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("expected the run to succeed, got %q", result.Message)
+	}
+
+	answered := false
+	for _, msg := range client.requests[1] {
+		if msg.Role == "tool" && msg.Content == "User answered: 9090" {
+			answered = true
+		}
+	}
+	if !answered {
+		t.Errorf("expected the user's answer to be returned as the tool result, got %+v", client.requests[1])
+	}
+}
+
+func TestAgentAskUserWithoutPrompterFailsSoftly(t *testing.T) {
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			toolCallResponse("call-1", "ask_user", jsonString(map[string]interface{}{"question": "Which port?"})),
+			textResponse("Assuming port 8080."),
+		},
+	}
+
+	a := NewAgent(client, WithApprover(&SimpleAutoApprover{}))
+	if _, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "start a server"},
+	}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, msg := range client.requests[1] {
+		if msg.Role == "tool" && !strings.Contains(msg.Content, "make a reasonable assumption") {
+			t.Errorf("expected the agent to be told to assume, got %q", msg.Content)
+		}
+	}
+}
+
+func TestMemorySurvivesCompaction(t *testing.T) {
+	tools.GlobalMemoryStore.Clear()
+	defer tools.GlobalMemoryStore.Clear()
+
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			toolCallResponse("call-1", "memory_write", jsonString(map[string]interface{}{
+				"key":   "auth",
+				"value": "the auth logic lives in auth/jwt.go",
+			})),
+			textResponse("Looked around the repository."), // summarization call
+			textResponse("done"),
+		},
+	}
+
+	a := NewAgent(client, WithApprover(&SimpleAutoApprover{}), WithAutoCompact(50))
+	_, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: strings.Repeat("find where authentication is handled ", 10)},
+	}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(client.requests) != 3 {
+		t.Fatalf("expected turn, compaction and turn requests, got %d", len(client.requests))
+	}
+	for _, msg := range client.requests[2] {
+		if msg.Role == "tool" {
+			t.Fatal("expected the tool call to be compacted away")
+		}
+		if strings.Contains(msg.Content, "auth: the auth logic lives in auth/jwt.go") {
+			return
+		}
+	}
+	t.Errorf("expected the saved note after compaction, got %+v", client.requests[2])
+}
+
+func TestPinnedFileIsRefreshedAfterEdit(t *testing.T) {
+	tools.GlobalPinnedFiles.Clear()
+	defer tools.GlobalPinnedFiles.Clear()
+
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte("package main // version one\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tools.GlobalPinnedFiles.Pin(path); err != nil {
+		t.Fatal(err)
+	}
+
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			toolCallResponse("call-1", "write_file", jsonString(map[string]interface{}{
+				"path":    path,
+				"content": "package main // version two\n",
+			})),
+			textResponse("done"),
+		},
+	}
+
+	a := NewAgent(client, WithApprover(&SimpleAutoApprover{}))
+	_, conversation, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "bump the version"},
+	}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(client.requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(client.requests))
+	}
+	if last := client.requests[0][len(client.requests[0])-1]; !strings.Contains(last.Content, "version one") {
+		t.Errorf("expected the original contents in the first request, got %q", last.Content)
+	}
+	last := client.requests[1][len(client.requests[1])-1]
+	if !strings.Contains(last.Content, "version two") || strings.Contains(last.Content, "version one") {
+		t.Errorf("expected only the edited contents in the second request, got %q", last.Content)
+	}
+
+	for _, msg := range conversation {
+		if strings.Contains(msg.Content, "Pinned files") {
+			t.Error("expected pinned contents to stay out of the stored conversation")
+		}
+	}
+}
+
+func TestNotificationHookReceivesWaitingMessage(t *testing.T) {
+	dir := t.TempDir()
+	approvalFile := filepath.Join(dir, "approval.json")
+	completeFile := filepath.Join(dir, "complete.json")
+	hookConfig := &hooks.HookConfig{
+		Notification: []hooks.HookMatcher{
+			{Matcher: "approval", Hooks: []hooks.Hook{{Type: "command", Command: "cat > " + approvalFile}}},
+			{Matcher: "complete", Hooks: []hooks.Hook{{Type: "command", Command: "cat > " + completeFile}}},
+		},
+	}
+	manager := hooks.NewManager(hookConfig, dir, false, "test")
+
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			toolCallResponse("call-1", "write_file", jsonString(map[string]interface{}{
+				"path":    filepath.Join(dir, "out.txt"),
+				"content": "hello",
+			})),
+			textResponse("done"),
+		},
+	}
+
+	approver := NewInteractiveApproverWithInput(strings.NewReader("y\n"))
+	a := NewAgent(client, WithApprover(approver), WithHookManager(manager))
+	captureStdout(t, func() {
+		if _, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+			{Role: "user", Content: "write a file"},
+		}, false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	for file, want := range map[string]hooks.HookInput{
+		approvalFile: {NotificationType: hooks.NotificationApproval, Message: "Waiting for approval to run write_file"},
+		completeFile: {NotificationType: hooks.NotificationComplete, Message: "Task completed"},
+	} {
+		input := waitForHookInput(t, file)
+		if input.HookEventName != hooks.Notification || input.NotificationType != want.NotificationType || input.Message != want.Message {
+			t.Errorf("unexpected notification in %s: %+v", filepath.Base(file), input)
+		}
+	}
+}
+
+// waitForHookInput polls for the JSON a background hook wrote to path
+func waitForHookInput(t *testing.T, path string) hooks.HookInput {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var input hooks.HookInput
+		data, err := os.ReadFile(path)
+		if err == nil && json.Unmarshal(data, &input) == nil {
+			return input
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("hook did not write %s", path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDisableToolRemovesItFromRequests(t *testing.T) {
+	client := &fakeLLMClient{}
+	a := NewAgent(client, WithApprover(&SimpleAutoApprover{}))
+
+	advertised := func() map[string]bool {
+		sent := client.toolsSent[len(client.toolsSent)-1]
+		names := make(map[string]bool, len(sent))
+		for _, tool := range sent {
+			names[tool.Function.Name] = true
+		}
+		return names
+	}
+	run := func() {
+		if _, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+			{Role: "user", Content: "hello"},
+		}, false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if err := a.DisableTool("run_shell"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := a.tools["run_shell"]; ok {
+		t.Error("expected run_shell to be removed from the tool map")
+	}
+	run()
+	if names := advertised(); names["run_shell"] || !names["read_file"] {
+		t.Errorf("expected only run_shell to be withheld, got %v", names)
+	}
+	if err := a.DisableTool("run_shell"); err == nil {
+		t.Error("expected disabling twice to fail")
+	}
+
+	if err := a.EnableTool("run_shell"); err != nil {
+		t.Fatal(err)
+	}
+	run()
+	if !advertised()["run_shell"] {
+		t.Error("expected run_shell to be advertised again after enabling")
+	}
+	if err := a.EnableTool("no_such_tool"); err == nil {
+		t.Error("expected enabling an unknown tool to fail")
+	}
+}
+
+func TestPostToolUseHookStdoutIsFedBack(t *testing.T) {
+	dir := t.TempDir()
+	hookConfig := &hooks.HookConfig{
+		PostToolUse: []hooks.HookMatcher{
+			{Matcher: "write_file", Hooks: []hooks.Hook{{Type: "command", Command: "echo 'main.go:3: exported func Foo should have comment'"}}},
+		},
+	}
+	manager := hooks.NewManager(hookConfig, dir, false, "test")
+
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			toolCallResponse("call-1", "write_file", jsonString(map[string]interface{}{
+				"path":    filepath.Join(dir, "main.go"),
+				"content": "package main\n",
+			})),
+			textResponse("done"),
+		},
+	}
+
+	a := NewAgent(client, WithApprover(&SimpleAutoApprover{}), WithHookManager(manager))
+	if _, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "write main.go"},
+	}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The output is part of the call's tool message, so nothing separates
+	// the tool responses from the assistant message that requested them
+	found := false
+	for _, msg := range client.requests[1] {
+		if strings.Contains(msg.Content, "exported func Foo should have comment") {
+			found = msg.Role == "tool" && msg.ToolCallID == "call-1" &&
+				strings.Contains(msg.Content, "\n\nHook output for write_file:\nmain.go:3:")
+		}
+	}
+	if !found {
+		t.Errorf("expected the hook's stdout in the call's tool message, got %+v", client.requests[1])
+	}
+}
+
+func TestDryRunWritesToStagingDir(t *testing.T) {
+	project := t.TempDir()
+	staging := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(project); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	if err := os.WriteFile("config.txt", []byte("debug = false\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			toolCallResponse("call-1", "write_file", jsonString(map[string]interface{}{
+				"path":    "src/main.go",
+				"content": "package main\n",
+			})),
+			toolCallResponse("call-2", "edit", jsonString(map[string]interface{}{
+				"file_path":  filepath.Join(project, "config.txt"),
+				"old_string": "debug = false",
+				"new_string": "debug = true",
+			})),
+			textResponse("done"),
+		},
+	}
+
+	a := NewAgent(client, WithApprover(&SimpleAutoApprover{}), WithStagingDir(staging))
+	captureStdout(t, func() {
+		if _, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+			{Role: "user", Content: "scaffold the project"},
+		}, true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if _, err := os.Stat(filepath.Join(project, "src", "main.go")); !os.IsNotExist(err) {
+		t.Errorf("expected no write to the real path, got %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(project, "config.txt")); string(data) != "debug = false\n" {
+		t.Errorf("expected the real file to be untouched, got %q", data)
+	}
+	if data, err := os.ReadFile(filepath.Join(staging, "src", "main.go")); err != nil || string(data) != "package main\n" {
+		t.Errorf("expected the new file in the staging dir, got %q (%v)", data, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(staging, "config.txt")); err != nil || string(data) != "debug = true\n" {
+		t.Errorf("expected the edited copy in the staging dir, got %q (%v)", data, err)
+	}
+}
+
+func TestDryRunLeavesProjectUnchanged(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	project := t.TempDir()
+	staging := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(project); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	git := func(args ...string) string {
+		out, err := exec.Command("git", args...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "--quiet")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test")
+	os.WriteFile("main.go", []byte("package main\n"), 0644)
+	git("add", "main.go")
+	git("commit", "--quiet", "-m", "initial")
+	os.WriteFile("main.go", []byte("package main // changed\n"), 0644)
+
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			toolCallResponse("call-1", "git_commit", `{"message":"wip"}`),
+			toolCallResponse("call-2", "apply_patch", `{"patch":"--- a/main.go\n+++ b/main.go\n"}`),
+			toolCallResponse("call-3", "notes", `{"content":"from the alias\n"}`),
+			toolCallResponse("call-4", "agent_tool", `{"description":"write","prompt":"write sub.txt"}`),
+			toolCallResponse("sub-1", "write_file", `{"path":"sub.txt","content":"from the sub-agent\n"}`),
+			textResponse("wrote sub.txt"),
+			textResponse("The sub-agent wrote sub.txt"), // sub-agent summary
+			textResponse("done"),
+		},
+	}
+	notes := tools.NewAliasTool("notes", tools.ToolAliasConfig{Args: map[string]interface{}{"path": "notes.txt"}}, tools.NewWriteFileTool())
+	a := NewAgent(client, WithApprover(&SimpleAutoApprover{}), WithStagingDir(staging), WithTools([]tools.Tool{notes}))
+	captureStdout(t, func() {
+		if _, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+			{Role: "user", Content: "commit and write notes"},
+		}, true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if count := git("rev-list", "--count", "HEAD"); count != "1" {
+		t.Errorf("expected git_commit to be refused in a dry run, got %s commits", count)
+	}
+	if status := git("status", "--porcelain"); status != "M main.go" {
+		t.Errorf("expected only the original change in the project, got %q", status)
+	}
+	for _, name := range []string{"notes.txt", "sub.txt"} {
+		if _, err := os.Stat(filepath.Join(staging, name)); err != nil {
+			t.Errorf("expected %s in the staging dir: %v", name, err)
+		}
+	}
+	for _, id := range []string{"call-1", "call-2"} {
+		refused := false
+		for _, msg := range client.requests[len(client.requests)-1] {
+			refused = refused || (msg.ToolCallID == id && strings.Contains(msg.Content, "not available in a dry run"))
+		}
+		if !refused {
+			t.Errorf("expected %s to be refused in the dry run", id)
+		}
+	}
+}
+
+func TestEmptyResponseIsRetriedOnce(t *testing.T) {
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{textResponse("  \n"), textResponse("All done.")},
+	}
+	a := NewAgent(client, WithApprover(&SimpleAutoApprover{}))
+	result, conversation, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "finish up"},
+	}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Success || result.Message != "All done." {
+		t.Errorf("expected the retry to complete the task, got %+v", result)
+	}
+	retry := client.requests[1]
+	if last := retry[len(retry)-1]; last.Role != "system" || last.Content != emptyResponseNudge {
+		t.Errorf("expected the retry to end with the nudge, got %+v", last)
+	}
+	for _, msg := range conversation {
+		if msg.Role == "assistant" && strings.TrimSpace(msg.Content) == "" && len(msg.ToolCalls) == 0 {
+			t.Error("expected the empty reply to be dropped from the conversation")
+		}
+	}
+}
+
+func TestRepeatedEmptyResponseFails(t *testing.T) {
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{textResponse(""), textResponse("")},
+	}
+	a := NewAgent(client, WithApprover(&SimpleAutoApprover{}))
+	result, conversation, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "finish up"},
+	}, false)
+	// A failed result rather than an error, so callers keep the history
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Success || result.Message != ErrEmptyResponse.Error() {
+		t.Errorf("expected a failed result, got %+v", result)
+	}
+	if len(conversation) == 0 || conversation[0].Content != "finish up" {
+		t.Errorf("expected the conversation to be returned, got %+v", conversation)
+	}
+	if client.generateCalls != 2 {
+		t.Errorf("expected exactly one retry, got %d calls", client.generateCalls)
+	}
+}
+
+func TestEachEmptyResponseGetsARetry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	os.WriteFile(path, []byte("notes\n"), 0644)
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			textResponse(""),
+			toolCallResponse("call-1", "read_file", jsonString(map[string]interface{}{"path": path})),
+			textResponse(""),
+			textResponse("All done."),
+		},
+	}
+	a := NewAgent(client, WithApprover(&SimpleAutoApprover{}))
+	result, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "read the notes"},
+	}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Success || result.Message != "All done." {
+		t.Errorf("expected a later stall to be retried too, got %+v", result)
+	}
+}
+
+func TestToolErrorPolicyAppliesToUnrecoveredFailures(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+	run := func(policy ToolErrorPolicy, responses ...openai.ChatCompletionResponse) *ExecutionResult {
+		t.Helper()
+		a := NewAgent(&fakeLLMClient{responses: responses},
+			WithMaxSteps(5),
+			WithApprover(&SimpleAutoApprover{}),
+			WithToolErrorPolicy(policy),
+		)
+		result, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+			{Role: "user", Content: "read it"},
+		}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+	failing := toolCallResponse("call-1", "read_file", jsonString(map[string]interface{}{"path": missing}))
+
+	if result := run(ToolErrorsFail, failing, textResponse("done")); result.Success || !result.HadErrors {
+		t.Errorf("fail policy: expected an unsuccessful flagged run, got success=%v hadErrors=%v", result.Success, result.HadErrors)
+	}
+	if result := run(ToolErrorsFlag, failing, textResponse("done")); !result.Success || !result.HadErrors {
+		t.Errorf("flag policy: expected a successful flagged run, got success=%v hadErrors=%v", result.Success, result.HadErrors)
+	}
+	if result := run(ToolErrorsIgnore, failing, textResponse("done")); !result.Success || result.HadErrors {
+		t.Errorf("ignore policy: expected a clean success, got success=%v hadErrors=%v", result.Success, result.HadErrors)
+	}
+
+	// A later clean tool call means the model recovered
+	recovered := run(ToolErrorsFail, failing, toolCallResponse("call-2", "todo_read", `{}`), textResponse("done"))
+	if !recovered.Success || recovered.HadErrors {
+		t.Errorf("expected recovery to clear the failure, got success=%v hadErrors=%v", recovered.Success, recovered.HadErrors)
+	}
+
+	if _, err := ParseToolErrorPolicy("strict"); err == nil {
+		t.Error("expected an unknown policy to be rejected")
+	}
+}
+
+func TestDangerousShellCommandNeedsExplicitApproval(t *testing.T) {
+	build := filepath.Join(t.TempDir(), "build")
+	os.MkdirAll(build, 0755)
+	args := jsonString(map[string]interface{}{"command": "rm -rf " + build})
+
+	// Auto-approval of run_shell does not cover a dangerous command: it is
+	// shown to the user with a warning and runs after an explicit yes
+	approver := NewInteractiveApproverWithInput(strings.NewReader("y\n"))
+	approver.SetAutoApprove([]string{"run_shell"})
+	client := &fakeLLMClient{responses: []openai.ChatCompletionResponse{toolCallResponse("call-1", "run_shell", args)}}
+	output := captureStdout(t, func() {
+		if _, _, err := NewAgent(client, WithApprover(approver)).ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+			{Role: "user", Content: "clean the build"},
+		}, false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(output, "DANGEROUS COMMAND") {
+		t.Errorf("expected a dangerous-command warning in the approval prompt, got:\n%s", output)
+	}
+	if _, err := os.Stat(build); !os.IsNotExist(err) {
+		t.Error("expected the approved command to run")
+	}
+
+	// Approvers that decide without the user cannot run it
+	os.MkdirAll(build, 0755)
+	client = &fakeLLMClient{responses: []openai.ChatCompletionResponse{toolCallResponse("call-1", "run_shell", args)}}
+	if _, _, err := NewAgent(client, WithApprover(&SimpleAutoApprover{})).ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "clean the build"},
+	}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(build); err != nil {
+		t.Error("expected the auto-approved dangerous command not to run")
+	}
+	rejected := false
+	for _, msg := range client.requests[1] {
+		if msg.Role == "tool" && strings.Contains(msg.Content, "needs explicit approval from the user") {
+			rejected = true
+		}
+	}
+	if !rejected {
+		t.Error("expected the model to be told the command needs explicit approval")
+	}
+}
+
+func TestDeterministicAgentSendsZeroTemperature(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		json.NewEncoder(w).Encode(textResponse("done"))
+	}))
+	defer server.Close()
+
+	client, err := llm.NewClient(llm.Config{Provider: "openai", APIKey: "test-key", Model: "gpt-4o", BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := NewAgent(client, WithDeterministic(true))
+	if _, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{{Role: "user", Content: "hi"}}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(bodies) == 0 {
+		t.Fatal("expected a request to the provider")
+	}
+	temperature, ok := bodies[0]["temperature"].(float64)
+	if !ok || temperature > 1e-6 {
+		t.Errorf("expected temperature 0 to be sent, got %v", bodies[0]["temperature"])
+	}
+	if seed, _ := bodies[0]["seed"].(float64); int(seed) != llm.DeterministicSeed {
+		t.Errorf("expected seed %d, got %v", llm.DeterministicSeed, bodies[0]["seed"])
+	}
+}
+
+func TestQuietAgentPrintsNothing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	run := func(quiet bool) string {
+		approver := NewInteractiveApprover()
+		approver.SetAutoApprove([]string{"write_file"})
+		approver.SetQuiet(quiet)
+		client := &fakeLLMClient{responses: []openai.ChatCompletionResponse{
+			toolCallResponse("call-1", "write_file", jsonString(map[string]interface{}{"path": path, "content": "hello\n"})),
+			textResponse("wrote notes.txt"),
+		}}
+		return captureStdout(t, func() {
+			result, _, err := NewAgent(client, WithApprover(approver), WithQuiet(quiet)).ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+				{Role: "user", Content: "write the notes"},
+			}, false)
+			if err != nil || result.Message != "wrote notes.txt" {
+				t.Fatalf("unexpected result %+v, %v", result, err)
+			}
+		})
+	}
+
+	if output := run(false); !strings.Contains(output, "notes.txt") || !strings.Contains(output, "Auto-approved") {
+		t.Fatalf("expected tool and approval output without quiet, got:\n%s", output)
+	}
+	if output := run(true); output != "" {
+		t.Errorf("expected no output in quiet mode, got:\n%s", output)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "hello\n" {
+		t.Errorf("expected the quiet run to still write the file, got %q", data)
+	}
+}
+
+func TestQuietHandlerKeepsWarningsOffStdout(t *testing.T) {
+	h := &TurnHandler{quiet: true}
+	output := captureStdout(t, func() {
+		h.handleIncompleteToolCall(IncompleteToolCallEvent{CallID: "call-1", Name: "write_file", Message: "arguments were cut off"})
+		h.handleError(ErrorEvent{Message: "boom", Error: errors.New("boom")})
+		h.handleUserCancelled()
+	})
+	if output != "" {
+		t.Errorf("expected nothing on stdout in quiet mode, got:\n%s", output)
+	}
+	if len(h.toolResponses) != 1 || !strings.Contains(h.toolResponses[0].Content, "arguments were cut off") {
+		t.Errorf("expected the truncated call to still be answered, got %+v", h.toolResponses)
+	}
+}
+
+func TestFailedToolResponseSuggestsRecovery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	client := &fakeLLMClient{responses: []openai.ChatCompletionResponse{
+		toolCallResponse("call-1", "edit", jsonString(map[string]interface{}{"file_path": path, "old_string": "missing", "new_string": "x"})),
+		toolCallResponse("call-2", "read_file", jsonString(map[string]interface{}{"path": path + ".bak"})),
+		textResponse("done"),
+	}}
+	a := NewAgent(client, WithApprover(&SimpleAutoApprover{}), WithQuiet(true))
+	if _, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "fix it"},
+	}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"call-1": "Error: old_string not found in file\nRecovery: Re-read the file with read_file",
+		"call-2": "\nRecovery: Check the path: list the directory with list_files",
+	}
+	for _, msg := range client.requests[len(client.requests)-1] {
+		if prefix, ok := want[msg.ToolCallID]; ok && msg.Role == "tool" {
+			if !strings.Contains(msg.Content, prefix) {
+				t.Errorf("%s: expected %q in the tool response, got %q", msg.ToolCallID, prefix, msg.Content)
+			}
+			delete(want, msg.ToolCallID)
+		}
+	}
+	if len(want) > 0 {
+		t.Errorf("missing tool responses for %v", want)
+	}
+}
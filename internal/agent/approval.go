@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/tools"
 )
 
 // ToolCallStatus represents the status of a tool call
@@ -156,6 +157,7 @@ type ApprovalRequest struct {
 	Description         string
 	Risks               map[string]RiskLevel
 	ConfirmationDetails ToolCallConfirmationDetails
+	Warning             string // Why the call is dangerous; only an explicit yes from the user runs it
 }
 
 // ApprovalResponse represents the user's approval decision
@@ -165,6 +167,8 @@ type ApprovalResponse struct {
 	ApprovedIDs []string
 	RejectedIDs []string
 	Reason      string
+	Feedback    string         // Instructions from the user for revising rejected calls
+	Source      DecisionSource // What made the decision (empty for generic approvers)
 }
 
 // RiskLevel represents the risk level of a tool
@@ -182,20 +186,41 @@ type ToolApprover interface {
 	NotifyExecution(toolCallID string, result interface{}, err error)
 }
 
+// promptChecker is implemented by approvers that can tell in advance whether
+// a request will wait for the user
+type promptChecker interface {
+	WillPrompt(request ApprovalRequest) bool
+}
+
+// UserPrompter asks the user a question during a run and returns the answer.
+// It is only available when someone is at the terminal to respond.
+type UserPrompter interface {
+	AskUser(ctx context.Context, question string, options []string) (string, error)
+}
+
 // AssessToolCallRisk evaluates the risk level of a tool call
 func AssessToolCallRisk(toolName string) RiskLevel {
 	switch toolName {
-	case "read_file", "read", "list_files", "grep", "glob", "read_many_files", "todo_write", "todo_read":
+	case "read_file", "read", "list_files", "grep", "glob", "read_many_files", "watch_file", "read_bytes", "summarize_file", "todo_write", "todo_read", "ask_user", "memory_write", "memory_read", "pin_file", "git_diff":
 		return RiskLow
-	case "write_file", "edit", "apply_patch":
+	case "write_file", "edit", "ast_edit", "edit_diff", "apply_patch", "make_directory":
 		return RiskMedium
-	case "run_shell":
+	case "run_shell", "run_tests", "git_commit", "delete_file":
 		return RiskHigh
 	default:
 		return RiskMedium // Default to medium for unknown tools
 	}
 }
 
+// assessToolRisk evaluates the risk level of a call to a registered tool.
+// An alias is as risky as the tool it calls.
+func assessToolRisk(toolName string, tool tools.Tool) RiskLevel {
+	if alias, ok := tool.(*tools.AliasTool); ok {
+		return assessToolRisk(alias.Target().Name(), alias.Target())
+	}
+	return AssessToolCallRisk(toolName)
+}
+
 // GetRiskIcon returns an icon for the risk level
 func GetRiskIcon(level RiskLevel) string {
 	switch level {
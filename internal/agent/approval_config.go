@@ -1,5 +1,10 @@
 package agent
 
+import (
+	"log"
+	"time"
+)
+
 // ApprovalConfig contains configuration for the approval system
 type ApprovalConfig struct {
 	// Mode can be "interactive", "auto", or "policy"
@@ -11,13 +16,16 @@ type ApprovalConfig struct {
 	// AutoApprove lists tool names that should be automatically approved
 	AutoApprove []string `yaml:"auto_approve" json:"auto_approve"`
 
+	// Rules auto-approve calls by tool name and arguments
+	Rules []ApprovalRule `yaml:"rules" json:"rules"`
+
 	// RequireApproval lists tool names that always require approval
 	RequireApproval []string `yaml:"require_approval" json:"require_approval"`
 
 	// DefaultApprove determines the default action when no specific rule applies
 	DefaultApprove bool `yaml:"default_approve" json:"default_approve"`
 
-	// TimeoutSeconds is the timeout for approval requests
+	// TimeoutSeconds is the timeout for approval requests (0 waits indefinitely)
 	TimeoutSeconds int `yaml:"timeout" json:"timeout"`
 }
 
@@ -33,14 +41,23 @@ func DefaultApprovalConfig() *ApprovalConfig {
 			"grep",
 			"glob",
 			"read_many_files",
+			"watch_file",
+			"read_bytes",
+			"summarize_file",
 			"todo_write",
 			"todo_read",
+			"memory_write",
+			"memory_read",
+			"pin_file",
+			"git_diff",
 		},
 		RequireApproval: []string{
 			"run_shell",
 			"write_file",
 			"edit",
 			"apply_patch",
+			"git_commit",
+			"delete_file",
 		},
 		DefaultApprove: false,
 		TimeoutSeconds: 60,
@@ -53,7 +70,11 @@ func NewApproverFromConfig(config *ApprovalConfig) ToolApprover {
 	case "interactive":
 		approver := NewInteractiveApprover()
 		approver.SetAutoApprove(config.AutoApprove)
+		if err := approver.SetApprovalRules(config.Rules); err != nil {
+			log.Printf("Ignoring approval rules: %v", err)
+		}
 		approver.SetAutoReject([]string{}) // Could be configured
+		approver.SetTimeout(time.Duration(config.TimeoutSeconds)*time.Second, config.DefaultApprove)
 		return approver
 	case "auto":
 		// Future: implement auto approver based on policy
@@ -0,0 +1,101 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// ApprovalRule auto-approves calls to one tool whose arguments match. A rule
+// with neither Command nor Path approves every call to the tool.
+type ApprovalRule struct {
+	Tool    string `mapstructure:"tool" yaml:"tool" json:"tool"`
+	Command string `mapstructure:"command" yaml:"command" json:"command"` // Regex the whole command must match
+	Path    string `mapstructure:"path" yaml:"path" json:"path"`          // Directory every path argument must be inside
+}
+
+// approvalRule is an ApprovalRule with its matchers compiled and resolved
+type approvalRule struct {
+	tool    string
+	command *regexp.Regexp
+	path    string
+}
+
+// SetApprovalRules configures argument-aware auto-approval. Command patterns
+// are anchored, so "npm test" does not match "npm test && rm -rf /", and never
+// match calls that set environment variables, which can change what the same
+// command runs (PATH, LD_PRELOAD, GOFLAGS, ...).
+func (ia *InteractiveApprover) SetApprovalRules(rules []ApprovalRule) error {
+	compiled := make([]approvalRule, 0, len(rules))
+	for i, rule := range rules {
+		if rule.Tool == "" {
+			return fmt.Errorf("approval rule %d: tool is required", i+1)
+		}
+		r := approvalRule{tool: rule.Tool}
+		if rule.Command != "" {
+			re, err := regexp.Compile(`^(?:` + rule.Command + `)$`)
+			if err != nil {
+				return fmt.Errorf("approval rule %d: invalid command pattern: %w", i+1, err)
+			}
+			r.command = re
+		}
+		if rule.Path != "" {
+			resolved, err := resolveTrustedPath(rule.Path)
+			if err != nil {
+				return fmt.Errorf("approval rule %d: invalid path: %w", i+1, err)
+			}
+			r.path = resolved
+		}
+		compiled = append(compiled, r)
+	}
+	ia.rules = compiled
+	return nil
+}
+
+// matchesApprovalRule reports whether any rule auto-approves the call
+func (ia *InteractiveApprover) matchesApprovalRule(call *PendingToolCall) bool {
+	for _, rule := range ia.rules {
+		if rule.matches(call) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r approvalRule) matches(call *PendingToolCall) bool {
+	if call.ToolCall.Function.Name != r.tool {
+		return false
+	}
+
+	if r.command != nil {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(call.ToolCall.Function.Arguments), &args); err != nil {
+			return false
+		}
+		command, _ := args["command"].(string)
+		if command == "" || !r.command.MatchString(command) {
+			return false
+		}
+		if env, ok := args["env"]; ok && env != nil {
+			if vars, isMap := env.(map[string]interface{}); !isMap || len(vars) > 0 {
+				return false
+			}
+		}
+	}
+
+	if r.path != "" {
+		// Every path must be inside the rule's directory; calls without a
+		// path never match a path rule
+		paths := toolCallPaths(call)
+		if len(paths) == 0 {
+			return false
+		}
+		for _, path := range paths {
+			resolved, err := resolveTrustedPath(path)
+			if err != nil || !isWithinDir(resolved, r.path) {
+				return false
+			}
+		}
+	}
+	return true
+}
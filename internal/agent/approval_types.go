@@ -10,6 +10,7 @@ type ToolFileConfirmationDetails struct {
 	IsNewFile       bool   // true if creating new file
 	OriginalContent string // Current file content (empty if new)
 	NewContent      string // What will be written
+	EncodingChange  string // How saving changes the file's encoding, e.g. "converted from UTF-16LE to UTF-8"
 	Risk            RiskLevel
 }
 
@@ -19,6 +20,9 @@ func (d *ToolFileConfirmationDetails) Title() string {
 	if d.IsNewFile {
 		return fmt.Sprintf("Create new file: %s", d.FilePath)
 	}
+	if d.EncodingChange != "" {
+		return fmt.Sprintf("Modify file: %s (%s)", d.FilePath, d.EncodingChange)
+	}
 	return fmt.Sprintf("Modify file: %s", d.FilePath)
 }
 
@@ -29,6 +33,8 @@ type ToolExecConfirmationDetails struct {
 	ToolName   string
 	Command    string
 	WorkingDir string
+	Scope      []string // Best-effort "rm: this will affect N files in M paths" lines
+	Env        []string // NAME=value pairs the call sets, sorted
 	Risk       RiskLevel
 }
 
@@ -40,6 +46,39 @@ func (d *ToolExecConfirmationDetails) Title() string {
 
 func (d *ToolExecConfirmationDetails) GetRisk() RiskLevel { return d.Risk }
 
+// ToolAgentConfirmationDetails represents a sub-agent launch by agent_tool
+type ToolAgentConfirmationDetails struct {
+	ToolName    string
+	AgentType   string
+	Description string // Short task description
+	Prompt      string // Full task handed to the sub-agent
+	MayWrite    bool   // The sub-agent can modify files or run commands without asking
+	Risk        RiskLevel
+}
+
+func (d *ToolAgentConfirmationDetails) Type() string { return "agent" }
+
+func (d *ToolAgentConfirmationDetails) Title() string {
+	return fmt.Sprintf("Launch %s sub-agent: %s", d.AgentType, d.Description)
+}
+
+func (d *ToolAgentConfirmationDetails) GetRisk() RiskLevel { return d.Risk }
+
+// ToolCustomConfirmationDetails carries the details a tool built itself
+// through tools.ConfirmationDetailer
+type ToolCustomConfirmationDetails struct {
+	ToolName string
+	Summary  string
+	Preview  string // Optional multi-line body
+	Risk     RiskLevel
+}
+
+func (d *ToolCustomConfirmationDetails) Type() string { return "custom" }
+
+func (d *ToolCustomConfirmationDetails) Title() string { return d.Summary }
+
+func (d *ToolCustomConfirmationDetails) GetRisk() RiskLevel { return d.Risk }
+
 // ToolInfoConfirmationDetails represents info/read operation confirmation
 type ToolInfoConfirmationDetails struct {
 	ToolName    string
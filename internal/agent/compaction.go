@@ -0,0 +1,85 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/hooks"
+	"github.com/trknhr/agenticode/internal/tools"
+)
+
+// compactionNotice is added after the summary so the model resumes the task
+const compactionNotice = "Earlier turns of this task were compacted into the summary above to stay within the context window. Continue the user's latest request from where it left off."
+
+// needsCompaction reports whether the conversation exceeds the agent's context budget
+func (a *Agent) needsCompaction(conversation []openai.ChatCompletionMessage) bool {
+	return a.maxContextTokens > 0 && estimateTokens(conversation) > a.maxContextTokens
+}
+
+// compactConversation replaces the body of the conversation with a summary.
+// The leading system/developer prompts, the first user message and the
+// latest one are kept so the agent keeps its instructions and, in an
+// interactive session, the request it is working on after compaction.
+func (a *Agent) compactConversation(ctx context.Context, conversation []openai.ChatCompletionMessage) ([]openai.ChatCompletionMessage, error) {
+	if a.hookManager != nil {
+		if _, err := a.hookManager.ExecuteHooks(ctx, hooks.PreCompact, hooks.HookInput{Trigger: "auto"}); err != nil {
+			log.Printf("PreCompact hook error: %v", err)
+		}
+	}
+
+	result, err := SummarizeConversation(ctx, a.llmClient, conversation, false, nil)
+	if err != nil {
+		return conversation, fmt.Errorf("failed to compact conversation: %w", err)
+	}
+
+	// Keep the leading prompts and the first user message (the task itself)
+	compacted := []openai.ChatCompletionMessage{}
+	first := -1
+	for i, msg := range conversation {
+		if msg.Role == "system" || msg.Role == "developer" {
+			compacted = append(compacted, msg)
+			continue
+		}
+		if msg.Role == "user" {
+			compacted = append(compacted, msg)
+			first = i
+		}
+		break
+	}
+
+	compacted = append(compacted, openai.ChatCompletionMessage{
+		Role:    "assistant",
+		Content: CreateSummaryMessage(result.Summary, result),
+	})
+
+	// A later prompt in the session is the request the agent is working on
+	for i := len(conversation) - 1; i > first; i-- {
+		if conversation[i].Role == "user" {
+			compacted = append(compacted, conversation[i])
+			break
+		}
+	}
+
+	compacted = append(compacted, openai.ChatCompletionMessage{
+		Role:    "system",
+		Content: compactionNotice,
+	})
+
+	// File contents read so far are gone from the conversation now
+	if a.readBudget != nil {
+		a.readBudget.reset()
+	}
+
+	// Notes saved with memory_write are not part of the conversation, so
+	// restate them for the model after compaction
+	if notes := tools.GlobalMemoryStore.Format(); notes != "" {
+		compacted = append(compacted, openai.ChatCompletionMessage{
+			Role:    "system",
+			Content: "Notes you saved with memory_write (read more with memory_read):\n" + notes,
+		})
+	}
+
+	return compacted, nil
+}
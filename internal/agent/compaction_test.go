@@ -0,0 +1,40 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestCompactionKeepsLatestPrompt(t *testing.T) {
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{textResponse("Renamed the config loader, then started on the flags.")},
+	}
+	a := NewAgent(client, WithApprover(&SimpleAutoApprover{}))
+
+	compacted, err := a.compactConversation(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "system", Content: "You are a coding agent."},
+		{Role: "user", Content: "rename the config loader"},
+		{Role: "assistant", Content: "Renamed it."},
+		{Role: "user", Content: "now add a --verbose flag"},
+		{Role: "assistant", Content: "Looking at the flags.", ToolCalls: []openai.ToolCall{{ID: "call-1", Function: openai.FunctionCall{Name: "read", Arguments: `{}`}}}},
+		{Role: "tool", Content: "package cmd", ToolCallID: "call-1"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var users []string
+	for _, msg := range compacted {
+		if msg.Role == "user" {
+			users = append(users, msg.Content)
+		}
+	}
+	if len(users) != 2 || users[0] != "rename the config loader" || users[1] != "now add a --verbose flag" {
+		t.Fatalf("expected the first and the latest prompt to survive, got %q", users)
+	}
+	if last := compacted[len(compacted)-1]; last.Content != compactionNotice {
+		t.Errorf("expected the notice to follow the latest prompt, got %+v", last)
+	}
+}
@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"fmt"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/llm"
+)
+
+// ContextStrategy decides how the conversation is shrunk once it exceeds the
+// auto-compaction threshold
+type ContextStrategy string
+
+const (
+	ContextSummarize ContextStrategy = "summarize" // Replace earlier turns with an LLM summary
+	ContextTrim      ContextStrategy = "trim"      // Blank the oldest tool results, keep everything else
+)
+
+// ParseContextStrategy validates a strategy name; an empty name is the
+// default strategy, summarize
+func ParseContextStrategy(name string) (ContextStrategy, error) {
+	switch strategy := ContextStrategy(name); strategy {
+	case "":
+		return ContextSummarize, nil
+	case ContextSummarize, ContextTrim:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown context strategy %q (expected summarize or trim)", name)
+	}
+}
+
+const (
+	// trimKeepRecent is how many trailing messages the trimmer never touches
+	trimKeepRecent = 8
+	// trimMinBytes is the smallest tool result worth trimming
+	trimMinBytes = 512
+)
+
+// trimConversation replaces the content of the oldest large tool results with
+// a short placeholder until the conversation fits in maxTokens. Prompts,
+// user and assistant messages and the last trimKeepRecent messages are kept
+// verbatim, and tool messages stay in place so every tool call keeps its
+// response. It returns the trimmed copy and how many results were trimmed.
+func trimConversation(conversation []openai.ChatCompletionMessage, maxTokens int) ([]openai.ChatCompletionMessage, int) {
+	trimmed := make([]openai.ChatCompletionMessage, len(conversation))
+	copy(trimmed, conversation)
+
+	tokens := estimateTokens(trimmed)
+	count := 0
+	for i := 0; i < len(trimmed)-trimKeepRecent && tokens > maxTokens; i++ {
+		msg := trimmed[i]
+		if msg.Role != "tool" || len(msg.Content) < trimMinBytes {
+			continue
+		}
+		placeholder := fmt.Sprintf("[%s result of %d bytes trimmed to save context; call the tool again if you still need it]", msg.Name, len(msg.Content))
+		tokens -= llm.EstimateTextTokens(msg.Content) - llm.EstimateTextTokens(placeholder)
+		trimmed[i].Content = placeholder
+		count++
+	}
+	return trimmed, count
+}
@@ -0,0 +1,115 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/llm"
+)
+
+// toolRound is an assistant tool call followed by its result
+func toolRound(id, tool, result string) []openai.ChatCompletionMessage {
+	return []openai.ChatCompletionMessage{
+		{Role: "assistant", ToolCalls: []openai.ToolCall{{ID: id, Type: "function", Function: openai.FunctionCall{Name: tool, Arguments: "{}"}}}},
+		{Role: "tool", Name: tool, ToolCallID: id, Content: result},
+	}
+}
+
+func TestTrimConversationDropsOldToolResults(t *testing.T) {
+	dump := strings.Repeat("package main // line of a large file\n", 200)
+	conversation := []openai.ChatCompletionMessage{
+		{Role: "system", Content: "system prompt"},
+		{Role: "developer", Content: "developer prompt"},
+		{Role: "user", Content: "refactor the parser"},
+	}
+	for i := 0; i < 3; i++ {
+		conversation = append(conversation, toolRound(fmt.Sprintf("old-%d", i), "read_file", dump)...)
+	}
+	for i := 0; i < 4; i++ {
+		conversation = append(conversation, toolRound(fmt.Sprintf("new-%d", i), "read_file", dump)...)
+	}
+
+	limit := estimateTokens(conversation) - llm.EstimateTextTokens(dump)
+	trimmed, count := trimConversation(conversation, limit)
+
+	if count != 2 {
+		t.Fatalf("expected the two oldest results trimmed to fit, got %d", count)
+	}
+	if estimateTokens(trimmed) > limit {
+		t.Errorf("expected the conversation to fit in %d tokens, got %d", limit, estimateTokens(trimmed))
+	}
+	if len(trimmed) != len(conversation) {
+		t.Fatalf("expected every message to stay in place, got %d of %d", len(trimmed), len(conversation))
+	}
+	for i, msg := range trimmed {
+		switch {
+		case i < 3:
+			if msg.Content != conversation[i].Content {
+				t.Errorf("message %d (%s) should be kept verbatim", i, msg.Role)
+			}
+		case msg.ToolCallID == "old-0" || msg.ToolCallID == "old-1":
+			if !strings.Contains(msg.Content, "trimmed to save context") {
+				t.Errorf("expected %s to be trimmed, got %d bytes", msg.ToolCallID, len(msg.Content))
+			}
+		case msg.Role == "tool":
+			if msg.Content != dump {
+				t.Errorf("expected %s to survive, got %q", msg.ToolCallID, msg.Content)
+			}
+		}
+	}
+	if conversation[4].Content != dump {
+		t.Error("trimming must not modify the caller's conversation")
+	}
+
+	// The most recent messages are kept even if the limit cannot be met
+	_, count = trimConversation(conversation, 1)
+	if count != 3 {
+		t.Errorf("expected only the results outside the recent window trimmed, got %d", count)
+	}
+}
+
+func TestTrimStrategyAvoidsSummarizing(t *testing.T) {
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{textResponse("done")},
+	}
+	dump := strings.Repeat("x", 30000)
+	history := []openai.ChatCompletionMessage{{Role: "user", Content: "look around"}}
+	for i := 0; i < 8; i++ {
+		history = append(history, toolRound(fmt.Sprintf("call-%d", i), "read_file", dump)...)
+	}
+	history = append(history, openai.ChatCompletionMessage{Role: "user", Content: "now finish"})
+
+	a := NewAgent(client, WithApprover(&SimpleAutoApprover{}), WithAutoCompact(50000), WithContextStrategy(ContextTrim))
+	if _, _, err := a.ExecuteWithHistory(context.Background(), history, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(client.requests) != 1 {
+		t.Fatalf("expected trimming instead of a summary request, got %d requests", len(client.requests))
+	}
+	sent := client.requests[0]
+	if sent[len(sent)-1].Content != "now finish" {
+		t.Errorf("expected the latest message to survive, got %q", sent[len(sent)-1].Content)
+	}
+	trimmed := 0
+	for _, msg := range sent {
+		if msg.Role == "tool" && strings.Contains(msg.Content, "trimmed to save context") {
+			trimmed++
+		}
+	}
+	if trimmed == 0 {
+		t.Error("expected old tool results to be trimmed")
+	}
+}
+
+func TestParseContextStrategy(t *testing.T) {
+	if strategy, err := ParseContextStrategy(""); err != nil || strategy != ContextSummarize {
+		t.Errorf("expected summarize by default, got %q, %v", strategy, err)
+	}
+	if _, err := ParseContextStrategy("drop"); err == nil {
+		t.Error("expected an unknown strategy to be rejected")
+	}
+}
@@ -0,0 +1,111 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DecisionSource identifies what decided whether a tool call could run
+type DecisionSource string
+
+const (
+	SourceUser         DecisionSource = "user"          // Answered at the approval prompt
+	SourceAutoApprove  DecisionSource = "auto-approve"  // Tool is on the auto-approve list
+	SourceAutoReject   DecisionSource = "auto-reject"   // Tool is on the auto-reject list
+	SourceRiskPolicy   DecisionSource = "risk-policy"   // Low-risk tools run without confirmation
+	SourcePathRule     DecisionSource = "path-rule"     // Paths are inside a trusted directory
+	SourceApprovalRule DecisionSource = "approval-rule" // An approval.rules entry matched the call's arguments
+	SourceHook         DecisionSource = "hook"          // A PreToolUse hook allowed or blocked the call
+	SourceTimeout      DecisionSource = "timeout"       // The prompt timed out and the default applied
+	SourceReadPolicy   DecisionSource = "read-policy"   // Edit rejected because the file was not read
+	SourceApprover     DecisionSource = "approver"      // A non-interactive approver decided
+	SourceDangerPolicy DecisionSource = "danger-policy" // Dangerous call rejected without an explicit yes from the user
+	SourcePlanMode     DecisionSource = "plan-mode"     // Call rejected while the agent was only planning
+)
+
+// ApprovalDecision records why a single tool call was allowed or refused
+type ApprovalDecision struct {
+	Time     time.Time      `json:"time"`
+	CallID   string         `json:"call_id"`
+	ToolName string         `json:"tool_name"`
+	Approved bool           `json:"approved"`
+	Source   DecisionSource `json:"source"`
+	Reason   string         `json:"reason"`
+}
+
+// DecisionLog keeps the approval decisions of a session and optionally
+// appends each one to a JSONL transcript
+type DecisionLog struct {
+	mu         sync.Mutex
+	decisions  []ApprovalDecision
+	transcript string
+}
+
+// NewDecisionLog creates a decision log. If transcriptPath is non-empty every
+// decision is also appended there as a JSON line.
+func NewDecisionLog(transcriptPath string) *DecisionLog {
+	return &DecisionLog{transcript: transcriptPath}
+}
+
+// Record adds a decision to the log
+func (l *DecisionLog) Record(decision ApprovalDecision) {
+	if l == nil {
+		return
+	}
+	if decision.Time.IsZero() {
+		decision.Time = time.Now()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.decisions = append(l.decisions, decision)
+
+	if l.transcript != "" {
+		if err := l.appendTranscript(decision); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write approval decision to transcript: %v\n", err)
+		}
+	}
+}
+
+// Decisions returns a copy of all recorded decisions in order
+func (l *DecisionLog) Decisions() []ApprovalDecision {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]ApprovalDecision(nil), l.decisions...)
+}
+
+// appendTranscript writes the decision as a JSON line to the transcript
+func (l *DecisionLog) appendTranscript(decision ApprovalDecision) error {
+	entry := struct {
+		Type string `json:"type"`
+		ApprovalDecision
+	}{Type: "approval_decision", ApprovalDecision: decision}
+	return appendTranscriptEntry(l.transcript, entry)
+}
+
+// appendTranscriptEntry writes entry as a JSON line to the transcript at path
+func appendTranscriptEntry(path string, entry interface{}) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,118 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/hooks"
+)
+
+func TestDecisionLogRecordsEachSource(t *testing.T) {
+	trusted := t.TempDir()
+	outside := t.TempDir()
+	existing := filepath.Join(outside, "existing.go")
+	if err := os.WriteFile(existing, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			toolCallResponse("call-risk", "todo_read", `{}`),
+			toolCallResponse("call-auto", "make_directory", jsonString(map[string]interface{}{"path": filepath.Join(outside, "dir")})),
+			toolCallResponse("call-path", "write_file", jsonString(map[string]interface{}{"path": filepath.Join(trusted, "a.txt"), "content": "a"})),
+			toolCallResponse("call-user", "write_file", jsonString(map[string]interface{}{"path": filepath.Join(outside, "b.txt"), "content": "b"})),
+			toolCallResponse("call-reject", "run_shell", `{"command":"echo hi"}`),
+			toolCallResponse("call-read", "edit", jsonString(map[string]interface{}{"file_path": existing, "old_string": "main", "new_string": "app"})),
+			toolCallResponse("call-hook", "todo_write", `{"items":[]}`),
+		},
+	}
+
+	// The only prompt is for the write outside the trusted directory
+	approver := NewInteractiveApproverWithInput(strings.NewReader("n\n"))
+	approver.SetAutoApprove([]string{"make_directory"})
+	approver.SetAutoReject([]string{"run_shell"})
+	approver.SetTrustedDirs([]string{trusted})
+
+	hookManager := hooks.NewManager(&hooks.HookConfig{
+		PreToolUse: []hooks.HookMatcher{{
+			Matcher: "todo_write",
+			Hooks:   []hooks.Hook{{Type: "command", Command: "echo 'todos are frozen' >&2; exit 2"}},
+		}},
+	}, t.TempDir(), false, "test")
+
+	transcript := filepath.Join(t.TempDir(), "session.jsonl")
+	decisionLog := NewDecisionLog(transcript)
+
+	a := NewAgent(client,
+		WithApprover(approver),
+		WithHookManager(hookManager),
+		WithReadBeforeEdit(true),
+		WithDecisionLog(decisionLog),
+	)
+	if _, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "do things"},
+	}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct {
+		callID   string
+		approved bool
+		source   DecisionSource
+		reason   string
+	}{
+		{"call-risk", true, SourceRiskPolicy, "Low-risk"},
+		{"call-auto", true, SourceAutoApprove, "auto-approve list"},
+		{"call-path", true, SourcePathRule, "trusted directory"},
+		{"call-user", false, SourceUser, "User rejected"},
+		{"call-reject", false, SourceAutoReject, "auto-rejection"},
+		{"call-read", false, SourceReadPolicy, "has not been read"},
+		{"call-hook", false, SourceHook, "todos are frozen"},
+	}
+
+	decisions := decisionLog.Decisions()
+	for _, w := range want {
+		found := false
+		for _, d := range decisions {
+			if d.CallID != w.callID || d.Source != w.source {
+				continue
+			}
+			found = true
+			if d.Approved != w.approved || !strings.Contains(d.Reason, w.reason) {
+				t.Errorf("decision for %s = %+v, want approved=%v reason containing %q", w.callID, d, w.approved, w.reason)
+			}
+		}
+		if !found {
+			t.Errorf("no %s decision recorded for %s; got %+v", w.source, w.callID, decisions)
+		}
+	}
+
+	// Every decision is also written to the transcript
+	f, err := os.Open(transcript)
+	if err != nil {
+		t.Fatalf("expected transcript to be written: %v", err)
+	}
+	defer f.Close()
+
+	lines := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("invalid transcript line %q: %v", scanner.Text(), err)
+		}
+		if entry["type"] != "approval_decision" {
+			t.Errorf("unexpected transcript entry type: %v", entry["type"])
+		}
+		lines++
+	}
+	if lines != len(decisions) {
+		t.Errorf("expected %d transcript lines, got %d", len(decisions), lines)
+	}
+}
@@ -2,9 +2,27 @@ package agent
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/sergi/go-diff/diffmatchpatch"
+	"golang.org/x/term"
+)
+
+// Diff styles for file confirmations
+const (
+	DiffStyleUnified    = "unified"
+	DiffStyleSideBySide = "side-by-side"
+)
+
+const (
+	// minSideBySideWidth is the narrowest terminal that gets two columns;
+	// narrower ones fall back to the unified diff
+	minSideBySideWidth = 80
+	// diffContextLines is how many unchanged lines surround each change
+	diffContextLines = 3
 )
 
 // DiffGenerator generates diffs for file changes
@@ -21,116 +39,151 @@ func NewDiffGenerator() *DiffGenerator {
 
 // GenerateUnifiedDiff generates a unified diff between two strings
 func (d *DiffGenerator) GenerateUnifiedDiff(original, new, fileName string) string {
-	diffs := d.dmp.DiffMain(original, new, false)
-	d.dmp.DiffCleanupSemantic(diffs)
-
-	if len(diffs) == 0 || (len(diffs) == 1 && diffs[0].Type == diffmatchpatch.DiffEqual) {
+	hunks := diffHunks(d.lineDiff(original, new), diffContextLines)
+	if len(hunks) == 0 {
 		return "No changes"
 	}
 
 	var result strings.Builder
-
-	// Add file header
 	result.WriteString(fmt.Sprintf("--- %s\n", fileName))
 	result.WriteString(fmt.Sprintf("+++ %s\n", fileName))
-
-	// Simple unified diff implementation
-	patches := d.dmp.PatchMake(original, diffs)
-
-	for _, patch := range patches {
-		result.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n",
-			patch.Start1+1, patch.Length1,
-			patch.Start2+1, patch.Length2))
-
-		// Extract text properly
-		patchText := patch.String()
-		// Remove the header line from patch text
-		lines := strings.Split(patchText, "\n")
-		for i, line := range lines {
-			if i == 0 && strings.HasPrefix(line, "@") {
-				continue
-			}
-			if line == "" && i == len(lines)-1 {
-				continue
-			}
-			// Unescape the line
-			line = strings.ReplaceAll(line, "%0A", "\n")
-			result.WriteString(line + "\n")
+	for _, hunk := range hunks {
+		result.WriteString(hunk.header() + "\n")
+		for _, line := range hunk.lines {
+			result.WriteString(fmt.Sprintf("%c%s\n", line.kind, line.text))
 		}
 	}
-
 	return result.String()
 }
 
 // GenerateColoredDiff generates a colored diff for terminal display
 func (d *DiffGenerator) GenerateColoredDiff(original, new, fileName string) string {
-	diffs := d.dmp.DiffMain(original, new, false)
-	d.dmp.DiffCleanupSemantic(diffs)
+	lines := d.lineDiff(original, new)
 
-	var result strings.Builder
 	var addedLines, removedLines int
-
-	// First pass: count changes
-	for _, diff := range diffs {
-		switch diff.Type {
-		case diffmatchpatch.DiffInsert:
-			addedLines += strings.Count(diff.Text, "\n")
-			if !strings.HasSuffix(diff.Text, "\n") && diff.Text != "" {
-				addedLines++
-			}
-		case diffmatchpatch.DiffDelete:
-			removedLines += strings.Count(diff.Text, "\n")
-			if !strings.HasSuffix(diff.Text, "\n") && diff.Text != "" {
-				removedLines++
-			}
+	for _, line := range lines {
+		switch line.kind {
+		case '+':
+			addedLines++
+		case '-':
+			removedLines++
 		}
 	}
 
-	// Add summary
+	var result strings.Builder
 	result.WriteString(fmt.Sprintf("Changes: %s+%d lines%s, %s-%d lines%s\n\n",
 		TermColors.Green, addedLines, TermColors.Reset,
 		TermColors.Red, removedLines, TermColors.Reset))
 
-	// Generate line-by-line diff with context
-	patches := d.dmp.PatchMake(original, diffs)
-
-	for i, patch := range patches {
+	for i, hunk := range diffHunks(lines, diffContextLines) {
 		if i > 0 {
 			result.WriteString("\n")
 		}
+		result.WriteString(fmt.Sprintf("%s%s%s\n", TermColors.Blue, hunk.header(), TermColors.Reset))
+		for _, line := range hunk.lines {
+			switch line.kind {
+			case '+':
+				result.WriteString(fmt.Sprintf("%s+%s%s\n", TermColors.Green, line.text, TermColors.Reset))
+			case '-':
+				result.WriteString(fmt.Sprintf("%s-%s%s\n", TermColors.Red, line.text, TermColors.Reset))
+			default:
+				result.WriteString(" " + line.text + "\n")
+			}
+		}
+	}
 
-		result.WriteString(fmt.Sprintf("%s@@ -%d,%d +%d,%d @@%s\n",
-			TermColors.Blue,
-			patch.Start1+1, patch.Length1,
-			patch.Start2+1, patch.Length2,
-			TermColors.Reset))
+	return result.String()
+}
 
-		// Process the patch text
-		patchText := patch.String()
-		lines := strings.Split(patchText, "\n")
-		for i, line := range lines {
-			if i == 0 && strings.HasPrefix(line, "@") {
-				continue // Skip header
-			}
-			if line == "" && i == len(lines)-1 {
-				continue
-			}
+// diffLine is one line of a line-based diff with its 1-based position in
+// the old and new content
+type diffLine struct {
+	kind           byte // ' ' unchanged, '-' removed, '+' added
+	text           string
+	oldNum, newNum int
+}
 
-			// Unescape the line
-			line = strings.ReplaceAll(line, "%0A", "\n")
+// diffHunk is a run of changed lines with surrounding context
+type diffHunk struct {
+	oldStart, oldLines int
+	newStart, newLines int
+	lines              []diffLine
+}
 
-			switch {
-			case strings.HasPrefix(line, "+"):
-				result.WriteString(fmt.Sprintf("%s%s%s\n", TermColors.Green, line, TermColors.Reset))
-			case strings.HasPrefix(line, "-"):
-				result.WriteString(fmt.Sprintf("%s%s%s\n", TermColors.Red, line, TermColors.Reset))
-			default:
-				result.WriteString(line + "\n")
+// header renders the "@@ -a,b +c,d @@" line. An empty side names the line
+// before the hunk, as in diff -u.
+func (h diffHunk) header() string {
+	oldStart, newStart := h.oldStart, h.newStart
+	if h.oldLines == 0 {
+		oldStart--
+	}
+	if h.newLines == 0 {
+		newStart--
+	}
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", oldStart, h.oldLines, newStart, h.newLines)
+}
+
+// lineDiff diffs the content line by line. Within each change the removed
+// lines come before the added ones.
+func (d *DiffGenerator) lineDiff(original, new string) []diffLine {
+	chars1, chars2, lineArray := d.dmp.DiffLinesToChars(original, new)
+	diffs := d.dmp.DiffCharsToLines(d.dmp.DiffMain(chars1, chars2, false), lineArray)
+
+	var lines, added []diffLine
+	oldNum, newNum := 1, 1
+	for _, diff := range diffs {
+		switch diff.Type {
+		case diffmatchpatch.DiffEqual:
+			lines = append(lines, added...)
+			added = nil
+			for _, text := range splitDiffLines(diff.Text) {
+				lines = append(lines, diffLine{kind: ' ', text: text, oldNum: oldNum, newNum: newNum})
+				oldNum++
+				newNum++
+			}
+		case diffmatchpatch.DiffDelete:
+			for _, text := range splitDiffLines(diff.Text) {
+				lines = append(lines, diffLine{kind: '-', text: text, oldNum: oldNum, newNum: newNum})
+				oldNum++
+			}
+		case diffmatchpatch.DiffInsert:
+			for _, text := range splitDiffLines(diff.Text) {
+				added = append(added, diffLine{kind: '+', text: text, oldNum: oldNum, newNum: newNum})
+				newNum++
 			}
 		}
 	}
+	return append(lines, added...)
+}
 
-	return result.String()
+// diffHunks groups changed lines into hunks with context unchanged lines on
+// each side, merging hunks whose context would overlap
+func diffHunks(lines []diffLine, context int) []diffHunk {
+	changed := make([]bool, len(lines))
+	for i, line := range lines {
+		changed[i] = line.kind != ' '
+	}
+
+	var hunks []diffHunk
+	var current *diffHunk
+	for i, line := range lines {
+		if !nearChange(changed, i, context) {
+			current = nil
+			continue
+		}
+		if current == nil {
+			hunks = append(hunks, diffHunk{oldStart: line.oldNum, newStart: line.newNum})
+			current = &hunks[len(hunks)-1]
+		}
+		current.lines = append(current.lines, line)
+		if line.kind != '+' {
+			current.oldLines++
+		}
+		if line.kind != '-' {
+			current.newLines++
+		}
+	}
+	return hunks
 }
 
 // GenerateInlineDiff generates a simple inline diff showing changes
@@ -163,3 +216,171 @@ func (d *DiffGenerator) GenerateInlineDiff(original, new string) string {
 
 	return result.String()
 }
+
+// sideBySideRow is one line of a side-by-side diff. A zero line number
+// leaves that column blank.
+type sideBySideRow struct {
+	oldNum, newNum   int
+	oldText, newText string
+	marker           byte // ' ' unchanged, '|' changed, '<' removed, '>' added
+}
+
+// GenerateSideBySideDiff shows the old and new content in two columns that
+// fit in width characters, pairing removed lines with the lines replacing
+// them. Long unchanged runs are collapsed; terminals narrower than
+// minSideBySideWidth get the unified diff instead.
+func (d *DiffGenerator) GenerateSideBySideDiff(original, new, fileName string, width int) string {
+	if width < minSideBySideWidth {
+		return d.GenerateColoredDiff(original, new, fileName)
+	}
+
+	rows := d.sideBySideRows(original, new)
+
+	var added, removed int
+	changed := make([]bool, len(rows))
+	for i, row := range rows {
+		if row.marker != ' ' {
+			changed[i] = true
+		}
+		if row.oldNum > 0 && row.marker != ' ' {
+			removed++
+		}
+		if row.newNum > 0 && row.marker != ' ' {
+			added++
+		}
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Changes: %s+%d lines%s, %s-%d lines%s\n\n",
+		TermColors.Green, added, TermColors.Reset,
+		TermColors.Red, removed, TermColors.Reset))
+
+	// Each column is "NNNN text"; the gutter between them is " x "
+	column := (width - 3) / 2
+	skipped := false
+	for i, row := range rows {
+		if !nearChange(changed, i, diffContextLines) {
+			skipped = true
+			continue
+		}
+		if skipped {
+			result.WriteString(fmt.Sprintf("%s%s%s\n", TermColors.Blue, strings.Repeat("┈", column)+" ┈ "+strings.Repeat("┈", column), TermColors.Reset))
+			skipped = false
+		}
+
+		left := formatDiffColumn(row.oldNum, row.oldText, column, true)
+		right := formatDiffColumn(row.newNum, row.newText, column, false)
+		switch row.marker {
+		case '|':
+			left = TermColors.Red + left + TermColors.Reset
+			right = TermColors.Green + right + TermColors.Reset
+		case '<':
+			left = TermColors.Red + left + TermColors.Reset
+		case '>':
+			right = TermColors.Green + right + TermColors.Reset
+		}
+		result.WriteString(fmt.Sprintf("%s %c %s\n", left, row.marker, right))
+	}
+
+	return result.String()
+}
+
+// sideBySideRows diffs the content line by line and aligns the two sides
+func (d *DiffGenerator) sideBySideRows(original, new string) []sideBySideRow {
+	chars1, chars2, lineArray := d.dmp.DiffLinesToChars(original, new)
+	diffs := d.dmp.DiffCharsToLines(d.dmp.DiffMain(chars1, chars2, false), lineArray)
+
+	var rows []sideBySideRow
+	oldNum, newNum := 1, 1
+	for i := 0; i < len(diffs); i++ {
+		lines := splitDiffLines(diffs[i].Text)
+		switch diffs[i].Type {
+		case diffmatchpatch.DiffEqual:
+			for _, line := range lines {
+				rows = append(rows, sideBySideRow{oldNum: oldNum, newNum: newNum, oldText: line, newText: line, marker: ' '})
+				oldNum++
+				newNum++
+			}
+		case diffmatchpatch.DiffDelete, diffmatchpatch.DiffInsert:
+			// A deletion next to an insertion is a change
+			var deleted, inserted []string
+			if diffs[i].Type == diffmatchpatch.DiffDelete {
+				deleted = lines
+			} else {
+				inserted = lines
+			}
+			if i+1 < len(diffs) && diffs[i+1].Type != diffmatchpatch.DiffEqual && diffs[i+1].Type != diffs[i].Type {
+				if deleted == nil {
+					deleted = splitDiffLines(diffs[i+1].Text)
+				} else {
+					inserted = splitDiffLines(diffs[i+1].Text)
+				}
+				i++
+			}
+			for j := 0; j < len(deleted) || j < len(inserted); j++ {
+				row := sideBySideRow{marker: '|'}
+				if j < len(deleted) {
+					row.oldNum, row.oldText = oldNum, deleted[j]
+					oldNum++
+				} else {
+					row.marker = '>'
+				}
+				if j < len(inserted) {
+					row.newNum, row.newText = newNum, inserted[j]
+					newNum++
+				} else {
+					row.marker = '<'
+				}
+				rows = append(rows, row)
+			}
+		}
+	}
+	return rows
+}
+
+// splitDiffLines splits a line-mode diff chunk into lines without newlines
+func splitDiffLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// nearChange reports whether a changed row lies within context rows of i
+func nearChange(changed []bool, i, context int) bool {
+	for j := i - context; j <= i+context; j++ {
+		if j >= 0 && j < len(changed) && changed[j] {
+			return true
+		}
+	}
+	return false
+}
+
+// formatDiffColumn renders "NNNN text" cut to width runes, padding it to
+// exactly width when pad is set
+func formatDiffColumn(num int, text string, width int, pad bool) string {
+	cell := ""
+	if num > 0 {
+		cell = fmt.Sprintf("%4d %s", num, strings.ReplaceAll(text, "\t", "    "))
+	}
+	n := utf8.RuneCountInString(cell)
+	if n > width {
+		return string([]rune(cell)[:width-1]) + "…"
+	}
+	if pad {
+		cell += strings.Repeat(" ", width-n)
+	}
+	return cell
+}
+
+// terminalWidth returns the width of the terminal on stdout, falling back to
+// $COLUMNS and then 80 columns
+func terminalWidth() int {
+	if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+		return width
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return 80
+}
@@ -0,0 +1,108 @@
+package agent
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var ansiCodes = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestSideBySideDiffAlignsChangedLines(t *testing.T) {
+	original := "package main\n\nfunc a() {}\nfunc b() {}\nfunc c() {}\n"
+	updated := "package main\n\nfunc a() {}\nfunc B() {}\nfunc c() {}\nfunc d() {}\n"
+
+	out := ansiCodes.ReplaceAllString(NewDiffGenerator().GenerateSideBySideDiff(original, updated, "main.go", 100), "")
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+
+	if lines[0] != "Changes: +2 lines, -1 lines" || lines[1] != "" {
+		t.Fatalf("unexpected header: %q", lines[:2])
+	}
+	rows := lines[2:]
+	column := (100 - 3) / 2
+	want := []struct {
+		left, marker, right string
+	}{
+		{"   1 package main", " ", "   1 package main"},
+		{"   2 ", " ", "   2 "},
+		{"   3 func a() {}", " ", "   3 func a() {}"},
+		{"   4 func b() {}", "|", "   4 func B() {}"},
+		{"   5 func c() {}", " ", "   5 func c() {}"},
+		{"", ">", "   6 func d() {}"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("expected %d rows, got %d:\n%s", len(want), len(rows), out)
+	}
+	for i, w := range want {
+		row := rows[i]
+		if len(row) < column+3 {
+			t.Fatalf("row %d too short: %q", i, row)
+		}
+		left, marker, right := row[:column], row[column+1:column+2], row[column+3:]
+		if strings.TrimRight(left, " ") != strings.TrimRight(w.left, " ") || marker != w.marker || right != w.right {
+			t.Errorf("row %d: expected %q %s %q, got %q", i, w.left, w.marker, w.right, row)
+		}
+	}
+}
+
+func TestSideBySideDiffFallsBackOnNarrowTerminals(t *testing.T) {
+	out := NewDiffGenerator().GenerateSideBySideDiff("a\nb\n", "a\nc\n", "x.txt", 60)
+	if !strings.Contains(out, "@@ -") {
+		t.Errorf("expected the unified diff below the minimum width, got %q", out)
+	}
+}
+
+func TestSideBySideDiffCollapsesUnchangedRuns(t *testing.T) {
+	var original strings.Builder
+	for i := 1; i <= 40; i++ {
+		fmt.Fprintf(&original, "line %d\n", i)
+	}
+	updated := strings.Replace(original.String(), "line 1\n", "first\n", 1)
+	updated = strings.Replace(updated, "line 40\n", "last\n", 1)
+
+	out := ansiCodes.ReplaceAllString(NewDiffGenerator().GenerateSideBySideDiff(original.String(), updated, "x.txt", 100), "")
+	if !strings.Contains(out, "┈") {
+		t.Error("expected a separator for the collapsed unchanged lines")
+	}
+	if rows := strings.Count(out, "\n"); rows > 2+2*(diffContextLines+1)+1 {
+		t.Errorf("expected unchanged lines far from the changes to be hidden, got %d lines:\n%s", rows, out)
+	}
+}
+
+func TestUnifiedDiffHunkHeadersUseLineNumbers(t *testing.T) {
+	var oldLines []string
+	for i := 1; i <= 30; i++ {
+		oldLines = append(oldLines, fmt.Sprintf("line %d", i))
+	}
+	newLines := append([]string(nil), oldLines...)
+	newLines[4] = "line 5 changed"                                                      // Replace line 5
+	newLines = append(newLines[:20], append([]string{"inserted"}, newLines[20:]...)...) // Insert after line 20
+	newLines = append(newLines[:28], newLines[29:]...)                                  // Drop old line 28
+
+	out := NewDiffGenerator().GenerateUnifiedDiff(strings.Join(oldLines, "\n")+"\n", strings.Join(newLines, "\n")+"\n", "f.txt")
+
+	var headers []string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "@@") {
+			headers = append(headers, line)
+		}
+	}
+	want := []string{"@@ -2,7 +2,7 @@", "@@ -18,6 +18,7 @@", "@@ -25,6 +26,5 @@"}
+	if strings.Join(headers, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("expected headers %q, got %q in:\n%s", want, headers, out)
+	}
+	if !strings.Contains(out, "\n-line 5\n+line 5 changed\n") || !strings.Contains(out, "\n line 20\n+inserted\n line 21\n") || !strings.Contains(out, "\n-line 28\n") {
+		t.Errorf("unexpected hunk bodies:\n%s", out)
+	}
+}
+
+func TestUnifiedDiffPureInsertionHeader(t *testing.T) {
+	out := NewDiffGenerator().GenerateUnifiedDiff("", "a\nb\n", "new.txt")
+	if !strings.Contains(out, "@@ -0,0 +1,2 @@\n+a\n+b\n") {
+		t.Errorf("unexpected diff for a new file:\n%s", out)
+	}
+	if got := NewDiffGenerator().GenerateUnifiedDiff("same\n", "same\n", "f.txt"); got != "No changes" {
+		t.Errorf("expected no changes, got %q", got)
+	}
+}
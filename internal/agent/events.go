@@ -15,6 +15,8 @@ const (
 	EventTypeUsageMetadata
 	EventTypeThought
 	EventTypeTurnComplete
+	EventTypeIncompleteToolCall
+	EventTypeLLMRequest
 )
 
 // Event is the base interface for all events
@@ -39,9 +41,28 @@ type ToolCallRequestEvent struct {
 
 func (e ToolCallRequestEvent) Type() EventType { return EventTypeToolCallRequest }
 
+// IncompleteToolCallEvent reports a tool call whose arguments were cut off
+// (e.g. the response hit the output token limit); it is never executed
+type IncompleteToolCallEvent struct {
+	CallID  string
+	Name    string
+	Message string
+}
+
+func (e IncompleteToolCallEvent) Type() EventType { return EventTypeIncompleteToolCall }
+
+// LLMRequestEvent is emitted just before the LLM is called
+type LLMRequestEvent struct {
+	Messages int // Messages sent, after filtering
+	Tools    int // Tools offered
+}
+
+func (e LLMRequestEvent) Type() EventType { return EventTypeLLMRequest }
+
 // ToolCallResponseEvent represents the result of a tool execution
 type ToolCallResponseEvent struct {
 	CallID        string
+	Name          string
 	Result        interface{}
 	ReturnDisplay string
 	Error         error
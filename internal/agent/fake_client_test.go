@@ -0,0 +1,129 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/llm"
+)
+
+// fakeLLMClient is a scripted llm.Client used in tests. Each Generate call
+// returns the next scripted response; once the script is exhausted it
+// returns a plain "done" message so the agent loop terminates.
+type fakeLLMClient struct {
+	mu          sync.Mutex
+	responses   []openai.ChatCompletionResponse
+	generateErr error
+	streamErr   error
+	streams     []*openai.ChatCompletionStream // Returned by Stream in order
+
+	generateCalls int
+	streamCalls   int
+	requests      [][]openai.ChatCompletionMessage
+	toolsSent     [][]openai.Tool
+}
+
+func (f *fakeLLMClient) Generate(ctx context.Context, messages []openai.ChatCompletionMessage, tools []openai.Tool) (openai.ChatCompletionResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.generateCalls++
+	f.requests = append(f.requests, append([]openai.ChatCompletionMessage(nil), messages...))
+	f.toolsSent = append(f.toolsSent, tools)
+
+	if f.generateErr != nil {
+		return openai.ChatCompletionResponse{}, f.generateErr
+	}
+	if len(f.responses) == 0 {
+		return textResponse("done"), nil
+	}
+	resp := f.responses[0]
+	f.responses = f.responses[1:]
+	return resp, nil
+}
+
+func (f *fakeLLMClient) Stream(ctx context.Context, messages []openai.ChatCompletionMessage, tools []openai.Tool) (llm.ChatStream, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.streamCalls++
+	if f.streamErr != nil || len(f.streams) == 0 {
+		return nil, f.streamErr
+	}
+	stream := f.streams[0]
+	f.streams = f.streams[1:]
+	return stream, nil
+}
+
+// newTestStream serves chunks as server-sent events and returns a real
+// ChatCompletionStream reading them
+func newTestStream(t *testing.T, chunks ...openai.ChatCompletionStreamResponse) *openai.ChatCompletionStream {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range chunks {
+			data, _ := json.Marshal(chunk)
+			fmt.Fprintf(w, "data: %s\n\n", data)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.DefaultConfig("test")
+	config.BaseURL = server.URL
+	stream, err := openai.NewClientWithConfig(config).CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{Model: "test"})
+	if err != nil {
+		t.Fatalf("failed to open test stream: %v", err)
+	}
+	return stream
+}
+
+// withUsage attaches token usage to a scripted response
+func withUsage(resp openai.ChatCompletionResponse, prompt, completion int) openai.ChatCompletionResponse {
+	resp.Usage = openai.Usage{
+		PromptTokens:     prompt,
+		CompletionTokens: completion,
+		TotalTokens:      prompt + completion,
+	}
+	return resp
+}
+
+// textResponse builds a response with plain assistant content
+func textResponse(content string) openai.ChatCompletionResponse {
+	return openai.ChatCompletionResponse{
+		Choices: []openai.ChatCompletionChoice{{
+			Message: openai.ChatCompletionMessage{
+				Role:    "assistant",
+				Content: content,
+			},
+			FinishReason: openai.FinishReasonStop,
+		}},
+	}
+}
+
+// toolCallResponse builds a response requesting a single tool call
+func toolCallResponse(id, name, arguments string) openai.ChatCompletionResponse {
+	return openai.ChatCompletionResponse{
+		Choices: []openai.ChatCompletionChoice{{
+			Message: openai.ChatCompletionMessage{
+				Role: "assistant",
+				ToolCalls: []openai.ToolCall{{
+					ID:   id,
+					Type: openai.ToolTypeFunction,
+					Function: openai.FunctionCall{
+						Name:      name,
+						Arguments: arguments,
+					},
+				}},
+			},
+			FinishReason: openai.FinishReasonToolCalls,
+		}},
+	}
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"os"
+	"slices"
+	"strings"
 
 	"github.com/sashabaranov/go-openai"
 	"github.com/trknhr/agenticode/internal/hooks"
@@ -20,6 +24,22 @@ type TurnHandler struct {
 	turn             *Turn
 	toolResponses    []openai.ChatCompletionMessage
 	hookManager      *hooks.Manager
+	usage            UsageMetadataEvent // Cumulative usage across all handled turns
+	readTracker      *readTracker       // Enforces read-before-edit when set
+	decisionLog      *DecisionLog       // Records why each tool call ran or was refused
+	userPrompter     UserPrompter       // Answers ask_user calls; nil when nobody is at the terminal
+	staging          *stagingArea       // Redirects file changes during a dry run
+	showReasoning    bool               // Print the model's reasoning as it arrives
+	reasoning        []string           // Reasoning from each turn, in order
+	changedFiles     []string           // Paths successfully modified by tools, in first-change order
+	turnExecuted     bool               // A tool ran during the current turn
+	turnFailures     []string           // Tools that failed during the current turn
+	failedTools      []string           // Failures of the last turn that ran tools
+	progress         ProgressDisplay    // Shows progress of tools that report it; nil hides it
+	quiet            bool               // Print neither model text nor tool displays
+	toolOutputs      *ToolOutputs       // Keeps tool displays and diffs for transcripts; nil skips it
+	outputBudget     *turnOutputBudget  // Caps the tool result bytes of each turn; nil means no cap
+	tracer           *Tracer            // Writes a structured record of every event; nil disables it
 }
 
 // NewTurnHandler creates a new turn handler
@@ -38,10 +58,94 @@ func (h *TurnHandler) SetHookManager(manager *hooks.Manager) {
 	h.hookManager = manager
 }
 
+// SetReadTracker enables the read-before-edit policy using the given tracker
+func (h *TurnHandler) SetReadTracker(tracker *readTracker) {
+	h.readTracker = tracker
+}
+
+// SetDecisionLog records approval decisions into log
+func (h *TurnHandler) SetDecisionLog(log *DecisionLog) {
+	h.decisionLog = log
+}
+
+// SetUserPrompter lets ask_user calls be answered interactively
+func (h *TurnHandler) SetUserPrompter(prompter UserPrompter) {
+	h.userPrompter = prompter
+}
+
+// SetShowReasoning prints the model's reasoning, dimmed, before its answer
+func (h *TurnHandler) SetShowReasoning(show bool) {
+	h.showReasoning = show
+}
+
+// SetTurnOutputBudget truncates tool results once a turn's results add up to
+// more than maxBytes (0 removes the cap)
+func (h *TurnHandler) SetTurnOutputBudget(maxBytes int) {
+	if maxBytes > 0 {
+		h.outputBudget = newTurnOutputBudget(maxBytes)
+	} else {
+		h.outputBudget = nil
+	}
+}
+
+// SetTracer writes a structured record of each event, tool result and
+// approval decision to tracer
+func (h *TurnHandler) SetTracer(tracer *Tracer) {
+	h.tracer = tracer
+}
+
+// SetToolOutputs records what each tool call shows the user, and the diffs
+// of files it changes, into outputs
+func (h *TurnHandler) SetToolOutputs(outputs *ToolOutputs) {
+	h.toolOutputs = outputs
+}
+
+// SetProgressDisplay shows the progress of long-running tools on display
+func (h *TurnHandler) SetProgressDisplay(display ProgressDisplay) {
+	h.progress = display
+}
+
+// SetQuiet hides the model's text and the tools' displays, for callers that
+// only want the final result on stdout
+func (h *TurnHandler) SetQuiet(quiet bool) {
+	h.quiet = quiet
+}
+
+// warn prints a warning, on stderr when quiet so stdout keeps only the result
+func (h *TurnHandler) warn(message string) {
+	var out io.Writer = os.Stdout
+	if h.quiet {
+		out = os.Stderr
+	}
+	fmt.Fprintf(out, "⚠️  %s\n", message)
+}
+
+// SetStagingArea sends file changes to the staging area instead of the project
+func (h *TurnHandler) SetStagingArea(staging *stagingArea) {
+	h.staging = staging
+}
+
+// recordDecision adds an approval decision for the call to the decision log
+func (h *TurnHandler) recordDecision(event ToolCallRequestEvent, approved bool, source DecisionSource, reason string) {
+	decision := ApprovalDecision{
+		CallID:   event.CallID,
+		ToolName: event.Name,
+		Approved: approved,
+		Source:   source,
+		Reason:   reason,
+	}
+	h.decisionLog.Record(decision)
+	h.tracer.traceDecision(decision)
+}
+
 // HandleTurn processes all events from a turn
 func (h *TurnHandler) HandleTurn(ctx context.Context, turn *Turn) error {
 	h.turn = turn
 	h.toolResponses = []openai.ChatCompletionMessage{} // Reset for new turn
+	h.turnExecuted, h.turnFailures = false, nil
+	if h.outputBudget != nil {
+		h.outputBudget.reset()
+	}
 	events := turn.Run(ctx)
 
 	for event := range events {
@@ -50,14 +154,28 @@ func (h *TurnHandler) HandleTurn(ctx context.Context, turn *Turn) error {
 		}
 	}
 
+	if h.turnExecuted {
+		h.failedTools = h.turnFailures
+	}
 	return nil
 }
 
+// UnresolvedToolErrors returns the tools that failed in the last turn that
+// executed any, or nil when that turn ran cleanly
+func (h *TurnHandler) UnresolvedToolErrors() []string {
+	return h.failedTools
+}
+
 // handleEvent processes a single event
 func (h *TurnHandler) handleEvent(ctx context.Context, event Event) error {
+	h.tracer.traceEvent(event)
 	switch e := event.(type) {
+	case LLMRequestEvent:
+		return nil // Only traced
 	case ContentEvent:
 		return h.handleContent(e)
+	case ThoughtEvent:
+		return h.handleThought(e)
 	case ToolCallRequestEvent:
 		return h.handleToolCallRequest(ctx, e)
 	case ToolCallConfirmationEvent:
@@ -66,6 +184,10 @@ func (h *TurnHandler) handleEvent(ctx context.Context, event Event) error {
 		return h.handleError(e)
 	case UserCancelledEvent:
 		return h.handleUserCancelled()
+	case UsageMetadataEvent:
+		return h.handleUsageMetadata(e)
+	case IncompleteToolCallEvent:
+		return h.handleIncompleteToolCall(e)
 	default:
 		log.Printf("Unhandled event type: %T", event)
 		return nil
@@ -74,15 +196,46 @@ func (h *TurnHandler) handleEvent(ctx context.Context, event Event) error {
 
 // handleContent displays content from the LLM
 func (h *TurnHandler) handleContent(event ContentEvent) error {
-	fmt.Println(event.Content)
+	if !h.quiet {
+		fmt.Println(event.Content)
+	}
+	return nil
+}
+
+// handleThought records the model's reasoning and shows it when enabled
+func (h *TurnHandler) handleThought(event ThoughtEvent) error {
+	h.reasoning = append(h.reasoning, event.Description)
+	if h.showReasoning {
+		fmt.Printf("\033[2m💭 %s\033[0m\n", event.Description)
+	}
 	return nil
 }
 
+// Reasoning returns the reasoning captured from every turn handled so far
+func (h *TurnHandler) Reasoning() []string {
+	return h.reasoning
+}
+
+// ChangedFiles returns the paths modified by successful tool calls so far
+func (h *TurnHandler) ChangedFiles() []string {
+	return h.changedFiles
+}
+
+// recordChangedFiles notes the paths a modifying tool call touched
+func (h *TurnHandler) recordChangedFiles(args map[string]interface{}) {
+	for _, path := range argPaths(args) {
+		if !slices.Contains(h.changedFiles, path) {
+			h.changedFiles = append(h.changedFiles, path)
+		}
+	}
+}
+
 // handleToolCallRequest processes a tool call request
 func (h *TurnHandler) handleToolCallRequest(ctx context.Context, event ToolCallRequestEvent) error {
 	// For low-risk tools that don't need confirmation, execute immediately
-	risk := AssessToolCallRisk(event.Name)
+	risk := assessToolRisk(event.Name, h.tools[event.Name])
 	if risk == RiskLow {
+		h.recordDecision(event, true, SourceRiskPolicy, "Low-risk tool runs without confirmation")
 		return h.executeToolCall(ctx, event)
 	}
 
@@ -93,6 +246,12 @@ func (h *TurnHandler) handleToolCallRequest(ctx context.Context, event ToolCallR
 
 // handleToolCallConfirmation handles approval requests
 func (h *TurnHandler) handleToolCallConfirmation(ctx context.Context, event ToolCallConfirmationEvent) error {
+	// Bounce blind edits before bothering the user for approval
+	if h.rejectUnreadEdit(event.Request) {
+		delete(h.pendingApprovals, event.Request.CallID)
+		return nil
+	}
+
 	// Schedule the tool call
 	pendingCalls := h.scheduler.ScheduleToolCalls(ctx, []openai.ToolCall{{
 		ID: event.Request.CallID,
@@ -109,6 +268,14 @@ func (h *TurnHandler) handleToolCallConfirmation(ctx context.Context, event Tool
 		Risks:               map[string]RiskLevel{event.Request.CallID: event.Details.GetRisk()},
 		ConfirmationDetails: event.Details,
 	}
+	if checker, ok := h.tools[event.Request.Name].(tools.DangerChecker); ok {
+		approvalReq.Warning = checker.DangerousCall(event.Request.Args)
+	}
+
+	// Let the user know the agent is blocked on them
+	if checker, ok := h.approver.(promptChecker); ok && h.hookManager != nil && checker.WillPrompt(approvalReq) {
+		h.hookManager.Notify(hooks.NotificationApproval, fmt.Sprintf("Waiting for approval to run %s", event.Request.Name))
+	}
 
 	// Request approval
 	approval, err := h.approver.RequestApproval(ctx, approvalReq)
@@ -116,8 +283,29 @@ func (h *TurnHandler) handleToolCallConfirmation(ctx context.Context, event Tool
 		return fmt.Errorf("approval error: %w", err)
 	}
 
+	// Record who decided and why
+	approved := len(approval.ApprovedIDs) > 0
+	source, reason := approval.Source, approval.Reason
+	if source == "" {
+		source = SourceApprover
+	}
+	if reason == "" {
+		reason = "Rejected by approver"
+		if approved {
+			reason = "Approved by approver"
+		}
+	}
+	// Dangerous calls never run on an automatic approval
+	dangerBlocked := approved && approvalReq.Warning != "" && source != SourceUser
+	if dangerBlocked {
+		approved = false
+		source = SourceDangerPolicy
+		reason = fmt.Sprintf("Dangerous call (%s) was not explicitly approved by the user", approvalReq.Warning)
+	}
+	h.recordDecision(event.Request, approved, source, reason)
+
 	// Process approval response
-	if len(approval.ApprovedIDs) > 0 {
+	if approved {
 		h.scheduler.ApproveCalls(approval.ApprovedIDs)
 		// Execute approved tool
 		if req, exists := h.pendingApprovals[event.Request.CallID]; exists {
@@ -128,11 +316,21 @@ func (h *TurnHandler) handleToolCallConfirmation(ctx context.Context, event Tool
 	} else {
 		// Tool was rejected
 		h.scheduler.RejectCalls([]string{event.Request.CallID})
-		// Add rejection to tool responses
+		// Add rejection to tool responses; feedback lets the model revise and retry
+		content := "Tool call rejected by user"
+		if dangerBlocked && event.Request.Name == "run_shell" {
+			content = fmt.Sprintf("Tool call rejected: the command %s, so it needs explicit approval from the user, which was not given. Use a safer command or ask the user to run it.", approvalReq.Warning)
+		} else if dangerBlocked {
+			content = fmt.Sprintf("Tool call rejected: the call %s, so it needs explicit approval from the user, which was not given. Make a smaller change or ask the user to approve it.", approvalReq.Warning)
+		} else if source == SourcePlanMode {
+			content = planModeRejection
+		} else if approval.Feedback != "" {
+			content = fmt.Sprintf("Tool call rejected by user with feedback: %s\nRevise the change according to this feedback and try again.", approval.Feedback)
+		}
 		h.toolResponses = append(h.toolResponses, openai.ChatCompletionMessage{
 			Role:       "tool",
 			Name:       event.Request.Name,
-			Content:    "Tool call rejected by user",
+			Content:    content,
 			ToolCallID: event.Request.CallID,
 		})
 	}
@@ -149,6 +347,10 @@ func (h *TurnHandler) executeToolCall(ctx context.Context, event ToolCallRequest
 		return fmt.Errorf("tool not found: %s", event.Name)
 	}
 
+	if h.rejectUnreadEdit(event) {
+		return nil
+	}
+
 	// Execute PreToolUse hooks if hook manager is available
 	if h.hookManager != nil {
 		hookInput := hooks.HookInput{
@@ -164,6 +366,7 @@ func (h *TurnHandler) executeToolCall(ctx context.Context, event ToolCallRequest
 		// Check if any hook blocks the tool execution
 		if blocked, reason := h.hookManager.ShouldBlockToolExecution(outputs); blocked {
 			log.Printf("Tool execution blocked by hook: %s", reason)
+			h.recordDecision(event, false, SourceHook, reason)
 			// Add blocked response
 			h.toolResponses = append(h.toolResponses, openai.ChatCompletionMessage{
 				Role:       "tool",
@@ -177,13 +380,33 @@ func (h *TurnHandler) executeToolCall(ctx context.Context, event ToolCallRequest
 		// Check if any hook auto-approves the tool
 		if approved, reason := h.hookManager.ShouldAutoApprove(outputs); approved {
 			log.Printf("Tool auto-approved by hook: %s", reason)
+			h.recordDecision(event, true, SourceHook, reason)
 		}
 	}
 
 	log.Printf("Executing tool: %s (CallID: %s)", event.Name, event.CallID)
 
-	// Execute the tool
-	result, err := tool.Execute(event.Args)
+	// Remember the files a write touches so the transcript can show its diff
+	var snapshot map[string]string
+	if h.toolOutputs != nil && h.staging == nil && !tool.ReadOnly() {
+		snapshot = snapshotFiles(argPaths(event.Args))
+	}
+
+	// Execute the tool. ask_user needs the terminal, so it is answered here
+	// when someone is available to respond.
+	var result *tools.ToolResult
+	var err error
+	if event.Name == "ask_user" && h.userPrompter != nil {
+		result, err = h.askUser(ctx, event)
+	} else if h.staging != nil {
+		var staged tools.Tool
+		var args map[string]interface{}
+		if staged, args, err = h.staging.redirect(tool, event.Args); err == nil {
+			result, err = h.runTool(staged, event.Name, args)
+		}
+	} else {
+		result, err = h.runTool(tool, event.Name, event.Args)
+	}
 	if err != nil {
 		log.Printf("Tool execution failed: %v", err)
 		result = &tools.ToolResult{
@@ -192,17 +415,34 @@ func (h *TurnHandler) executeToolCall(ctx context.Context, event ToolCallRequest
 			Error:         err,
 		}
 	}
+	if result.Error != nil && result.Recovery == "" {
+		result.Recovery = tools.RecoveryHint(result.Error)
+	}
 
 	// Display result to user
-	if result.ReturnDisplay != "" {
+	if result.ReturnDisplay != "" && !h.quiet {
 		fmt.Println(result.ReturnDisplay)
 	}
+	if h.toolOutputs != nil {
+		output := ToolOutput{Display: result.ReturnDisplay}
+		if result.Error == nil {
+			output.Diffs = diffSnapshot(snapshot, argPaths(event.Args))
+		}
+		h.toolOutputs.Record(event.CallID, output)
+	}
 
 	// Create tool response message
 	content := result.LLMContent
 	if result.Error != nil {
 		content = fmt.Sprintf("Error: %v", result.Error)
 	}
+	if h.outputBudget != nil {
+		content = h.outputBudget.fit(content)
+	}
+	// Say how to recover so the model does not repeat the failing call
+	if result.Recovery != "" {
+		content += "\nRecovery: " + result.Recovery
+	}
 
 	toolResponse := openai.ChatCompletionMessage{
 		Role:       "tool",
@@ -210,13 +450,32 @@ func (h *TurnHandler) executeToolCall(ctx context.Context, event ToolCallRequest
 		Content:    content,
 		ToolCallID: event.CallID,
 	}
-
-	// Store the tool response
+	h.tracer.traceEvent(ToolCallResponseEvent{
+		CallID:        event.CallID,
+		Name:          event.Name,
+		Result:        content,
+		ReturnDisplay: result.ReturnDisplay,
+		Error:         result.Error,
+	})
+
+	// Store the tool response; PostToolUse hooks below may add to it
 	h.toolResponses = append(h.toolResponses, toolResponse)
+	responseIndex := len(h.toolResponses) - 1
 	log.Printf("Added tool response for %s (CallID: %s), total responses: %d", event.Name, event.CallID, len(h.toolResponses))
 
 	// Mark as executed in scheduler
 	h.scheduler.MarkExecuted(event.CallID, result, err)
+	h.turnExecuted = true
+	if result.Error != nil {
+		h.turnFailures = append(h.turnFailures, event.Name)
+	}
+
+	if h.readTracker != nil && result.Error == nil {
+		h.readTracker.observe(event.Name, event.Args)
+	}
+	if h.staging == nil && result.Error == nil && !tool.ReadOnly() {
+		h.recordChangedFiles(event.Args)
+	}
 
 	// Execute PostToolUse hooks if hook manager is available
 	if h.hookManager != nil {
@@ -240,35 +499,137 @@ func (h *TurnHandler) executeToolCall(ctx context.Context, event ToolCallRequest
 			log.Printf("PostToolUse hook error: %v", err)
 		}
 
-		// Check if any hook wants to provide feedback
+		// Hook feedback goes into this call's tool message: a separate
+		// message could land between the responses to one assistant
+		// message's tool calls, which the APIs reject
+		response := &h.toolResponses[responseIndex]
 		for _, output := range outputs {
 			if output.Decision == "block" && output.Reason != "" {
-				// Add hook feedback to conversation
-				h.toolResponses = append(h.toolResponses, openai.ChatCompletionMessage{
-					Role:    "system",
-					Content: fmt.Sprintf("Hook feedback: %s", output.Reason),
-				})
+				response.Content += fmt.Sprintf("\n\nHook feedback: %s", output.Reason)
+			}
+		}
+
+		// Plain stdout from a successful hook (e.g. lint warnings) goes back
+		// to the model so it can react to it
+		if additionalContext := h.hookManager.GetAdditionalContext(outputs); additionalContext != "" {
+			response.Content += fmt.Sprintf("\n\nHook output for %s:\n%s", event.Name, strings.TrimRight(additionalContext, "\n"))
+		}
+	}
+
+	return nil
+}
+
+// runTool executes a tool, passing its progress to the display when both
+// support it
+func (h *TurnHandler) runTool(tool tools.Tool, name string, args map[string]interface{}) (*tools.ToolResult, error) {
+	progressTool, ok := tool.(tools.ProgressTool)
+	if !ok || h.progress == nil {
+		return tool.Execute(args)
+	}
+	defer h.progress.Finish(name)
+	return progressTool.ExecuteWithProgress(args, func(update tools.ProgressUpdate) {
+		h.progress.Update(name, update)
+	})
+}
+
+// askUser puts the agent's question to the user and returns the answer as the tool result
+func (h *TurnHandler) askUser(ctx context.Context, event ToolCallRequestEvent) (*tools.ToolResult, error) {
+	question, _ := event.Args["question"].(string)
+	if question == "" {
+		return nil, fmt.Errorf("question is required")
+	}
+	var options []string
+	if raw, ok := event.Args["options"].([]interface{}); ok {
+		for _, o := range raw {
+			if option, ok := o.(string); ok {
+				options = append(options, option)
 			}
 		}
 	}
 
+	answer, err := h.userPrompter.AskUser(ctx, question, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get an answer from the user: %w", err)
+	}
+	if answer == "" {
+		return &tools.ToolResult{LLMContent: "The user did not answer. Make a reasonable assumption, state it, and continue."}, nil
+	}
+	return &tools.ToolResult{LLMContent: fmt.Sprintf("User answered: %s", answer)}, nil
+}
+
+// rejectUnreadEdit answers an edit to a file that was never read with a
+// corrective tool response. It reports whether the call was rejected.
+func (h *TurnHandler) rejectUnreadEdit(event ToolCallRequestEvent) bool {
+	if h.readTracker == nil {
+		return false
+	}
+
+	message, ok := h.readTracker.checkEdit(event.Name, event.Args)
+	if ok {
+		return false
+	}
+
+	log.Printf("Rejected %s without prior read (CallID: %s)", event.Name, event.CallID)
+	h.recordDecision(event, false, SourceReadPolicy, message)
+	h.warn(message)
+	h.scheduler.RejectCalls([]string{event.CallID})
+	h.toolResponses = append(h.toolResponses, openai.ChatCompletionMessage{
+		Role:       "tool",
+		Name:       event.Name,
+		Content:    message,
+		ToolCallID: event.CallID,
+	})
+	return true
+}
+
+// handleIncompleteToolCall answers a truncated tool call with an error so the
+// model retries instead of the call being executed with partial arguments
+func (h *TurnHandler) handleIncompleteToolCall(event IncompleteToolCallEvent) error {
+	h.warn(event.Message)
+	h.toolResponses = append(h.toolResponses, openai.ChatCompletionMessage{
+		Role:       "tool",
+		Name:       event.Name,
+		Content:    "Error: " + event.Message,
+		ToolCallID: event.CallID,
+	})
 	return nil
 }
 
-// handleError handles error events
+// handleError handles error events; quiet callers report the returned error
+// themselves
 func (h *TurnHandler) handleError(event ErrorEvent) error {
 	log.Printf("Error: %s", event.Message)
-	fmt.Printf("❌ Error: %s\n", event.Message)
+	if !h.quiet {
+		fmt.Printf("❌ Error: %s\n", event.Message)
+	}
 	return event.Error
 }
 
 // handleUserCancelled handles cancellation
 func (h *TurnHandler) handleUserCancelled() error {
 	log.Println("User cancelled operation")
-	fmt.Println("❌ Operation cancelled")
+	if !h.quiet {
+		fmt.Println("❌ Operation cancelled")
+	}
 	return fmt.Errorf("cancelled by user")
 }
 
+// handleUsageMetadata accumulates token usage reported by the LLM
+func (h *TurnHandler) handleUsageMetadata(event UsageMetadataEvent) error {
+	h.usage.PromptTokens += event.PromptTokens
+	h.usage.CompletionTokens += event.CompletionTokens
+	h.usage.TotalTokens += event.TotalTokens
+	h.usage.DurationMs += event.DurationMs
+	log.Printf("Token usage: %d prompt + %d completion (%d total so far)",
+		event.PromptTokens, event.CompletionTokens, h.usage.TotalTokens)
+	return nil
+}
+
+// TotalUsage returns the token usage accumulated across all handled turns
+func (h *TurnHandler) TotalUsage() UsageMetadataEvent {
+	return h.usage
+}
+
 // GetToolResponses returns all tool response messages
 func (h *TurnHandler) GetToolResponses() []openai.ChatCompletionMessage {
 	return h.toolResponses
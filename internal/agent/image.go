@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// maxImageBytes caps the size of an attached image; providers reject
+// larger uploads anyway
+const maxImageBytes = 20 * 1024 * 1024
+
+// imageTypes are the formats vision models accept
+var imageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// NewUserMessage builds a user message. Without images it is plain text;
+// with images the text and each image (as a base64 data URL) become parts of
+// a multi-part message.
+func NewUserMessage(text string, imagePaths []string) (openai.ChatCompletionMessage, error) {
+	if len(imagePaths) == 0 {
+		return openai.ChatCompletionMessage{Role: "user", Content: text}, nil
+	}
+
+	parts := []openai.ChatMessagePart{{Type: openai.ChatMessagePartTypeText, Text: text}}
+	for _, path := range imagePaths {
+		url, err := imageDataURL(path)
+		if err != nil {
+			return openai.ChatCompletionMessage{}, err
+		}
+		parts = append(parts, openai.ChatMessagePart{
+			Type:     openai.ChatMessagePartTypeImageURL,
+			ImageURL: &openai.ChatMessageImageURL{URL: url, Detail: openai.ImageURLDetailAuto},
+		})
+	}
+	return openai.ChatCompletionMessage{Role: "user", MultiContent: parts}, nil
+}
+
+// imageDataURL reads an image file and encodes it as a data URL
+func imageDataURL(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read image: %w", err)
+	}
+	if info.Size() > maxImageBytes {
+		return "", fmt.Errorf("image %s is %d bytes, larger than the %d byte limit", path, info.Size(), maxImageBytes)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read image: %w", err)
+	}
+
+	mimeType := http.DetectContentType(data)
+	if !imageTypes[mimeType] {
+		return "", fmt.Errorf("%s is not a PNG, JPEG, GIF or WebP image (detected %s)", path, mimeType)
+	}
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestNewUserMessageAttachesImage(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "screenshot.png")
+	os.WriteFile(path, buf.Bytes(), 0644)
+
+	msg, err := NewUserMessage("Why is this dialog empty?", []string{path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Role != "user" || msg.Content != "" || len(msg.MultiContent) != 2 {
+		t.Fatalf("expected a two-part user message, got %+v", msg)
+	}
+	if part := msg.MultiContent[0]; part.Type != openai.ChatMessagePartTypeText || part.Text != "Why is this dialog empty?" {
+		t.Errorf("unexpected text part %+v", part)
+	}
+	part := msg.MultiContent[1]
+	if part.Type != openai.ChatMessagePartTypeImageURL || part.ImageURL == nil {
+		t.Fatalf("expected an image part, got %+v", part)
+	}
+	encoded, ok := strings.CutPrefix(part.ImageURL.URL, "data:image/png;base64,")
+	if !ok {
+		t.Fatalf("expected a PNG data URL, got %.40s", part.ImageURL.URL)
+	}
+	if decoded, _ := base64.StdEncoding.DecodeString(encoded); !bytes.Equal(decoded, buf.Bytes()) {
+		t.Error("the data URL does not hold the image bytes")
+	}
+
+	text := filepath.Join(dir, "notes.txt")
+	os.WriteFile(text, []byte("not an image"), 0644)
+	if _, err := NewUserMessage("look", []string{text}); err == nil {
+		t.Error("expected a non-image file to be rejected")
+	}
+	if msg, _ := NewUserMessage("plain", nil); msg.Content != "plain" || msg.MultiContent != nil {
+		t.Errorf("expected a plain text message without images, got %+v", msg)
+	}
+}
@@ -4,29 +4,95 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
+// errApprovalTimeout is returned by readChoice when no input arrived in time
+var errApprovalTimeout = errors.New("approval timed out")
+
+// inputLine is a single line (or read failure) delivered by the background reader
+type inputLine struct {
+	text string
+	err  error
+}
+
 // InteractiveApprover implements approval through CLI interaction
 type InteractiveApprover struct {
 	scanner      *bufio.Scanner
 	autoApprove  map[string]bool // Tool names that are auto-approved
 	autoReject   map[string]bool // Tool names that are auto-rejected
 	defaultAllow bool            // Default action when timeout
+	timeout      time.Duration   // How long to wait for a choice (0 waits forever)
+	trustedDirs  []string        // Resolved directories whose operations are auto-approved
+	rules        []approvalRule  // Argument-aware auto-approve rules
+	diffStyle    string          // DiffStyleUnified or DiffStyleSideBySide
+	quiet        bool            // Hide auto-approval notices
+
+	readOnce    sync.Once
+	readReq     chan struct{}  // Asks the background reader for one more line
+	lines       chan inputLine // Lines produced by the background reader
+	readPending bool           // A line has been requested but not yet consumed
 }
 
 // NewInteractiveApprover creates a new interactive approver
 func NewInteractiveApprover() *InteractiveApprover {
+	return NewInteractiveApproverWithInput(os.Stdin)
+}
+
+// NewInteractiveApproverWithInput creates an interactive approver reading choices from r
+func NewInteractiveApproverWithInput(r io.Reader) *InteractiveApprover {
 	return &InteractiveApprover{
-		scanner:     bufio.NewScanner(os.Stdin),
+		scanner:     bufio.NewScanner(r),
 		autoApprove: make(map[string]bool),
 		autoReject:  make(map[string]bool),
 	}
 }
 
+// SetQuiet hides the notices printed when calls are approved or rejected
+// automatically, and execution failures the handler reports anyway.
+// Prompts that need an answer are still shown.
+func (ia *InteractiveApprover) SetQuiet(quiet bool) {
+	ia.quiet = quiet
+}
+
+// notify prints an informational line unless the approver is quiet
+func (ia *InteractiveApprover) notify(message string) {
+	if !ia.quiet {
+		fmt.Println(message)
+	}
+}
+
+// SetDiffStyle chooses how file changes are shown: DiffStyleUnified (the
+// default) or DiffStyleSideBySide
+func (ia *InteractiveApprover) SetDiffStyle(style string) error {
+	switch style {
+	case "", DiffStyleUnified:
+		ia.diffStyle = DiffStyleUnified
+	case DiffStyleSideBySide:
+		ia.diffStyle = DiffStyleSideBySide
+	default:
+		return fmt.Errorf("unknown diff style %q (use %s or %s)", style, DiffStyleUnified, DiffStyleSideBySide)
+	}
+	return nil
+}
+
+// fileDiff renders a file change in the configured diff style
+func (ia *InteractiveApprover) fileDiff(details *ToolFileConfirmationDetails) string {
+	if ia.diffStyle == DiffStyleSideBySide {
+		// Leave room for the indentation of the approval prompt
+		return NewDiffGenerator().GenerateSideBySideDiff(details.OriginalContent, details.NewContent, details.FilePath, terminalWidth()-3)
+	}
+	return details.FileDiff
+}
+
 // SetAutoApprove configures tools that should be automatically approved
 func (ia *InteractiveApprover) SetAutoApprove(toolNames []string) {
 	for _, name := range toolNames {
@@ -41,7 +107,49 @@ func (ia *InteractiveApprover) SetAutoReject(toolNames []string) {
 	}
 }
 
-// RequestApproval prompts the user for approval
+// SetTrustedDirs configures directories in which file operations are
+// auto-approved. High-risk tools such as delete_file still ask. Paths are resolved to absolute,
+// symlink-free form so the check cannot be escaped through a link.
+func (ia *InteractiveApprover) SetTrustedDirs(dirs []string) {
+	ia.trustedDirs = nil
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		if resolved, err := resolveTrustedPath(dir); err == nil {
+			ia.trustedDirs = append(ia.trustedDirs, resolved)
+		}
+	}
+}
+
+// SetTimeout configures how long to wait for a choice before falling back
+// to the default action. A zero timeout waits indefinitely.
+func (ia *InteractiveApprover) SetTimeout(timeout time.Duration, defaultAllow bool) {
+	ia.timeout = timeout
+	ia.defaultAllow = defaultAllow
+}
+
+// WillPrompt reports whether RequestApproval will ask the user, i.e. the
+// calls are not all auto-rejected or all auto-approved
+func (ia *InteractiveApprover) WillPrompt(request ApprovalRequest) bool {
+	rejected, approved := 0, 0
+	for _, call := range request.ToolCalls {
+		switch {
+		case ia.autoReject[call.ToolCall.Function.Name]:
+			rejected++
+		case request.Warning != "":
+			// Dangerous calls always prompt
+		case ia.autoApprove[call.ToolCall.Function.Name], ia.matchesApprovalRule(call), ia.inTrustedDir(call, request.Risks[call.ID]):
+			approved++
+		}
+	}
+	total := len(request.ToolCalls)
+	return total > 0 && rejected != total && approved != total
+}
+
+// RequestApproval prompts the user for approval.
+// If a timeout is configured and no choice is entered in time, the default
+// action is applied to every pending tool call.
 func (ia *InteractiveApprover) RequestApproval(ctx context.Context, request ApprovalRequest) (ApprovalResponse, error) {
 	response := ApprovalResponse{
 		RequestID:   request.RequestID,
@@ -51,6 +159,8 @@ func (ia *InteractiveApprover) RequestApproval(ctx context.Context, request Appr
 
 	// Check for auto-approval/rejection
 	allAutoApproved := true
+	anyTrusted := false
+	anyRule := false
 	for _, call := range request.ToolCalls {
 		toolName := call.ToolCall.Function.Name
 		if ia.autoReject[toolName] {
@@ -58,9 +168,31 @@ func (ia *InteractiveApprover) RequestApproval(ctx context.Context, request Appr
 			response.Reason = fmt.Sprintf("Tool '%s' is configured for auto-rejection", toolName)
 			continue
 		}
-		if !ia.autoApprove[toolName] {
+		if request.Warning != "" {
+			// Dangerous calls are never auto-approved
 			allAutoApproved = false
+			continue
+		}
+		if ia.autoApprove[toolName] {
+			continue
+		}
+		if ia.matchesApprovalRule(call) {
+			anyRule = true
+			continue
 		}
+		if ia.inTrustedDir(call, request.Risks[call.ID]) {
+			anyTrusted = true
+			continue
+		}
+		allAutoApproved = false
+	}
+
+	// If every tool is auto-rejected, there is nothing left to ask about
+	if len(request.ToolCalls) > 0 && len(response.RejectedIDs) == len(request.ToolCalls) {
+		response.Approved = false
+		response.Source = SourceAutoReject
+		ia.notify("❌ Auto-rejected denied operations")
+		return response, nil
 	}
 
 	// If all tools are auto-approved, approve them all
@@ -69,7 +201,19 @@ func (ia *InteractiveApprover) RequestApproval(ctx context.Context, request Appr
 			response.ApprovedIDs = append(response.ApprovedIDs, call.ID)
 		}
 		response.Approved = true
-		fmt.Println("✅ Auto-approved read-only operations")
+		if anyRule {
+			response.Source = SourceApprovalRule
+			response.Reason = "Matched an auto-approve rule"
+			ia.notify("✅ Auto-approved operations matching approval rules")
+		} else if anyTrusted {
+			response.Source = SourcePathRule
+			response.Reason = "All paths are inside a trusted directory"
+			ia.notify("✅ Auto-approved operations in trusted directories")
+		} else {
+			response.Source = SourceAutoApprove
+			response.Reason = "Tool is on the auto-approve list"
+			ia.notify("✅ Auto-approved read-only operations")
+		}
 		return response, nil
 	}
 
@@ -77,6 +221,14 @@ func (ia *InteractiveApprover) RequestApproval(ctx context.Context, request Appr
 	fmt.Println("\n" + strings.Repeat("─", 60))
 	fmt.Println("🔧 TOOL APPROVAL REQUEST")
 	fmt.Println(strings.Repeat("─", 60))
+	if request.Warning != "" {
+		label := "DANGEROUS CALL"
+		if len(request.ToolCalls) > 0 && request.ToolCalls[0].ToolCall.Function.Name == "run_shell" {
+			label = "DANGEROUS COMMAND"
+		}
+		fmt.Println(Colorize(fmt.Sprintf("\n⚠️  %s: %s", label, request.Warning), TermColors.Red+TermColors.Bold))
+		fmt.Println(Colorize("   It runs only if you approve it here; auto-approval does not apply.", TermColors.Red))
+	}
 
 	for i, call := range request.ToolCalls {
 		if ia.autoReject[call.ToolCall.Function.Name] {
@@ -97,7 +249,7 @@ func (ia *InteractiveApprover) RequestApproval(ctx context.Context, request Appr
 				if !fileDetails.IsNewFile && fileDetails.FileDiff != "" {
 					fmt.Println("   Preview of changes:")
 					// Show first few lines of the diff
-					diffLines := strings.Split(fileDetails.FileDiff, "\n")
+					diffLines := strings.Split(ia.fileDiff(fileDetails), "\n")
 					maxLines := 10
 					for j, line := range diffLines {
 						if j >= maxLines && j < len(diffLines)-3 {
@@ -121,6 +273,10 @@ func (ia *InteractiveApprover) RequestApproval(ctx context.Context, request Appr
 						fmt.Printf("   ... (%d more lines) ...\n", len(contentLines)-5)
 					}
 				}
+			} else if agentDetails, ok := request.ConfirmationDetails.(*ToolAgentConfirmationDetails); ok {
+				printAgentDetails(agentDetails, 10)
+			} else if customDetails, ok := request.ConfirmationDetails.(*ToolCustomConfirmationDetails); ok {
+				printCustomDetails(customDetails, 10)
 			} else {
 				// For non-file operations, show arguments as before
 				var args map[string]interface{}
@@ -135,6 +291,9 @@ func (ia *InteractiveApprover) RequestApproval(ctx context.Context, request Appr
 						fmt.Printf("   - %s: %s\n", key, valueStr)
 					}
 				}
+				if execDetails, ok := request.ConfirmationDetails.(*ToolExecConfirmationDetails); ok {
+					printExecScope(execDetails)
+				}
 			}
 		} else {
 			// Fallback to showing arguments
@@ -157,15 +316,23 @@ func (ia *InteractiveApprover) RequestApproval(ctx context.Context, request Appr
 	fmt.Println("Options:")
 	fmt.Println("  y/yes    - Approve all")
 	fmt.Println("  n/no     - Reject all")
+	fmt.Println("  a/always - Approve and always allow these tools for this session")
+	fmt.Println("  d/deny   - Reject and always deny these tools for this session")
+	fmt.Println("  f/feedback - Reject and tell the agent what to change")
 	fmt.Println("  s/select - Choose individual tools")
 	fmt.Println("  i/info   - Show more details")
-	fmt.Print("\nYour choice [y/n/s/i]: ")
+	fmt.Printf("\nYour choice [y/n/a/d/f/s/i]%s: ", ia.timeoutHint())
 
-	if !ia.scanner.Scan() {
-		return response, fmt.Errorf("failed to read user input")
+	line, err := ia.readChoice(ctx)
+	if errors.Is(err, errApprovalTimeout) {
+		return ia.applyDefault(request, response), nil
+	}
+	if err != nil {
+		return response, err
 	}
 
-	input := strings.ToLower(strings.TrimSpace(ia.scanner.Text()))
+	input := strings.ToLower(strings.TrimSpace(line))
+	response.Source = SourceUser
 
 	switch input {
 	case "y", "yes":
@@ -175,6 +342,7 @@ func (ia *InteractiveApprover) RequestApproval(ctx context.Context, request Appr
 			}
 		}
 		response.Approved = true
+		response.Reason = "User approved all tool calls"
 		fmt.Println("✅ All tools approved")
 
 	case "n", "no":
@@ -185,8 +353,44 @@ func (ia *InteractiveApprover) RequestApproval(ctx context.Context, request Appr
 		response.Reason = "User rejected all tool calls"
 		fmt.Println("❌ All tools rejected")
 
+	case "a", "always":
+		for _, call := range request.ToolCalls {
+			toolName := call.ToolCall.Function.Name
+			if !ia.autoReject[toolName] {
+				response.ApprovedIDs = append(response.ApprovedIDs, call.ID)
+				ia.autoApprove[toolName] = true
+			}
+		}
+		response.Approved = true
+		response.Reason = "User approved all tool calls and allowed them for this session"
+		fmt.Println("✅ All tools approved (will be auto-approved for the rest of this session)")
+
+	case "d", "deny":
+		for _, call := range request.ToolCalls {
+			response.RejectedIDs = append(response.RejectedIDs, call.ID)
+			ia.autoReject[call.ToolCall.Function.Name] = true
+		}
+		response.Approved = false
+		response.Reason = "User rejected all tool calls and denied them for this session"
+		fmt.Println("❌ All tools rejected (will be auto-rejected for the rest of this session)")
+
+	case "f", "feedback":
+		for _, call := range request.ToolCalls {
+			response.RejectedIDs = append(response.RejectedIDs, call.ID)
+		}
+		response.Approved = false
+		response.Reason = "User rejected all tool calls with feedback"
+
+		fmt.Printf("What should be changed?%s: ", ia.timeoutHint())
+		feedback, err := ia.readChoice(ctx)
+		if err != nil && !errors.Is(err, errApprovalTimeout) {
+			return response, err
+		}
+		response.Feedback = strings.TrimSpace(feedback)
+		fmt.Println("❌ All tools rejected; the agent will revise them using your feedback")
+
 	case "s", "select":
-		response = ia.selectiveApproval(request)
+		response = ia.selectiveApproval(ctx, request)
 
 	case "i", "info":
 		ia.showDetailedInfo(request)
@@ -201,7 +405,7 @@ func (ia *InteractiveApprover) RequestApproval(ctx context.Context, request Appr
 }
 
 // selectiveApproval allows the user to choose individual tools
-func (ia *InteractiveApprover) selectiveApproval(request ApprovalRequest) ApprovalResponse {
+func (ia *InteractiveApprover) selectiveApproval(ctx context.Context, request ApprovalRequest) ApprovalResponse {
 	response := ApprovalResponse{
 		RequestID:   request.RequestID,
 		ApprovedIDs: []string{},
@@ -209,14 +413,18 @@ func (ia *InteractiveApprover) selectiveApproval(request ApprovalRequest) Approv
 	}
 
 	fmt.Println("\nEnter the numbers of tools to approve (comma-separated), or 'all' for all, 'none' for none:")
-	fmt.Print("Your selection: ")
+	fmt.Printf("Your selection%s: ", ia.timeoutHint())
 
-	if !ia.scanner.Scan() {
+	line, err := ia.readChoice(ctx)
+	if errors.Is(err, errApprovalTimeout) {
+		return ia.applyDefault(request, response)
+	}
+	if err != nil {
 		fmt.Println("Error reading input")
 		return response
 	}
 
-	input := strings.ToLower(strings.TrimSpace(ia.scanner.Text()))
+	input := strings.ToLower(strings.TrimSpace(line))
 
 	if input == "all" {
 		for _, call := range request.ToolCalls {
@@ -251,12 +459,167 @@ func (ia *InteractiveApprover) selectiveApproval(request ApprovalRequest) Approv
 		response.Approved = len(response.ApprovedIDs) > 0
 	}
 
+	response.Source = SourceUser
+	response.Reason = "User selected the tool calls to approve"
 	fmt.Printf("✅ Approved %d tools, ❌ Rejected %d tools\n",
 		len(response.ApprovedIDs), len(response.RejectedIDs))
 
 	return response
 }
 
+// readChoice waits for one line of input, honouring ctx and the configured timeout.
+// A countdown is printed while waiting so unattended runs show why they pause.
+func (ia *InteractiveApprover) readChoice(ctx context.Context) (string, error) {
+	return ia.readLine(ctx, ia.timeout)
+}
+
+// readLine waits for one line of input from the shared background reader.
+// A zero timeout waits until ctx is done.
+func (ia *InteractiveApprover) readLine(ctx context.Context, timeout time.Duration) (string, error) {
+	ia.readOnce.Do(func() {
+		ia.readReq = make(chan struct{}, 1)
+		ia.lines = make(chan inputLine, 1)
+		go ia.readLoop()
+	})
+
+	// Only request a new line if the previous request was not left unanswered
+	if !ia.readPending {
+		ia.readReq <- struct{}{}
+		ia.readPending = true
+	}
+
+	var timeoutC, tickC <-chan time.Time
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timeoutC = timer.C
+
+		ticker := time.NewTicker(countdownInterval(timeout))
+		defer ticker.Stop()
+		tickC = ticker.C
+	}
+
+	for {
+		select {
+		case line := <-ia.lines:
+			ia.readPending = false
+			if line.err != nil {
+				return "", fmt.Errorf("failed to read user input: %w", line.err)
+			}
+			return line.text, nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-timeoutC:
+			fmt.Println()
+			return "", errApprovalTimeout
+		case <-tickC:
+			remaining := time.Until(deadline).Round(time.Second)
+			if remaining > 0 {
+				fmt.Printf("\n⏳ %s left before %s... ", remaining, ia.defaultActionName())
+			}
+		}
+	}
+}
+
+// readLoop reads one line from the input per request so that no input is
+// consumed while the approver is not waiting for a choice
+func (ia *InteractiveApprover) readLoop() {
+	for range ia.readReq {
+		if ia.scanner.Scan() {
+			ia.lines <- inputLine{text: ia.scanner.Text()}
+			continue
+		}
+		err := ia.scanner.Err()
+		if err == nil {
+			err = io.EOF
+		}
+		ia.lines <- inputLine{err: err}
+	}
+}
+
+// ReadLine waits for the next line of the approver's input, without a
+// timeout. The interactive session reads its prompts through it so the
+// terminal has a single reader: a line typed after an approval timed out
+// reaches the next prompt instead of answering a later approval.
+func (ia *InteractiveApprover) ReadLine(ctx context.Context) (string, error) {
+	return ia.readLine(ctx, 0)
+}
+
+// AskUser prints a question from the agent and waits for a free-form answer.
+// When options are given, the user may answer with an option's number.
+// The approval timeout does not apply: the agent cannot proceed without an answer.
+func (ia *InteractiveApprover) AskUser(ctx context.Context, question string, options []string) (string, error) {
+	fmt.Printf("\n❓ The agent has a question:\n   %s\n", question)
+	for i, option := range options {
+		fmt.Printf("   %d. %s\n", i+1, option)
+	}
+	fmt.Print("Your answer: ")
+
+	answer, err := ia.readLine(ctx, 0)
+	if err != nil {
+		return "", err
+	}
+	answer = strings.TrimSpace(answer)
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+		answer = options[n-1]
+	}
+	return answer, nil
+}
+
+// countdownInterval picks how often the remaining time is printed
+func countdownInterval(timeout time.Duration) time.Duration {
+	switch {
+	case timeout > time.Minute:
+		return 30 * time.Second
+	case timeout > 10*time.Second:
+		return 10 * time.Second
+	default:
+		return time.Second
+	}
+}
+
+// timeoutHint renders the prompt suffix describing the timeout, if any
+func (ia *InteractiveApprover) timeoutHint() string {
+	if ia.timeout <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%s in %s)", ia.defaultActionName(), ia.timeout.Round(time.Second))
+}
+
+// defaultActionName describes what happens when the prompt times out
+func (ia *InteractiveApprover) defaultActionName() string {
+	if ia.defaultAllow {
+		return "auto-approve"
+	}
+	return "auto-reject"
+}
+
+// applyDefault resolves a timed-out request using the default action
+func (ia *InteractiveApprover) applyDefault(request ApprovalRequest, response ApprovalResponse) ApprovalResponse {
+	response.ApprovedIDs = []string{}
+	response.RejectedIDs = []string{}
+	for _, call := range request.ToolCalls {
+		if ia.defaultAllow && request.Warning == "" && !ia.autoReject[call.ToolCall.Function.Name] {
+			response.ApprovedIDs = append(response.ApprovedIDs, call.ID)
+		} else {
+			response.RejectedIDs = append(response.RejectedIDs, call.ID)
+		}
+	}
+	response.Approved = len(response.ApprovedIDs) > 0
+	response.Source = SourceTimeout
+
+	if response.Approved {
+		response.Reason = "Approval timed out; default action approved the tool calls"
+		fmt.Println("⏰ No choice entered in time, tools approved by default")
+	} else {
+		response.Reason = "Approval timed out; default action rejected the tool calls"
+		fmt.Println("⏰ No choice entered in time, tools rejected by default")
+	}
+	return response
+}
+
 // showDetailedInfo displays detailed information about the tool calls
 func (ia *InteractiveApprover) showDetailedInfo(request ApprovalRequest) {
 	fmt.Println("\n" + strings.Repeat("═", 60))
@@ -286,12 +649,20 @@ func (ia *InteractiveApprover) showDetailedInfo(request ApprovalRequest) {
 				} else {
 					fmt.Println("\n   Full diff:")
 					fmt.Println(strings.Repeat("-", 50))
-					fmt.Println(fileDetails.FileDiff)
+					fmt.Println(ia.fileDiff(fileDetails))
 					fmt.Println(strings.Repeat("-", 50))
 				}
 			} else if execDetails, ok := request.ConfirmationDetails.(*ToolExecConfirmationDetails); ok {
 				fmt.Printf("   Command: %s\n", execDetails.Command)
 				fmt.Printf("   Working Directory: %s\n", execDetails.WorkingDir)
+				if len(execDetails.Env) > 0 {
+					fmt.Printf("   Environment: %s\n", strings.Join(execDetails.Env, " "))
+				}
+				printExecScope(execDetails)
+			} else if agentDetails, ok := request.ConfirmationDetails.(*ToolAgentConfirmationDetails); ok {
+				printAgentDetails(agentDetails, 0)
+			} else if customDetails, ok := request.ConfirmationDetails.(*ToolCustomConfirmationDetails); ok {
+				printCustomDetails(customDetails, 0)
 			} else {
 				// For other tools, show arguments
 				var args map[string]interface{}
@@ -326,13 +697,78 @@ func (ia *InteractiveApprover) showDetailedInfo(request ApprovalRequest) {
 		}
 	}
 
+	fmt.Println("\nSession auto-approval settings:")
+	fmt.Printf("   Auto-approved tools: %s\n", formatToolSet(ia.autoApprove))
+	fmt.Printf("   Auto-rejected tools: %s\n", formatToolSet(ia.autoReject))
+
 	fmt.Println("\n" + strings.Repeat("═", 60))
 }
 
+// printExecScope shows how many files a command is estimated to touch
+func printExecScope(details *ToolExecConfirmationDetails) {
+	for _, scope := range details.Scope {
+		fmt.Printf("   %s\n", Colorize(scope, TermColors.Yellow))
+	}
+}
+
+// printAgentDetails shows what a sub-agent will be asked to do. maxLines
+// limits the prompt preview (0 shows all of it).
+func printAgentDetails(details *ToolAgentConfirmationDetails, maxLines int) {
+	fmt.Printf("   %s\n", details.Title())
+	fmt.Printf("   Agent Type: %s\n", details.AgentType)
+	if details.MayWrite {
+		fmt.Println("   ⚠️  This sub-agent may modify files and run commands without asking")
+	} else {
+		fmt.Println("   This sub-agent only uses read-only tools")
+	}
+
+	fmt.Println("   Prompt:")
+	lines := strings.Split(details.Prompt, "\n")
+	for j, line := range lines {
+		if maxLines > 0 && j == maxLines {
+			fmt.Printf("   ... (%d more lines) ...\n", len(lines)-maxLines)
+			break
+		}
+		fmt.Printf("   │ %s\n", line)
+	}
+}
+
+// printCustomDetails shows the details a tool provided for its own call.
+// maxLines limits the preview (0 shows all of it).
+func printCustomDetails(details *ToolCustomConfirmationDetails, maxLines int) {
+	fmt.Printf("   %s\n", details.Title())
+	if details.Preview == "" {
+		return
+	}
+	lines := strings.Split(strings.TrimRight(details.Preview, "\n"), "\n")
+	for j, line := range lines {
+		if maxLines > 0 && j == maxLines {
+			fmt.Printf("   ... (%d more lines) ...\n", len(lines)-maxLines)
+			break
+		}
+		fmt.Printf("   │ %s\n", line)
+	}
+}
+
+// formatToolSet renders a tool name set as a sorted, comma-separated list
+func formatToolSet(set map[string]bool) string {
+	names := make([]string, 0, len(set))
+	for name, enabled := range set {
+		if enabled {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "(none)"
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
 // NotifyExecution notifies about tool execution results
 func (ia *InteractiveApprover) NotifyExecution(toolCallID string, result interface{}, err error) {
 	if err != nil {
-		fmt.Printf("❌ Tool execution failed (ID: %s): %v\n", toolCallID, err)
+		ia.notify(fmt.Sprintf("❌ Tool execution failed (ID: %s): %v", toolCallID, err))
 	} else {
 		// Silent success - execution results will be shown by the tool itself
 	}
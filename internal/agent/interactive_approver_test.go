@@ -0,0 +1,511 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/tools"
+)
+
+func newTestApprovalRequest(id, toolName string) ApprovalRequest {
+	return ApprovalRequest{
+		RequestID: id,
+		ToolCalls: []*PendingToolCall{
+			{
+				ID: id,
+				ToolCall: openai.ToolCall{
+					ID: id,
+					Function: openai.FunctionCall{
+						Name:      toolName,
+						Arguments: `{"command":"ls"}`,
+					},
+				},
+			},
+		},
+		Risks: map[string]RiskLevel{id: RiskHigh},
+	}
+}
+
+func TestInteractiveApproverAlwaysAllow(t *testing.T) {
+	// Only one line of input: the second request must not need to read anything
+	approver := NewInteractiveApproverWithInput(strings.NewReader("a\n"))
+
+	first, err := approver.RequestApproval(context.Background(), newTestApprovalRequest("call-1", "run_shell"))
+	if err != nil {
+		t.Fatalf("first approval failed: %v", err)
+	}
+	if !first.Approved || len(first.ApprovedIDs) != 1 {
+		t.Fatalf("expected first call to be approved, got %+v", first)
+	}
+
+	second, err := approver.RequestApproval(context.Background(), newTestApprovalRequest("call-2", "run_shell"))
+	if err != nil {
+		t.Fatalf("second approval should be automatic, got error: %v", err)
+	}
+	if !second.Approved || len(second.ApprovedIDs) != 1 || second.ApprovedIDs[0] != "call-2" {
+		t.Errorf("expected second call to be auto-approved, got %+v", second)
+	}
+}
+
+func TestInteractiveApproverAlwaysDeny(t *testing.T) {
+	approver := NewInteractiveApproverWithInput(strings.NewReader("d\n"))
+
+	first, err := approver.RequestApproval(context.Background(), newTestApprovalRequest("call-1", "run_shell"))
+	if err != nil {
+		t.Fatalf("first approval failed: %v", err)
+	}
+	if first.Approved || len(first.RejectedIDs) != 1 {
+		t.Fatalf("expected first call to be rejected, got %+v", first)
+	}
+
+	second, err := approver.RequestApproval(context.Background(), newTestApprovalRequest("call-2", "run_shell"))
+	if err != nil {
+		t.Fatalf("second approval should be automatic, got error: %v", err)
+	}
+	if second.Approved || len(second.RejectedIDs) != 1 {
+		t.Errorf("expected second call to be auto-rejected, got %+v", second)
+	}
+}
+
+func TestInteractiveApproverTimeoutUsesDefault(t *testing.T) {
+	// A pipe that is never written to simulates an unattended terminal
+	reader, writer := io.Pipe()
+	defer writer.Close()
+
+	approver := NewInteractiveApproverWithInput(reader)
+	approver.SetTimeout(50*time.Millisecond, false)
+
+	response, err := approver.RequestApproval(context.Background(), newTestApprovalRequest("call-1", "run_shell"))
+	if err != nil {
+		t.Fatalf("expected timeout to resolve with the default action, got error: %v", err)
+	}
+	if response.Approved || len(response.RejectedIDs) != 1 {
+		t.Fatalf("expected default reject, got %+v", response)
+	}
+
+	approver.SetTimeout(50*time.Millisecond, true)
+	response, err = approver.RequestApproval(context.Background(), newTestApprovalRequest("call-2", "run_shell"))
+	if err != nil {
+		t.Fatalf("second approval failed: %v", err)
+	}
+	if !response.Approved || len(response.ApprovedIDs) != 1 {
+		t.Fatalf("expected default approve, got %+v", response)
+	}
+}
+
+func TestLineAfterApprovalTimeoutGoesToNextPrompt(t *testing.T) {
+	reader, writer := io.Pipe()
+	defer writer.Close()
+
+	approver := NewInteractiveApproverWithInput(reader)
+	approver.SetTimeout(50*time.Millisecond, false)
+	captureStdout(t, func() {
+		if response, err := approver.RequestApproval(context.Background(), newTestApprovalRequest("call-1", "run_shell")); err != nil || response.Approved {
+			t.Fatalf("expected the timed out call to be rejected, got %+v (%v)", response, err)
+		}
+	})
+
+	// The user types their next prompt once the session is back at "> "
+	go writer.Write([]byte("yes, now refactor the parser\n"))
+	line, err := approver.ReadLine(context.Background())
+	if err != nil || line != "yes, now refactor the parser" {
+		t.Fatalf("expected the session to read the prompt, got %q (%v)", line, err)
+	}
+
+	// The prompt was not left behind to answer the next approval
+	go writer.Write([]byte("n\n"))
+	captureStdout(t, func() {
+		approver.SetTimeout(0, false)
+		if response, err := approver.RequestApproval(context.Background(), newTestApprovalRequest("call-2", "run_shell")); err != nil || response.Approved {
+			t.Errorf("expected the next approval to read its own answer, got %+v (%v)", response, err)
+		}
+	})
+}
+
+func TestInteractiveApproverRespectsContext(t *testing.T) {
+	reader, writer := io.Pipe()
+	defer writer.Close()
+
+	approver := NewInteractiveApproverWithInput(reader)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := approver.RequestApproval(ctx, newTestApprovalRequest("call-1", "run_shell"))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context cancellation error, got %v", err)
+	}
+}
+
+func newWriteApprovalRequest(id, path string) ApprovalRequest {
+	request := newTestApprovalRequest(id, "write_file")
+	request.ToolCalls[0].ToolCall.Function.Arguments = fmt.Sprintf(`{"path":%q,"content":"x"}`, path)
+	request.Risks[id] = RiskMedium
+	return request
+}
+
+func TestInteractiveApproverTrustedDirs(t *testing.T) {
+	trusted := t.TempDir()
+	outside := t.TempDir()
+
+	// The only input line answers the prompt for the write outside the trusted dir
+	approver := NewInteractiveApproverWithInput(strings.NewReader("n\n"))
+	approver.SetTrustedDirs([]string{trusted})
+
+	inside, err := approver.RequestApproval(context.Background(), newWriteApprovalRequest("call-1", filepath.Join(trusted, "sub", "new.go")))
+	if err != nil {
+		t.Fatalf("approval failed: %v", err)
+	}
+	if !inside.Approved || len(inside.ApprovedIDs) != 1 {
+		t.Fatalf("expected the write under the trusted dir to be auto-approved, got %+v", inside)
+	}
+
+	other, err := approver.RequestApproval(context.Background(), newWriteApprovalRequest("call-2", filepath.Join(outside, "new.go")))
+	if err != nil {
+		t.Fatalf("approval failed: %v", err)
+	}
+	if other.Approved || len(other.RejectedIDs) != 1 {
+		t.Errorf("expected the write outside the trusted dir to prompt and be rejected, got %+v", other)
+	}
+}
+
+func TestTrustedDirsIgnoreEscapes(t *testing.T) {
+	trusted := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(trusted, "link")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	approver := NewInteractiveApprover()
+	approver.SetTrustedDirs([]string{trusted})
+
+	for _, path := range []string{
+		filepath.Join(trusted, "..", filepath.Base(outside), "file.go"),
+		filepath.Join(trusted, "link", "file.go"),
+	} {
+		call := newWriteApprovalRequest("call", path).ToolCalls[0]
+		if approver.inTrustedDir(call, RiskMedium) {
+			t.Errorf("expected %s not to be trusted", path)
+		}
+	}
+
+	shell := newTestApprovalRequest("call", "run_shell").ToolCalls[0]
+	if approver.inTrustedDir(shell, RiskHigh) {
+		t.Error("calls without a path should never be trusted")
+	}
+
+	remove := newTestApprovalRequest("call", "delete_file").ToolCalls[0]
+	remove.ToolCall.Function.Arguments = jsonString(map[string]interface{}{"path": filepath.Join(trusted, "file.go")})
+	if approver.inTrustedDir(remove, RiskHigh) {
+		t.Error("deletes should always ask, even in a trusted directory")
+	}
+
+	alias := newWriteApprovalRequest("call", filepath.Join(trusted, "file.go")).ToolCalls[0]
+	alias.ToolCall.Function.Name = "clean"
+	if approver.inTrustedDir(alias, RiskHigh) {
+		t.Error("aliases of high-risk tools should always ask, even in a trusted directory")
+	}
+}
+
+func TestInteractiveApproverFeedback(t *testing.T) {
+	approver := NewInteractiveApproverWithInput(strings.NewReader("f\n  use a context timeout  \n"))
+
+	response, err := approver.RequestApproval(context.Background(), newTestApprovalRequest("call-1", "run_shell"))
+	if err != nil {
+		t.Fatalf("approval failed: %v", err)
+	}
+	if response.Approved || len(response.RejectedIDs) != 1 {
+		t.Fatalf("expected the call to be rejected, got %+v", response)
+	}
+	if response.Feedback != "use a context timeout" {
+		t.Errorf("unexpected feedback: %q", response.Feedback)
+	}
+}
+
+// captureStdout returns everything fn prints to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+
+	w.Close()
+	os.Stdout = stdout
+	out, _ := io.ReadAll(r)
+	return string(out)
+}
+
+func TestInteractiveApproverAskUser(t *testing.T) {
+	approver := NewInteractiveApproverWithInput(strings.NewReader("2\n"))
+
+	var answer string
+	var err error
+	output := captureStdout(t, func() {
+		answer, err = approver.AskUser(context.Background(), "Which database should I use?", []string{"sqlite", "postgres"})
+	})
+	if err != nil {
+		t.Fatalf("AskUser failed: %v", err)
+	}
+	if !strings.Contains(output, "Which database should I use?") || !strings.Contains(output, "2. postgres") {
+		t.Errorf("expected the question and options to be shown, got %q", output)
+	}
+	if answer != "postgres" {
+		t.Errorf("expected the numbered option to be resolved, got %q", answer)
+	}
+}
+
+func newShellApprovalRequest(id, command string) ApprovalRequest {
+	request := newTestApprovalRequest(id, "run_shell")
+	request.ToolCalls[0].ToolCall.Function.Arguments = fmt.Sprintf(`{"command":%q}`, command)
+	return request
+}
+
+func TestApprovalRuleMatchesCommandPattern(t *testing.T) {
+	// One input line answers the prompt for the command that does not match
+	approver := NewInteractiveApproverWithInput(strings.NewReader("n\n"))
+	if err := approver.SetApprovalRules([]ApprovalRule{{Tool: "run_shell", Command: `npm test|go build( \./\.\.\.)?`}}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, command := range []string{"npm test", "go build ./..."} {
+		response, err := approver.RequestApproval(context.Background(), newShellApprovalRequest("call-1", command))
+		if err != nil {
+			t.Fatalf("approval failed: %v", err)
+		}
+		if !response.Approved || response.Source != SourceApprovalRule {
+			t.Errorf("expected %q to be auto-approved by the rule, got %+v", command, response)
+		}
+	}
+
+	// The pattern must match the whole command
+	response, err := approver.RequestApproval(context.Background(), newShellApprovalRequest("call-2", "npm test && rm -rf /"))
+	if err != nil {
+		t.Fatalf("approval failed: %v", err)
+	}
+	if response.Approved || response.Source != SourceUser {
+		t.Errorf("expected the chained command to prompt and be rejected, got %+v", response)
+	}
+}
+
+func TestApprovalRuleIgnoresCallsWithEnv(t *testing.T) {
+	approver := NewInteractiveApproverWithInput(strings.NewReader("n\n"))
+	if err := approver.SetApprovalRules([]ApprovalRule{{Tool: "run_shell", Command: `go test \./\.\.\.`}}); err != nil {
+		t.Fatal(err)
+	}
+	request := newShellApprovalRequest("call-1", "go test ./...")
+	request.ToolCalls[0].ToolCall.Function.Arguments = `{"command":"go test ./...","env":{"GOFLAGS":"-toolexec=/tmp/x"}}`
+	response, err := approver.RequestApproval(context.Background(), request)
+	if err != nil {
+		t.Fatalf("approval failed: %v", err)
+	}
+	if response.Approved || response.Source == SourceApprovalRule {
+		t.Errorf("expected a call with env not to match the command rule, got %+v", response)
+	}
+}
+
+func TestApprovalRulePathPrefixRejectsOutOfScopeWrite(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "src")
+
+	approver := NewInteractiveApproverWithInput(strings.NewReader("n\n"))
+	if err := approver.SetApprovalRules([]ApprovalRule{{Tool: "write_file", Path: src}}); err != nil {
+		t.Fatal(err)
+	}
+
+	inside, err := approver.RequestApproval(context.Background(), newWriteApprovalRequest("call-1", filepath.Join(src, "main.go")))
+	if err != nil {
+		t.Fatalf("approval failed: %v", err)
+	}
+	if !inside.Approved || inside.Source != SourceApprovalRule {
+		t.Fatalf("expected the write under src to be auto-approved, got %+v", inside)
+	}
+
+	outside, err := approver.RequestApproval(context.Background(), newWriteApprovalRequest("call-2", filepath.Join(src, "..", "main.go")))
+	if err != nil {
+		t.Fatalf("approval failed: %v", err)
+	}
+	if outside.Approved || len(outside.RejectedIDs) != 1 {
+		t.Errorf("expected the out-of-scope write to prompt and be rejected, got %+v", outside)
+	}
+}
+
+func TestSetApprovalRulesRejectsInvalidPatterns(t *testing.T) {
+	approver := NewInteractiveApproverWithInput(strings.NewReader(""))
+	if err := approver.SetApprovalRules([]ApprovalRule{{Tool: "run_shell", Command: "("}}); err == nil {
+		t.Error("expected an invalid regex to be rejected")
+	}
+	if err := approver.SetApprovalRules([]ApprovalRule{{Command: "ls"}}); err == nil {
+		t.Error("expected a rule without a tool to be rejected")
+	}
+}
+
+func TestAgentToolConfirmationShowsTypeAndPrompt(t *testing.T) {
+	args := map[string]interface{}{
+		"description": "Audit error handling",
+		"prompt":      "Review internal/llm for ignored errors.\nReport file and line for each.",
+		"agent_type":  "executor",
+	}
+	turn := NewTurn(&fakeLLMClient{}, nil, nil, &NoOpDebugger{})
+	details, ok := turn.createConfirmationDetails("agent_tool", args, RiskMedium).(*ToolAgentConfirmationDetails)
+	if !ok {
+		t.Fatalf("expected sub-agent confirmation details, got %T", details)
+	}
+	if details.AgentType != "executor" || details.Description != "Audit error handling" || !details.MayWrite {
+		t.Errorf("unexpected details: %+v", details)
+	}
+
+	request := newTestApprovalRequest("call-1", "agent_tool")
+	request.ToolCalls[0].ToolCall.Function.Arguments = jsonString(args)
+	request.ConfirmationDetails = details
+
+	approver := NewInteractiveApproverWithInput(strings.NewReader("y\n"))
+	output := captureStdout(t, func() {
+		if _, err := approver.RequestApproval(context.Background(), request); err != nil {
+			t.Fatalf("approval failed: %v", err)
+		}
+	})
+	for _, want := range []string{
+		"Launch executor sub-agent: Audit error handling",
+		"Agent Type: executor",
+		"may modify files and run commands",
+		"│ Review internal/llm for ignored errors.",
+		"│ Report file and line for each.",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in the approval prompt, got:\n%s", want, output)
+		}
+	}
+
+	searcher := turn.createConfirmationDetails("agent_tool", map[string]interface{}{"agent_type": "searcher"}, RiskMedium).(*ToolAgentConfirmationDetails)
+	if searcher.MayWrite {
+		t.Error("expected searcher sub-agents not to be flagged as writing")
+	}
+}
+
+// moveTool describes its calls itself through tools.ConfirmationDetailer
+type moveTool struct{}
+
+func (moveTool) Name() string                          { return "move_file" }
+func (moveTool) Description() string                   { return "Move a file" }
+func (moveTool) ReadOnly() bool                        { return false }
+func (moveTool) GetParameters() map[string]interface{} { return nil }
+func (moveTool) Execute(args map[string]interface{}) (*tools.ToolResult, error) {
+	return &tools.ToolResult{}, nil
+}
+
+func (moveTool) ConfirmationDetails(args map[string]interface{}) *tools.ConfirmationDetails {
+	return &tools.ConfirmationDetails{
+		Title:   fmt.Sprintf("Move %v → %v", args["source"], args["destination"]),
+		Preview: "overwrites the destination if it exists",
+	}
+}
+
+func TestToolProvidedConfirmationDetails(t *testing.T) {
+	args := map[string]interface{}{"source": "a.txt", "destination": "b.txt"}
+	turn := NewTurn(&fakeLLMClient{}, map[string]tools.Tool{"move_file": moveTool{}}, nil, &NoOpDebugger{})
+
+	details, ok := turn.createConfirmationDetails("move_file", args, RiskMedium).(*ToolCustomConfirmationDetails)
+	if !ok {
+		t.Fatalf("expected the tool's own confirmation details, got %T", details)
+	}
+	if details.Title() != "Move a.txt → b.txt" || details.GetRisk() != RiskMedium {
+		t.Errorf("unexpected details: %+v", details)
+	}
+
+	request := newTestApprovalRequest("call-1", "move_file")
+	request.ToolCalls[0].ToolCall.Function.Arguments = jsonString(args)
+	request.ConfirmationDetails = details
+
+	approver := NewInteractiveApproverWithInput(strings.NewReader("y\n"))
+	output := captureStdout(t, func() {
+		if _, err := approver.RequestApproval(context.Background(), request); err != nil {
+			t.Fatalf("approval failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "Move a.txt → b.txt") || !strings.Contains(output, "│ overwrites the destination") {
+		t.Errorf("expected the custom details in the approval prompt, got:\n%s", output)
+	}
+
+	// Tools without the interface keep the generic rendering
+	if _, ok := turn.createConfirmationDetails("make_directory", map[string]interface{}{"path": "x"}, RiskMedium).(*ToolInfoConfirmationDetails); !ok {
+		t.Error("expected generic details for tools without ConfirmationDetailer")
+	}
+}
+
+func TestSideBySideDiffStyleInApprovalPrompt(t *testing.T) {
+	t.Setenv("COLUMNS", "120")
+	approver := NewInteractiveApproverWithInput(strings.NewReader("y\n"))
+	if err := approver.SetDiffStyle("split"); err == nil {
+		t.Error("expected an unknown diff style to be rejected")
+	}
+	if err := approver.SetDiffStyle(DiffStyleSideBySide); err != nil {
+		t.Fatal(err)
+	}
+
+	request := newTestApprovalRequest("call-1", "edit")
+	request.ConfirmationDetails = &ToolFileConfirmationDetails{
+		ToolName:        "edit",
+		FilePath:        "main.go",
+		OriginalContent: "a := 1\n",
+		NewContent:      "a := 2\n",
+		FileDiff:        "unified diff",
+	}
+	output := captureStdout(t, func() {
+		if _, err := approver.RequestApproval(context.Background(), request); err != nil {
+			t.Fatalf("approval failed: %v", err)
+		}
+	})
+	if strings.Contains(output, "unified diff") || !strings.Contains(output, "a := 1") || !strings.Contains(output, "a := 2") {
+		t.Errorf("expected a side-by-side preview, got:\n%s", output)
+	}
+}
+
+func TestEditPreviewDecodesLikeTheTool(t *testing.T) {
+	// "héllo\n" in UTF-16LE with a BOM
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte{0xFF, 0xFE, 'h', 0, 0xE9, 0, 'l', 0, 'l', 0, 'o', 0, '\n', 0}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	turn := NewTurn(&fakeLLMClient{}, map[string]tools.Tool{}, nil, &NoOpDebugger{})
+	details := turn.createFileConfirmationDetails("edit", map[string]interface{}{
+		"file_path":  path,
+		"old_string": "héllo",
+		"new_string": "hello",
+	}, RiskMedium)
+	if details == nil {
+		t.Fatal("expected edit details")
+	}
+	if details.OriginalContent != "héllo\n" || details.NewContent != "hello\n" {
+		t.Errorf("expected the decoded text in the preview, got %q -> %q", details.OriginalContent, details.NewContent)
+	}
+	if !strings.Contains(details.Title(), "converted from UTF-16LE to UTF-8") {
+		t.Errorf("expected the encoding change in the title, got %q", details.Title())
+	}
+}
+
+func TestAliasesTakeTheirTargetsRisk(t *testing.T) {
+	shell := tools.NewAliasTool("test", tools.ToolAliasConfig{Args: map[string]interface{}{"command": "go test ./..."}}, tools.NewRunShellTool())
+	read := tools.NewAliasTool("readme", tools.ToolAliasConfig{Args: map[string]interface{}{"path": "README.md"}}, tools.NewReadTool())
+
+	if risk := assessToolRisk("test", shell); risk != RiskHigh {
+		t.Errorf("expected an alias of run_shell to be high risk, got %v", risk)
+	}
+	if risk := assessToolRisk("readme", read); risk != RiskLow {
+		t.Errorf("expected an alias of read to be low risk, got %v", risk)
+	}
+	if risk := assessToolRisk("unknown", nil); risk != RiskMedium {
+		t.Errorf("expected unknown tools to stay medium risk, got %v", risk)
+	}
+}
@@ -25,11 +25,11 @@ func (a *LLMAdapter) ProcessContent(ctx context.Context, content, prompt string)
 	messages := []openai.ChatCompletionMessage{
 		{
 			Role:    "system",
-			Content: "You are a helpful assistant that analyzes web content. Be concise and focus on answering the user's specific question about the content.",
+			Content: "You are a helpful assistant that analyzes web pages and files. Be concise and focus on answering the user's specific question about the content.",
 		},
 		{
 			Role:    "user",
-			Content: fmt.Sprintf("Here is the web content:\n\n%s\n\n%s", content, prompt),
+			Content: fmt.Sprintf("Here is the content:\n\n%s\n\n%s", content, prompt),
 		},
 	}
 
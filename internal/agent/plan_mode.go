@@ -0,0 +1,56 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// planModeInstruction is added to the conversation of a plan-mode run
+const planModeInstruction = "Plan mode: do not change anything yet. Investigate with read-only tools if you need to, then record a step-by-step plan with todo_write and stop with a short summary of it. Writes, edits and commands are rejected until the user approves the plan."
+
+// PlanApprovedPrompt is the user message that ends plan mode and asks the
+// model to carry out its plan
+const PlanApprovedPrompt = "The plan is approved. Carry it out now, updating the todos with todo_write as you complete each step."
+
+// planModeRejection is the tool result for calls refused in plan mode
+const planModeRejection = "Tool call rejected: plan mode only allows read-only tools. Record the plan with todo_write and stop; the user will review it before anything is changed."
+
+// PlanWithHistory runs the conversation in plan mode: the model is asked for
+// a todo_write plan and every call that needs approval is rejected, so only
+// read-only tools (and the todo and memory tools) run
+func (a *Agent) PlanWithHistory(ctx context.Context, conversation []openai.ChatCompletionMessage) (*ExecutionResult, []openai.ChatCompletionMessage, error) {
+	approver := a.approver
+	a.approver = planModeApprover{next: approver}
+	defer func() { a.approver = approver }()
+
+	planning := make([]openai.ChatCompletionMessage, 0, len(conversation)+1)
+	planning = append(planning, conversation...)
+	planning = append(planning, openai.ChatCompletionMessage{
+		Role:    "system",
+		Content: planModeInstruction,
+	})
+	return a.ExecuteWithHistory(ctx, planning, false)
+}
+
+// planModeApprover rejects every call that reaches it. Low-risk tools never
+// ask for approval, so they keep running.
+type planModeApprover struct {
+	next ToolApprover // The approver plan mode stands in for
+}
+
+func (planModeApprover) RequestApproval(ctx context.Context, request ApprovalRequest) (ApprovalResponse, error) {
+	response := ApprovalResponse{
+		RequestID: request.RequestID,
+		Reason:    "Plan mode only allows read-only tools",
+		Source:    SourcePlanMode,
+	}
+	for _, call := range request.ToolCalls {
+		response.RejectedIDs = append(response.RejectedIDs, call.ID)
+	}
+	return response, nil
+}
+
+func (p planModeApprover) NotifyExecution(toolCallID string, result interface{}, err error) {
+	p.next.NotifyExecution(toolCallID, result, err)
+}
@@ -0,0 +1,67 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/tools"
+)
+
+func TestPlanModeRecordsTodosWithoutWriting(t *testing.T) {
+	tools.GlobalTodoStore.Clear()
+	defer tools.GlobalTodoStore.Clear()
+
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "main.go")
+	os.WriteFile(existing, []byte("package main\n"), 0644)
+	created := filepath.Join(dir, "parser.go")
+
+	todos := jsonString(map[string]interface{}{"items": []map[string]interface{}{
+		{"title": "Add a parser type", "state": "pending"},
+		{"title": "Call it from main", "state": "pending"},
+	}})
+	client := &fakeLLMClient{responses: []openai.ChatCompletionResponse{
+		toolCallResponse("call-1", "read", jsonString(map[string]interface{}{"file_path": existing})),
+		toolCallResponse("call-2", "todo_write", todos),
+		toolCallResponse("call-3", "write_file", jsonString(map[string]interface{}{"path": created, "content": "package main\n"})),
+		textResponse("Plan: add a parser, then call it from main."),
+	}}
+	approver := &SimpleAutoApprover{}
+	a := NewAgent(client, WithApprover(approver))
+
+	result, conversation, err := a.PlanWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "add a parser"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if items := tools.GlobalTodoStore.ReadAll(); len(items) != 2 {
+		t.Errorf("expected the plan in the todo store, got %+v", items)
+	}
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Error("expected plan mode to reject the write")
+	}
+	rejected := false
+	for _, msg := range client.requests[3] {
+		if msg.Role == "tool" && msg.ToolCallID == "call-3" && strings.Contains(msg.Content, "plan mode only allows read-only tools") {
+			rejected = true
+		}
+	}
+	if !rejected {
+		t.Error("expected the model to be told why the write was rejected")
+	}
+	if result.Message != "Plan: add a parser, then call it from main." {
+		t.Errorf("unexpected plan summary %q", result.Message)
+	}
+	if conversation[1].Role != "system" || !strings.HasPrefix(conversation[1].Content, "Plan mode") {
+		t.Errorf("expected the plan-mode instruction after the task, got %+v", conversation[1])
+	}
+	if a.approver != approver {
+		t.Error("expected the original approver to be restored after planning")
+	}
+}
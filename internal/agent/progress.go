@@ -0,0 +1,63 @@
+package agent
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/trknhr/agenticode/internal/tools"
+)
+
+// ProgressDisplay shows the progress of a running tool call
+type ProgressDisplay interface {
+	Update(toolName string, update tools.ProgressUpdate)
+	Finish(toolName string)
+}
+
+// progressInterval limits how often the terminal line is redrawn
+const progressInterval = 100 * time.Millisecond
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// TerminalProgress draws tool progress as a single spinner line that is
+// cleared when the call finishes
+type TerminalProgress struct {
+	mu    sync.Mutex
+	out   io.Writer
+	frame int
+	last  time.Time
+	shown bool
+}
+
+// NewTerminalProgress creates a progress display writing to out
+func NewTerminalProgress(out io.Writer) *TerminalProgress {
+	return &TerminalProgress{out: out}
+}
+
+func (p *TerminalProgress) Update(toolName string, update tools.ProgressUpdate) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if time.Since(p.last) < progressInterval {
+		return
+	}
+	p.last = time.Now()
+
+	line := fmt.Sprintf("%s %s: %s", spinnerFrames[p.frame%len(spinnerFrames)], toolName, update.Message)
+	if update.Total > 0 {
+		line += fmt.Sprintf(" (%d/%d, %d%%)", update.Done, update.Total, update.Done*100/update.Total)
+	}
+	p.frame++
+	fmt.Fprintf(p.out, "\r\033[K%s", line)
+	p.shown = true
+}
+
+func (p *TerminalProgress) Finish(toolName string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.shown {
+		fmt.Fprint(p.out, "\r\033[K")
+	}
+	p.shown = false
+	p.last = time.Time{}
+}
@@ -0,0 +1,83 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/tools"
+)
+
+// progressTool reports a fixed sequence of updates before finishing
+type progressTool struct{}
+
+func (t *progressTool) Name() string        { return "slow_scan" }
+func (t *progressTool) Description() string { return "Scan slowly" }
+func (t *progressTool) ReadOnly() bool      { return true }
+func (t *progressTool) GetParameters() map[string]interface{} {
+	return map[string]interface{}{"type": "object"}
+}
+func (t *progressTool) Execute(args map[string]interface{}) (*tools.ToolResult, error) {
+	return t.ExecuteWithProgress(args, nil)
+}
+func (t *progressTool) ExecuteWithProgress(args map[string]interface{}, report tools.ProgressReporter) (*tools.ToolResult, error) {
+	for i := 1; i <= 3; i++ {
+		if report != nil {
+			report(tools.ProgressUpdate{Message: "scanning", Done: i, Total: 3})
+		}
+	}
+	return &tools.ToolResult{LLMContent: "scanned"}, nil
+}
+
+// recordingProgress keeps every update and finish it receives
+type recordingProgress struct {
+	events []string
+}
+
+func (p *recordingProgress) Update(toolName string, update tools.ProgressUpdate) {
+	p.events = append(p.events, toolName+":"+strings.Repeat("#", update.Done))
+}
+
+func (p *recordingProgress) Finish(toolName string) {
+	p.events = append(p.events, toolName+":done")
+}
+
+func TestToolProgressReachesDisplayInOrder(t *testing.T) {
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			toolCallResponse("call-1", "slow_scan", `{}`),
+			textResponse("done"),
+		},
+	}
+	display := &recordingProgress{}
+	a := NewAgent(client,
+		WithMaxSteps(3),
+		WithApprover(&SimpleAutoApprover{}),
+		WithTools([]tools.Tool{&progressTool{}}),
+		WithProgressDisplay(display),
+	)
+
+	if _, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "scan"},
+	}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "slow_scan:# slow_scan:## slow_scan:### slow_scan:done"
+	if got := strings.Join(display.events, " "); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestTerminalProgressClearsLineWhenFinished(t *testing.T) {
+	var out bytes.Buffer
+	progress := NewTerminalProgress(&out)
+	progress.Update("grep", tools.ProgressUpdate{Message: "reading a.go", Done: 1, Total: 4})
+	progress.Finish("grep")
+
+	if got := out.String(); !strings.Contains(got, "grep: reading a.go (1/4, 25%)") || !strings.HasSuffix(got, "\r\033[K") {
+		t.Errorf("unexpected terminal output %q", got)
+	}
+}
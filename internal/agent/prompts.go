@@ -2,13 +2,17 @@ package agent
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"log"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"text/template"
 	"time"
+	"unicode/utf8"
 
 	_ "embed"
 
@@ -36,11 +40,61 @@ type PromptData struct {
 	MainBranch       string
 	GitStatus        string
 	GitRecentCommits string
+	Vars             map[string]string // User-defined variables from prompts.vars, referenced as .Vars.name
 }
 
+// promptVars holds the user-defined template variables for all prompts
+var promptVars map[string]string
+
+// SetPromptVars sets custom variables available to the system and developer
+// prompt templates as {{ .Vars.name }}
+func SetPromptVars(vars map[string]string) {
+	promptVars = vars
+}
+
+// customSystemPrompt replaces the embedded system prompt template when set
+var customSystemPrompt string
+
+// maxInstructionsBytes caps each instructions file added to the system prompt
+const maxInstructionsBytes = 32 * 1024
+
+// SetSystemPromptTemplate replaces the embedded system prompt template; an
+// empty template restores the default. The template is rendered once with
+// sample data, so references to fields PromptData does not have are reported
+// here rather than when a session starts.
+func SetSystemPromptTemplate(content string) error {
+	if content != "" {
+		sample := PromptData{WorkingDir: "/project", Platform: runtime.GOOS, ModelName: "model", Vars: promptVars}
+		if _, err := renderSystemPrompt(content, sample); err != nil {
+			return fmt.Errorf("invalid system prompt template: %w", err)
+		}
+	}
+	customSystemPrompt = content
+	return nil
+}
+
+// renderSystemPrompt executes a system prompt template with sprig functions;
+// unknown keys of Vars render empty
+func renderSystemPrompt(content string, data PromptData) (string, error) {
+	tmpl, err := template.New("system-prompt").Funcs(sprig.FuncMap()).Option("missingkey=zero").Parse(content)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// GetSystemPrompt renders the system prompt followed by the user's and the
+// project's instructions, if any
 func GetSystemPrompt(modelName string) string {
 	// Read the template file
 	templateContent := systemPromptTemplate
+	if customSystemPrompt != "" {
+		templateContent = customSystemPrompt
+	}
 
 	// Gather system information
 	workingDir, err := os.Getwd()
@@ -56,6 +110,7 @@ func GetSystemPrompt(modelName string) string {
 		OSVersion:  getOSVersion(),
 		Date:       time.Now().Format("2006-01-02"),
 		ModelName:  modelName,
+		Vars:       promptVars,
 	}
 
 	// Get git information if in a git repo
@@ -66,23 +121,115 @@ func GetSystemPrompt(modelName string) string {
 		data.GitRecentCommits = getGitRecentCommits()
 	}
 
-	// Create template with sprig functions
-	tmpl, err := template.New("system-prompt").Funcs(sprig.FuncMap()).Parse(string(templateContent))
+	// A custom template can still fail on real data (e.g. a sprig function
+	// given an empty git status); fall back to the default prompt
+	prompt, err := renderSystemPrompt(templateContent, data)
+	if err != nil && templateContent != systemPromptTemplate {
+		log.Printf("Failed to render the custom system prompt template, using the default: %v", err)
+		prompt, err = renderSystemPrompt(systemPromptTemplate, data)
+	}
 	if err != nil {
-		panic(fmt.Sprintf("Failed to parse system prompt template: %v", err))
+		panic(fmt.Sprintf("Failed to render system prompt template: %v", err))
 	}
 
-	// Execute template
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		panic(fmt.Sprintf("Failed to execute system prompt template: %v", err))
+	return prompt + renderInstructions()
+}
+
+// renderInstructions returns the user's ~/.agenticode/instructions.md and the
+// project's AGENTIC.md as system prompt sections
+func renderInstructions() string {
+	var b strings.Builder
+	if home, err := os.UserHomeDir(); err == nil {
+		appendInstructions(&b, "User instructions", filepath.Join(home, ".agenticode", "instructions.md"))
 	}
+	appendInstructions(&b, "Project instructions (AGENTIC.md)", "AGENTIC.md")
+	return b.String()
+}
 
-	return buf.String()
+func appendInstructions(b *strings.Builder, title, path string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	content := strings.TrimSpace(string(data))
+	if content == "" {
+		return
+	}
+	if len(content) > maxInstructionsBytes {
+		cut := maxInstructionsBytes
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		content = content[:cut] + "\n[truncated]"
+	}
+	fmt.Fprintf(b, "\n\n# %s\n\nFollow these instructions from %s:\n\n%s\n", title, path, content)
+}
+
+// ToolExample is a few-shot demonstration of a correct tool call, configured
+// under prompts.tool_examples
+type ToolExample struct {
+	Request   string                 `mapstructure:"request" yaml:"request" json:"request"`       // What the user asked
+	Tool      string                 `mapstructure:"tool" yaml:"tool" json:"tool"`                // The tool to call
+	Arguments map[string]interface{} `mapstructure:"arguments" yaml:"arguments" json:"arguments"` // The arguments to call it with
+	Note      string                 `mapstructure:"note" yaml:"note" json:"note"`                // Optional explanation
+}
+
+// toolExamples are appended to the developer prompt
+var toolExamples []ToolExample
+
+// SetToolExamples sets the few-shot tool call examples added after the
+// developer prompt
+func SetToolExamples(examples []ToolExample) {
+	toolExamples = examples
+}
+
+// renderToolExamples formats the configured examples as a prompt section
+func renderToolExamples(examples []ToolExample) string {
+	if len(examples) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\n# Tool use examples\n\nFollow these examples when calling tools:\n")
+	for _, example := range examples {
+		args, err := json.Marshal(example.Arguments)
+		if err != nil || example.Arguments == nil {
+			args = []byte("{}")
+		}
+		b.WriteString("\n<example>\n")
+		if example.Request != "" {
+			fmt.Fprintf(&b, "user: %s\n", example.Request)
+		}
+		fmt.Fprintf(&b, "assistant: [calls %s with %s]\n", example.Tool, args)
+		if example.Note != "" {
+			fmt.Fprintf(&b, "note: %s\n", example.Note)
+		}
+		b.WriteString("</example>\n")
+	}
+	return b.String()
 }
 
 func GetDeveloperPrompt() string {
-	return developerPromptTemplate
+	return renderDeveloperPrompt() + renderToolExamples(toolExamples)
+}
+
+func renderDeveloperPrompt() string {
+	if len(promptVars) == 0 {
+		return developerPromptTemplate
+	}
+
+	// Only custom variables are available here; fall back to the raw prompt
+	// rather than failing on a template error
+	tmpl, err := template.New("developer-prompt").Funcs(sprig.FuncMap()).Option("missingkey=zero").Parse(developerPromptTemplate)
+	if err != nil {
+		return developerPromptTemplate
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, PromptData{Vars: promptVars}); err != nil {
+		return developerPromptTemplate
+	}
+	return buf.String()
 }
 
 func GetInitPrompt() string {
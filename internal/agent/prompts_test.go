@@ -1,8 +1,14 @@
 package agent
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"unicode/utf8"
+
+	"github.com/sashabaranov/go-openai"
 )
 
 func TestGetDeveloperPrompt(t *testing.T) {
@@ -51,3 +57,126 @@ func TestGetSystemPrompt(t *testing.T) {
 		t.Error("GetSystemPrompt doesn't contain the provided model name")
 	}
 }
+
+func TestPromptVarsAppearInRenderedPrompt(t *testing.T) {
+	SetPromptVars(map[string]string{
+		"company":          "Acme Corp",
+		"coding_standards": "https://example.com/standards",
+	})
+	t.Cleanup(func() { SetPromptVars(nil) })
+
+	prompt := GetSystemPrompt("test-model")
+	for _, want := range []string{"company: Acme Corp", "coding_standards: https://example.com/standards"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected system prompt to contain %q", want)
+		}
+	}
+
+	if GetDeveloperPrompt() != developerPromptTemplate {
+		t.Error("developer prompt without variable references should be unchanged")
+	}
+}
+
+func TestPromptVarsAbsentByDefault(t *testing.T) {
+	if strings.Contains(GetSystemPrompt("test-model"), "Project context configured by the user") {
+		t.Error("expected no project context section without configured vars")
+	}
+}
+
+func TestToolExamplesAreSentToTheModel(t *testing.T) {
+	SetToolExamples([]ToolExample{{
+		Request:   "Where is the config loaded?",
+		Tool:      "grep",
+		Arguments: map[string]interface{}{"pattern": "ReadInConfig"},
+		Note:      "Search before reading whole files",
+	}})
+	defer SetToolExamples(nil)
+
+	client := &fakeLLMClient{}
+	a := NewAgent(client, WithApprover(&SimpleAutoApprover{}))
+	if _, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "system", Content: "system"},
+		{Role: "developer", Content: GetDeveloperPrompt()},
+		{Role: "user", Content: "hi"},
+	}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	var developer string
+	for _, msg := range client.requests[0] {
+		if msg.Role == "developer" {
+			developer = msg.Content
+		}
+	}
+	for _, want := range []string{
+		"# Tool use examples",
+		"user: Where is the config loaded?",
+		`assistant: [calls grep with {"pattern":"ReadInConfig"}]`,
+		"note: Search before reading whole files",
+	} {
+		if !strings.Contains(developer, want) {
+			t.Errorf("expected %q in the developer prompt sent to the model", want)
+		}
+	}
+	if !strings.HasPrefix(developer, developerPromptTemplate) {
+		t.Error("expected the examples to follow the developer prompt")
+	}
+}
+
+func TestInstructionsAppendedToSystemPrompt(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	os.MkdirAll(filepath.Join(home, ".agenticode"), 0755)
+	os.WriteFile(filepath.Join(home, ".agenticode", "instructions.md"), []byte("Answer tersely.\n"), 0644)
+
+	project := t.TempDir()
+	wd, _ := os.Getwd()
+	if err := os.Chdir(project); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	os.WriteFile("AGENTIC.md", []byte("# Build\n\nRun `make check` before committing.\n"), 0644)
+
+	prompt := GetSystemPrompt("test-model")
+	for _, want := range []string{"# User instructions", "Answer tersely.", "# Project instructions (AGENTIC.md)", "Run `make check` before committing."} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected the system prompt to contain %q", want)
+		}
+	}
+	if strings.Index(prompt, "Answer tersely.") > strings.Index(prompt, "make check") {
+		t.Error("expected project instructions after the user's")
+	}
+}
+
+func TestCustomSystemPromptTemplate(t *testing.T) {
+	if err := SetSystemPromptTemplate("You are {{ .ModelName }} for Acme."); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetSystemPromptTemplate("") })
+
+	if prompt := GetSystemPrompt("test-model"); !strings.HasPrefix(prompt, "You are test-model for Acme.") {
+		t.Errorf("expected the custom template, got %.80q", prompt)
+	}
+	if err := SetSystemPromptTemplate("{{ .Broken"); err == nil {
+		t.Error("expected an unparsable template to be rejected")
+	}
+	if err := SetSystemPromptTemplate("Working in {{ .Workdir }}"); err == nil {
+		t.Error("expected a template using a missing PromptData field to be rejected")
+	}
+	if prompt := GetSystemPrompt("test-model"); !strings.HasPrefix(prompt, "You are test-model for Acme.") {
+		t.Errorf("expected a rejected template to leave the previous one in place, got %.80q", prompt)
+	}
+}
+
+func TestAppendInstructionsTruncatesOnRuneBoundary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "AGENTIC.md")
+	// "é" is two bytes, so the limit falls inside one
+	if err := os.WriteFile(path, []byte("x"+strings.Repeat("é", maxInstructionsBytes)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	appendInstructions(&b, "Project instructions", path)
+	if !utf8.ValidString(b.String()) || !strings.Contains(b.String(), "\n[truncated]") {
+		t.Errorf("expected valid UTF-8 cut before the limit, got %q", b.String()[len(b.String())-20:])
+	}
+}
@@ -0,0 +1,59 @@
+package agent
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// readBudget tracks how many bytes of file content reached the conversation
+// since the last compaction. Past the limit the agent compacts and asks the
+// model to rely on summaries and notes instead of re-reading files.
+type readBudget struct {
+	mu    sync.Mutex
+	limit int
+	used  int
+}
+
+func newReadBudget(limit int) *readBudget {
+	return &readBudget{limit: limit}
+}
+
+// isFileReadTool reports whether the tool's result is file content
+func isFileReadTool(name string) bool {
+	switch name {
+	case "read_file", "read", "read_many_files", "read_bytes", "watch_file":
+		return true
+	}
+	return false
+}
+
+// record adds the file content in responses and reports whether the
+// budget is now exceeded
+func (b *readBudget) record(responses []openai.ChatCompletionMessage) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, resp := range responses {
+		if isFileReadTool(resp.Name) {
+			b.used += len(resp.Content)
+		}
+	}
+	return b.used > b.limit
+}
+
+// reset starts counting again, e.g. after the conversation was compacted
+func (b *readBudget) reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.used = 0
+}
+
+// guidance is the message injected once the budget is exceeded. It only
+// mentions the summary when compaction succeeded.
+func (b *readBudget) guidance(compacted bool) string {
+	if !compacted {
+		return fmt.Sprintf("More than %d KB of file contents were read into this conversation. Rely on your memory_write notes instead of re-reading files; read only the specific line ranges you still need, and use grep or summarize_file to locate them.", b.limit/1024)
+	}
+	return fmt.Sprintf("More than %d KB of file contents were read into this conversation, so earlier turns were compacted into a summary. Rely on the summary and your memory_write notes instead of re-reading files; read only the specific line ranges you still need, and use grep or summarize_file to locate them.", b.limit/1024)
+}
@@ -0,0 +1,72 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestReadBudgetInjectsGuidanceAndCompacts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "big.txt")
+	if err := os.WriteFile(path, []byte(strings.Repeat("some file content\n", 300)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			toolCallResponse("call-1", "read", `{"file_path":"`+path+`"}`),
+			textResponse("Read big.txt; it repeats one line."), // compaction summary
+			textResponse("done"),
+		},
+	}
+	a := NewAgent(client, WithApprover(&SimpleAutoApprover{}), WithReadBudget(2048))
+
+	if _, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "look at big.txt"},
+	}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(client.requests) != 3 {
+		t.Fatalf("expected turn, compaction and turn requests, got %d", len(client.requests))
+	}
+	guided := false
+	for _, msg := range client.requests[2] {
+		if msg.Role == "tool" {
+			t.Error("file contents should have been compacted away")
+		}
+		if msg.Role == "system" && strings.Contains(msg.Content, "instead of re-reading files") {
+			guided = true
+		}
+	}
+	if !guided {
+		t.Error("expected read budget guidance after crossing the threshold")
+	}
+	if a.readBudget.used != 0 {
+		t.Errorf("expected the budget to restart after compaction, used=%d", a.readBudget.used)
+	}
+}
+
+func TestReadBudgetOnlyCountsFileReads(t *testing.T) {
+	budget := newReadBudget(10)
+	if budget.record([]openai.ChatCompletionMessage{{Role: "tool", Name: "run_shell", Content: strings.Repeat("x", 100)}}) {
+		t.Error("command output should not count towards the read budget")
+	}
+	if !budget.record([]openai.ChatCompletionMessage{{Role: "tool", Name: "read_file", Content: strings.Repeat("x", 11)}}) {
+		t.Error("expected file content past the limit to exceed the budget")
+	}
+}
+
+func TestReadBudgetGuidanceOnlyMentionsSummaryAfterCompaction(t *testing.T) {
+	budget := newReadBudget(2048)
+	if !strings.Contains(budget.guidance(true), "compacted into a summary") {
+		t.Error("expected the guidance to point at the summary after compaction")
+	}
+	if strings.Contains(budget.guidance(false), "summary") {
+		t.Error("guidance should not mention a summary when compaction failed")
+	}
+}
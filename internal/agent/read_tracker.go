@@ -0,0 +1,79 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// readTracker remembers which files the agent has seen during a session so
+// edits to files it has never read can be bounced back to the model
+type readTracker struct {
+	mu    sync.Mutex
+	paths map[string]bool
+}
+
+func newReadTracker() *readTracker {
+	return &readTracker{paths: make(map[string]bool)}
+}
+
+// markRead records that the file's current content is known to the model
+func (r *readTracker) markRead(path string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.paths[normalizeTrackedPath(path)] = true
+}
+
+// hasRead reports whether the file was read earlier in the session
+func (r *readTracker) hasRead(path string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.paths[normalizeTrackedPath(path)]
+}
+
+// observe records paths whose content the model now knows after a successful tool call
+func (r *readTracker) observe(toolName string, args map[string]interface{}) {
+	var path string
+	switch toolName {
+	case "read", "edit", "multi_edit", "edit_diff":
+		path, _ = args["file_path"].(string)
+	case "read_file", "write_file":
+		path, _ = args["path"].(string)
+	}
+	if path != "" {
+		r.markRead(path)
+	}
+}
+
+// checkEdit returns a corrective message if the call edits an existing file
+// that has not been read yet
+func (r *readTracker) checkEdit(toolName string, args map[string]interface{}) (string, bool) {
+	if toolName != "edit" && toolName != "multi_edit" && toolName != "edit_diff" {
+		return "", true
+	}
+
+	path, _ := args["file_path"].(string)
+	if path == "" {
+		return "", true
+	}
+
+	// New files have nothing to read
+	if _, err := os.Stat(path); err != nil {
+		return "", true
+	}
+
+	if r.hasRead(path) {
+		return "", true
+	}
+
+	return fmt.Sprintf("Edit rejected: %s has not been read in this session. Read the file first (with the read tool) so the edit matches its current content exactly, then retry the edit.", path), false
+}
+
+// normalizeTrackedPath makes relative and absolute spellings of a path compare equal
+func normalizeTrackedPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
@@ -0,0 +1,62 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestReadBeforeEditBouncesBlindEdit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	editArgs := `{"file_path":"` + path + `","old_string":"main","new_string":"app"}`
+
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			toolCallResponse("call-1", "edit", editArgs),
+			toolCallResponse("call-2", "read", `{"file_path":"`+path+`"}`),
+			toolCallResponse("call-3", "edit", editArgs),
+			textResponse("renamed"),
+		},
+	}
+	a := NewAgent(client, WithApprover(&SimpleAutoApprover{}), WithReadBeforeEdit(true))
+
+	_, conversation, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "rename the package"},
+	}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	responses := map[string]string{}
+	for _, msg := range conversation {
+		if msg.Role == "tool" {
+			responses[msg.ToolCallID] = msg.Content
+		}
+	}
+	if !strings.Contains(responses["call-1"], "has not been read") {
+		t.Errorf("expected the blind edit to be bounced, got %q", responses["call-1"])
+	}
+	if strings.Contains(responses["call-3"], "has not been read") {
+		t.Errorf("expected the edit after reading to be allowed, got %q", responses["call-3"])
+	}
+
+	content, _ := os.ReadFile(path)
+	if string(content) != "package app\n" {
+		t.Errorf("expected the second edit to apply, got %q", content)
+	}
+}
+
+func TestReadBeforeEditAllowsNewFiles(t *testing.T) {
+	tracker := newReadTracker()
+	missing := filepath.Join(t.TempDir(), "new.go")
+	if _, ok := tracker.checkEdit("multi_edit", map[string]interface{}{"file_path": missing}); !ok {
+		t.Error("edits creating a new file should not require a prior read")
+	}
+}
@@ -0,0 +1,22 @@
+package agent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// reasoningBlock matches the <think> block some reasoning models put before
+// their answer
+var reasoningBlock = regexp.MustCompile(`(?s)^\s*<(think|thinking)>(.*?)</(?:think|thinking)>`)
+
+// splitReasoning separates a leading reasoning block from the answer. Content
+// without one is returned unchanged as the answer.
+func splitReasoning(content string) (reasoning, answer string) {
+	match := reasoningBlock.FindStringSubmatchIndex(content)
+	if match == nil {
+		return "", content
+	}
+	reasoning = strings.TrimSpace(content[match[4]:match[5]])
+	answer = strings.TrimLeft(content[match[1]:], "\n")
+	return reasoning, answer
+}
@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"time"
+)
+
+// RunSummary is the single transcript record written when a run finishes
+type RunSummary struct {
+	Type             string         `json:"type"`
+	Time             time.Time      `json:"time"`
+	Outcome          string         `json:"outcome"` // "success", "incomplete" or "error"
+	Message          string         `json:"message,omitempty"`
+	Error            string         `json:"error,omitempty"`
+	HadErrors        bool           `json:"had_errors,omitempty"` // Tools failed at the end of the run
+	Steps            int            `json:"steps"`
+	ToolCalls        map[string]int `json:"tool_calls"`
+	FilesChanged     []string       `json:"files_changed"`
+	PromptTokens     int            `json:"prompt_tokens"`
+	CompletionTokens int            `json:"completion_tokens"`
+	TotalTokens      int            `json:"total_tokens"`
+	CostUSD          float64        `json:"cost_usd,omitempty"` // Omitted when the model has no pricing
+	DurationMs       int64          `json:"duration_ms"`
+}
+
+// NewRunSummary summarizes a finished run. result may be nil when the run
+// failed before producing one; runErr is the error the run returned, if any.
+func NewRunSummary(result *ExecutionResult, runErr error, duration time.Duration) RunSummary {
+	summary := RunSummary{
+		Type:         "run_summary",
+		Time:         time.Now(),
+		Outcome:      "incomplete",
+		ToolCalls:    make(map[string]int),
+		FilesChanged: []string{},
+		DurationMs:   duration.Milliseconds(),
+	}
+	if result != nil {
+		if result.Success {
+			summary.Outcome = "success"
+		}
+		summary.Message = result.Message
+		summary.HadErrors = result.HadErrors
+		summary.Steps = len(result.Steps)
+		for _, step := range result.Steps {
+			if step.ToolName != "" {
+				summary.ToolCalls[step.ToolName]++
+			}
+		}
+		if result.FilesChanged != nil {
+			summary.FilesChanged = result.FilesChanged
+		}
+		summary.PromptTokens = result.PromptTokens
+		summary.CompletionTokens = result.CompletionTokens
+		summary.TotalTokens = result.TokensUsed
+	}
+	if runErr != nil {
+		summary.Outcome = "error"
+		summary.Error = runErr.Error()
+	}
+	return summary
+}
+
+// WriteRunSummary appends the summary as a JSON line to the transcript
+func WriteRunSummary(transcriptPath string, summary RunSummary) error {
+	return appendTranscriptEntry(transcriptPath, summary)
+}
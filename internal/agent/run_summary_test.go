@@ -0,0 +1,88 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestRunSummaryWrittenToTranscript(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "hello.txt")
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{
+			withUsage(toolCallResponse("call-1", "write_file", jsonString(map[string]interface{}{"path": target, "content": "hi\n"})), 1000, 200),
+			withUsage(toolCallResponse("call-2", "todo_read", `{}`), 1500, 100),
+			withUsage(textResponse("wrote the file"), 2000, 50),
+		},
+	}
+	a := NewAgent(client, WithMaxSteps(5), WithApprover(&SimpleAutoApprover{}))
+
+	result, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "write hello.txt"},
+	}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transcript := filepath.Join(dir, "transcripts", "session.jsonl")
+	summary := NewRunSummary(result, nil, 1500*time.Millisecond)
+	summary.CostUSD = 0.25
+	if err := WriteRunSummary(transcript, summary); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(transcript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var record map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		record = nil
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid transcript line %q: %v", scanner.Text(), err)
+		}
+	}
+	if record == nil || record["type"] != "run_summary" {
+		t.Fatalf("expected a run_summary record last, got %v", record)
+	}
+
+	if record["outcome"] != "success" || record["message"] != "wrote the file" {
+		t.Errorf("unexpected outcome %v / message %v", record["outcome"], record["message"])
+	}
+	if record["steps"] != float64(2) {
+		t.Errorf("expected 2 steps, got %v", record["steps"])
+	}
+	toolCalls, _ := record["tool_calls"].(map[string]interface{})
+	if toolCalls["write_file"] != float64(1) || toolCalls["todo_read"] != float64(1) {
+		t.Errorf("unexpected tool counts %v", record["tool_calls"])
+	}
+	files, _ := record["files_changed"].([]interface{})
+	if len(files) != 1 || files[0] != target {
+		t.Errorf("expected only %s as changed, got %v", target, record["files_changed"])
+	}
+	if record["prompt_tokens"] != float64(4500) || record["completion_tokens"] != float64(350) || record["total_tokens"] != float64(4850) {
+		t.Errorf("unexpected token counts %v/%v/%v", record["prompt_tokens"], record["completion_tokens"], record["total_tokens"])
+	}
+	if record["cost_usd"] != 0.25 || record["duration_ms"] != float64(1500) {
+		t.Errorf("unexpected cost %v or duration %v", record["cost_usd"], record["duration_ms"])
+	}
+}
+
+func TestRunSummaryRecordsError(t *testing.T) {
+	summary := NewRunSummary(nil, context.DeadlineExceeded, time.Second)
+	if summary.Outcome != "error" || summary.Error != context.DeadlineExceeded.Error() {
+		t.Errorf("unexpected summary for a failed run: %+v", summary)
+	}
+	if summary.ToolCalls == nil || summary.FilesChanged == nil {
+		t.Error("expected empty collections rather than null")
+	}
+}
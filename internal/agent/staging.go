@@ -0,0 +1,135 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/trknhr/agenticode/internal/tools"
+)
+
+// stagingWriteTools change the file named by their path argument
+var stagingWriteTools = map[string]string{
+	"write_file":     "path",
+	"make_directory": "path",
+	"delete_file":    "path",
+	"edit":           "file_path",
+	"multi_edit":     "file_path",
+	"ast_edit":       "file_path",
+	"edit_diff":      "file_path",
+}
+
+// stagingSessionTools change no project files: they update the session's
+// own state, or run a sub-agent that stages its changes too
+var stagingSessionTools = map[string]bool{
+	"todo_write":   true,
+	"memory_write": true,
+	"agent_tool":   true,
+}
+
+// stagingReadTools read a file; they see the staged copy once there is one
+var stagingReadTools = map[string]string{
+	"read_file":  "path",
+	"read":       "file_path",
+	"read_bytes": "path",
+}
+
+// stagingArea redirects file changes made during a dry run into a separate
+// directory that mirrors the project layout, leaving the real files alone
+type stagingArea struct {
+	dir  string // Absolute staging directory
+	root string // Project directory the staged paths are relative to
+}
+
+func newStagingArea(dir string) (*stagingArea, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid staging directory: %w", err)
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return &stagingArea{dir: absDir, root: root}, nil
+}
+
+// stagedPath maps a project path to its location in the staging directory.
+// Paths outside the project are kept under "_external".
+func (s *stagingArea) stagedPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if abs == s.dir || strings.HasPrefix(abs, s.dir+string(filepath.Separator)) {
+		return abs, nil // Already staged
+	}
+	rel, err := filepath.Rel(s.root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		rel = filepath.Join("_external", strings.TrimPrefix(abs, filepath.VolumeName(abs)))
+	}
+	return filepath.Join(s.dir, rel), nil
+}
+
+// redirect returns the tool and arguments to run for a call. Aliases run
+// their target with the presets applied, so they stage like the target.
+// Writes always go to the staging directory, seeded with the original file
+// so edits apply to its current content; reads use the staged copy when it
+// exists. Other tools that change something outside the session (e.g.
+// run_shell or git_commit) cannot be staged and are refused.
+func (s *stagingArea) redirect(tool tools.Tool, args map[string]interface{}) (tools.Tool, map[string]interface{}, error) {
+	if alias, ok := tool.(*tools.AliasTool); ok {
+		tool, args = alias.Resolve(args)
+	}
+	toolName := tool.Name()
+	key, writes := stagingWriteTools[toolName]
+	if !writes {
+		if !tool.ReadOnly() && !stagingSessionTools[toolName] {
+			return nil, nil, fmt.Errorf("%s is not available in a dry run because its changes cannot be staged; make file changes with write_file or edit instead", toolName)
+		}
+		key = stagingReadTools[toolName]
+	}
+	path, _ := args[key].(string)
+	if key == "" || path == "" {
+		return tool, args, nil
+	}
+
+	staged, err := s.stagedPath(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to stage %s: %w", path, err)
+	}
+	if writes {
+		if err := s.seed(path, staged); err != nil {
+			return nil, nil, err
+		}
+	} else if _, err := os.Stat(staged); err != nil {
+		return tool, args, nil
+	}
+
+	redirected := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		redirected[k] = v
+	}
+	redirected[key] = staged
+	return tool, redirected, nil
+}
+
+// seed copies the original file into the staging directory the first time
+// it is changed
+func (s *stagingArea) seed(original, staged string) error {
+	if _, err := os.Stat(staged); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(staged), 0755); err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	info, err := os.Stat(original)
+	if err != nil || !info.Mode().IsRegular() {
+		return nil // New file or directory; nothing to copy
+	}
+	content, err := os.ReadFile(original)
+	if err != nil {
+		return fmt.Errorf("failed to stage %s: %w", original, err)
+	}
+	return os.WriteFile(staged, content, info.Mode().Perm())
+}
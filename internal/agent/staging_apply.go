@@ -0,0 +1,135 @@
+package agent
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// StagedFile is a file a dry run wrote to the staging directory whose
+// content differs from the project file it stands for
+type StagedFile struct {
+	Path     string // Project path the change belongs to
+	Staged   string // Copy in the staging directory
+	Original string // Current project content; empty for a new file
+	New      string // Staged content
+	IsNew    bool
+}
+
+// ApplyChoice is the user's answer for one staged file
+type ApplyChoice int
+
+const (
+	ApplyFile  ApplyChoice = iota // Write this file
+	SkipFile                      // Leave this file alone
+	ApplyAll                      // Write this and every remaining file without asking
+	AbortApply                    // Write nothing more
+)
+
+// ApplyResult records what an apply step wrote. Files after an abort or a
+// failed write are listed as NotApplied, so the summary covers every file.
+type ApplyResult struct {
+	Applied    []string
+	Skipped    []string
+	NotApplied []string
+	Failed     string // Path whose write failed, if any
+	Err        error
+}
+
+// ListStagedFiles returns the files in a staging directory that differ from
+// the project rooted at root, in path order. Staged deletions and
+// directories are not listed.
+func ListStagedFiles(stagingDir, root string) ([]StagedFile, error) {
+	var files []StagedFile
+	err := filepath.WalkDir(stagingDir, func(staged string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(stagingDir, staged)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(root, rel)
+		if external, ok := strings.CutPrefix(rel, "_external"+string(filepath.Separator)); ok {
+			path = string(filepath.Separator) + external
+		}
+
+		content, err := os.ReadFile(staged)
+		if err != nil {
+			return fmt.Errorf("failed to read staged %s: %w", rel, err)
+		}
+		file := StagedFile{Path: path, Staged: staged, New: string(content)}
+		original, err := os.ReadFile(path)
+		switch {
+		case os.IsNotExist(err):
+			file.IsNew = true
+		case err != nil:
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		case string(original) == file.New:
+			return nil // Unchanged
+		default:
+			file.Original = string(original)
+		}
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// ApplyStagedFiles asks choose about each file in turn and writes the ones
+// it approves to their project paths. It stops at the first failed write
+// rather than applying the rest of a change set half-way.
+func ApplyStagedFiles(files []StagedFile, choose func(StagedFile) ApplyChoice) ApplyResult {
+	var result ApplyResult
+	applyAll := false
+	for i, file := range files {
+		choice := ApplyFile
+		if !applyAll {
+			choice = choose(file)
+		}
+		switch choice {
+		case SkipFile:
+			result.Skipped = append(result.Skipped, file.Path)
+			continue
+		case AbortApply:
+			for _, rest := range files[i:] {
+				result.NotApplied = append(result.NotApplied, rest.Path)
+			}
+			return result
+		case ApplyAll:
+			applyAll = true
+		}
+
+		if err := writeStagedFile(file); err != nil {
+			result.Failed, result.Err = file.Path, err
+			for _, rest := range files[i+1:] {
+				result.NotApplied = append(result.NotApplied, rest.Path)
+			}
+			return result
+		}
+		result.Applied = append(result.Applied, file.Path)
+	}
+	return result
+}
+
+func writeStagedFile(file StagedFile) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(file.Staged); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(file.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(file.Path, []byte(file.New), mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file.Path, err)
+	}
+	return nil
+}
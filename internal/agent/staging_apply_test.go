@@ -0,0 +1,36 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyStagedFilesStopsAtFailedWrite(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "blocker"), []byte("a file, not a directory"), 0644)
+	files := []StagedFile{
+		{Path: filepath.Join(root, "first.txt"), New: "1"},
+		{Path: filepath.Join(root, "blocker", "second.txt"), New: "2"},
+		{Path: filepath.Join(root, "third.txt"), New: "3"},
+	}
+
+	asked := 0
+	result := ApplyStagedFiles(files, func(StagedFile) ApplyChoice {
+		asked++
+		return ApplyAll
+	})
+
+	if asked != 1 {
+		t.Errorf("expected apply-all to stop the questions, asked %d times", asked)
+	}
+	if len(result.Applied) != 1 || result.Failed != files[1].Path || result.Err == nil {
+		t.Fatalf("expected the second write to fail, got %+v", result)
+	}
+	if len(result.NotApplied) != 1 || result.NotApplied[0] != files[2].Path {
+		t.Errorf("expected the rest to be left unapplied, got %+v", result.NotApplied)
+	}
+	if _, err := os.Stat(files[2].Path); !os.IsNotExist(err) {
+		t.Error("expected nothing written after the failure")
+	}
+}
@@ -28,7 +28,7 @@ func SummarizeConversation(ctx context.Context, client llm.Client, conversation
 		return nil, fmt.Errorf("conversation too short to summarize (need at least 2 messages)")
 	}
 
-	// Estimate original token count (rough estimate: 1 token per 4 characters)
+	// Estimate original token count
 	originalTokens := estimateTokens(userAssistantMessages)
 
 	// Create summarization prompt
@@ -105,16 +105,10 @@ func filterUserAssistantMessages(conversation []openai.ChatCompletionMessage) []
 	return filtered
 }
 
-// estimateTokens provides a rough token count estimate
+// estimateTokens provides a rough token count estimate, the same one used to
+// size max_tokens for a request
 func estimateTokens(messages []openai.ChatCompletionMessage) int {
-	totalChars := 0
-	for _, msg := range messages {
-		totalChars += len(msg.Content)
-		// Add overhead for message structure
-		totalChars += 10 // Rough estimate for role and message metadata
-	}
-	// Rough estimate: 1 token per 4 characters for English text
-	return totalChars / 4
+	return llm.EstimatePromptTokens(messages, nil)
 }
 
 // buildSummarizationPrompt creates the prompt for summarization
@@ -0,0 +1,27 @@
+package agent
+
+import "fmt"
+
+// ToolErrorPolicy decides how tool calls that failed at the end of a run
+// affect its result. A failure counts until a later turn runs tools again
+// without errors, i.e. until the model recovers from it.
+type ToolErrorPolicy string
+
+const (
+	ToolErrorsIgnore ToolErrorPolicy = "ignore" // Report success regardless of tool errors
+	ToolErrorsFlag   ToolErrorPolicy = "flag"   // Keep Success but set HadErrors
+	ToolErrorsFail   ToolErrorPolicy = "fail"   // Set HadErrors and mark the run unsuccessful
+)
+
+// ParseToolErrorPolicy validates a policy name; an empty name is the default
+// policy, flag
+func ParseToolErrorPolicy(name string) (ToolErrorPolicy, error) {
+	switch policy := ToolErrorPolicy(name); policy {
+	case "":
+		return ToolErrorsFlag, nil
+	case ToolErrorsIgnore, ToolErrorsFlag, ToolErrorsFail:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown tool error policy %q (expected ignore, flag or fail)", name)
+	}
+}
@@ -0,0 +1,157 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// traceSummaryBytes is how much of a tool result a trace record keeps
+const traceSummaryBytes = 200
+
+// TraceRecord is one line of a --trace file
+type TraceRecord struct {
+	Time  time.Time `json:"time"`
+	Event string    `json:"event"` // llm_request, content, thought, tool_call, confirmation, approval, tool_result, usage, error, incomplete_tool_call, cancelled
+
+	Messages int `json:"messages,omitempty"` // llm_request: messages sent
+	Tools    int `json:"tools,omitempty"`    // llm_request: tools offered
+
+	CallID string                 `json:"call_id,omitempty"`
+	Tool   string                 `json:"tool,omitempty"`
+	Args   map[string]interface{} `json:"args,omitempty"`
+
+	Approved *bool          `json:"approved,omitempty"`
+	Source   DecisionSource `json:"source,omitempty"`
+	Reason   string         `json:"reason,omitempty"`
+
+	Content     string `json:"content,omitempty"`      // Model text, or the start of a tool result
+	ResultBytes int    `json:"result_bytes,omitempty"` // Full size of a tool result
+	Error       string `json:"error,omitempty"`
+
+	PromptTokens     int   `json:"prompt_tokens,omitempty"`
+	CompletionTokens int   `json:"completion_tokens,omitempty"`
+	TotalTokens      int   `json:"total_tokens,omitempty"`
+	DurationMs       int64 `json:"duration_ms,omitempty"`
+}
+
+// Tracer writes one JSON record per agent event to a JSONL file, separate
+// from the debug log, for analysing runs afterwards
+type Tracer struct {
+	mu     sync.Mutex
+	file   *os.File
+	enc    *json.Encoder
+	failed bool
+}
+
+// NewTracer creates (or truncates) the trace file at path
+func NewTracer(path string) (*Tracer, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace file: %w", err)
+	}
+	return &Tracer{file: file, enc: json.NewEncoder(file)}, nil
+}
+
+// Close closes the trace file
+func (t *Tracer) Close() error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.file.Close()
+}
+
+// Record writes a record, stamping it with the current time if unset
+func (t *Tracer) Record(record TraceRecord) {
+	if t == nil {
+		return
+	}
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.enc.Encode(record); err != nil && !t.failed {
+		t.failed = true // Report once rather than on every event
+		log.Printf("Failed to write trace record: %v", err)
+	}
+}
+
+// traceEvent records an event from the turn's event stream
+func (t *Tracer) traceEvent(event Event) {
+	if t == nil {
+		return
+	}
+	switch e := event.(type) {
+	case LLMRequestEvent:
+		t.Record(TraceRecord{Event: "llm_request", Messages: e.Messages, Tools: e.Tools})
+	case ContentEvent:
+		t.Record(TraceRecord{Event: "content", Content: e.Content})
+	case ThoughtEvent:
+		t.Record(TraceRecord{Event: "thought", Content: e.Description})
+	case ToolCallRequestEvent:
+		t.Record(TraceRecord{Event: "tool_call", CallID: e.CallID, Tool: e.Name, Args: e.Args})
+	case ToolCallConfirmationEvent:
+		t.Record(TraceRecord{Event: "confirmation", CallID: e.Request.CallID, Tool: e.Request.Name})
+	case ToolCallResponseEvent:
+		record := TraceRecord{Event: "tool_result", CallID: e.CallID, Tool: e.Name}
+		if result, ok := e.Result.(string); ok {
+			record.Content, record.ResultBytes = traceSummary(result), len(result)
+		}
+		if e.Error != nil {
+			record.Error = e.Error.Error()
+		}
+		t.Record(record)
+	case UsageMetadataEvent:
+		t.Record(TraceRecord{
+			Event:            "usage",
+			PromptTokens:     e.PromptTokens,
+			CompletionTokens: e.CompletionTokens,
+			TotalTokens:      e.TotalTokens,
+			DurationMs:       e.DurationMs,
+		})
+	case ErrorEvent:
+		record := TraceRecord{Event: "error", Reason: e.Message}
+		if e.Error != nil {
+			record.Error = e.Error.Error()
+		}
+		t.Record(record)
+	case IncompleteToolCallEvent:
+		t.Record(TraceRecord{Event: "incomplete_tool_call", CallID: e.CallID, Tool: e.Name, Reason: e.Message})
+	case UserCancelledEvent:
+		t.Record(TraceRecord{Event: "cancelled"})
+	}
+}
+
+// traceDecision records why a tool call was allowed or refused
+func (t *Tracer) traceDecision(decision ApprovalDecision) {
+	if t == nil {
+		return
+	}
+	approved := decision.Approved
+	t.Record(TraceRecord{
+		Event:    "approval",
+		CallID:   decision.CallID,
+		Tool:     decision.ToolName,
+		Approved: &approved,
+		Source:   decision.Source,
+		Reason:   decision.Reason,
+	})
+}
+
+// traceSummary keeps the start of a tool result, cut on a rune boundary
+func traceSummary(s string) string {
+	if len(s) <= traceSummaryBytes {
+		return s
+	}
+	cut := traceSummaryBytes
+	for cut > 0 && s[cut]&0xC0 == 0x80 {
+		cut--
+	}
+	return s[:cut] + "..."
+}
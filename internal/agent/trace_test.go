@@ -0,0 +1,78 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestTracerRecordsScriptedRun(t *testing.T) {
+	dir := t.TempDir()
+	notes := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(notes, []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tracePath := filepath.Join(dir, "trace.jsonl")
+	tracer, err := NewTracer(tracePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	approver := NewInteractiveApprover()
+	approver.SetAutoApprove([]string{"read_file"})
+	approver.SetQuiet(true)
+	client := &fakeLLMClient{responses: []openai.ChatCompletionResponse{
+		toolCallResponse("call-1", "read_file", jsonString(map[string]interface{}{"path": notes})),
+		textResponse("done"),
+	}}
+	_, _, err = NewAgent(client, WithApprover(approver), WithQuiet(true), WithTracer(tracer)).ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "read the notes"},
+	}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tracer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(tracePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	byEvent := make(map[string][]TraceRecord)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record TraceRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid trace line %q: %v", scanner.Text(), err)
+		}
+		if record.Time.IsZero() {
+			t.Errorf("record without a time: %s", scanner.Text())
+		}
+		byEvent[record.Event] = append(byEvent[record.Event], record)
+	}
+
+	if got := len(byEvent["llm_request"]); got != 2 {
+		t.Errorf("expected 2 llm_request records, got %d", got)
+	} else if byEvent["llm_request"][0].Messages == 0 {
+		t.Errorf("expected the message count, got %+v", byEvent["llm_request"][0])
+	}
+	calls := byEvent["tool_call"]
+	if len(calls) != 1 || calls[0].CallID != "call-1" || calls[0].Tool != "read_file" || calls[0].Args["path"] != notes {
+		t.Errorf("expected the read_file call with its args, got %+v", calls)
+	}
+	approvals := byEvent["approval"]
+	if len(approvals) != 1 || approvals[0].Approved == nil || !*approvals[0].Approved || approvals[0].Source == "" {
+		t.Errorf("expected an approval record with its source, got %+v", approvals)
+	}
+	results := byEvent["tool_result"]
+	if len(results) != 1 || results[0].CallID != "call-1" || results[0].ResultBytes == 0 || results[0].Error != "" {
+		t.Errorf("expected a successful result record for call-1, got %+v", results)
+	}
+}
@@ -0,0 +1,163 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/tools"
+)
+
+// ToolOutput is what a tool call showed the user
+type ToolOutput struct {
+	Display string   // The tool's ReturnDisplay
+	Diffs   []string // Unified diffs of the files the call changed
+}
+
+// ToolOutputs remembers what each tool call of a session showed the user,
+// which the conversation itself does not keep, so it can be exported
+type ToolOutputs struct {
+	mu      sync.Mutex
+	outputs map[string]ToolOutput
+}
+
+// NewToolOutputs creates an empty record of tool outputs
+func NewToolOutputs() *ToolOutputs {
+	return &ToolOutputs{outputs: make(map[string]ToolOutput)}
+}
+
+// Record stores the output of a tool call
+func (o *ToolOutputs) Record(callID string, output ToolOutput) {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.outputs[callID] = output
+}
+
+// Get returns the output recorded for a tool call
+func (o *ToolOutputs) Get(callID string) (ToolOutput, bool) {
+	if o == nil {
+		return ToolOutput{}, false
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	output, ok := o.outputs[callID]
+	return output, ok
+}
+
+// snapshotFiles reads the current content of paths so the changes a tool
+// makes to them can be diffed afterwards; missing files read as empty
+func snapshotFiles(paths []string) map[string]string {
+	snapshot := make(map[string]string, len(paths))
+	for _, path := range paths {
+		content, _ := os.ReadFile(path)
+		snapshot[path] = string(content)
+	}
+	return snapshot
+}
+
+// diffSnapshot diffs each snapshotted file against its content now
+func diffSnapshot(snapshot map[string]string, paths []string) []string {
+	var diffs []string
+	diff := NewDiffGenerator()
+	for _, path := range paths {
+		before, ok := snapshot[path]
+		if !ok {
+			continue
+		}
+		content, _ := os.ReadFile(path)
+		if after := string(content); after != before {
+			diffs = append(diffs, diff.GenerateUnifiedDiff(before, after, path))
+		}
+	}
+	return diffs
+}
+
+// RenderTranscript writes the conversation as a Markdown document: user
+// prompts, assistant messages, and each tool call with what it showed the
+// user and the diffs of files it changed. System and developer prompts are
+// left out. Calls without a recorded output show the result the model got.
+func RenderTranscript(conversation []openai.ChatCompletionMessage, outputs *ToolOutputs) string {
+	results := make(map[string]string)
+	for _, msg := range conversation {
+		if msg.Role == "tool" {
+			results[msg.ToolCallID] = msg.Content
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("# AgentiCode transcript\n")
+	lastRole := ""
+	for _, msg := range conversation {
+		switch msg.Role {
+		case "user":
+			b.WriteString("\n## You\n\n")
+			b.WriteString(userMessageText(msg))
+			b.WriteString("\n")
+		case "assistant":
+			if lastRole != "assistant" {
+				b.WriteString("\n## AgentiCode\n")
+			}
+			if content := strings.TrimSpace(msg.Content); content != "" {
+				fmt.Fprintf(&b, "\n%s\n", content)
+			}
+			for _, call := range msg.ToolCalls {
+				writeTranscriptToolCall(&b, call, results, outputs)
+			}
+		default:
+			continue
+		}
+		lastRole = msg.Role
+	}
+	return b.String()
+}
+
+// userMessageText returns the text of a user message, noting attached images
+func userMessageText(msg openai.ChatCompletionMessage) string {
+	if len(msg.MultiContent) == 0 {
+		return strings.TrimSpace(msg.Content)
+	}
+	var parts []string
+	for _, part := range msg.MultiContent {
+		switch part.Type {
+		case openai.ChatMessagePartTypeText:
+			parts = append(parts, strings.TrimSpace(part.Text))
+		case openai.ChatMessagePartTypeImageURL:
+			parts = append(parts, "_(image attached)_")
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func writeTranscriptToolCall(b *strings.Builder, call openai.ToolCall, results map[string]string, outputs *ToolOutputs) {
+	fmt.Fprintf(b, "\n### 🔧 `%s`\n", call.Function.Name)
+
+	args := call.Function.Arguments
+	var indented bytes.Buffer
+	if json.Indent(&indented, []byte(args), "", "  ") == nil {
+		args = indented.String()
+	}
+	if strings.TrimSpace(args) != "" && strings.TrimSpace(args) != "{}" {
+		fmt.Fprintf(b, "\n```json\n%s\n```\n", args)
+	}
+
+	output, ok := outputs.Get(call.ID)
+	if !ok {
+		if result, ok := results[call.ID]; ok {
+			fmt.Fprintf(b, "\n```text\n%s\n```\n", strings.TrimRight(result, "\n"))
+		}
+		return
+	}
+	if display := strings.TrimSpace(tools.StripANSI(output.Display)); display != "" {
+		fmt.Fprintf(b, "\n%s\n", display)
+	}
+	for _, diff := range output.Diffs {
+		fmt.Fprintf(b, "\n```diff\n%s```\n", diff)
+	}
+}
@@ -0,0 +1,95 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestRenderTranscript(t *testing.T) {
+	conversation := []openai.ChatCompletionMessage{
+		{Role: "system", Content: "SYSTEM PROMPT"},
+		{Role: "developer", Content: "DEVELOPER PROMPT"},
+		{Role: "user", Content: "Fix the greeting"},
+		{Role: "assistant", Content: "Let me look.", ToolCalls: []openai.ToolCall{
+			{ID: "call-1", Type: openai.ToolTypeFunction, Function: openai.FunctionCall{Name: "read_file", Arguments: `{"path":"hello.go"}`}},
+		}},
+		{Role: "tool", ToolCallID: "call-1", Name: "read_file", Content: "File content of hello.go"},
+		{Role: "assistant", ToolCalls: []openai.ToolCall{
+			{ID: "call-2", Type: openai.ToolTypeFunction, Function: openai.FunctionCall{Name: "edit", Arguments: `{"file_path":"hello.go"}`}},
+		}},
+		{Role: "tool", ToolCallID: "call-2", Name: "edit", Content: "Successfully replaced 1 occurrence(s)"},
+		{Role: "assistant", Content: "Done."},
+	}
+	outputs := NewToolOutputs()
+	outputs.Record("call-2", ToolOutput{
+		Display: "✅ **Edited** `\x1b[1mhello.go\x1b[0m`",
+		Diffs:   []string{"--- hello.go\n+++ hello.go\n@@ -1 +1 @@\n-hi\n+hello\n"},
+	})
+
+	md := RenderTranscript(conversation, outputs)
+
+	for _, hidden := range []string{"SYSTEM PROMPT", "DEVELOPER PROMPT", "\x1b["} {
+		if strings.Contains(md, hidden) {
+			t.Errorf("transcript should not contain %q:\n%s", hidden, md)
+		}
+	}
+	want := []string{
+		"# AgentiCode transcript\n",
+		"\n## You\n\nFix the greeting\n",
+		"\n## AgentiCode\n\nLet me look.\n",
+		"\n### 🔧 `read_file`\n\n```json\n{\n  \"path\": \"hello.go\"\n}\n```\n",
+		"\n```text\nFile content of hello.go\n```\n", // No recorded display: the model's result
+		"\n### 🔧 `edit`\n",
+		"\n✅ **Edited** `hello.go`\n",
+		"\n```diff\n--- hello.go\n+++ hello.go\n@@ -1 +1 @@\n-hi\n+hello\n```\n",
+		"\nDone.\n",
+	}
+	last := 0
+	for _, part := range want {
+		i := strings.Index(md[last:], part)
+		if i < 0 {
+			t.Fatalf("expected %q after offset %d in:\n%s", part, last, md)
+		}
+		last += i + len(part)
+	}
+	if strings.Count(md, "## AgentiCode\n") != 1 {
+		t.Errorf("consecutive assistant messages should share one heading:\n%s", md)
+	}
+}
+
+func TestAgentRecordsToolOutputsWithDiffs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("one\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	approver := NewInteractiveApprover()
+	approver.SetAutoApprove([]string{"write_file"})
+	approver.SetQuiet(true)
+	client := &fakeLLMClient{responses: []openai.ChatCompletionResponse{
+		toolCallResponse("call-1", "write_file", jsonString(map[string]interface{}{"path": path, "content": "two\n"})),
+		textResponse("done"),
+	}}
+	outputs := NewToolOutputs()
+	_, conversation, err := NewAgent(client, WithApprover(approver), WithQuiet(true), WithToolOutputs(outputs)).ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "update the notes"},
+	}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, ok := outputs.Get("call-1")
+	if !ok || output.Display == "" {
+		t.Fatalf("expected the write_file display to be recorded, got %+v", output)
+	}
+	if len(output.Diffs) != 1 || !strings.Contains(output.Diffs[0], "-one\n+two\n") {
+		t.Errorf("expected a diff of the change, got %q", output.Diffs)
+	}
+	if md := RenderTranscript(conversation, outputs); !strings.Contains(md, "```diff\n") || !strings.Contains(md, "update the notes") {
+		t.Errorf("expected the diff and prompt in the transcript:\n%s", md)
+	}
+}
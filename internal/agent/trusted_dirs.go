@@ -0,0 +1,106 @@
+package agent
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// resolveTrustedPath makes path absolute and resolves symlinks in its longest
+// existing prefix, so a link inside a trusted dir cannot point outside of it
+func resolveTrustedPath(path string) (string, error) {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	// Walk up until an existing ancestor is found (the target may not exist yet)
+	existing, rest := abs, ""
+	for {
+		if resolved, err := filepath.EvalSymlinks(existing); err == nil {
+			return filepath.Join(resolved, rest), nil
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return abs, nil
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = parent
+	}
+}
+
+// isWithinDir reports whether path is dir itself or somewhere below it
+func isWithinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// toolCallPaths extracts the file system paths a tool call operates on
+func toolCallPaths(call *PendingToolCall) []string {
+	var args map[string]interface{}
+	if err := json.Unmarshal([]byte(call.ToolCall.Function.Arguments), &args); err != nil {
+		return nil
+	}
+	return argPaths(args)
+}
+
+// argPaths returns the file paths named in a tool's arguments
+func argPaths(args map[string]interface{}) []string {
+	var paths []string
+	for _, key := range []string{"path", "file_path"} {
+		if p, ok := args[key].(string); ok && p != "" {
+			paths = append(paths, p)
+		}
+	}
+	if list, ok := args["paths"].([]interface{}); ok {
+		for _, item := range list {
+			if p, ok := item.(string); ok && p != "" {
+				paths = append(paths, p)
+			}
+		}
+	}
+	return paths
+}
+
+// inTrustedDir reports whether every path the call touches resolves under a
+// trusted directory. Calls without a path (e.g. run_shell) and high-risk calls
+// (e.g. delete_file, or an alias of it) are never trusted.
+func (ia *InteractiveApprover) inTrustedDir(call *PendingToolCall, risk RiskLevel) bool {
+	if len(ia.trustedDirs) == 0 || risk == RiskHigh || AssessToolCallRisk(call.ToolCall.Function.Name) == RiskHigh {
+		return false
+	}
+
+	paths := toolCallPaths(call)
+	if len(paths) == 0 {
+		return false
+	}
+
+	for _, path := range paths {
+		resolved, err := resolveTrustedPath(path)
+		if err != nil {
+			return false
+		}
+
+		trusted := false
+		for _, dir := range ia.trustedDirs {
+			if isWithinDir(resolved, dir) {
+				trusted = true
+				break
+			}
+		}
+		if !trusted {
+			return false
+		}
+	}
+	return true
+}
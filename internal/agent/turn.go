@@ -3,10 +3,14 @@ package agent
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/sashabaranov/go-openai"
 	"github.com/trknhr/agenticode/internal/llm"
@@ -21,6 +25,15 @@ type Turn struct {
 	pendingCalls []ToolCallRequestEvent
 	eventStream  *EventStream
 	debugger     Debugger
+
+	// streaming enables the streaming API; streamFallback retries with a
+	// regular Generate call when the stream fails before completing
+	streaming      bool
+	streamFallback bool
+
+	// contextMessages are sent after the conversation on this turn only and
+	// are never stored in it, e.g. the current contents of pinned files
+	contextMessages []openai.ChatCompletionMessage
 }
 
 // NewTurn creates a new Turn instance
@@ -35,6 +48,19 @@ func NewTurn(llmClient llm.Client, availableTools map[string]tools.Tool, convers
 	}
 }
 
+// SetStreaming configures whether the turn uses the streaming API and whether
+// it falls back to a non-streaming request when the stream fails
+func (t *Turn) SetStreaming(enabled, fallback bool) {
+	t.streaming = enabled
+	t.streamFallback = fallback
+}
+
+// SetContextMessages sets messages that are sent to the LLM after the
+// conversation for this turn without becoming part of it
+func (t *Turn) SetContextMessages(messages []openai.ChatCompletionMessage) {
+	t.contextMessages = messages
+}
+
 // Run executes the turn and yields events
 func (t *Turn) Run(ctx context.Context) <-chan Event {
 	go t.run(ctx)
@@ -46,6 +72,7 @@ func (t *Turn) run(ctx context.Context) {
 	defer t.eventStream.Close()
 
 	// Call LLM
+	start := time.Now()
 	response, err := t.callLLM(ctx)
 	if err != nil {
 		t.eventStream.Emit(ErrorEvent{
@@ -55,6 +82,27 @@ func (t *Turn) run(ctx context.Context) {
 		return
 	}
 
+	// Report token usage when the provider returned it
+	if response.Usage.TotalTokens > 0 {
+		t.eventStream.Emit(UsageMetadataEvent{
+			PromptTokens:     response.Usage.PromptTokens,
+			CompletionTokens: response.Usage.CompletionTokens,
+			TotalTokens:      response.Usage.TotalTokens,
+			DurationMs:       time.Since(start).Milliseconds(),
+		})
+	}
+
+	// Reasoning is shown to the user but not sent back to the model
+	if response.Reasoning == "" {
+		response.Reasoning, response.Content = splitReasoning(response.Content)
+	}
+	if response.Reasoning != "" {
+		t.eventStream.Emit(ThoughtEvent{
+			Subject:     "Reasoning",
+			Description: response.Reasoning,
+		})
+	}
+
 	// Add assistant response to conversation
 	t.conversation = append(t.conversation, openai.ChatCompletionMessage{
 		Role:      "assistant",
@@ -71,6 +119,10 @@ func (t *Turn) run(ctx context.Context) {
 
 	// Handle tool calls
 	for _, toolCall := range response.ToolCalls {
+		if isTruncatedToolCall(toolCall, response.FinishReason) {
+			t.handleTruncatedToolCall(toolCall)
+			continue
+		}
 		t.handleToolCall(toolCall)
 	}
 }
@@ -79,6 +131,12 @@ func (t *Turn) run(ctx context.Context) {
 func (t *Turn) callLLM(ctx context.Context) (*LLMResponse, error) {
 	// Filter conversation for LLM
 	filteredConversation := filterConversationForLLM(t.conversation)
+	if len(t.contextMessages) > 0 {
+		// Copy so the extra messages never leak into the caller's conversation
+		withContext := make([]openai.ChatCompletionMessage, 0, len(filteredConversation)+len(t.contextMessages))
+		withContext = append(withContext, filteredConversation...)
+		filteredConversation = append(withContext, t.contextMessages...)
+	}
 
 	// Check with debugger before making LLM call
 	if t.debugger != nil && !t.debugger.ShouldContinue(filteredConversation) {
@@ -89,6 +147,19 @@ func (t *Turn) callLLM(ctx context.Context) (*LLMResponse, error) {
 	openAITools := t.getOpenAITools()
 	
 	log.Printf("Calling LLM with %d messages in conversation and %d tools", len(filteredConversation), len(openAITools))
+	t.eventStream.Emit(LLMRequestEvent{Messages: len(filteredConversation), Tools: len(openAITools)})
+
+	if t.streaming {
+		response, err := t.streamLLM(ctx, filteredConversation, openAITools)
+		if err == nil {
+			return response, nil
+		}
+		if !t.streamFallback {
+			return nil, err
+		}
+		log.Printf("Streaming failed, falling back to non-streaming request: %v", err)
+	}
+
 	resp, err := t.llmClient.Generate(ctx, filteredConversation, openAITools)
 	if err != nil {
 		return nil, err
@@ -100,31 +171,96 @@ func (t *Turn) callLLM(ctx context.Context) (*LLMResponse, error) {
 
 	choice := resp.Choices[0]
 	return &LLMResponse{
-		Role:      choice.Message.Role,
-		Content:   choice.Message.Content,
-		ToolCalls: choice.Message.ToolCalls,
+		Role:         choice.Message.Role,
+		Content:      choice.Message.Content,
+		ToolCalls:    choice.Message.ToolCalls,
+		Usage:        resp.Usage,
+		FinishReason: choice.FinishReason,
 	}, nil
 }
 
+// streamLLM calls the LLM using the streaming API and assembles the full response
+func (t *Turn) streamLLM(ctx context.Context, messages []openai.ChatCompletionMessage, openAITools []openai.Tool) (*LLMResponse, error) {
+	stream, err := t.llmClient.Stream(ctx, messages, openAITools)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start stream: %w", err)
+	}
+	if stream == nil {
+		return nil, fmt.Errorf("failed to start stream: no stream returned")
+	}
+	defer stream.Close()
+
+	response := &LLMResponse{Role: "assistant"}
+	var content strings.Builder
+	var toolCalls []openai.ToolCall
+	finished := false
+
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("stream interrupted: %w", err)
+		}
+
+		for _, choice := range chunk.Choices {
+			if choice.Index != 0 {
+				continue
+			}
+			content.WriteString(choice.Delta.Content)
+			toolCalls = mergeToolCallDeltas(toolCalls, choice.Delta.ToolCalls)
+			if choice.FinishReason != "" {
+				finished = true
+				response.FinishReason = choice.FinishReason
+			}
+		}
+	}
+
+	if !finished {
+		return nil, fmt.Errorf("stream ended without a finish reason")
+	}
+
+	response.Content = content.String()
+	response.ToolCalls = toolCalls
+	return response, nil
+}
+
+// mergeToolCallDeltas folds streamed tool call fragments into complete tool calls
+func mergeToolCallDeltas(calls []openai.ToolCall, deltas []openai.ToolCall) []openai.ToolCall {
+	for _, delta := range deltas {
+		idx := len(calls) - 1
+		if delta.Index != nil {
+			idx = *delta.Index
+		} else if delta.ID != "" || idx < 0 {
+			idx = len(calls)
+		}
+		for len(calls) <= idx {
+			calls = append(calls, openai.ToolCall{Type: openai.ToolTypeFunction})
+		}
+
+		call := &calls[idx]
+		if delta.ID != "" {
+			call.ID = delta.ID
+		}
+		if delta.Type != "" {
+			call.Type = delta.Type
+		}
+		if delta.Function.Name != "" {
+			call.Function.Name = delta.Function.Name
+		}
+		call.Function.Arguments += delta.Function.Arguments
+	}
+	return calls
+}
+
 // getOpenAITools converts agent tools to OpenAI format
 func (t *Turn) getOpenAITools() []openai.Tool {
-	openAITools := make([]openai.Tool, 0, len(t.tools))
+	agentTools := make([]tools.Tool, 0, len(t.tools))
 	for _, tool := range t.tools {
-		// Skip tools that are not yet implemented
-		if tool.Name() == "apply_patch" {
-			continue
-		}
-		
-		openAITools = append(openAITools, openai.Tool{
-			Type: "function",
-			Function: openai.FunctionDefinition{
-				Name:        tool.Name(),
-				Description: tool.Description(),
-				Parameters:  tool.GetParameters(),
-			},
-		})
+		agentTools = append(agentTools, tool)
 	}
-	return openAITools
+	return llm.BuildOpenAITools(agentTools)
 }
 
 // handleToolCall processes a single tool call request
@@ -172,7 +308,7 @@ func (t *Turn) handleToolCall(toolCall openai.ToolCall) {
 	t.eventStream.Emit(event)
 
 	// Emit confirmation request if needed (based on risk level)
-	risk := AssessToolCallRisk(toolCall.Function.Name)
+	risk := assessToolRisk(toolCall.Function.Name, t.tools[toolCall.Function.Name])
 	if risk != RiskLow {
 		// Create confirmation details based on tool type
 		details := t.createConfirmationDetails(toolCall.Function.Name, args, risk)
@@ -185,6 +321,38 @@ func (t *Turn) handleToolCall(toolCall openai.ToolCall) {
 	}
 }
 
+// isTruncatedToolCall reports whether the response was cut off by the output
+// token limit in the middle of this call, leaving its arguments unparseable
+func isTruncatedToolCall(toolCall openai.ToolCall, finishReason openai.FinishReason) bool {
+	if finishReason != openai.FinishReasonLength {
+		return false
+	}
+	return !json.Valid([]byte(toolCall.Function.Arguments))
+}
+
+// handleTruncatedToolCall skips execution of a cut-off call and tells the
+// model why, so it can retry with a smaller call
+func (t *Turn) handleTruncatedToolCall(toolCall openai.ToolCall) {
+	callID := toolCall.ID
+	if callID == "" {
+		callID = fmt.Sprintf("%s-%d", toolCall.Function.Name, len(t.pendingCalls))
+	}
+	log.Printf("Tool call %s (%s) was truncated by the output token limit", callID, toolCall.Function.Name)
+
+	// Track it as pending so the agent loop continues and the model can retry
+	t.pendingCalls = append(t.pendingCalls, ToolCallRequestEvent{
+		CallID: callID,
+		Name:   toolCall.Function.Name,
+	})
+
+	t.eventStream.Emit(IncompleteToolCallEvent{
+		CallID: callID,
+		Name:   toolCall.Function.Name,
+		Message: fmt.Sprintf("Tool call to %s was cut off because the response reached the output token limit, so its arguments are incomplete and it was not executed. "+
+			"Retry with smaller arguments, for example by splitting large content across several calls.", toolCall.Function.Name),
+	})
+}
+
 // GetPendingCalls returns the list of pending tool calls
 func (t *Turn) GetPendingCalls() []ToolCallRequestEvent {
 	return t.pendingCalls
@@ -212,11 +380,59 @@ func (t *Turn) GetConversation() []openai.ChatCompletionMessage {
 
 // createConfirmationDetails creates appropriate confirmation details based on tool type
 func (t *Turn) createConfirmationDetails(toolName string, args map[string]interface{}, risk RiskLevel) ToolCallConfirmationDetails {
+	// Tools that know how to describe their calls take precedence
+	if detailer, ok := t.tools[toolName].(tools.ConfirmationDetailer); ok {
+		if details := detailer.ConfirmationDetails(args); details != nil {
+			return &ToolCustomConfirmationDetails{
+				ToolName: toolName,
+				Summary:  details.Title,
+				Preview:  details.Preview,
+				Risk:     risk,
+			}
+		}
+	}
+
 	switch toolName {
-	case "write_file", "edit":
+	case "write_file", "edit", "ast_edit", "edit_diff":
 		return t.createFileConfirmationDetails(toolName, args, risk)
-	case "run_shell":
+	case "run_shell", "run_tests":
 		return t.createExecConfirmationDetails(toolName, args, risk)
+	case "git_commit":
+		message, _ := args["message"].(string)
+		description := fmt.Sprintf("Commit all tracked changes: %s", message)
+		if paths, ok := args["paths"].([]interface{}); ok && len(paths) > 0 {
+			description = fmt.Sprintf("Commit %v: %s", paths, message)
+		}
+		return &ToolInfoConfirmationDetails{
+			ToolName:    toolName,
+			Description: description,
+			Parameters:  args,
+			Risk:        risk,
+		}
+	case "agent_tool":
+		agentType, _ := args["agent_type"].(string)
+		if agentType == "" {
+			agentType = "general-purpose"
+		}
+		description, _ := args["description"].(string)
+		prompt, _ := args["prompt"].(string)
+		return &ToolAgentConfirmationDetails{
+			ToolName:    toolName,
+			AgentType:   agentType,
+			Description: description,
+			Prompt:      prompt,
+			// Only searcher and analyzer sub-agents are restricted to read-only tools
+			MayWrite: agentType != "searcher" && agentType != "analyzer",
+			Risk:     risk,
+		}
+	case "make_directory":
+		path, _ := args["path"].(string)
+		return &ToolInfoConfirmationDetails{
+			ToolName:    toolName,
+			Description: fmt.Sprintf("Create directory: %s", path),
+			Parameters:  args,
+			Risk:        risk,
+		}
 	default:
 		// For other tools, create basic info confirmation
 		return &ToolInfoConfirmationDetails{
@@ -246,10 +462,11 @@ func (t *Turn) createFileConfirmationDetails(toolName string, args map[string]in
 
 		// Check if file exists
 		if _, err := os.Stat(details.FilePath); err == nil {
-			// File exists, read current content
-			currentContent, err := os.ReadFile(details.FilePath)
+			// File exists, read current content; write_file saves UTF-8
+			currentContent, encodingChange, err := tools.ReadTextForPreview(details.FilePath, false)
 			if err == nil {
-				details.OriginalContent = string(currentContent)
+				details.OriginalContent = currentContent
+				details.EncodingChange = encodingChange
 				details.IsNewFile = false
 
 				// Generate diff
@@ -265,13 +482,14 @@ func (t *Turn) createFileConfirmationDetails(toolName string, args map[string]in
 			details.FilePath = path
 		}
 
-		// Read current file content
-		currentContent, err := os.ReadFile(details.FilePath)
+		// Read current file content, decoded as the edit tool will
+		currentContent, encodingChange, err := tools.ReadTextForPreview(details.FilePath, true)
 		if err != nil {
 			return nil // Can't edit non-existent file
 		}
 
-		details.OriginalContent = string(currentContent)
+		details.OriginalContent = currentContent
+		details.EncodingChange = encodingChange
 		details.IsNewFile = false
 
 		// Calculate new content
@@ -286,6 +504,48 @@ func (t *Turn) createFileConfirmationDetails(toolName string, args map[string]in
 		}
 
 		// Generate diff
+		diffGen := NewDiffGenerator()
+		details.FileDiff = diffGen.GenerateColoredDiff(details.OriginalContent, details.NewContent, details.FilePath)
+	} else if toolName == "ast_edit" {
+		if path, ok := args["file_path"].(string); ok {
+			details.FilePath = path
+		}
+
+		// Preview the structured edit. Invalid edits are shown without a
+		// diff and fail with a descriptive error when executed.
+		currentContent, err := os.ReadFile(details.FilePath)
+		if err != nil {
+			return details
+		}
+		details.OriginalContent = string(currentContent)
+		updated, _, err := tools.ApplyASTEdit(details.FilePath, currentContent, args)
+		if err != nil {
+			return details
+		}
+		details.NewContent = string(updated)
+
+		diffGen := NewDiffGenerator()
+		details.FileDiff = diffGen.GenerateColoredDiff(details.OriginalContent, details.NewContent, details.FilePath)
+	} else if toolName == "edit_diff" {
+		if path, ok := args["file_path"].(string); ok {
+			details.FilePath = path
+		}
+
+		// Preview the patched file; a diff that does not apply is shown
+		// without a preview and fails with a descriptive error when executed
+		currentContent, encodingChange, err := tools.ReadTextForPreview(details.FilePath, true)
+		if err != nil {
+			return details
+		}
+		details.OriginalContent = currentContent
+		details.EncodingChange = encodingChange
+		diff, _ := args["diff"].(string)
+		updated, _, err := tools.ApplyUnifiedDiff(details.FilePath, details.OriginalContent, diff)
+		if err != nil {
+			return details
+		}
+		details.NewContent = updated
+
 		diffGen := NewDiffGenerator()
 		details.FileDiff = diffGen.GenerateColoredDiff(details.OriginalContent, details.NewContent, details.FilePath)
 	}
@@ -303,6 +563,11 @@ func (t *Turn) createExecConfirmationDetails(toolName string, args map[string]in
 	if cmd, ok := args["command"].(string); ok {
 		details.Command = cmd
 	}
+	if toolName == "run_tests" && details.Command == "" {
+		// Show the command run_tests will pick
+		dir, _ := args["working_directory"].(string)
+		details.Command, _ = tools.DetectTestCommand(dir)
+	}
 
 	if wd, ok := args["working_directory"].(string); ok {
 		details.WorkingDir = wd
@@ -313,5 +578,19 @@ func (t *Turn) createExecConfirmationDetails(toolName string, args map[string]in
 		}
 	}
 
+	if env, ok := args["env"].(map[string]interface{}); ok {
+		for name, value := range env {
+			details.Env = append(details.Env, fmt.Sprintf("%s=%v", name, value))
+		}
+		sort.Strings(details.Env)
+	}
+
+	// Say how many files a recognizable rm/mv/cp/chmod/chown would touch
+	if toolName == "run_shell" {
+		for _, scope := range tools.EstimateShellScope(details.Command, details.WorkingDir) {
+			details.Scope = append(details.Scope, scope.String())
+		}
+	}
+
 	return details
 }
@@ -0,0 +1,48 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// turnOutputBudget caps how many bytes of tool results a single turn adds to
+// the conversation. Per-tool limits cannot stop one turn from calling many
+// tools, so once the turn's results pass the budget the rest are truncated.
+type turnOutputBudget struct {
+	limit int
+	used  int
+}
+
+func newTurnOutputBudget(limit int) *turnOutputBudget {
+	return &turnOutputBudget{limit: limit}
+}
+
+// reset starts a new turn
+func (b *turnOutputBudget) reset() {
+	b.used = 0
+}
+
+// fit returns content, truncated to what is left of the turn's budget with
+// a note asking the model to be more targeted
+func (b *turnOutputBudget) fit(content string) string {
+	remaining := b.limit - b.used
+	if len(content) <= remaining {
+		b.used += len(content)
+		return content
+	}
+
+	keep := ""
+	if remaining > 0 {
+		keep = content[:remaining]
+		// Cut at a line break when there is one, and never inside a rune
+		if i := strings.LastIndexByte(keep, '\n'); i > 0 {
+			keep = keep[:i+1]
+		}
+		for len(keep) > 0 && !utf8.ValidString(keep) {
+			keep = keep[:len(keep)-1]
+		}
+	}
+	b.used = b.limit
+	return fmt.Sprintf("%s\n[%d of %d bytes omitted: this turn's tool results passed the %d byte budget. Be more targeted: grep with narrower patterns, read specific files or line ranges with offset/limit, and make fewer large calls per turn.]", keep, len(content)-len(keep), len(content), b.limit)
+}
@@ -0,0 +1,71 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestTurnOutputBudgetTruncatesLaterResults(t *testing.T) {
+	dir := t.TempDir()
+	var paths []string
+	for _, name := range []string{"a.txt", "b.txt", "c.txt", "d.txt"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(strings.Repeat(name+" line\n", 300)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	// One turn reads three ~3KB files, the next turn reads a fourth
+	bigTurn := toolCallResponse("call-1", "read_file", jsonString(map[string]interface{}{"path": paths[0]}))
+	for i, path := range paths[1:3] {
+		bigTurn.Choices[0].Message.ToolCalls = append(bigTurn.Choices[0].Message.ToolCalls, openai.ToolCall{
+			ID:       fmt.Sprintf("call-%d", i+2),
+			Type:     openai.ToolTypeFunction,
+			Function: openai.FunctionCall{Name: "read_file", Arguments: jsonString(map[string]interface{}{"path": path})},
+		})
+	}
+	client := &fakeLLMClient{responses: []openai.ChatCompletionResponse{
+		bigTurn,
+		toolCallResponse("call-4", "read_file", jsonString(map[string]interface{}{"path": paths[3]})),
+		textResponse("done"),
+	}}
+	a := NewAgent(client, WithApprover(&SimpleAutoApprover{}), WithQuiet(true), WithTurnOutputBudget(5000))
+	if _, _, err := a.ExecuteWithHistory(context.Background(), []openai.ChatCompletionMessage{
+		{Role: "user", Content: "read everything"},
+	}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	results := make(map[string]string)
+	for _, msg := range client.requests[len(client.requests)-1] {
+		if msg.Role == "tool" {
+			results[msg.ToolCallID] = msg.Content
+		}
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected four tool results, got %d", len(results))
+	}
+
+	if strings.Contains(results["call-1"], "bytes omitted") || !strings.Contains(results["call-1"], "a.txt line") {
+		t.Errorf("expected the first result in full, got %d bytes", len(results["call-1"]))
+	}
+	if !strings.Contains(results["call-2"], "b.txt line") || !strings.Contains(results["call-2"], "passed the 5000 byte budget") {
+		t.Errorf("expected the second result cut at the budget, got:\n%s", results["call-2"])
+	}
+	if strings.Contains(results["call-3"], "c.txt line") || !strings.Contains(results["call-3"], "Be more targeted") {
+		t.Errorf("expected the third result to be left out with a note, got:\n%s", results["call-3"])
+	}
+	if total := len(results["call-1"]) + len(results["call-2"]) + len(results["call-3"]); total > 5000+2*400 {
+		t.Errorf("expected the turn to stay near its budget, got %d bytes", total)
+	}
+	if strings.Contains(results["call-4"], "bytes omitted") {
+		t.Error("expected the budget to start over in the next turn")
+	}
+}
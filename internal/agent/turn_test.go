@@ -0,0 +1,147 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/tools"
+)
+
+func TestTurnStreamingFallsBackToGenerate(t *testing.T) {
+	client := &fakeLLMClient{
+		streamErr: errors.New("stream reset by peer"),
+		responses: []openai.ChatCompletionResponse{textResponse("hello from generate")},
+	}
+
+	turn := NewTurn(client, map[string]tools.Tool{}, []openai.ChatCompletionMessage{
+		{Role: "user", Content: "hi"},
+	}, &NoOpDebugger{})
+	turn.SetStreaming(true, true)
+
+	response, err := turn.callLLM(context.Background())
+	if err != nil {
+		t.Fatalf("expected fallback to succeed, got error: %v", err)
+	}
+	if response.Content != "hello from generate" {
+		t.Errorf("unexpected content: %q", response.Content)
+	}
+	if client.streamCalls != 1 || client.generateCalls != 1 {
+		t.Errorf("expected one stream attempt and one generate call, got %d/%d", client.streamCalls, client.generateCalls)
+	}
+}
+
+func TestTurnStreamingWithoutFallbackFails(t *testing.T) {
+	client := &fakeLLMClient{streamErr: errors.New("stream reset by peer")}
+
+	turn := NewTurn(client, map[string]tools.Tool{}, nil, &NoOpDebugger{})
+	turn.SetStreaming(true, false)
+
+	if _, err := turn.callLLM(context.Background()); err == nil {
+		t.Fatal("expected an error when fallback is disabled")
+	}
+	if client.generateCalls != 0 {
+		t.Errorf("expected no generate call, got %d", client.generateCalls)
+	}
+}
+
+func TestMergeToolCallDeltas(t *testing.T) {
+	zero, one := 0, 1
+	var calls []openai.ToolCall
+	calls = mergeToolCallDeltas(calls, []openai.ToolCall{{Index: &zero, ID: "call_a", Function: openai.FunctionCall{Name: "read", Arguments: `{"file_`}}})
+	calls = mergeToolCallDeltas(calls, []openai.ToolCall{{Index: &zero, Function: openai.FunctionCall{Arguments: `path":"a.go"}`}}})
+	calls = mergeToolCallDeltas(calls, []openai.ToolCall{{Index: &one, ID: "call_b", Function: openai.FunctionCall{Name: "grep", Arguments: `{}`}}})
+
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 tool calls, got %d", len(calls))
+	}
+	if calls[0].ID != "call_a" || calls[0].Function.Arguments != `{"file_path":"a.go"}` {
+		t.Errorf("first call not assembled correctly: %+v", calls[0])
+	}
+	if calls[1].Function.Name != "grep" {
+		t.Errorf("second call not assembled correctly: %+v", calls[1])
+	}
+}
+
+func TestTurnStreamEndingMidToolCallIsNotExecuted(t *testing.T) {
+	zero := 0
+	target := t.TempDir() + "/big.txt"
+	client := &fakeLLMClient{
+		streams: []*openai.ChatCompletionStream{newTestStream(t,
+			openai.ChatCompletionStreamResponse{Choices: []openai.ChatCompletionStreamChoice{{
+				Delta: openai.ChatCompletionStreamChoiceDelta{ToolCalls: []openai.ToolCall{{
+					Index: &zero, ID: "call-1", Type: openai.ToolTypeFunction,
+					Function: openai.FunctionCall{Name: "write_file", Arguments: `{"path":"` + target + `","content":"lorem ip`},
+				}}},
+			}}},
+			openai.ChatCompletionStreamResponse{Choices: []openai.ChatCompletionStreamChoice{{
+				FinishReason: openai.FinishReasonLength,
+			}}},
+		)},
+	}
+
+	turn := NewTurn(client, map[string]tools.Tool{"write_file": tools.NewWriteFileTool()}, []openai.ChatCompletionMessage{
+		{Role: "user", Content: "write a big file"},
+	}, &NoOpDebugger{})
+	turn.SetStreaming(true, false)
+
+	handler := NewTurnHandler(turn.tools, &SimpleAutoApprover{})
+	if err := handler.HandleTurn(context.Background(), turn); err != nil {
+		t.Fatalf("expected the truncated call to be handled, got error: %v", err)
+	}
+
+	responses := handler.GetToolResponses()
+	if len(responses) != 1 || responses[0].ToolCallID != "call-1" {
+		t.Fatalf("expected one tool response for the truncated call, got %+v", responses)
+	}
+	if !strings.Contains(responses[0].Content, "output token limit") {
+		t.Errorf("expected a clear truncation error, got %q", responses[0].Content)
+	}
+	if len(turn.GetPendingCalls()) != 1 {
+		t.Error("expected the truncated call to keep the agent loop going")
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Error("the truncated write must not be executed")
+	}
+}
+
+func TestTurnEmitsThoughtEventForReasoning(t *testing.T) {
+	client := &fakeLLMClient{
+		responses: []openai.ChatCompletionResponse{textResponse("<think>\nThe user wants a greeting.\n</think>\n\nHello!")},
+	}
+	turn := NewTurn(client, map[string]tools.Tool{}, []openai.ChatCompletionMessage{
+		{Role: "user", Content: "hi"},
+	}, &NoOpDebugger{})
+
+	var thoughts []ThoughtEvent
+	var contents []string
+	for event := range turn.Run(context.Background()) {
+		switch e := event.(type) {
+		case ThoughtEvent:
+			thoughts = append(thoughts, e)
+		case ContentEvent:
+			contents = append(contents, e.Content)
+		}
+	}
+
+	if len(thoughts) != 1 || thoughts[0].Description != "The user wants a greeting." {
+		t.Fatalf("expected one thought with the reasoning, got %+v", thoughts)
+	}
+	if len(contents) != 1 || contents[0] != "Hello!" {
+		t.Errorf("expected the answer without the reasoning, got %q", contents)
+	}
+	conversation := turn.GetConversation()
+	if last := conversation[len(conversation)-1]; last.Content != "Hello!" {
+		t.Errorf("expected the reasoning to stay out of the conversation, got %q", last.Content)
+	}
+}
+
+func TestSplitReasoningLeavesPlainContentAlone(t *testing.T) {
+	content := "Use <think> tags to show reasoning."
+	if reasoning, answer := splitReasoning(content); reasoning != "" || answer != content {
+		t.Errorf("expected no reasoning, got %q / %q", reasoning, answer)
+	}
+}
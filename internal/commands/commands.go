@@ -0,0 +1,157 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Command is a custom slash command defined as a prompt template in
+// .agenticode/commands/<name>.md
+type Command struct {
+	Name        string
+	Description string
+	Template    string
+	Path        string
+}
+
+// Registry holds the custom commands available in a session
+type Registry struct {
+	commands map[string]*Command
+}
+
+// DefaultDirs returns the directories searched for commands, lowest priority
+// first: the user's ~/.agenticode/commands, then the project's
+func DefaultDirs(projectDir string) []string {
+	var dirs []string
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".agenticode", "commands"))
+	}
+	return append(dirs, filepath.Join(projectDir, ".agenticode", "commands"))
+}
+
+// Load reads every *.md file in dirs. Commands in later directories override
+// earlier ones with the same name. Missing directories are skipped.
+func Load(dirs ...string) (*Registry, error) {
+	registry := &Registry{commands: make(map[string]*Command)}
+
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read commands directory %s: %w", dir, err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || filepath.Ext(entry.Name()) != ".md" {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read command %s: %w", path, err)
+			}
+
+			name := strings.ToLower(strings.TrimSuffix(entry.Name(), ".md"))
+			description, template := parseFrontmatter(string(data))
+			registry.commands[name] = &Command{
+				Name:        name,
+				Description: description,
+				Template:    template,
+				Path:        path,
+			}
+		}
+	}
+
+	return registry, nil
+}
+
+// Lookup returns the command with the given name (without the leading slash)
+func (r *Registry) Lookup(name string) (*Command, bool) {
+	if r == nil {
+		return nil, false
+	}
+	cmd, ok := r.commands[strings.ToLower(name)]
+	return cmd, ok
+}
+
+// List returns all commands sorted by name
+func (r *Registry) List() []*Command {
+	if r == nil {
+		return nil
+	}
+	list := make([]*Command, 0, len(r.commands))
+	for _, cmd := range r.commands {
+		list = append(list, cmd)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Parse splits "/name args..." into the command and its raw argument string.
+// It reports false if the input does not name a known command.
+func (r *Registry) Parse(input string) (*Command, string, bool) {
+	if !strings.HasPrefix(input, "/") {
+		return nil, "", false
+	}
+
+	name, args, _ := strings.Cut(strings.TrimPrefix(input, "/"), " ")
+	cmd, ok := r.Lookup(name)
+	if !ok {
+		return nil, "", false
+	}
+	return cmd, strings.TrimSpace(args), true
+}
+
+// Expand substitutes the arguments into the template. $ARGS is replaced with
+// the full argument string and $1..$9 with individual words. If the template
+// has no placeholders, the arguments are appended. Placeholders are replaced
+// in one pass, so text such as "$1" inside the arguments is left alone.
+func (c *Command) Expand(args string) string {
+	fields := strings.Fields(args)
+	used := strings.Contains(c.Template, "$ARGS")
+	pairs := []string{"$ARGS", args}
+	for i := 1; i <= 9; i++ {
+		placeholder := fmt.Sprintf("$%d", i)
+		if strings.Contains(c.Template, placeholder) {
+			used = true
+		}
+		value := ""
+		if i <= len(fields) {
+			value = fields[i-1]
+		}
+		pairs = append(pairs, placeholder, value)
+	}
+	result := strings.NewReplacer(pairs...).Replace(c.Template)
+
+	if !used && args != "" {
+		result = strings.TrimRight(result, "\n") + "\n\n" + args
+	}
+	return strings.TrimSpace(result)
+}
+
+// parseFrontmatter extracts an optional "description:" from a leading
+// ---/--- block and returns it with the remaining template
+func parseFrontmatter(content string) (string, string) {
+	if !strings.HasPrefix(content, "---\n") {
+		return "", content
+	}
+
+	header, body, ok := strings.Cut(content[4:], "\n---")
+	if !ok {
+		return "", content
+	}
+
+	description := ""
+	for _, line := range strings.Split(header, "\n") {
+		if key, value, ok := strings.Cut(line, ":"); ok && strings.TrimSpace(key) == "description" {
+			description = strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return description, strings.TrimPrefix(body, "\n")
+}
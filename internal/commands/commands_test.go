@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeCommand(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadAndExpandCustomCommand(t *testing.T) {
+	projectDir := t.TempDir()
+	commandsDir := filepath.Join(projectDir, ".agenticode", "commands")
+	writeCommand(t, commandsDir, "review.md", "---\ndescription: Review a file\n---\nReview $ARGS for bugs.\nFocus on $1 first.\n")
+	writeCommand(t, commandsDir, "notes.txt", "not a command")
+
+	registry, err := Load(filepath.Join(projectDir, "missing"), commandsDir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if len(registry.List()) != 1 {
+		t.Fatalf("expected exactly one command, got %d", len(registry.List()))
+	}
+
+	cmd, args, ok := registry.Parse("/review main.go util.go")
+	if !ok {
+		t.Fatal("expected /review to be discovered")
+	}
+	if cmd.Description != "Review a file" {
+		t.Errorf("unexpected description: %q", cmd.Description)
+	}
+
+	got := cmd.Expand(args)
+	want := "Review main.go util.go for bugs.\nFocus on main.go first."
+	if got != want {
+		t.Errorf("Expand() = %q, want %q", got, want)
+	}
+}
+
+func TestExpandLeavesPlaceholdersInArgs(t *testing.T) {
+	cmd := &Command{Name: "shell", Template: "Run $ARGS, then report on $2."}
+	if got := cmd.Expand(`echo $1 $ARGS`); got != "Run echo $1 $ARGS, then report on $1." {
+		t.Errorf("unexpected expansion: %q", got)
+	}
+}
+
+func TestExpandAppendsArgsWithoutPlaceholder(t *testing.T) {
+	cmd := &Command{Name: "explain", Template: "Explain the following code.\n"}
+	if got := cmd.Expand("cmd/root.go"); got != "Explain the following code.\n\ncmd/root.go" {
+		t.Errorf("unexpected expansion: %q", got)
+	}
+}
+
+func TestProjectCommandsOverrideUserCommands(t *testing.T) {
+	userDir := filepath.Join(t.TempDir(), "user")
+	projectDir := filepath.Join(t.TempDir(), "project")
+	writeCommand(t, userDir, "test.md", "user version")
+	writeCommand(t, projectDir, "test.md", "project version")
+
+	registry, err := Load(userDir, projectDir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	cmd, ok := registry.Lookup("test")
+	if !ok || cmd.Template != "project version" {
+		t.Errorf("expected the project command to win, got %+v", cmd)
+	}
+}
+
+func TestParseIgnoresUnknownCommands(t *testing.T) {
+	registry := &Registry{commands: map[string]*Command{}}
+	if _, _, ok := registry.Parse("/etc/hosts looks wrong"); ok {
+		t.Error("unknown commands should be passed through as prompts")
+	}
+}
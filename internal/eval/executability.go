@@ -0,0 +1,93 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// executionTimeout bounds how long a build/test command may run
+	executionTimeout = 2 * time.Minute
+
+	// maxExecutionOutput caps the captured output kept in the result
+	maxExecutionOutput = 4000
+)
+
+// CheckExecutability runs the test case's build/test command inside the
+// output directory and records the outcome in result.Execution and
+// result.Metrics.Executability. If the case has no run command and none can
+// be detected from the generated files, the check is skipped.
+func CheckExecutability(ctx context.Context, result *EvalResult) {
+	command := result.TestCase.Expect.Run
+	if command == "" {
+		command = detectRunCommand(result.OutputDir)
+	}
+	if command == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, executionTimeout)
+	defer cancel()
+
+	start := time.Now()
+	output, err := runShell(ctx, result.OutputDir, command)
+
+	check := &ExecutionCheck{
+		Command:  command,
+		Passed:   err == nil,
+		Output:   truncateOutput(output),
+		Duration: time.Since(start),
+	}
+	result.Execution = check
+	result.Metrics.Executability = check.Passed
+
+	if !check.Passed {
+		result.Errors = append(result.Errors, fmt.Sprintf("Execution check failed (%s): %v", command, err))
+	}
+}
+
+// detectRunCommand picks a build/syntax check based on the files in dir
+func detectRunCommand(dir string) string {
+	switch {
+	case fileExists(filepath.Join(dir, "go.mod")):
+		return "go build ./..."
+	case hasFiles(dir, "*.go"):
+		return "go build -o " + os.DevNull + " *.go"
+	case fileExists(filepath.Join(dir, "package.json")):
+		return "npm test --silent"
+	case hasFiles(dir, "*.js"):
+		return `for f in *.js; do node --check "$f" || exit 1; done`
+	case hasFiles(dir, "*.py"):
+		return "python3 -m py_compile *.py"
+	}
+	return ""
+}
+
+// runShell runs command with sh in dir and returns its combined output
+func runShell(ctx context.Context, dir, command string) (string, error) {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func hasFiles(dir, pattern string) bool {
+	matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+	return len(matches) > 0
+}
+
+func truncateOutput(output string) string {
+	if len(output) <= maxExecutionOutput {
+		return output
+	}
+	return output[:maxExecutionOutput] + "\n... (output truncated)"
+}
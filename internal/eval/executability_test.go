@@ -0,0 +1,95 @@
+package eval
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func writeGoFixture(t *testing.T, source string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module fixture\n\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestCheckExecutability(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	tests := []struct {
+		name   string
+		source string
+		want   bool
+	}{
+		{
+			name:   "compiles",
+			source: "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(\"hello\") }\n",
+			want:   true,
+		},
+		{
+			name:   "does not compile",
+			source: "package main\n\nfunc main() { undefinedCall() }\n",
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &EvalResult{
+				TestCase:  &TestCase{Name: tt.name},
+				OutputDir: writeGoFixture(t, tt.source),
+			}
+
+			CheckExecutability(context.Background(), result)
+
+			if result.Execution == nil {
+				t.Fatal("expected an execution check to run")
+			}
+			if result.Execution.Command != "go build ./..." {
+				t.Errorf("unexpected detected command: %q", result.Execution.Command)
+			}
+			if result.Metrics.Executability != tt.want || result.Execution.Passed != tt.want {
+				t.Errorf("executability = %v, want %v (output: %s)", result.Metrics.Executability, tt.want, result.Execution.Output)
+			}
+			if !tt.want && (len(result.Errors) == 0 || result.Execution.Output == "") {
+				t.Errorf("expected the failure and compiler output to be recorded, got errors %v", result.Errors)
+			}
+		})
+	}
+}
+
+func TestCheckExecutabilityUsesRunCommand(t *testing.T) {
+	dir := t.TempDir()
+	result := &EvalResult{
+		TestCase:  &TestCase{Expect: Expectations{Run: "echo checked && exit 3"}},
+		OutputDir: dir,
+	}
+
+	CheckExecutability(context.Background(), result)
+
+	if result.Execution == nil || result.Execution.Passed {
+		t.Fatalf("expected the configured command to run and fail, got %+v", result.Execution)
+	}
+	if result.Execution.Output != "checked\n" {
+		t.Errorf("expected captured output, got %q", result.Execution.Output)
+	}
+}
+
+func TestCheckExecutabilitySkipsWithoutCommand(t *testing.T) {
+	result := &EvalResult{TestCase: &TestCase{}, OutputDir: t.TempDir()}
+
+	CheckExecutability(context.Background(), result)
+
+	if result.Execution != nil {
+		t.Errorf("expected no check for an empty output dir, got %+v", result.Execution)
+	}
+}
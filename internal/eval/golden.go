@@ -0,0 +1,132 @@
+package eval
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// goldenContextLines is the number of unchanged lines shown around each hunk
+const goldenContextLines = 3
+
+// CheckGoldenFiles compares generated files against their golden files and
+// records a unified diff in result.Errors for each mismatch. With update set,
+// the golden files are rewritten from the generated output instead.
+func CheckGoldenFiles(result *EvalResult, update bool) error {
+	for _, expect := range result.TestCase.Expect.Files {
+		if expect.GoldenFile == "" {
+			continue
+		}
+
+		goldenPath := expect.GoldenFile
+		if !filepath.IsAbs(goldenPath) && result.TestCase.Dir != "" {
+			goldenPath = filepath.Join(result.TestCase.Dir, goldenPath)
+		}
+
+		generated, err := os.ReadFile(filepath.Join(result.OutputDir, expect.Path))
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("Golden check for %s: generated file not found", expect.Path))
+			continue
+		}
+
+		if update {
+			if err := os.MkdirAll(filepath.Dir(goldenPath), 0755); err != nil {
+				return fmt.Errorf("failed to create golden file directory: %w", err)
+			}
+			if err := os.WriteFile(goldenPath, generated, 0644); err != nil {
+				return fmt.Errorf("failed to update golden file %s: %w", goldenPath, err)
+			}
+			continue
+		}
+
+		golden, err := os.ReadFile(goldenPath)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("Golden file %s not found (run with --update-golden to create it)", expect.GoldenFile))
+			continue
+		}
+
+		if string(golden) != string(generated) {
+			diff := unifiedLineDiff(string(golden), string(generated), expect.GoldenFile, expect.Path)
+			result.Errors = append(result.Errors, fmt.Sprintf("Golden file mismatch for %s:\n%s", expect.Path, diff))
+		}
+	}
+
+	return nil
+}
+
+// diffLine is a single line of a line-level diff
+type diffLine struct {
+	op   diffmatchpatch.Operation
+	text string
+}
+
+// unifiedLineDiff renders a line-based unified diff from expected to actual
+func unifiedLineDiff(expected, actual, expectedName, actualName string) string {
+	dmp := diffmatchpatch.New()
+	a, b, lineArray := dmp.DiffLinesToChars(expected, actual)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(a, b, false), lineArray)
+
+	var lines []diffLine
+	for _, d := range diffs {
+		for _, text := range strings.SplitAfter(d.Text, "\n") {
+			if text != "" {
+				lines = append(lines, diffLine{op: d.Type, text: strings.TrimSuffix(text, "\n")})
+			}
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", expectedName, actualName)
+
+	// Line numbers (1-based) in expected/actual at the start of each line
+	oldLine, newLine := make([]int, len(lines)+1), make([]int, len(lines)+1)
+	oldLine[0], newLine[0] = 1, 1
+	for i, l := range lines {
+		oldLine[i+1], newLine[i+1] = oldLine[i], newLine[i]
+		if l.op != diffmatchpatch.DiffInsert {
+			oldLine[i+1]++
+		}
+		if l.op != diffmatchpatch.DiffDelete {
+			newLine[i+1]++
+		}
+	}
+
+	for i := 0; i < len(lines); {
+		if lines[i].op == diffmatchpatch.DiffEqual {
+			i++
+			continue
+		}
+
+		// Extend the hunk while changes are within two contexts of each other
+		start := max(i-goldenContextLines, 0)
+		end := i
+		for j := i; j < len(lines); j++ {
+			if lines[j].op != diffmatchpatch.DiffEqual {
+				end = j + 1
+			} else if j-end >= 2*goldenContextLines {
+				break
+			}
+		}
+		end = min(end+goldenContextLines, len(lines))
+
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n",
+			oldLine[start], oldLine[end]-oldLine[start],
+			newLine[start], newLine[end]-newLine[start])
+		for _, l := range lines[start:end] {
+			switch l.op {
+			case diffmatchpatch.DiffDelete:
+				out.WriteString("-" + l.text + "\n")
+			case diffmatchpatch.DiffInsert:
+				out.WriteString("+" + l.text + "\n")
+			default:
+				out.WriteString(" " + l.text + "\n")
+			}
+		}
+		i = end
+	}
+
+	return out.String()
+}
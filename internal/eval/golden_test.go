@@ -0,0 +1,98 @@
+package eval
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func goldenFixture(t *testing.T, golden, generated string) *EvalResult {
+	t.Helper()
+	casesDir := t.TempDir()
+	outputDir := t.TempDir()
+
+	if golden != "" {
+		if err := os.MkdirAll(filepath.Join(casesDir, "golden"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(casesDir, "golden", "main.go"), []byte(golden), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "main.go"), []byte(generated), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return &EvalResult{
+		TestCase: &TestCase{
+			Dir: casesDir,
+			Expect: Expectations{Files: []FileExpectation{
+				{Path: "main.go", GoldenFile: "golden/main.go"},
+			}},
+		},
+		OutputDir: outputDir,
+	}
+}
+
+const goldenSource = "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hello\")\n}\n"
+
+func TestCheckGoldenFilesMatch(t *testing.T) {
+	result := goldenFixture(t, goldenSource, goldenSource)
+
+	if err := CheckGoldenFiles(result, false); err != nil {
+		t.Fatalf("CheckGoldenFiles failed: %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Errorf("expected no errors for a match, got %v", result.Errors)
+	}
+}
+
+func TestCheckGoldenFilesMismatchRecordsDiff(t *testing.T) {
+	generated := strings.Replace(goldenSource, `"hello"`, `"goodbye"`, 1)
+	result := goldenFixture(t, goldenSource, generated)
+
+	if err := CheckGoldenFiles(result, false); err != nil {
+		t.Fatalf("CheckGoldenFiles failed: %v", err)
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("expected one mismatch error, got %v", result.Errors)
+	}
+
+	diff := result.Errors[0]
+	for _, want := range []string{
+		"--- golden/main.go",
+		"+++ main.go",
+		"@@ -3,5 +3,5 @@",
+		"-\tfmt.Println(\"hello\")",
+		"+\tfmt.Println(\"goodbye\")",
+		" func main() {",
+	} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("expected diff to contain %q, got:\n%s", want, diff)
+		}
+	}
+	if strings.Contains(diff, " package main") {
+		t.Errorf("expected lines far from the change to be left out, got:\n%s", diff)
+	}
+}
+
+func TestCheckGoldenFilesUpdate(t *testing.T) {
+	result := goldenFixture(t, "", goldenSource)
+
+	if err := CheckGoldenFiles(result, true); err != nil {
+		t.Fatalf("CheckGoldenFiles update failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(result.TestCase.Dir, "golden", "main.go"))
+	if err != nil {
+		t.Fatalf("expected golden file to be written: %v", err)
+	}
+	if string(data) != goldenSource {
+		t.Errorf("golden file content = %q, want %q", data, goldenSource)
+	}
+
+	if err := CheckGoldenFiles(result, false); err != nil || len(result.Errors) != 0 {
+		t.Errorf("expected the updated golden file to match, got err=%v errors=%v", err, result.Errors)
+	}
+}
@@ -25,6 +25,8 @@ func LoadTestCase(path string) (*TestCase, error) {
 		tc.Name = filepath.Base(path)
 	}
 
+	tc.Dir = filepath.Dir(path)
+
 	// Use description as prompt if prompt not specified
 	if tc.Prompt == "" {
 		tc.Prompt = tc.Description
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/tabwriter"
+	"time"
 )
 
 // Reporter handles evaluation result reporting
@@ -65,6 +67,18 @@ func (r *Reporter) Report(results []*EvalResult) {
 		float64(totalPassed)/float64(len(results))*100,
 	)
 
+	// Per-tag breakdown
+	if tagSummaries := GroupByTag(results); len(tagSummaries) > 0 {
+		fmt.Println("\n🏷️  Results by tag")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "Tag\tPassed\tTotal\tPass Rate")
+		fmt.Fprintln(w, "---\t------\t-----\t---------")
+		for _, ts := range tagSummaries {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%.1f%%\n", ts.Tag, ts.Passed, ts.Total, ts.PassRate*100)
+		}
+		w.Flush()
+	}
+
 	// Detailed results if verbose
 	if r.verbose {
 		r.reportDetailed(results)
@@ -92,6 +106,17 @@ func (r *Reporter) reportDetailed(results []*EvalResult) {
 			}
 		}
 
+		if result.Execution != nil {
+			status := "✅ passed"
+			if !result.Execution.Passed {
+				status = "❌ failed"
+			}
+			fmt.Printf("\n⚙️  Execution: %s (%s, %v)\n", status, result.Execution.Command, result.Execution.Duration.Round(time.Millisecond))
+			if !result.Execution.Passed && result.Execution.Output != "" {
+				fmt.Printf("%s\n", strings.TrimSpace(result.Execution.Output))
+			}
+		}
+
 		if result.Metrics.GPTScore != nil {
 			fmt.Printf("\n🤖 GPT Evaluation:\n")
 			fmt.Printf("  Score: %d/10\n", result.Metrics.GPTScore.Score)
@@ -120,11 +145,66 @@ func (r *Reporter) SaveJSON(results []*EvalResult, outputPath string) error {
 
 // GenerateSummary creates a summary report
 type Summary struct {
-	TotalTests     int     `json:"total_tests"`
-	Passed         int     `json:"passed"`
-	Failed         int     `json:"failed"`
-	PassRate       float64 `json:"pass_rate"`
-	AverageMetrics Metrics `json:"average_metrics"`
+	TotalTests     int          `json:"total_tests"`
+	Passed         int          `json:"passed"`
+	Failed         int          `json:"failed"`
+	PassRate       float64      `json:"pass_rate"`
+	AverageMetrics Metrics      `json:"average_metrics"`
+	ByTag          []TagSummary `json:"by_tag,omitempty"`
+}
+
+// TagSummary aggregates results for all test cases sharing a tag
+type TagSummary struct {
+	Tag      string  `json:"tag"`
+	Total    int     `json:"total"`
+	Passed   int     `json:"passed"`
+	Failed   int     `json:"failed"`
+	PassRate float64 `json:"pass_rate"`
+}
+
+// untaggedLabel groups test cases without tags in the per-tag breakdown
+const untaggedLabel = "(untagged)"
+
+// GroupByTag computes pass rates per tag, sorted by tag name. A case with
+// several tags counts towards each of them. It returns nil when no case is tagged.
+func GroupByTag(results []*EvalResult) []TagSummary {
+	byTag := make(map[string]*TagSummary)
+	tagged := false
+
+	for _, result := range results {
+		tags := result.TestCase.Tags
+		if len(tags) == 0 {
+			tags = []string{untaggedLabel}
+		} else {
+			tagged = true
+		}
+
+		for _, tag := range tags {
+			ts, ok := byTag[tag]
+			if !ok {
+				ts = &TagSummary{Tag: tag}
+				byTag[tag] = ts
+			}
+			ts.Total++
+			if result.Success {
+				ts.Passed++
+			} else {
+				ts.Failed++
+			}
+		}
+	}
+
+	if !tagged {
+		return nil
+	}
+
+	summaries := make([]TagSummary, 0, len(byTag))
+	for _, ts := range byTag {
+		ts.PassRate = float64(ts.Passed) / float64(ts.Total)
+		summaries = append(summaries, *ts)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Tag < summaries[j].Tag })
+	return summaries
 }
 
 func (r *Reporter) GenerateSummary(results []*EvalResult) Summary {
@@ -146,6 +226,7 @@ func (r *Reporter) GenerateSummary(results []*EvalResult) Summary {
 		summary.PassRate = float64(summary.Passed) / float64(summary.TotalTests)
 		summary.AverageMetrics.PassRate = totalPassRate / float64(len(results))
 	}
+	summary.ByTag = GroupByTag(results)
 
 	return summary
 }
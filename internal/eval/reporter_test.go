@@ -0,0 +1,92 @@
+package eval
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func taggedResult(name string, success bool, tags ...string) *EvalResult {
+	return &EvalResult{
+		TestCase: &TestCase{Name: name, Tags: tags},
+		Success:  success,
+	}
+}
+
+func TestGroupByTag(t *testing.T) {
+	results := []*EvalResult{
+		taggedResult("http-server", true, "http"),
+		taggedResult("http-client", false, "http"),
+		taggedResult("cli-flags", true, "cli"),
+		taggedResult("cli-refactor", false, "cli", "refactor"),
+		taggedResult("misc", true),
+	}
+
+	summaries := GroupByTag(results)
+
+	want := map[string]TagSummary{
+		"(untagged)": {Tag: "(untagged)", Total: 1, Passed: 1, PassRate: 1},
+		"cli":        {Tag: "cli", Total: 2, Passed: 1, Failed: 1, PassRate: 0.5},
+		"http":       {Tag: "http", Total: 2, Passed: 1, Failed: 1, PassRate: 0.5},
+		"refactor":   {Tag: "refactor", Total: 1, Failed: 1, PassRate: 0},
+	}
+	if len(summaries) != len(want) {
+		t.Fatalf("expected %d tag summaries, got %d: %+v", len(want), len(summaries), summaries)
+	}
+	for i, ts := range summaries {
+		if ts != want[ts.Tag] {
+			t.Errorf("summary for %q = %+v, want %+v", ts.Tag, ts, want[ts.Tag])
+		}
+		if i > 0 && summaries[i-1].Tag > ts.Tag {
+			t.Errorf("summaries not sorted: %q before %q", summaries[i-1].Tag, ts.Tag)
+		}
+	}
+
+	summary := NewReporter(false).GenerateSummary(results)
+	if len(summary.ByTag) != len(want) {
+		t.Errorf("expected summary to include per-tag breakdown, got %+v", summary.ByTag)
+	}
+}
+
+func TestGroupByTagWithoutTags(t *testing.T) {
+	if summaries := GroupByTag([]*EvalResult{taggedResult("a", true)}); summaries != nil {
+		t.Errorf("expected no breakdown without tags, got %+v", summaries)
+	}
+}
+
+func TestSaveJSONIncludesTags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.json")
+	if err := NewReporter(false).SaveJSON([]*EvalResult{taggedResult("http-server", true, "http")}, path); err != nil {
+		t.Fatalf("SaveJSON failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if !strings.Contains(string(data), `"http"`) {
+		t.Errorf("expected tags in JSON output:\n%s", data)
+	}
+}
+
+func TestLoadTestCaseTags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "case.yaml")
+	content := "name: server\nprompt: build a server\ntags: [http, go]\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tc, err := LoadTestCase(path)
+	if err != nil {
+		t.Fatalf("LoadTestCase failed: %v", err)
+	}
+	if len(tc.Tags) != 2 || tc.Tags[0] != "http" || tc.Tags[1] != "go" {
+		t.Errorf("unexpected tags: %v", tc.Tags)
+	}
+}
@@ -0,0 +1,246 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/agent"
+	"github.com/trknhr/agenticode/internal/llm"
+)
+
+// maxGeneratedFileSize skips large files when collecting the generated output
+const maxGeneratedFileSize = 256 * 1024
+
+// RunnerConfig controls how test cases are run
+type RunnerConfig struct {
+	MaxSteps      int  // Agent steps per test case
+	Deterministic bool // Sample with temperature 0 and a fixed seed so runs are comparable
+	UseGPT        bool // Score the output against the test case's criteria with the model
+	KeepFailed    bool // Keep the output directory of a failed test case for inspection
+	UpdateGolden  bool // Rewrite golden files from the generated output instead of comparing
+}
+
+// Runner generates code for test cases with the agent and checks the output
+type Runner struct {
+	client llm.Client
+	config RunnerConfig
+}
+
+// NewRunner creates a runner that generates code with client
+func NewRunner(client llm.Client, config RunnerConfig) *Runner {
+	if config.MaxSteps <= 0 {
+		config.MaxSteps = 10
+	}
+	return &Runner{client: client, config: config}
+}
+
+// Run generates code for tc in a fresh output directory, prepared by the
+// case's setup steps, evaluates it and then runs the teardown steps
+func (r *Runner) Run(ctx context.Context, tc *TestCase) *EvalResult {
+	result := &EvalResult{
+		TestCase:       tc,
+		GeneratedFiles: make(map[string]string),
+		ExecutedAt:     time.Now(),
+	}
+
+	outputDir, err := os.MkdirTemp("", "agenticode-eval-")
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("Failed to create output dir: %v", err))
+		return result
+	}
+	result.OutputDir = outputDir
+	defer func() {
+		if result.Success || !r.config.KeepFailed {
+			os.RemoveAll(outputDir)
+			result.OutputDir = ""
+		}
+	}()
+
+	if err := RunSetup(ctx, tc, outputDir); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("Setup failed: %v", err))
+		return result
+	}
+	defer func() {
+		if err := RunTeardown(ctx, tc, outputDir); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("Teardown failed: %v", err))
+			result.Success = false
+		}
+	}()
+
+	if err := r.generate(ctx, tc.Prompt, outputDir); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("Generation failed: %v", err))
+		return result
+	}
+
+	collectGeneratedFiles(result)
+	runStaticChecks(result)
+	if err := CheckGoldenFiles(result, r.config.UpdateGolden); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+	CheckExecutability(ctx, result)
+
+	if r.config.UseGPT && len(tc.Criteria) > 0 {
+		evaluation, err := r.judge(ctx, result)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("GPT evaluation failed: %v", err))
+		}
+		result.Metrics.GPTScore = evaluation
+	}
+
+	result.Success = len(result.Errors) == 0
+	return result
+}
+
+// generate runs the agent on prompt inside outputDir. The agent's tools
+// resolve paths against the working directory, so test cases run one at a
+// time.
+func (r *Runner) generate(ctx context.Context, prompt, outputDir string) error {
+	previous, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(outputDir); err != nil {
+		return err
+	}
+	defer os.Chdir(previous)
+
+	a := agent.NewAgent(r.client,
+		agent.WithMaxSteps(r.config.MaxSteps),
+		agent.WithDeterministic(r.config.Deterministic),
+		agent.WithApprover(&agent.SimpleAutoApprover{}),
+		agent.WithQuiet(true),
+	)
+	response, _, err := a.ExecuteWithHistory(ctx, []openai.ChatCompletionMessage{
+		{Role: "user", Content: prompt},
+	}, false)
+	if err != nil {
+		return err
+	}
+	if !response.Success {
+		return errors.New(response.Message)
+	}
+	return nil
+}
+
+// collectGeneratedFiles records the text files in the output dir, skipping
+// version control and dependency directories
+func collectGeneratedFiles(result *EvalResult) {
+	filepath.WalkDir(result.OutputDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case ".git", "node_modules", "vendor", "__pycache__":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.Size() > maxGeneratedFileSize {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(result.OutputDir, path)
+		result.GeneratedFiles[filepath.ToSlash(rel)] = string(content)
+		return nil
+	})
+}
+
+// runStaticChecks checks that the expected files exist (or do not) and
+// contain the expected strings, and sets the pass rate over those checks
+func runStaticChecks(result *EvalResult) {
+	total, passed := 0, 0
+	for _, expect := range result.TestCase.Expect.Files {
+		shouldExist := expect.ShouldExist == nil || *expect.ShouldExist
+		content, err := os.ReadFile(filepath.Join(result.OutputDir, expect.Path))
+		exists := err == nil
+
+		total++
+		switch {
+		case exists == shouldExist:
+			passed++
+		case shouldExist:
+			result.Errors = append(result.Errors, fmt.Sprintf("Expected file %s was not generated", expect.Path))
+		default:
+			result.Errors = append(result.Errors, fmt.Sprintf("File %s should not exist", expect.Path))
+		}
+		if !exists {
+			total += len(expect.ShouldContain)
+			continue
+		}
+
+		for _, want := range expect.ShouldContain {
+			total++
+			if strings.Contains(string(content), want) {
+				passed++
+			} else {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s does not contain %q", expect.Path, want))
+			}
+		}
+	}
+
+	result.Metrics.PassRate = 1
+	if total > 0 {
+		result.Metrics.PassRate = float64(passed) / float64(total)
+	}
+}
+
+// judge asks the model to score the generated files against the test
+// case's criteria
+func (r *Runner) judge(ctx context.Context, result *EvalResult) (*GPTEvaluation, error) {
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Task given to the code generator:\n%s\n\nCriteria:\n", result.TestCase.Prompt)
+	for _, criterion := range result.TestCase.Criteria {
+		fmt.Fprintf(&prompt, "- %s\n", criterion)
+	}
+	prompt.WriteString("\nGenerated files:\n")
+	for path, content := range result.GeneratedFiles {
+		fmt.Fprintf(&prompt, "\n=== %s ===\n%s\n", path, content)
+	}
+
+	resp, err := r.client.Generate(ctx, []openai.ChatCompletionMessage{
+		{
+			Role: "system",
+			Content: `You review generated code against a list of criteria. Reply with JSON only, in the form
+{"score": 0-10, "reasoning": "...", "feedback": "...", "criteria_scores": {"<criterion>": 0-10}}`,
+		},
+		{Role: "user", Content: prompt.String()},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Choices) == 0 {
+		return nil, errors.New("no response from the model")
+	}
+
+	var reply struct {
+		Score          int            `json:"score"`
+		Reasoning      string         `json:"reasoning"`
+		Feedback       string         `json:"feedback"`
+		CriteriaScores map[string]int `json:"criteria_scores"`
+	}
+	content := strings.TrimSpace(resp.Choices[0].Message.Content)
+	content = strings.TrimPrefix(strings.TrimPrefix(content, "```json"), "```")
+	content = strings.TrimSuffix(strings.TrimSpace(content), "```")
+	if err := json.Unmarshal([]byte(content), &reply); err != nil {
+		return nil, fmt.Errorf("unreadable evaluation %q: %w", content, err)
+	}
+	return &GPTEvaluation{
+		Score:          reply.Score,
+		Reasoning:      reply.Reasoning,
+		Feedback:       reply.Feedback,
+		CriteriaScores: reply.CriteriaScores,
+	}, nil
+}
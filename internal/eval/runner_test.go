@@ -0,0 +1,207 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/llm"
+)
+
+// scriptedClient returns its responses in order, then a plain "done"
+type scriptedClient struct {
+	responses     []openai.ChatCompletionResponse
+	requests      [][]openai.ChatCompletionMessage
+	deterministic bool
+}
+
+func (c *scriptedClient) SetDeterministic(enabled bool) {
+	c.deterministic = enabled
+}
+
+func (c *scriptedClient) Generate(ctx context.Context, messages []openai.ChatCompletionMessage, tools []openai.Tool) (openai.ChatCompletionResponse, error) {
+	c.requests = append(c.requests, messages)
+	if len(c.responses) == 0 {
+		return textReply("done"), nil
+	}
+	resp := c.responses[0]
+	c.responses = c.responses[1:]
+	return resp, nil
+}
+
+func (c *scriptedClient) Stream(ctx context.Context, messages []openai.ChatCompletionMessage, tools []openai.Tool) (llm.ChatStream, error) {
+	return nil, errors.New("streaming not supported")
+}
+
+func textReply(content string) openai.ChatCompletionResponse {
+	return openai.ChatCompletionResponse{Choices: []openai.ChatCompletionChoice{{
+		Message:      openai.ChatCompletionMessage{Role: "assistant", Content: content},
+		FinishReason: openai.FinishReasonStop,
+	}}}
+}
+
+func writeFileReply(path, content string) openai.ChatCompletionResponse {
+	args, _ := json.Marshal(map[string]string{"path": path, "content": content})
+	return openai.ChatCompletionResponse{Choices: []openai.ChatCompletionChoice{{
+		Message: openai.ChatCompletionMessage{Role: "assistant", ToolCalls: []openai.ToolCall{{
+			ID:       "call-1",
+			Type:     openai.ToolTypeFunction,
+			Function: openai.FunctionCall{Name: "write_file", Arguments: string(args)},
+		}}},
+		FinishReason: openai.FinishReasonToolCalls,
+	}}}
+}
+
+func TestRunnerChecksGeneratedFiles(t *testing.T) {
+	wd, _ := os.Getwd()
+	tc := &TestCase{
+		Name:   "notes",
+		Prompt: "write the notes",
+		Expect: Expectations{
+			Files: []FileExpectation{{Path: "notes.txt", ShouldContain: []string{"hello", "missing"}}},
+			Run:   "test -f notes.txt",
+		},
+	}
+	client := &scriptedClient{responses: []openai.ChatCompletionResponse{
+		writeFileReply("notes.txt", "hello\n"),
+		textReply("wrote notes.txt"),
+	}}
+
+	result := NewRunner(client, RunnerConfig{KeepFailed: true}).Run(context.Background(), tc)
+	if result.OutputDir != "" {
+		defer os.RemoveAll(result.OutputDir)
+	}
+
+	if got, _ := os.Getwd(); got != wd {
+		t.Errorf("expected the working directory to be restored, got %s", got)
+	}
+	if result.Success || len(result.Errors) != 1 || !strings.Contains(result.Errors[0], `"missing"`) {
+		t.Fatalf("expected one failed contains check, got %+v", result.Errors)
+	}
+	if result.Metrics.PassRate < 0.66 || result.Metrics.PassRate > 0.67 {
+		t.Errorf("expected 2 of 3 checks to pass, got %v", result.Metrics.PassRate)
+	}
+	if result.GeneratedFiles["notes.txt"] != "hello\n" {
+		t.Errorf("expected notes.txt to be collected, got %v", result.GeneratedFiles)
+	}
+	if result.Execution == nil || !result.Execution.Passed {
+		t.Errorf("expected the run command to pass in the output dir, got %+v", result.Execution)
+	}
+	if result.OutputDir == "" {
+		t.Error("expected a failed case's output dir to be kept with KeepFailed")
+	}
+}
+
+func TestRunnerRemovesPassingOutput(t *testing.T) {
+	tc := &TestCase{
+		Name:     "notes",
+		Prompt:   "write the notes",
+		Expect:   Expectations{Files: []FileExpectation{{Path: "notes.txt", ShouldContain: []string{"hello"}}}},
+		Criteria: []string{"Says hello"},
+	}
+	client := &scriptedClient{responses: []openai.ChatCompletionResponse{
+		writeFileReply("notes.txt", "hello\n"),
+		textReply("wrote notes.txt"),
+		textReply("```json\n{\"score\": 8, \"reasoning\": \"greets\", \"criteria_scores\": {\"Says hello\": 9}}\n```"),
+	}}
+
+	result := NewRunner(client, RunnerConfig{UseGPT: true, KeepFailed: true}).Run(context.Background(), tc)
+	if !result.Success {
+		t.Fatalf("expected the case to pass, got %+v", result.Errors)
+	}
+	if result.OutputDir != "" {
+		t.Errorf("expected a passing case's output dir to be removed, got %s", result.OutputDir)
+	}
+	if score := result.Metrics.GPTScore; score == nil || score.Score != 8 || score.CriteriaScores["Says hello"] != 9 {
+		t.Errorf("unexpected evaluation %+v", score)
+	}
+}
+
+func TestRunnerRunsSetupAndTeardown(t *testing.T) {
+	casesDir := t.TempDir()
+	os.MkdirAll(filepath.Join(casesDir, "fixture"), 0755)
+	os.WriteFile(filepath.Join(casesDir, "fixture", "app.js"), []byte("const routes = [];\n"), 0644)
+	marker := filepath.Join(t.TempDir(), "torn-down")
+
+	tc := &TestCase{
+		Name:   "add-route",
+		Prompt: "add a route",
+		Dir:    casesDir,
+		Setup:  &Setup{Fixture: "fixture", Commands: []string{"echo ready > setup.txt"}},
+		Expect: Expectations{Files: []FileExpectation{
+			{Path: "app.js", ShouldContain: []string{"/health"}},
+			{Path: "setup.txt", ShouldContain: []string{"ready"}},
+		}, Run: "true"},
+		Teardown: []string{"touch " + marker},
+	}
+	client := &scriptedClient{responses: []openai.ChatCompletionResponse{
+		writeFileReply("app.js", "const routes = ['/health'];\n"),
+		textReply("added the route"),
+	}}
+
+	result := NewRunner(client, RunnerConfig{}).Run(context.Background(), tc)
+	if !result.Success {
+		t.Fatalf("expected the modified fixture to pass, got %+v", result.Errors)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Error("expected the teardown command to run")
+	}
+
+	tc.Setup.Commands = []string{"false"}
+	result = NewRunner(&scriptedClient{}, RunnerConfig{}).Run(context.Background(), tc)
+	if result.Success || len(result.Errors) != 1 || !strings.HasPrefix(result.Errors[0], "Setup failed") {
+		t.Errorf("expected a failed setup to stop the case, got %+v", result.Errors)
+	}
+}
+
+func TestRunnerComparesAndUpdatesGoldenFiles(t *testing.T) {
+	casesDir := t.TempDir()
+	golden := filepath.Join(casesDir, "golden", "notes.txt")
+	tc := &TestCase{
+		Name:   "notes",
+		Prompt: "write the notes",
+		Dir:    casesDir,
+		Expect: Expectations{Files: []FileExpectation{{Path: "notes.txt", GoldenFile: "golden/notes.txt"}}, Run: "true"},
+	}
+	run := func(update bool) *EvalResult {
+		client := &scriptedClient{responses: []openai.ChatCompletionResponse{
+			writeFileReply("notes.txt", "hello\n"),
+			textReply("wrote notes.txt"),
+		}}
+		return NewRunner(client, RunnerConfig{UpdateGolden: update}).Run(context.Background(), tc)
+	}
+
+	if result := run(false); result.Success || !strings.Contains(strings.Join(result.Errors, "\n"), "--update-golden") {
+		t.Fatalf("expected a missing golden file to fail, got %+v", result.Errors)
+	}
+	if result := run(true); !result.Success {
+		t.Fatalf("expected the update run to pass, got %+v", result.Errors)
+	}
+	if data, _ := os.ReadFile(golden); string(data) != "hello\n" {
+		t.Fatalf("expected the golden file to be written, got %q", data)
+	}
+	if result := run(false); !result.Success {
+		t.Errorf("expected output matching the golden file to pass, got %+v", result.Errors)
+	}
+
+	os.WriteFile(golden, []byte("goodbye\n"), 0644)
+	if result := run(false); result.Success || !strings.Contains(strings.Join(result.Errors, "\n"), "-goodbye") {
+		t.Errorf("expected a mismatch with a diff, got %+v", result.Errors)
+	}
+}
+
+func TestRunnerCanSampleDeterministically(t *testing.T) {
+	tc := &TestCase{Name: "noop", Prompt: "say hi"}
+	for _, deterministic := range []bool{true, false} {
+		client := &scriptedClient{deterministic: !deterministic}
+		NewRunner(client, RunnerConfig{Deterministic: deterministic}).Run(context.Background(), tc)
+		if client.deterministic != deterministic {
+			t.Errorf("expected deterministic=%v to reach the client", deterministic)
+		}
+	}
+}
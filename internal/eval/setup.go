@@ -0,0 +1,88 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RunSetup prepares outputDir for the test case: the fixture directory is
+// copied in first, then the setup commands run inside it. The runner calls
+// this after creating the output dir and before generating code.
+func RunSetup(ctx context.Context, tc *TestCase, outputDir string) error {
+	if tc.Setup == nil {
+		return nil
+	}
+
+	if tc.Setup.Fixture != "" {
+		fixture := tc.Setup.Fixture
+		if !filepath.IsAbs(fixture) && tc.Dir != "" {
+			fixture = filepath.Join(tc.Dir, fixture)
+		}
+		if err := copyDir(fixture, outputDir); err != nil {
+			return fmt.Errorf("failed to copy fixture %s: %w", tc.Setup.Fixture, err)
+		}
+	}
+
+	for _, command := range tc.Setup.Commands {
+		if output, err := runShell(ctx, outputDir, command); err != nil {
+			return fmt.Errorf("setup command %q failed: %w\n%s", command, err, strings.TrimSpace(output))
+		}
+	}
+
+	return nil
+}
+
+// RunTeardown runs the teardown commands in outputDir. Every command runs even
+// if an earlier one fails; all failures are returned together.
+func RunTeardown(ctx context.Context, tc *TestCase, outputDir string) error {
+	var errs []error
+	for _, command := range tc.Teardown {
+		if output, err := runShell(ctx, outputDir, command); err != nil {
+			errs = append(errs, fmt.Errorf("teardown command %q failed: %w\n%s", command, err, strings.TrimSpace(output)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// copyDir recursively copies the contents of src into dst
+func copyDir(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if info.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		return copyFile(path, target, info.Mode())
+	})
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,91 @@
+package eval
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetupCopiesFixtureBeforeGeneration(t *testing.T) {
+	casesDir := t.TempDir()
+	fixtureDir := filepath.Join(casesDir, "fixtures", "express")
+	if err := os.MkdirAll(filepath.Join(fixtureDir, "routes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	app := "const express = require('express');\nconst app = express();\n"
+	if err := os.WriteFile(filepath.Join(fixtureDir, "routes", "app.js"), []byte(app), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	casePath := filepath.Join(casesDir, "add-route.yaml")
+	yaml := `name: add-route
+prompt: Add a /health route to the Express app
+setup:
+  fixture: fixtures/express
+  commands:
+    - echo ready > setup.log
+teardown:
+  - rm setup.log
+`
+	if err := os.WriteFile(casePath, []byte(yaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tc, err := LoadTestCase(casePath)
+	if err != nil {
+		t.Fatalf("LoadTestCase failed: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	if err := RunSetup(context.Background(), tc, outputDir); err != nil {
+		t.Fatalf("RunSetup failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "setup.log")); err != nil {
+		t.Errorf("expected setup command to run in the output dir: %v", err)
+	}
+
+	// Stand in for the generator modifying the existing project
+	appPath := filepath.Join(outputDir, "routes", "app.js")
+	f, err := os.OpenFile(appPath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("fixture was not copied: %v", err)
+	}
+	f.WriteString("app.get('/health', (req, res) => res.send('ok'));\n")
+	f.Close()
+
+	data, err := os.ReadFile(appPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"const app = express();", "app.get('/health'"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected modified app.js to contain %q, got:\n%s", want, data)
+		}
+	}
+
+	if err := RunTeardown(context.Background(), tc, outputDir); err != nil {
+		t.Fatalf("RunTeardown failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "setup.log")); !os.IsNotExist(err) {
+		t.Error("expected teardown to remove setup.log")
+	}
+}
+
+func TestSetupAndTeardownReportFailures(t *testing.T) {
+	outputDir := t.TempDir()
+
+	tc := &TestCase{Setup: &Setup{Commands: []string{"exit 1"}}}
+	if err := RunSetup(context.Background(), tc, outputDir); err == nil {
+		t.Error("expected a failing setup command to abort setup")
+	}
+
+	tc = &TestCase{Teardown: []string{"exit 1", "touch cleaned"}}
+	if err := RunTeardown(context.Background(), tc, outputDir); err == nil {
+		t.Error("expected the teardown failure to be reported")
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "cleaned")); err != nil {
+		t.Error("expected later teardown commands to run after a failure")
+	}
+}
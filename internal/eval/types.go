@@ -11,11 +11,23 @@ type TestCase struct {
 	Prompt      string       `yaml:"prompt"`
 	Expect      Expectations `yaml:"expect"`
 	Criteria    []string     `yaml:"criteria"`
+	Tags        []string     `yaml:"tags"` // Categories used to group results (e.g. http, cli, refactor)
+	Setup       *Setup       `yaml:"setup,omitempty"`
+	Teardown    []string     `yaml:"teardown,omitempty"` // Shell commands run in the output dir after evaluation
+	Dir         string       `yaml:"-"`                  // Directory of the test case file; fixtures are resolved against it
+}
+
+// Setup prepares the output directory before generation so a test case can
+// start from an existing project instead of an empty directory
+type Setup struct {
+	Fixture  string   `yaml:"fixture"`  // Directory copied into the output dir
+	Commands []string `yaml:"commands"` // Shell commands run in the output dir after the fixture is copied
 }
 
 // Expectations defines what to check in generated files
 type Expectations struct {
 	Files []FileExpectation `yaml:"files"`
+	Run   string            `yaml:"run,omitempty"` // Build/test command run in the output dir; detected from the files if empty
 }
 
 // FileExpectation defines expectations for a single file
@@ -23,6 +35,7 @@ type FileExpectation struct {
 	Path          string   `yaml:"path"`
 	ShouldContain []string `yaml:"should_contain"`
 	ShouldExist   *bool    `yaml:"should_exist,omitempty"`
+	GoldenFile    string   `yaml:"golden_file,omitempty"` // Expected exact content, relative to the test case file
 }
 
 // EvalResult represents the evaluation result for a test case
@@ -34,6 +47,15 @@ type EvalResult struct {
 	GeneratedFiles map[string]string
 	OutputDir      string
 	ExecutedAt     time.Time
+	Execution      *ExecutionCheck
+}
+
+// ExecutionCheck records the outcome of building or running the generated code
+type ExecutionCheck struct {
+	Command  string
+	Passed   bool
+	Output   string
+	Duration time.Duration
 }
 
 // Metrics contains evaluation metrics
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 )
 
 // ConfigPaths defines the order of configuration files to check
@@ -155,6 +156,16 @@ func validateHookMatcher(event string, index int, matcher HookMatcher) error {
 				return fmt.Errorf("%s[%d]: invalid matcher pattern: %w", event, index, err)
 			}
 		}
+		for name, pattern := range matcher.ToolInput {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("%s[%d]: invalid tool_input pattern for %q: %w", event, index, name, err)
+			}
+		}
+		for _, glob := range matcher.Paths {
+			if _, err := compileGlob(glob); err != nil {
+				return fmt.Errorf("%s[%d]: invalid paths glob: %w", event, index, err)
+			}
+		}
 	}
 
 	return nil
@@ -0,0 +1,103 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// pathArguments are the tool arguments that name the file a tool acts on
+var pathArguments = []string{"path", "file_path"}
+
+// compileGlob turns a path glob into a regex. "*" and "?" stay within one
+// path segment, "**" spans directories and "[...]" is a character class.
+func compileGlob(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?") // "**/" also matches no directory
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated character class in %q", pattern)
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// matchesPaths checks the matcher's path globs against the file the tool
+// targets. A glob without "/" matches the file name anywhere; one with "/"
+// matches the path relative to the working directory.
+func (m *Manager) matchesPaths(globs []string, args map[string]interface{}, event HookEvent) bool {
+	if len(globs) == 0 || (event != PreToolUse && event != PostToolUse) {
+		return true
+	}
+
+	path := ""
+	for _, name := range pathArguments {
+		if value, ok := args[name].(string); ok && value != "" {
+			path = value
+			break
+		}
+	}
+	if path == "" {
+		return false // Tools without a target path never match a path matcher
+	}
+	relative := relativeToCWD(path)
+
+	for _, glob := range globs {
+		re, err := compileGlob(glob)
+		if err != nil {
+			continue
+		}
+		if strings.Contains(glob, "/") {
+			if re.MatchString(relative) {
+				return true
+			}
+		} else if re.MatchString(filepath.Base(relative)) {
+			return true
+		}
+	}
+	return false
+}
+
+// relativeToCWD returns path relative to the working directory with forward
+// slashes, or the cleaned path when it lies outside
+func relativeToCWD(path string) string {
+	path = filepath.Clean(path)
+	if filepath.IsAbs(path) {
+		if cwd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(rel, "..") {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(path)
+}
@@ -32,10 +32,15 @@ func NewManager(config *HookConfig, projectDir string, debug bool, sessionID str
 		projectDir: projectDir,
 		debug:      debug,
 		sessionID:  sessionID,
-		transcript: filepath.Join(os.Getenv("HOME"), ".agenticode", "sessions", sessionID+".jsonl"),
+		transcript: TranscriptPath(sessionID),
 	}
 }
 
+// TranscriptPath returns the JSONL transcript location for a session
+func TranscriptPath(sessionID string) string {
+	return filepath.Join(os.Getenv("HOME"), ".agenticode", "sessions", sessionID+".jsonl")
+}
+
 // SetConfig updates the hook configuration
 func (m *Manager) SetConfig(config *HookConfig) {
 	m.mu.Lock()
@@ -65,9 +70,14 @@ func (m *Manager) ExecuteHooks(ctx context.Context, event HookEvent, input HookI
 	}
 
 	// Find matching hooks
+	matchValue := input.ToolName
+	if event == Notification {
+		matchValue = input.NotificationType
+	}
 	var hooks []Hook
 	for _, matcher := range matchers {
-		if m.matchesPattern(matcher.Matcher, input.ToolName, event) {
+		if m.matchesPattern(matcher.Matcher, matchValue, event) && m.matchesToolInput(matcher.ToolInput, input.ToolInput, event) &&
+			m.matchesPaths(matcher.Paths, input.ToolInput, event) {
 			hooks = append(hooks, matcher.Hooks...)
 		}
 	}
@@ -106,6 +116,17 @@ func (m *Manager) ExecuteHooks(ctx context.Context, event HookEvent, input HookI
 	return outputs, nil
 }
 
+// Notify runs Notification hooks in the background so a slow notifier never
+// holds up the agent
+func (m *Manager) Notify(notificationType, message string) {
+	go func() {
+		input := HookInput{NotificationType: notificationType, Message: message}
+		if _, err := m.ExecuteHooks(context.Background(), Notification, input); err != nil {
+			log.Printf("Notification hook error: %v", err)
+		}
+	}()
+}
+
 // getHookMatchers returns the hook matchers for a given event
 func (m *Manager) getHookMatchers(event HookEvent) []HookMatcher {
 	if m.config == nil {
@@ -136,8 +157,8 @@ func (m *Manager) getHookMatchers(event HookEvent) []HookMatcher {
 
 // matchesPattern checks if a pattern matches the given value
 func (m *Manager) matchesPattern(pattern, value string, event HookEvent) bool {
-	// For non-tool events, always match (no matcher needed)
-	if event != PreToolUse && event != PostToolUse {
+	// Other non-tool events always match (no matcher needed)
+	if event != PreToolUse && event != PostToolUse && event != Notification {
 		return true
 	}
 
@@ -159,6 +180,33 @@ func (m *Manager) matchesPattern(pattern, value string, event HookEvent) bool {
 	return false
 }
 
+// matchesToolInput checks the matcher's tool_input patterns against the
+// tool's arguments. Non-string arguments are matched against their JSON form.
+func (m *Manager) matchesToolInput(patterns map[string]string, args map[string]interface{}, event HookEvent) bool {
+	if event != PreToolUse && event != PostToolUse {
+		return true
+	}
+
+	for name, pattern := range patterns {
+		value, ok := args[name]
+		if !ok {
+			return false
+		}
+		text, isString := value.(string)
+		if !isString {
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return false
+			}
+			text = string(encoded)
+		}
+		if matched, err := regexp.MatchString(pattern, text); err != nil || !matched {
+			return false
+		}
+	}
+	return true
+}
+
 // executeHook executes a single hook command
 func (m *Manager) executeHook(ctx context.Context, hook Hook, input HookInput) HookResult {
 	result := HookResult{
@@ -259,12 +307,18 @@ func (m *Manager) processHookResult(event HookEvent, result HookResult) *HookOut
 	case 0:
 		// Success
 		output.Continue = true
-		// For UserPromptSubmit and SessionStart, stdout becomes additional context
+		// For UserPromptSubmit, PostToolUse and SessionStart, stdout becomes
+		// additional context
 		if event == UserPromptSubmit && result.Stdout != "" {
 			output.HookSpecificOutput = UserPromptSubmitOutput{
 				HookEventName:     string(UserPromptSubmit),
 				AdditionalContext: result.Stdout,
 			}
+		} else if event == PostToolUse && strings.TrimSpace(result.Stdout) != "" {
+			output.HookSpecificOutput = PostToolUseOutput{
+				HookEventName:     string(PostToolUse),
+				AdditionalContext: result.Stdout,
+			}
 		} else if event == SessionStart && result.Stdout != "" {
 			output.HookSpecificOutput = SessionStartOutput{
 				HookEventName:     string(SessionStart),
@@ -353,6 +407,13 @@ func (m *Manager) GetAdditionalContext(outputs []HookOutput) string {
 			}
 		}
 
+		// Check PostToolUse specific output (e.g. linter warnings)
+		if toolOutput, ok := output.HookSpecificOutput.(PostToolUseOutput); ok && !output.SuppressOutput {
+			if toolOutput.AdditionalContext != "" {
+				contexts = append(contexts, toolOutput.AdditionalContext)
+			}
+		}
+
 		// Check SessionStart specific output
 		if sessionOutput, ok := output.HookSpecificOutput.(SessionStartOutput); ok {
 			if sessionOutput.AdditionalContext != "" {
@@ -0,0 +1,152 @@
+package hooks
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPreToolUseHookMatchesToolInput(t *testing.T) {
+	config := &HookConfig{
+		PreToolUse: []HookMatcher{{
+			Matcher:   "run_shell",
+			ToolInput: map[string]string{"command": `^git push\b`},
+			Hooks:     []Hook{{Type: "command", Command: "echo 'pushing is not allowed' >&2; exit 2"}},
+		}},
+	}
+	if err := ValidateHookConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	manager := NewManager(config, t.TempDir(), false, "test")
+
+	cases := []struct {
+		tool    string
+		command string
+		fires   bool
+	}{
+		{"run_shell", "git push origin main", true},
+		{"run_shell", "git status", false},
+		{"write_file", "git push origin main", false},
+	}
+	for _, tc := range cases {
+		outputs, err := manager.ExecuteHooks(context.Background(), PreToolUse, HookInput{
+			ToolName:  tc.tool,
+			ToolInput: map[string]interface{}{"command": tc.command},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fired := len(outputs) > 0; fired != tc.fires {
+			t.Errorf("%s %q: expected fired=%v, got %d outputs", tc.tool, tc.command, tc.fires, len(outputs))
+			continue
+		}
+		if blocked, reason := manager.ShouldBlockToolExecution(outputs); blocked != tc.fires || (tc.fires && reason != "pushing is not allowed\n") {
+			t.Errorf("%s %q: unexpected block decision %v %q", tc.tool, tc.command, blocked, reason)
+		}
+	}
+}
+
+func TestToolInputRequiresArgument(t *testing.T) {
+	manager := NewManager(nil, t.TempDir(), false, "test")
+	patterns := map[string]string{"path": `^/etc/`}
+
+	if manager.matchesToolInput(patterns, map[string]interface{}{"command": "ls"}, PreToolUse) {
+		t.Error("expected a missing argument not to match")
+	}
+	if !manager.matchesToolInput(patterns, map[string]interface{}{"path": "/etc/hosts"}, PreToolUse) {
+		t.Error("expected a protected path to match")
+	}
+	if !manager.matchesToolInput(map[string]string{"replace_all": "true"}, map[string]interface{}{"replace_all": true}, PostToolUse) {
+		t.Error("expected non-string arguments to match their JSON form")
+	}
+}
+
+func TestValidateHookConfigRejectsBadToolInputPattern(t *testing.T) {
+	config := &HookConfig{
+		PreToolUse: []HookMatcher{{
+			ToolInput: map[string]string{"command": "("},
+			Hooks:     []Hook{{Command: "true"}},
+		}},
+	}
+	if err := ValidateHookConfig(config); err == nil {
+		t.Error("expected an invalid tool_input regex to be rejected")
+	}
+}
+
+func TestPostToolUseHookMatchesPathGlob(t *testing.T) {
+	config := &HookConfig{
+		PostToolUse: []HookMatcher{{
+			Matcher: "write_file|edit",
+			Paths:   []string{"*.go"},
+			Hooks:   []Hook{{Type: "command", Command: "echo linted"}},
+		}},
+	}
+	if err := ValidateHookConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	manager := NewManager(config, t.TempDir(), false, "test")
+
+	cases := []struct {
+		tool  string
+		args  map[string]interface{}
+		fires bool
+	}{
+		{"write_file", map[string]interface{}{"path": "internal/agent/agent.go"}, true},
+		{"edit", map[string]interface{}{"file_path": "main.go"}, true},
+		{"write_file", map[string]interface{}{"path": "README.md"}, false},
+		{"run_shell", map[string]interface{}{"command": "gofmt -w main.go"}, false},
+	}
+	for _, tc := range cases {
+		outputs, err := manager.ExecuteHooks(context.Background(), PostToolUse, HookInput{ToolName: tc.tool, ToolInput: tc.args})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fired := len(outputs) > 0; fired != tc.fires {
+			t.Errorf("%s %v: expected fired=%v", tc.tool, tc.args, tc.fires)
+		}
+	}
+}
+
+func TestPathGlobs(t *testing.T) {
+	manager := NewManager(nil, t.TempDir(), false, "test")
+	cases := []struct {
+		glob  string
+		path  string
+		match bool
+	}{
+		{"*.go", "cmd/root.go", true},
+		{"*.go", "docs/hooks.md", false},
+		{"internal/**/*.go", "internal/hooks/glob.go", true},
+		{"internal/**/*.go", "internal/x.go", true},
+		{"internal/**/*.go", "cmd/root.go", false},
+		{"cmd/*.go", "cmd/sub/root.go", false},
+		{"[!_]*.md", "_draft.md", false},
+	}
+	for _, tc := range cases {
+		got := manager.matchesPaths([]string{tc.glob}, map[string]interface{}{"path": tc.path}, PostToolUse)
+		if got != tc.match {
+			t.Errorf("%q against %q: expected %v", tc.glob, tc.path, tc.match)
+		}
+	}
+
+	if err := ValidateHookConfig(&HookConfig{PostToolUse: []HookMatcher{{Paths: []string{"[abc"}, Hooks: []Hook{{Command: "true"}}}}}); err == nil {
+		t.Error("expected an unterminated character class to be rejected")
+	}
+}
+
+func TestPostToolUseStdoutBecomesAdditionalContext(t *testing.T) {
+	manager := NewManager(nil, t.TempDir(), false, "test")
+
+	output := manager.processHookResult(PostToolUse, HookResult{Stdout: "2 lint warnings\n"})
+	if got := manager.GetAdditionalContext([]HookOutput{*output}); got != "2 lint warnings\n" {
+		t.Errorf("expected stdout as additional context, got %q", got)
+	}
+
+	output.SuppressOutput = true
+	if got := manager.GetAdditionalContext([]HookOutput{*output}); got != "" {
+		t.Errorf("expected suppressed output to be dropped, got %q", got)
+	}
+
+	if output := manager.processHookResult(PostToolUse, HookResult{Stdout: "\n"}); output.HookSpecificOutput != nil {
+		t.Errorf("expected blank stdout to be ignored, got %+v", output.HookSpecificOutput)
+	}
+}
@@ -2,6 +2,10 @@ package hooks
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
 	"time"
 )
 
@@ -24,6 +28,12 @@ const (
 	SessionStart HookEvent = "SessionStart"
 )
 
+// Notification types, matched by Notification hook matchers
+const (
+	NotificationApproval = "approval" // The agent is waiting for the user to approve a tool call
+	NotificationComplete = "complete" // A task finished
+)
+
 // HookInput represents the data passed to a hook
 type HookInput struct {
 	// Common fields
@@ -39,6 +49,7 @@ type HookInput struct {
 
 	// Event-specific fields
 	Message            string `json:"message,omitempty"`             // For Notification
+	NotificationType   string `json:"notification_type,omitempty"`   // For Notification: "approval" or "complete"
 	Prompt             string `json:"prompt,omitempty"`              // For UserPromptSubmit
 	StopHookActive     bool   `json:"stop_hook_active,omitempty"`    // For Stop/SubagentStop
 	Trigger            string `json:"trigger,omitempty"`             // For PreCompact
@@ -74,6 +85,12 @@ type UserPromptSubmitOutput struct {
 	AdditionalContext string `json:"additionalContext,omitempty"`
 }
 
+// PostToolUseOutput represents hook-specific output for PostToolUse events
+type PostToolUseOutput struct {
+	HookEventName     string `json:"hookEventName"`
+	AdditionalContext string `json:"additionalContext,omitempty"`
+}
+
 // SessionStartOutput represents hook-specific output for SessionStart events
 type SessionStartOutput struct {
 	HookEventName     string `json:"hookEventName"`
@@ -84,13 +101,80 @@ type SessionStartOutput struct {
 type Hook struct {
 	Type    string        `json:"type"`              // Currently only "command" is supported
 	Command string        `json:"command"`           // The bash command to execute
-	Timeout time.Duration `json:"timeout,omitempty"` // Optional timeout
+	Timeout time.Duration `json:"timeout,omitempty"` // Optional timeout; configured in seconds (see ParseTimeout)
+}
+
+// UnmarshalJSON reads timeout the way users write it: a number of seconds
+// ("timeout": 30) or a duration string ("timeout": "1m30s")
+func (h *Hook) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type    string      `json:"type"`
+		Command string      `json:"command"`
+		Timeout interface{} `json:"timeout,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	timeout, err := ParseTimeout(raw.Timeout)
+	if err != nil {
+		return err
+	}
+	*h = Hook{Type: raw.Type, Command: raw.Command, Timeout: timeout}
+	return nil
+}
+
+// ParseTimeout converts a configured timeout to a duration. Numbers are
+// seconds; strings are either seconds ("30") or Go durations ("1m30s").
+func ParseTimeout(value interface{}) (time.Duration, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case time.Duration:
+		return v, nil
+	case float64:
+		return time.Duration(v * float64(time.Second)), nil
+	case float32:
+		return time.Duration(float64(v) * float64(time.Second)), nil
+	case int:
+		return time.Duration(v) * time.Second, nil
+	case int64:
+		return time.Duration(v) * time.Second, nil
+	case uint64:
+		return time.Duration(v) * time.Second, nil
+	case string:
+		if seconds, err := strconv.ParseFloat(v, 64); err == nil {
+			return time.Duration(seconds * float64(time.Second)), nil
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid timeout %q: use seconds (30) or a duration (\"1m30s\")", v)
+		}
+		return d, nil
+	default:
+		return 0, fmt.Errorf("invalid timeout %v: use seconds (30) or a duration (\"1m30s\")", value)
+	}
+}
+
+// TimeoutDecodeHook is a mapstructure decode hook that applies ParseTimeout
+// to durations, for hooks loaded through viper from YAML config
+func TimeoutDecodeHook(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if to != reflect.TypeOf(time.Duration(0)) {
+		return data, nil
+	}
+	return ParseTimeout(data)
 }
 
 // HookMatcher represents a hook configuration with optional matcher
 type HookMatcher struct {
-	Matcher string `json:"matcher,omitempty"` // Pattern to match (for tool events)
-	Hooks   []Hook `json:"hooks"`
+	Matcher string `json:"matcher,omitempty"` // Pattern to match (tool name, or notification type for Notification)
+	// ToolInput maps tool argument names to regexes; for tool events every
+	// listed argument must be present and match for the hooks to run
+	ToolInput map[string]string `json:"tool_input,omitempty" mapstructure:"tool_input"`
+	// Paths are globs (e.g. "*.go", "internal/**/*.go") on the tool's path or
+	// file_path argument; for tool events the hooks run when any glob matches
+	Paths []string `json:"paths,omitempty" mapstructure:"paths"`
+	Hooks []Hook   `json:"hooks"`
 }
 
 // HookConfig represents the complete hooks configuration
@@ -0,0 +1,69 @@
+package hooks
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestHookTimeoutUnmarshalsAsSeconds(t *testing.T) {
+	cases := map[string]time.Duration{
+		`{"type":"command","command":"true","timeout":30}`:      30 * time.Second,
+		`{"type":"command","command":"true","timeout":1.5}`:     1500 * time.Millisecond,
+		`{"type":"command","command":"true","timeout":"45"}`:    45 * time.Second,
+		`{"type":"command","command":"true","timeout":"1m30s"}`: 90 * time.Second,
+		`{"type":"command","command":"true"}`:                   0,
+	}
+	for input, want := range cases {
+		var hook Hook
+		if err := json.Unmarshal([]byte(input), &hook); err != nil {
+			t.Fatalf("%s: %v", input, err)
+		}
+		if hook.Timeout != want || hook.Command != "true" || hook.Type != "command" {
+			t.Errorf("%s: expected timeout %s, got %+v", input, want, hook)
+		}
+	}
+
+	var hook Hook
+	if err := json.Unmarshal([]byte(`{"command":"true","timeout":"soon"}`), &hook); err == nil {
+		t.Error("expected an invalid timeout to be rejected")
+	}
+}
+
+func TestHookTimeoutFromSettingsFile(t *testing.T) {
+	var settings Settings
+	data := `{"hooks":{"PreToolUse":[{"matcher":"run_shell","hooks":[{"type":"command","command":"check.sh","timeout":30}]}]}}`
+	if err := json.Unmarshal([]byte(data), &settings); err != nil {
+		t.Fatal(err)
+	}
+	if got := settings.Hooks.PreToolUse[0].Hooks[0].Timeout; got != 30*time.Second {
+		t.Errorf("expected 30s, got %s", got)
+	}
+}
+
+func TestHookTimeoutFromYAMLConfig(t *testing.T) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	config := `hooks:
+  PostToolUse:
+    - matcher: run_shell
+      hooks:
+        - type: command
+          command: check.sh
+          timeout: 30
+`
+	if err := v.ReadConfig(strings.NewReader(config)); err != nil {
+		t.Fatal(err)
+	}
+
+	var hooks HookConfig
+	if err := v.UnmarshalKey("hooks", &hooks, viper.DecodeHook(TimeoutDecodeHook)); err != nil {
+		t.Fatal(err)
+	}
+	if got := hooks.PostToolUse[0].Hooks[0].Timeout; got != 30*time.Second {
+		t.Errorf("expected 30s, got %s", got)
+	}
+}
@@ -9,7 +9,13 @@ import (
 
 type Client interface {
 	Generate(ctx context.Context, messages []openai.ChatCompletionMessage, tools []openai.Tool) (openai.ChatCompletionResponse, error)
-	Stream(ctx context.Context, messages []openai.ChatCompletionMessage) (*openai.ChatCompletionStream, error)
+	Stream(ctx context.Context, messages []openai.ChatCompletionMessage, tools []openai.Tool) (ChatStream, error)
+}
+
+// ChatStream is a streaming chat completion; callers must Close it
+type ChatStream interface {
+	Recv() (openai.ChatCompletionStreamResponse, error)
+	Close()
 }
 
 type CodeGeneration struct {
@@ -54,7 +60,7 @@ func NewClient(cfg Config) (Client, error) {
 	if cfg.ProvidersConfig != nil && cfg.ModelSelection != "" {
 		provider, model, err := cfg.ProvidersConfig.ParseModelString(cfg.ModelSelection)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse model selection '%s': %w", cfg.ModelSelection, err)
+			return nil, fmt.Errorf("failed to parse model selection '%s': %w\n%s", cfg.ModelSelection, err, cfg.ProvidersConfig.ModelListing())
 		}
 
 		return NewProviderClient(provider, model)
@@ -62,26 +68,8 @@ func NewClient(cfg Config) (Client, error) {
 
 	// Legacy configuration support
 	if cfg.Provider == "openai" && cfg.APIKey != "" && cfg.Model != "" {
-		// If BaseURL is provided, create a custom provider config
-		if cfg.BaseURL != "" {
-			provider := &ProviderConfig{
-				Type:    "openai",
-				BaseURL: cfg.BaseURL,
-				APIKey:  cfg.APIKey,
-				Models: []ModelConfig{
-					{
-						ID:            cfg.Model,
-						Name:          cfg.Model,
-						ContextWindow: 128000,
-						MaxTokens:     4096,
-					},
-				},
-			}
-			return NewProviderClient(provider, &provider.Models[0])
-		}
-
-		// Otherwise use legacy OpenAI client
-		return NewOpenAIClient(cfg.APIKey, cfg.Model), nil
+		provider := legacyOpenAIProvider(cfg.APIKey, cfg.Model, cfg.BaseURL)
+		return NewProviderClient(provider, &provider.Models[0])
 	}
 
 	// Handle other legacy providers
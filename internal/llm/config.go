@@ -3,6 +3,7 @@ package llm
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 )
 
@@ -12,6 +13,10 @@ type ProviderConfig struct {
 	BaseURL string        `yaml:"base_url" json:"base_url" mapstructure:"base_url"` // Base URL for the API
 	APIKey  string        `yaml:"api_key" json:"api_key" mapstructure:"api_key"`    // API key (can use $ENV_VAR syntax)
 	Models  []ModelConfig `yaml:"models" json:"models" mapstructure:"models"`       // Available models for this provider
+
+	// MaxConcurrency caps simultaneous Generate calls to this provider's
+	// endpoint across all agents (0 means no limit)
+	MaxConcurrency int `yaml:"max_concurrency" json:"max_concurrency" mapstructure:"max_concurrency"`
 }
 
 // ModelConfig represents a single model configuration
@@ -20,6 +25,20 @@ type ModelConfig struct {
 	Name          string `yaml:"name" json:"name" mapstructure:"name"`                               // Human-readable name
 	ContextWindow int    `yaml:"context_window" json:"context_window" mapstructure:"context_window"` // Maximum context size
 	MaxTokens     int    `yaml:"max_tokens" json:"max_tokens" mapstructure:"max_tokens"`             // Default max tokens for responses
+	Vision        bool   `yaml:"vision" json:"vision" mapstructure:"vision"`                         // Accepts image content parts
+
+	// Optional pricing in USD per million tokens, used to estimate run cost
+	InputCostPerMTok  float64 `yaml:"input_cost_per_mtok" json:"input_cost_per_mtok" mapstructure:"input_cost_per_mtok"`
+	OutputCostPerMTok float64 `yaml:"output_cost_per_mtok" json:"output_cost_per_mtok" mapstructure:"output_cost_per_mtok"`
+}
+
+// Cost estimates the USD cost of the given token usage. It is zero when the
+// model has no pricing configured.
+func (m *ModelConfig) Cost(promptTokens, completionTokens int) float64 {
+	if m == nil {
+		return 0
+	}
+	return (float64(promptTokens)*m.InputCostPerMTok + float64(completionTokens)*m.OutputCostPerMTok) / 1e6
 }
 
 // ModelSelection represents a model choice with provider and model ID
@@ -93,3 +112,43 @@ func (p *ProvidersConfig) ParseModelString(modelStr string) (*ProviderConfig, *M
 
 	return nil, nil, fmt.Errorf("invalid model string: %s (use 'provider/model' or a named selection)", modelStr)
 }
+
+// ModelListing describes the named selections and provider/model pairs that
+// a model selection can refer to
+func (p *ProvidersConfig) ModelListing() string {
+	var b strings.Builder
+
+	names := make([]string, 0, len(p.Models))
+	for name := range p.Models {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	b.WriteString("Named selections:\n")
+	if len(names) == 0 {
+		b.WriteString("  (none; add them under models: in the config)\n")
+	}
+	for _, name := range names {
+		selection := p.Models[name]
+		fmt.Fprintf(&b, "  %s -> %s/%s\n", name, selection.Provider, selection.Model)
+	}
+
+	providers := make([]string, 0, len(p.Providers))
+	for name := range p.Providers {
+		providers = append(providers, name)
+	}
+	sort.Strings(providers)
+	b.WriteString("Provider models:\n")
+	if len(providers) == 0 {
+		b.WriteString("  (none; add them under providers: in the config)\n")
+	}
+	for _, name := range providers {
+		for _, model := range p.Providers[name].Models {
+			fmt.Fprintf(&b, "  %s/%s", name, model.ID)
+			if model.ContextWindow > 0 {
+				fmt.Fprintf(&b, " (context %d tokens)", model.ContextWindow)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,68 @@
+package llm
+
+import (
+	"context"
+	"sync"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// concurrencyLimiter caps simultaneous requests to one provider endpoint.
+// A nil limiter allows unlimited requests.
+type concurrencyLimiter chan struct{}
+
+var (
+	limitersMu sync.Mutex
+	limiters   = make(map[string]concurrencyLimiter)
+)
+
+// providerLimiter returns the limiter shared by every client of the provider's
+// endpoint, so sub-agents and other models on the same server count against
+// one limit. The first client created for an endpoint sets its size.
+func providerLimiter(provider *ProviderConfig) concurrencyLimiter {
+	if provider.MaxConcurrency <= 0 {
+		return nil
+	}
+
+	key := provider.Type + "|" + provider.BaseURL
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+	if limiter, ok := limiters[key]; ok {
+		return limiter
+	}
+	limiter := make(concurrencyLimiter, provider.MaxConcurrency)
+	limiters[key] = limiter
+	return limiter
+}
+
+// acquire waits for a free slot or for ctx to be done
+func (l concurrencyLimiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	select {
+	case l <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a slot taken by acquire
+func (l concurrencyLimiter) release() {
+	if l != nil {
+		<-l
+	}
+}
+
+// limitedStream holds a limiter slot until the stream is closed
+type limitedStream struct {
+	*openai.ChatCompletionStream
+	release func()
+	once    sync.Once
+}
+
+func (s *limitedStream) Close() {
+	s.ChatCompletionStream.Close()
+	s.once.Do(s.release)
+}
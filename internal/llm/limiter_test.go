@@ -0,0 +1,129 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+func TestGenerateQueuesBeyondConcurrencyLimit(t *testing.T) {
+	var inFlight, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			old := atomic.LoadInt32(&peak)
+			if current <= old || atomic.CompareAndSwapInt32(&peak, old, current) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	provider := &ProviderConfig{
+		Type:           "openai",
+		BaseURL:        server.URL,
+		APIKey:         "test-key",
+		MaxConcurrency: 2,
+		Models:         []ModelConfig{{ID: "local-model"}},
+	}
+	client, err := NewProviderClient(provider, &provider.Models[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 6)
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.Generate(context.Background(), []openai.ChatCompletionMessage{{Role: "user", Content: "hi"}}, nil)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if peak := atomic.LoadInt32(&peak); peak != 2 {
+		t.Errorf("expected at most 2 requests in flight (and the limit reached), got %d", peak)
+	}
+}
+
+func TestStreamHoldsSlotUntilClosed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"ok\"},\"finish_reason\":\"stop\"}]}\n\ndata: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	provider := &ProviderConfig{
+		Type:           "openai",
+		BaseURL:        server.URL,
+		APIKey:         "test-key",
+		MaxConcurrency: 1,
+		Models:         []ModelConfig{{ID: "stream-model"}},
+	}
+	client, err := NewProviderClient(provider, &provider.Models[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := []openai.ChatCompletionMessage{{Role: "user", Content: "hi"}}
+
+	stream, err := client.Stream(context.Background(), messages, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.Stream(ctx, messages, nil); err == nil {
+		t.Fatal("expected a second stream to wait while the first is open")
+	}
+
+	stream.Close()
+	stream.Close() // Closing twice releases the slot once
+	second, err := client.Stream(context.Background(), messages, nil)
+	if err != nil {
+		t.Fatalf("expected the slot to be free after Close: %v", err)
+	}
+	second.Close()
+	if held := len(client.limiter); held != 0 {
+		t.Errorf("expected every slot to be released, %d still held", held)
+	}
+}
+
+func TestLimiterIsSharedPerEndpoint(t *testing.T) {
+	a := &ProviderConfig{Type: "openai", BaseURL: "http://localhost:11434/v1", MaxConcurrency: 1}
+	b := &ProviderConfig{Type: "openai", BaseURL: "http://localhost:11434/v1", MaxConcurrency: 1}
+	if providerLimiter(a) != providerLimiter(b) {
+		t.Error("expected clients of one endpoint to share a limiter")
+	}
+	if providerLimiter(&ProviderConfig{Type: "openai", BaseURL: "http://localhost:11434/v1"}) != nil {
+		t.Error("expected no limiter without max_concurrency")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	limiter := providerLimiter(a)
+	if err := limiter.acquire(ctx); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if err := limiter.acquire(ctx); err == nil {
+		t.Error("expected a cancelled wait to fail")
+	}
+	limiter.release()
+}
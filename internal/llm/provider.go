@@ -3,8 +3,11 @@ package llm
 import (
 	"context"
 	"fmt"
+	"log"
+	"math"
 
 	openai "github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/redact"
 )
 
 // ProviderClient is a provider-agnostic client that works with any OpenAI-compatible API
@@ -13,6 +16,20 @@ type ProviderClient struct {
 	providerConfig *ProviderConfig
 	modelConfig    *ModelConfig
 	currentModel   string
+	limiter        concurrencyLimiter // Shared per endpoint; nil when unlimited
+	deterministic  bool               // Pin temperature to 0 and send DeterministicSeed
+}
+
+// DeterministicSeed is the seed sent in deterministic mode, for providers
+// that support seeded sampling
+const DeterministicSeed = 42
+
+// debugLogging enables diagnostic logging of client setup (set via --debug)
+var debugLogging bool
+
+// SetDebug toggles diagnostic logging for clients created afterwards
+func SetDebug(enabled bool) {
+	debugLogging = enabled
 }
 
 // NewProviderClient creates a new provider-agnostic client
@@ -36,7 +53,10 @@ func NewProviderClient(provider *ProviderConfig, model *ModelConfig) (*ProviderC
 		return nil, fmt.Errorf("model %s not found in provider %s", model.ID, provider.Type)
 	}
 
-	fmt.Println("provider.APIKey", provider.APIKey)
+	if debugLogging {
+		log.Printf("Creating %s client for %s (api key %s)", provider.Type, model.ID, redact.Value(provider.APIKey))
+	}
+
 	// Create OpenAI-compatible client with custom base URL
 	config := openai.DefaultConfig(provider.APIKey)
 	if provider.BaseURL != "" {
@@ -48,15 +68,27 @@ func NewProviderClient(provider *ProviderConfig, model *ModelConfig) (*ProviderC
 		providerConfig: provider,
 		modelConfig:    model,
 		currentModel:   model.ID,
+		limiter:        providerLimiter(provider),
 	}, nil
 }
 
-// // Legacy constructor for backwards compatibility
+// NewOpenAIClient creates a client for a single OpenAI model. It is a thin
+// wrapper over ProviderClient so every entry point shares one request path.
 func NewOpenAIClient(apiKey, model string) *ProviderClient {
-	// Create a legacy OpenAI provider config
-	provider := &ProviderConfig{
+	provider := legacyOpenAIProvider(apiKey, model, "")
+	client, _ := NewProviderClient(provider, &provider.Models[0])
+	return client
+}
+
+// legacyOpenAIProvider builds the provider config used by the legacy
+// provider/api_key/model settings
+func legacyOpenAIProvider(apiKey, model, baseURL string) *ProviderConfig {
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	return &ProviderConfig{
 		Type:    "openai",
-		BaseURL: "https://api.openai.com/v1",
+		BaseURL: baseURL,
 		APIKey:  apiKey,
 		Models: []ModelConfig{
 			{
@@ -67,89 +99,64 @@ func NewOpenAIClient(apiKey, model string) *ProviderClient {
 			},
 		},
 	}
-
-	modelConfig := &provider.Models[0]
-
-	client, _ := NewProviderClient(provider, modelConfig)
-	return client
-}
-
-type Message struct {
-	Role    string `json:"role"`
-	Name    string `json:"name,omitempty"`    // optional: for tool messages
-	Content string `json:"content,omitempty"` // system/user/assistant/tool messages
-}
-
-type ChatCompletionRequest struct {
-	Model       string    `json:"model"`
-	Messages    []Message `json:"messages"`
-	Temperature float32   `json:"temperature,omitempty"`
-	MaxTokens   int       `json:"max_tokens,omitempty"`
-	Tools       []Tool    `json:"tools,omitempty"`
-	ToolChoice  string    `json:"tool_choice,omitempty"`
-}
-
-type Tool struct {
-	Type     string      `json:"type"`
-	Function FunctionDef `json:"function"`
-}
-
-type FunctionDef struct {
-	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	Parameters  map[string]interface{} `json:"parameters"`
 }
 
-type ChatCompletionResponse struct {
-	ID      string   `json:"id"`
-	Object  string   `json:"object"`
-	Created int64    `json:"created"`
-	Model   string   `json:"model"`
-	Choices []Choice `json:"choices"`
-}
-
-type Choice struct {
-	Index        int        `json:"index"`
-	Message      Message    `json:"message"`
-	ToolCalls    []ToolCall `json:"tool_calls,omitempty"`
-	FinishReason string     `json:"finish_reason"`
-}
-
-type ToolCall struct {
-	ID       string       `json:"id"`
-	Type     string       `json:"type"`
-	Function FunctionCall `json:"function"`
+// Generate sends a chat completion request to the provider, waiting for a
+// free slot when the provider has a concurrency limit
+func (c *ProviderClient) Generate(ctx context.Context, messages []openai.ChatCompletionMessage, tools []openai.Tool) (openai.ChatCompletionResponse, error) {
+	if err := c.limiter.acquire(ctx); err != nil {
+		return openai.ChatCompletionResponse{}, err
+	}
+	defer c.limiter.release()
+	return c.client.CreateChatCompletion(ctx, c.buildRequest(messages, tools, false))
 }
 
-type FunctionCall struct {
-	Name      string `json:"name"`
-	Arguments string `json:"arguments"`
+// Stream sends a streaming chat completion request to the provider. Like
+// Generate it waits for a free slot, which is held until the stream is closed.
+func (c *ProviderClient) Stream(ctx context.Context, messages []openai.ChatCompletionMessage, tools []openai.Tool) (ChatStream, error) {
+	if err := c.limiter.acquire(ctx); err != nil {
+		return nil, err
+	}
+	stream, err := c.client.CreateChatCompletionStream(ctx, c.buildRequest(messages, tools, true))
+	if err != nil {
+		c.limiter.release()
+		return nil, err
+	}
+	return &limitedStream{ChatCompletionStream: stream, release: c.limiter.release}, nil
 }
 
-// Generate sends a chat completion request to the provider
-func (c *ProviderClient) Generate(ctx context.Context, messages []openai.ChatCompletionMessage, tools []openai.Tool) (openai.ChatCompletionResponse, error) {
+// buildRequest assembles the request shared by Generate and Stream
+func (c *ProviderClient) buildRequest(messages []openai.ChatCompletionMessage, tools []openai.Tool, stream bool) openai.ChatCompletionRequest {
 	req := openai.ChatCompletionRequest{
-		Model:      c.currentModel,
-		Messages:   messages,
-		Tools:      tools,
-		ToolChoice: "auto",
+		Model:    c.currentModel,
+		Messages: messages,
+		Tools:    tools,
+		Stream:   stream,
+	}
+	if len(tools) > 0 {
+		req.ToolChoice = "auto"
 	}
 
 	// Apply model-specific settings
 	if c.modelConfig.MaxTokens > 0 {
-		req.MaxTokens = c.modelConfig.MaxTokens
+		promptTokens, exact := CountPromptTokens(c.currentModel, messages, tools)
+		req.MaxTokens = effectiveMaxTokens(c.modelConfig, promptTokens, exact)
 	}
 
-	return c.client.CreateChatCompletion(ctx, req)
+	// go-openai omits a zero temperature, so send the smallest positive one
+	if c.deterministic {
+		req.Temperature = math.SmallestNonzeroFloat32
+		seed := DeterministicSeed
+		req.Seed = &seed
+	}
+
+	return req
 }
 
-// Stream sends a streaming chat completion request to the provider
-func (c *ProviderClient) Stream(ctx context.Context, messages []openai.ChatCompletionMessage) (*openai.ChatCompletionStream, error) {
-	return c.client.CreateChatCompletionStream(ctx, openai.ChatCompletionRequest{
-		Model:    c.currentModel,
-		Messages: messages,
-		Stream:   true,
-	})
+// SetDeterministic makes requests sample greedily (temperature 0) with a
+// fixed seed, so repeated runs such as evals give comparable results
+func (c *ProviderClient) SetDeterministic(enabled bool) {
+	c.deterministic = enabled
 }
 
 // GetCurrentModel returns the currently active model ID
@@ -157,6 +164,16 @@ func (c *ProviderClient) GetCurrentModel() string {
 	return c.currentModel
 }
 
+// Cost estimates the USD cost of token usage on the current model
+func (c *ProviderClient) Cost(promptTokens, completionTokens int) float64 {
+	return c.modelConfig.Cost(promptTokens, completionTokens)
+}
+
+// SupportsVision reports whether the current model accepts images
+func (c *ProviderClient) SupportsVision() bool {
+	return c.modelConfig != nil && c.modelConfig.Vision
+}
+
 // GetProviderName returns the provider name
 func (c *ProviderClient) GetProviderName() string {
 	return c.providerConfig.Type
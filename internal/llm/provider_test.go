@@ -0,0 +1,177 @@
+package llm
+
+import (
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+func TestConstructorsShareRequestSettings(t *testing.T) {
+	legacy := NewOpenAIClient("test-key", "gpt-4o")
+
+	fromConfig, err := NewClient(Config{Provider: "openai", APIKey: "test-key", Model: "gpt-4o"})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	fromProviders, err := NewClient(Config{
+		ProvidersConfig: &ProvidersConfig{Providers: map[string]ProviderConfig{
+			"openai": {
+				Type:   "openai",
+				APIKey: "test-key",
+				Models: []ModelConfig{{ID: "gpt-4o", MaxTokens: 4096}},
+			},
+		}},
+		ModelSelection: "openai/gpt-4o",
+	})
+	if err != nil {
+		t.Fatalf("NewClient with providers failed: %v", err)
+	}
+
+	messages := []openai.ChatCompletionMessage{{Role: "user", Content: "hi"}}
+	tools := []openai.Tool{{Type: openai.ToolTypeFunction, Function: openai.FunctionDefinition{Name: "grep"}}}
+
+	want := legacy.buildRequest(messages, tools, false)
+	if want.MaxTokens != 4096 || want.ToolChoice != "auto" {
+		t.Fatalf("unexpected legacy request settings: %+v", want)
+	}
+
+	for name, client := range map[string]Client{"legacy config": fromConfig, "providers config": fromProviders} {
+		got := client.(*ProviderClient).buildRequest(messages, tools, false)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s request = %+v, want %+v", name, got, want)
+		}
+	}
+
+	// Streaming uses the same settings
+	stream := legacy.buildRequest(messages, tools, true)
+	stream.Stream = false
+	if !reflect.DeepEqual(stream, want) {
+		t.Errorf("stream request differs from generate request: %+v", stream)
+	}
+}
+
+func TestBuildRequestOmitsToolChoiceWithoutTools(t *testing.T) {
+	req := NewOpenAIClient("test-key", "gpt-4o").buildRequest(nil, nil, false)
+	if req.ToolChoice != nil {
+		t.Errorf("expected no tool_choice without tools, got %v", req.ToolChoice)
+	}
+}
+
+func TestNewProviderClientWritesNothingToStdout(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	provider := legacyOpenAIProvider("sk-secret-key-1234", "gpt-4o", "")
+	_, err = NewProviderClient(provider, &provider.Models[0])
+
+	w.Close()
+	os.Stdout = stdout
+	if err != nil {
+		t.Fatalf("NewProviderClient failed: %v", err)
+	}
+
+	output, _ := io.ReadAll(r)
+	if len(output) > 0 {
+		t.Errorf("expected no stdout output, got %q", output)
+	}
+}
+
+func TestBuildRequestShrinksMaxTokensForLargePrompt(t *testing.T) {
+	client := NewOpenAIClient("test-key", "gpt-4o")
+	client.modelConfig.ContextWindow = 8000
+	client.modelConfig.MaxTokens = 4096
+
+	small := []openai.ChatCompletionMessage{{Role: "user", Content: "hi"}}
+	if got := client.buildRequest(small, nil, false).MaxTokens; got != 4096 {
+		t.Errorf("expected the configured max_tokens for a small prompt, got %d", got)
+	}
+
+	large := []openai.ChatCompletionMessage{{Role: "user", Content: strings.Repeat("word ", 5000)}}
+	prompt, exact := CountPromptTokens("gpt-4o", large, nil)
+	if !exact {
+		t.Fatal("expected gpt-4o to be counted with its tokenizer")
+	}
+	want := 8000 - prompt - maxTokensSafetyMargin
+	if got := client.buildRequest(large, nil, false).MaxTokens; got != want || got >= 4096 {
+		t.Errorf("expected max_tokens %d for a %d token prompt, got %d", want, prompt, got)
+	}
+
+	full := []openai.ChatCompletionMessage{{Role: "user", Content: strings.Repeat("word ", 8000)}}
+	if got := client.buildRequest(full, nil, false).MaxTokens; got != minMaxTokens {
+		t.Errorf("expected the floor of %d when the prompt fills the window, got %d", minMaxTokens, got)
+	}
+}
+
+func TestBuildRequestWidensMarginForEstimatedPrompt(t *testing.T) {
+	client := NewOpenAIClient("test-key", "gpt-4o")
+	client.currentModel = "llama3-70b"
+	client.modelConfig.ContextWindow = 8000
+	client.modelConfig.MaxTokens = 4096
+
+	messages := []openai.ChatCompletionMessage{{Role: "user", Content: strings.Repeat("word ", 2400)}}
+	prompt, exact := CountPromptTokens("llama3-70b", messages, nil)
+	if exact {
+		t.Fatal("expected a model without a known tokenizer to be estimated")
+	}
+	want := 8000 - prompt - maxTokensSafetyMargin - prompt/estimateMarginDivisor
+	if got := client.buildRequest(messages, nil, false).MaxTokens; got != want {
+		t.Errorf("expected max_tokens %d for an estimated %d token prompt, got %d", want, prompt, got)
+	}
+}
+
+func TestEstimateTextTokensDoesNotUndercount(t *testing.T) {
+	enc := tokenizerFor("gpt-4o")
+	if enc == nil {
+		t.Fatal("expected a tokenizer for gpt-4o")
+	}
+	samples := map[string]string{
+		"prose": "The quick brown fox jumps over the lazy dog while the agent reads files.",
+		"code":  "func (c *Client) Do(ctx context.Context) error {\n\tif err := c.x[i+1]; err != nil {\n\t\treturn fmt.Errorf(\"do: %w\", err)\n\t}\n}",
+		"json":  `{"id":"call_01","args":{"path":"./a/b.go","lines":[1,2,3],"ok":true}}`,
+		"cjk":   "日本語のテキストはトークン数が多くなりがちです。設定ファイルを読み込みます。",
+	}
+	for name, text := range samples {
+		if est, real := EstimateTextTokens(text), len(enc.EncodeOrdinary(text)); est < real {
+			t.Errorf("%s: estimate %d is below the tokenizer's %d", name, est, real)
+		}
+	}
+}
+
+func TestNewClientListsModelsForUnknownSelection(t *testing.T) {
+	providers := &ProvidersConfig{
+		Providers: map[string]ProviderConfig{
+			"openai": {Type: "openai", APIKey: "test-key", Models: []ModelConfig{{ID: "gpt-4o", ContextWindow: 128000}}},
+			"groq":   {Type: "openai", APIKey: "test-key", Models: []ModelConfig{{ID: "llama3-70b"}}},
+		},
+		Models: map[string]ModelSelection{"default": {Provider: "openai", Model: "gpt-4o"}},
+	}
+
+	_, err := NewClient(Config{ProvidersConfig: providers, ModelSelection: "groq/llama3-8b"})
+	if err == nil {
+		t.Fatal("expected an unknown model to be rejected")
+	}
+	for _, want := range []string{
+		"model llama3-8b not found in provider groq",
+		"default -> openai/gpt-4o",
+		"groq/llama3-70b",
+		"openai/gpt-4o (context 128000 tokens)",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in the error, got:\n%v", want, err)
+		}
+	}
+
+	if _, err := NewClient(Config{ProvidersConfig: providers, ModelSelection: "default"}); err != nil {
+		t.Errorf("expected the named selection to resolve, got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package llm
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	tiktoken "github.com/pkoukk/tiktoken-go"
+	tiktoken_loader "github.com/pkoukk/tiktoken-go-loader"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+const (
+	// maxTokensSafetyMargin is the headroom always left between the prompt
+	// and the reply, for framing the token count does not see
+	maxTokensSafetyMargin = 512
+	// estimateMarginDivisor adds a tenth of an estimated prompt to the
+	// margin, since the error of an estimate grows with the prompt
+	estimateMarginDivisor = 10
+	// minMaxTokens keeps a nearly full context from requesting a uselessly
+	// short reply; the provider reports the overflow instead
+	minMaxTokens = 256
+	// messageOverheadTokens covers the role and framing of each message
+	messageOverheadTokens = 4
+	// imagePartTokens is a flat estimate for an attached image; the base64
+	// data URL is far longer than what the model is billed for
+	imagePartTokens = 1000
+	// wordBytesPerToken errs high on purpose: English prose averages about
+	// 4 bytes per token, identifiers in code come closer to 3
+	wordBytesPerToken = 3
+)
+
+// o200kModelPrefixes are OpenAI models that use o200k_base but are not yet
+// mapped by tiktoken-go
+var o200kModelPrefixes = []string{"gpt-5", "o1", "o3", "o4"}
+
+var (
+	tokenizersMu sync.Mutex
+	tokenizers   = make(map[string]*tiktoken.Tiktoken)
+)
+
+func init() {
+	// Use the encodings built into the binary instead of downloading them
+	tiktoken.SetBpeLoader(tiktoken_loader.NewOfflineLoader())
+}
+
+// tokenizerFor returns the tokenizer of an OpenAI model, or nil for models
+// it is not known for, such as those of other providers
+func tokenizerFor(model string) *tiktoken.Tiktoken {
+	tokenizersMu.Lock()
+	defer tokenizersMu.Unlock()
+	if enc, ok := tokenizers[model]; ok {
+		return enc
+	}
+
+	enc, err := tiktoken.EncodingForModel(model)
+	if err != nil {
+		enc = nil
+		for _, prefix := range o200kModelPrefixes {
+			if strings.HasPrefix(model, prefix) {
+				enc, _ = tiktoken.GetEncoding(tiktoken.MODEL_O200K_BASE)
+				break
+			}
+		}
+	}
+	tokenizers[model] = enc
+	return enc
+}
+
+// EstimateTextTokens approximates how many tokens text occupies without a
+// tokenizer. It errs high: letters, digits and whitespace count at
+// wordBytesPerToken, while punctuation, which dominates JSON, and every
+// non-ASCII character, such as CJK text, count as a token each.
+func EstimateTextTokens(text string) int {
+	word, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r)) {
+			word++
+		} else {
+			other++
+		}
+	}
+	return (word+wordBytesPerToken-1)/wordBytesPerToken + other
+}
+
+// EstimatePromptTokens approximates how many tokens messages and tool
+// definitions occupy with EstimateTextTokens, plus a little framing per
+// message and a flat size per image
+func EstimatePromptTokens(messages []openai.ChatCompletionMessage, tools []openai.Tool) int {
+	return promptTokens(messages, tools, EstimateTextTokens)
+}
+
+// CountPromptTokens counts the tokens of messages and tool definitions for
+// model. OpenAI models are counted with their tokenizer; other models fall
+// back to EstimatePromptTokens, and exact is false.
+func CountPromptTokens(model string, messages []openai.ChatCompletionMessage, tools []openai.Tool) (tokens int, exact bool) {
+	enc := tokenizerFor(model)
+	if enc == nil {
+		return EstimatePromptTokens(messages, tools), false
+	}
+	count := func(text string) int {
+		return len(enc.EncodeOrdinary(text))
+	}
+	return promptTokens(messages, tools, count), true
+}
+
+func promptTokens(messages []openai.ChatCompletionMessage, tools []openai.Tool, count func(string) int) int {
+	tokens := 0
+	for _, msg := range messages {
+		tokens += messageOverheadTokens + count(msg.Content)
+		for _, part := range msg.MultiContent {
+			tokens += count(part.Text)
+			if part.ImageURL != nil {
+				tokens += imagePartTokens
+			}
+		}
+		for _, call := range msg.ToolCalls {
+			tokens += count(call.Function.Name) + count(call.Function.Arguments)
+		}
+	}
+	for _, tool := range tools {
+		if data, err := json.Marshal(tool.Function); err == nil {
+			tokens += count(string(data))
+		}
+	}
+	return tokens
+}
+
+// effectiveMaxTokens caps the configured reply size to what still fits in the
+// context window after the prompt. An estimated prompt leaves a wider margin.
+func effectiveMaxTokens(model *ModelConfig, promptTokens int, exact bool) int {
+	if model.ContextWindow <= 0 {
+		return model.MaxTokens
+	}
+	margin := maxTokensSafetyMargin
+	if !exact {
+		margin += promptTokens / estimateMarginDivisor
+	}
+	available := model.ContextWindow - promptTokens - margin
+	if available < minMaxTokens {
+		available = minMaxTokens
+	}
+	if available < model.MaxTokens {
+		return available
+	}
+	return model.MaxTokens
+}
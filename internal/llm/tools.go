@@ -0,0 +1,38 @@
+package llm
+
+import (
+	"sort"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/tools"
+)
+
+// unsupportedTools are registered but not offered to the model yet
+var unsupportedTools = map[string]bool{
+	"apply_patch": true,
+}
+
+// BuildOpenAITools converts tools to the OpenAI function-calling schema.
+// Tools are sorted by name so requests are stable across runs.
+func BuildOpenAITools(agentTools []tools.Tool) []openai.Tool {
+	openAITools := make([]openai.Tool, 0, len(agentTools))
+	for _, tool := range agentTools {
+		if unsupportedTools[tool.Name()] {
+			continue
+		}
+
+		openAITools = append(openAITools, openai.Tool{
+			Type: openai.ToolTypeFunction,
+			Function: openai.FunctionDefinition{
+				Name:        tool.Name(),
+				Description: tool.Description(),
+				Parameters:  tool.GetParameters(),
+			},
+		})
+	}
+
+	sort.Slice(openAITools, func(i, j int) bool {
+		return openAITools[i].Function.Name < openAITools[j].Function.Name
+	})
+	return openAITools
+}
@@ -0,0 +1,49 @@
+package llm
+
+import (
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/trknhr/agenticode/internal/tools"
+)
+
+// stubTool is a minimal tools.Tool with a fixed name and schema
+type stubTool struct {
+	name string
+}
+
+func (s stubTool) Name() string        { return s.name }
+func (s stubTool) Description() string { return s.name + " description" }
+func (s stubTool) ReadOnly() bool      { return true }
+func (s stubTool) Execute(args map[string]interface{}) (*tools.ToolResult, error) {
+	return &tools.ToolResult{}, nil
+}
+func (s stubTool) GetParameters() map[string]interface{} {
+	return map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+}
+
+func TestBuildOpenAITools(t *testing.T) {
+	built := BuildOpenAITools([]tools.Tool{
+		stubTool{name: "write_file"},
+		stubTool{name: "apply_patch"},
+		stubTool{name: "grep"},
+	})
+
+	if len(built) != 2 {
+		t.Fatalf("expected apply_patch to be skipped, got %d tools", len(built))
+	}
+	if built[0].Function.Name != "grep" || built[1].Function.Name != "write_file" {
+		t.Errorf("expected tools sorted by name, got %s, %s", built[0].Function.Name, built[1].Function.Name)
+	}
+
+	tool := built[0]
+	if tool.Type != openai.ToolTypeFunction {
+		t.Errorf("unexpected tool type %q", tool.Type)
+	}
+	if tool.Function.Description != "grep description" {
+		t.Errorf("unexpected description %q", tool.Function.Description)
+	}
+	if params, ok := tool.Function.Parameters.(map[string]interface{}); !ok || params["type"] != "object" {
+		t.Errorf("expected the tool schema to be passed through, got %#v", tool.Function.Parameters)
+	}
+}
@@ -0,0 +1,97 @@
+// Package locale holds the catalog of user- and model-facing messages so the
+// CLI can run consistently in one language
+package locale
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Supported languages
+const (
+	English  = "en"
+	Japanese = "ja"
+)
+
+// Message keys
+const (
+	RepetitionGuidance   = "repetition_guidance"
+	ExecutingPrompt      = "executing_prompt"
+	TaskSucceeded        = "task_succeeded"
+	TaskFailed           = "task_failed"
+	ToolErrorsUnresolved = "tool_errors_unresolved"
+	FinalMessage         = "final_message"
+	ExecutionSummary     = "execution_summary"
+	GeneratedFiles       = "generated_files"
+	RemovedLastTurn      = "removed_last_turn"
+)
+
+// catalog maps each language to its messages; English must have every key
+var catalog = map[string]map[string]string{
+	English: {
+		RepetitionGuidance:   "You seem to be repeating the same actions. Please review the previous results and try a different approach.",
+		ExecutingPrompt:      "🚀 Executing prompt with max %d turns...",
+		TaskSucceeded:        "✅ Task completed successfully!",
+		TaskFailed:           "⚠️  Task did not complete successfully",
+		ToolErrorsUnresolved: "⚠️  The last tool calls failed and the agent did not recover from them",
+		FinalMessage:         "💬 Final message: %s",
+		ExecutionSummary:     "📊 Execution summary: %d steps taken",
+		GeneratedFiles:       "📝 Generated %d file(s):",
+		RemovedLastTurn:      "↩️  Removed the last turn. Previous prompt:\n%s",
+	},
+	Japanese: {
+		RepetitionGuidance:   "同じ操作を繰り返しているようです。これまでの結果を見直し、別の方法を試してください。",
+		ExecutingPrompt:      "🚀 最大 %d ターンでプロンプトを実行します...",
+		TaskSucceeded:        "✅ タスクが正常に完了しました！",
+		TaskFailed:           "⚠️  タスクは正常に完了しませんでした",
+		ToolErrorsUnresolved: "⚠️  最後のツール呼び出しが失敗し、エージェントは回復しませんでした",
+		FinalMessage:         "💬 最終メッセージ: %s",
+		ExecutionSummary:     "📊 実行サマリー: %d ステップ",
+		GeneratedFiles:       "📝 %d 個のファイルを生成しました:",
+		RemovedLastTurn:      "↩️  直前のターンを削除しました。前回のプロンプト:\n%s",
+	},
+}
+
+var current atomic.Value // string
+
+// SetLanguage selects the language of all messages; an empty name selects
+// English
+func SetLanguage(lang string) error {
+	if lang == "" {
+		lang = English
+	}
+	if _, ok := catalog[lang]; !ok {
+		return fmt.Errorf("unsupported language %q (expected en or ja)", lang)
+	}
+	current.Store(lang)
+	return nil
+}
+
+// Language returns the selected language
+func Language() string {
+	if lang, ok := current.Load().(string); ok {
+		return lang
+	}
+	return English
+}
+
+// T returns the message for key in the selected language, formatted with args
+func T(key string, args ...interface{}) string {
+	return Message(Language(), key, args...)
+}
+
+// Message returns the message for key in lang, falling back to English for
+// keys that are not translated, formatted with args
+func Message(lang, key string, args ...interface{}) string {
+	format, ok := catalog[lang][key]
+	if !ok {
+		format, ok = catalog[English][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,37 @@
+package locale
+
+import "testing"
+
+func TestMessageInSelectedLanguage(t *testing.T) {
+	t.Cleanup(func() { SetLanguage(English) })
+
+	if got := T(ExecutionSummary, 3); got != "📊 Execution summary: 3 steps taken" {
+		t.Errorf("unexpected English message %q", got)
+	}
+	if err := SetLanguage(Japanese); err != nil {
+		t.Fatal(err)
+	}
+	if got := T(ExecutionSummary, 3); got != "📊 実行サマリー: 3 ステップ" {
+		t.Errorf("unexpected Japanese message %q", got)
+	}
+	if got := T(RepetitionGuidance); got != catalog[Japanese][RepetitionGuidance] {
+		t.Errorf("expected the Japanese repetition guidance, got %q", got)
+	}
+
+	if err := SetLanguage("fr"); err == nil {
+		t.Error("expected an unsupported language to be rejected")
+	}
+	if Language() != Japanese {
+		t.Errorf("a rejected language must not change the selection, got %q", Language())
+	}
+}
+
+func TestCatalogsHaveTheSameKeys(t *testing.T) {
+	for lang, messages := range catalog {
+		for key := range catalog[English] {
+			if _, ok := messages[key]; !ok {
+				t.Errorf("%s is missing %s", lang, key)
+			}
+		}
+	}
+}
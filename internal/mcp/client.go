@@ -12,7 +12,18 @@ import (
 
 // clientWrapper wraps the mcp-go client to implement our MCPClient interface
 type clientWrapper struct {
-	client *client.Client
+	client  *client.Client
+	headers *headerProvider // Set for http/sse servers with a refreshable token
+}
+
+// retryUnauthorized re-runs a request once with a fresh token after a 401
+func (c *clientWrapper) retryUnauthorized(err error) bool {
+	if c.headers == nil || !c.headers.hasToken() || !isUnauthorized(err) {
+		return false
+	}
+	log.Printf("MCP server rejected the token, refreshing it")
+	c.headers.Invalidate()
+	return true
 }
 
 func (c *clientWrapper) Initialize(ctx context.Context, request mcp.InitializeRequest) (*mcp.InitializeResult, error) {
@@ -20,11 +31,19 @@ func (c *clientWrapper) Initialize(ctx context.Context, request mcp.InitializeRe
 }
 
 func (c *clientWrapper) ListTools(ctx context.Context, request mcp.ListToolsRequest) (*mcp.ListToolsResult, error) {
-	return c.client.ListTools(ctx, request)
+	result, err := c.client.ListTools(ctx, request)
+	if c.retryUnauthorized(err) {
+		return c.client.ListTools(ctx, request)
+	}
+	return result, err
 }
 
 func (c *clientWrapper) CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	return c.client.CallTool(ctx, request)
+	result, err := c.client.CallTool(ctx, request)
+	if c.retryUnauthorized(err) {
+		return c.client.CallTool(ctx, request)
+	}
+	return result, err
 }
 
 func (c *clientWrapper) Close() error {
@@ -35,6 +54,10 @@ func (c *clientWrapper) Start(ctx context.Context) error {
 	return c.client.Start(ctx)
 }
 
+func (c *clientWrapper) OnNotification(handler func(notification mcp.JSONRPCNotification)) {
+	c.client.OnNotification(handler)
+}
+
 // MCPClient is an interface for MCP client operations
 type MCPClient interface {
 	Initialize(ctx context.Context, request mcp.InitializeRequest) (*mcp.InitializeResult, error)
@@ -43,6 +66,8 @@ type MCPClient interface {
 	Close() error
 	// Start is called before Initialize for clients that need it (e.g., stdio)
 	Start(ctx context.Context) error
+	// OnNotification registers a handler for server-sent notifications (e.g., progress)
+	OnNotification(handler func(notification mcp.JSONRPCNotification))
 }
 
 // CreateClient creates an MCP client based on the configuration
@@ -93,13 +118,17 @@ func createHTTPClient(config MCPConfig) (MCPClient, error) {
 
 	log.Printf("Creating HTTP MCP client: %s", config.URL)
 	
-	// Use variadic arguments directly
-	c, err := client.NewStreamableHttpClient(config.URL, transport.WithHTTPHeaders(config.ResolvedHeaders()))
+	// Headers are resolved per request so refreshed tokens are picked up
+	headers := newHeaderProvider(config)
+	c, err := client.NewStreamableHttpClient(config.URL,
+		transport.WithHTTPHeaderFunc(headers.Headers),
+		transport.WithHTTPBasicClient(newAuthHTTPClient()),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP MCP client: %w", err)
 	}
 	
-	return &clientWrapper{client: c}, nil
+	return &clientWrapper{client: c, headers: headers}, nil
 }
 
 // createSSEClient creates an SSE-based MCP client
@@ -110,13 +139,17 @@ func createSSEClient(config MCPConfig) (MCPClient, error) {
 
 	log.Printf("Creating SSE MCP client: %s", config.URL)
 	
-	// Use variadic arguments directly
-	c, err := client.NewSSEMCPClient(config.URL, client.WithHeaders(config.ResolvedHeaders()))
+	// Headers are resolved per request so refreshed tokens are picked up
+	headers := newHeaderProvider(config)
+	c, err := client.NewSSEMCPClient(config.URL,
+		client.WithHeaderFunc(headers.Headers),
+		transport.WithHTTPClient(newAuthHTTPClient()),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create SSE MCP client: %w", err)
 	}
 	
-	return &clientWrapper{client: c}, nil
+	return &clientWrapper{client: c, headers: headers}, nil
 }
 
 // GetTools retrieves available tools from an MCP server
@@ -3,6 +3,7 @@ package mcp
 import (
 	"os"
 	"strings"
+	"time"
 )
 
 // MCPType represents the type of MCP server connection
@@ -23,6 +24,35 @@ type MCPConfig struct {
 	Env      map[string]string `yaml:"env" mapstructure:"env"`           // Environment variables
 	Headers  map[string]string `yaml:"headers" mapstructure:"headers"`   // HTTP headers (for http/sse)
 	Disabled bool              `yaml:"disabled" mapstructure:"disabled"` // Whether this server is disabled
+
+	TokenEnv     string        `yaml:"token_env" mapstructure:"token_env"`         // Env var re-read per request for a bearer token (for http/sse)
+	TokenCommand string        `yaml:"token_command" mapstructure:"token_command"` // Command printing a bearer token, re-run after token_ttl or a 401 (for http/sse)
+	TokenTTL     time.Duration `yaml:"token_ttl" mapstructure:"token_ttl"`         // How long a token_command result is reused (default 5m)
+
+	StartupRetries int           `yaml:"startup_retries" mapstructure:"startup_retries"` // Extra startup attempts before giving up (default 2, -1 disables)
+	StartupBackoff time.Duration `yaml:"startup_backoff" mapstructure:"startup_backoff"` // Delay before the first retry, doubled each time (default 1s)
+}
+
+const (
+	defaultStartupRetries = 2
+	defaultStartupBackoff = time.Second
+)
+
+// startupPolicy returns the number of startup retries and the initial backoff
+func (m MCPConfig) startupPolicy() (int, time.Duration) {
+	retries := m.StartupRetries
+	switch {
+	case retries == 0:
+		retries = defaultStartupRetries
+	case retries < 0:
+		retries = 0
+	}
+
+	backoff := m.StartupBackoff
+	if backoff <= 0 {
+		backoff = defaultStartupBackoff
+	}
+	return retries, backoff
 }
 
 // MCPServersConfig represents the complete MCP configuration
@@ -0,0 +1,18 @@
+package mcp
+
+import "log"
+
+// debugLogging enables verbose logging of MCP tool arguments and schemas.
+// It is off by default because arguments can contain credentials.
+var debugLogging bool
+
+// SetDebug toggles verbose MCP logging (set via --debug)
+func SetDebug(enabled bool) {
+	debugLogging = enabled
+}
+
+func debugf(format string, args ...interface{}) {
+	if debugLogging {
+		log.Printf(format, args...)
+	}
+}
@@ -0,0 +1,122 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultTokenTTL is how long a token_command result is reused
+const defaultTokenTTL = 5 * time.Minute
+
+// headerProvider resolves an http/sse server's headers for every request, so
+// environment references and bearer tokens pick up new values mid-session
+type headerProvider struct {
+	config MCPConfig
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+func newHeaderProvider(config MCPConfig) *headerProvider {
+	return &headerProvider{config: config}
+}
+
+// hasToken reports whether the server is configured with a refreshable token
+func (p *headerProvider) hasToken() bool {
+	return p.config.TokenCommand != "" || p.config.TokenEnv != ""
+}
+
+// Headers returns the headers for one request. A token from token_env or
+// token_command becomes the Authorization header, overriding a static one.
+func (p *headerProvider) Headers(ctx context.Context) map[string]string {
+	headers := p.config.ResolvedHeaders()
+	if !p.hasToken() {
+		return headers
+	}
+	token, err := p.Token(ctx)
+	if err != nil {
+		log.Printf("Warning: MCP token refresh for %s failed: %v", p.config.URL, err)
+		return headers
+	}
+	if token != "" {
+		headers["Authorization"] = "Bearer " + token
+	}
+	return headers
+}
+
+// Token returns the current bearer token. token_env is read on every call;
+// token_command output is cached for token_ttl.
+func (p *headerProvider) Token(ctx context.Context) (string, error) {
+	if p.config.TokenEnv != "" {
+		return strings.TrimSpace(os.Getenv(p.config.TokenEnv)), nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token != "" && time.Now().Before(p.expires) {
+		return p.token, nil
+	}
+
+	out, err := exec.CommandContext(ctx, "sh", "-c", p.config.TokenCommand).Output()
+	if err != nil {
+		return "", fmt.Errorf("token_command failed: %w", err)
+	}
+	ttl := p.config.TokenTTL
+	if ttl <= 0 {
+		ttl = defaultTokenTTL
+	}
+	p.token = strings.TrimSpace(string(out))
+	p.expires = time.Now().Add(ttl)
+	return p.token, nil
+}
+
+// Invalidate drops the cached token so the next request fetches a new one
+func (p *headerProvider) Invalidate() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.token = ""
+}
+
+// statusError is an HTTP response an http/sse server rejected a request with
+type statusError struct {
+	StatusCode int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("HTTP %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// unauthorizedTransport turns 401 responses into a statusError, which mcp-go
+// would otherwise only describe in an error message
+type unauthorizedTransport struct {
+	base http.RoundTripper
+}
+
+func (t unauthorizedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	resp.Body.Close()
+	return nil, &statusError{StatusCode: resp.StatusCode}
+}
+
+// newAuthHTTPClient returns the HTTP client for http/sse servers
+func newAuthHTTPClient() *http.Client {
+	return &http.Client{Transport: unauthorizedTransport{base: http.DefaultTransport}}
+}
+
+// isUnauthorized reports whether err comes from an HTTP 401 response
+func isUnauthorized(err error) bool {
+	var statusErr *statusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusUnauthorized
+}
@@ -0,0 +1,98 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestHTTPClientRefreshesExpiredToken(t *testing.T) {
+	var mu sync.Mutex
+	valid := "token-1"
+	var seen []string
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("ping"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("pong"), nil
+	})
+	handler := server.NewStreamableHTTPServer(mcpServer)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		auth := r.Header.Get("Authorization")
+		seen = append(seen, auth)
+		ok := auth == "Bearer "+valid
+		mu.Unlock()
+		if !ok {
+			http.Error(w, "token expired", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	os.WriteFile(tokenFile, []byte("token-1\n"), 0600)
+	config := MCPConfig{Type: MCPHttp, URL: ts.URL, TokenCommand: "cat " + tokenFile, TokenTTL: time.Hour}
+
+	manager := NewClientManager()
+	defer manager.CloseAll()
+	ctx := context.Background()
+	if err := manager.InitializeClient(ctx, "remote", config); err != nil {
+		t.Fatalf("expected the first token to work: %v", err)
+	}
+
+	// The token expires and the provider issues a new one; the cached token
+	// is rejected once and the client retries with the fresh one
+	os.WriteFile(tokenFile, []byte("token-2\n"), 0600)
+	mu.Lock()
+	valid = "token-2"
+	mu.Unlock()
+
+	client, err := manager.GetClient("remote")
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "ping"
+	if _, err := client.CallTool(ctx, request); err != nil {
+		t.Fatalf("expected the call to succeed with the refreshed token: %v", err)
+	}
+
+	mu.Lock()
+	last := seen[len(seen)-1]
+	mu.Unlock()
+	if last != "Bearer token-2" {
+		t.Errorf("expected the new token on the retried request, got %q", last)
+	}
+
+	// A reconnect starts from a fresh provider
+	if err := manager.Reconnect(ctx, "remote"); err != nil {
+		t.Fatalf("expected reconnect to succeed: %v", err)
+	}
+}
+
+func TestHeaderProviderReadsTokenEnvPerRequest(t *testing.T) {
+	t.Setenv("MCP_TEST_TOKEN", "first")
+	provider := newHeaderProvider(MCPConfig{
+		Headers:  map[string]string{"Authorization": "Bearer static", "X-Team": "core"},
+		TokenEnv: "MCP_TEST_TOKEN",
+	})
+
+	headers := provider.Headers(context.Background())
+	if headers["Authorization"] != "Bearer first" || headers["X-Team"] != "core" {
+		t.Fatalf("expected the env token to override the static header, got %v", headers)
+	}
+
+	os.Setenv("MCP_TEST_TOKEN", "second")
+	if got := provider.Headers(context.Background())["Authorization"]; got != "Bearer second" {
+		t.Errorf("expected the rotated token, got %q", got)
+	}
+}
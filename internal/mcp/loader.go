@@ -65,12 +65,14 @@ func LoadMCPTools(ctx context.Context, approver agent.ToolApprover, v *viper.Vip
 	for name, config := range mcpConfigs {
 		if config.Disabled {
 			log.Printf("Skipping disabled MCP server: %s", name)
+			manager.updateState(name, StateDisabled, nil, nil, 0, 0)
 			continue
 		}
 		
 		// Validate configuration
 		if err := config.Validate(); err != nil {
 			log.Printf("Invalid MCP configuration for %s: %v", name, err)
+			manager.updateState(name, StateError, err, nil, 0, 0)
 			continue
 		}
 		
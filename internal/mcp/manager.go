@@ -43,35 +43,83 @@ type ClientInfo struct {
 	Client      MCPClient
 	ToolCount   int
 	ConnectedAt time.Time
+	Attempts    int // Startup attempts made, including the successful one
 }
 
 // ClientManager manages MCP client connections
 type ClientManager struct {
-	clients sync.Map // map[string]MCPClient
-	states  sync.Map // map[string]ClientInfo
-	mu      sync.RWMutex
+	clients  sync.Map // map[string]MCPClient
+	states   sync.Map // map[string]ClientInfo
+	configs  sync.Map // map[string]MCPConfig, kept for Reconnect
+	mu       sync.RWMutex
+	progress progressRouter
+
+	// createClient builds clients from config; replaced in tests
+	createClient func(config MCPConfig) (MCPClient, error)
 }
 
 // NewClientManager creates a new client manager
 func NewClientManager() *ClientManager {
-	return &ClientManager{}
+	return &ClientManager{createClient: CreateClient}
 }
 
-// InitializeClient creates and initializes an MCP client
+// InitializeClient creates and initializes an MCP client. Servers that fail to
+// start are retried with exponential backoff before being marked as errored.
 func (m *ClientManager) InitializeClient(ctx context.Context, name string, config MCPConfig) error {
-	// Update state to starting
-	m.updateState(name, StateStarting, nil, nil, 0)
+	m.configs.Store(name, config)
+	retries, backoff := config.startupPolicy()
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		m.updateState(name, StateStarting, nil, nil, 0, attempt)
+
+		if err = m.connect(ctx, name, config, attempt); err == nil {
+			return nil
+		}
+		if attempt > retries {
+			break
+		}
+
+		delay := backoff << (attempt - 1)
+		log.Printf("MCP server %s failed to start (attempt %d/%d): %v; retrying in %v", name, attempt, retries+1, err, delay)
+		select {
+		case <-ctx.Done():
+			m.updateState(name, StateError, err, nil, 0, attempt)
+			return err
+		case <-time.After(delay):
+		}
+	}
+
+	return err
+}
 
+// Reconnect closes a server's client and connects again. The new client
+// resolves its headers afresh, so expired tokens are not carried over.
+func (m *ClientManager) Reconnect(ctx context.Context, name string) error {
+	value, ok := m.configs.Load(name)
+	if !ok {
+		return fmt.Errorf("client %s not found", name)
+	}
+	if old, ok := m.clients.LoadAndDelete(name); ok {
+		if client, ok := old.(MCPClient); ok {
+			client.Close()
+		}
+	}
+	return m.InitializeClient(ctx, name, value.(MCPConfig))
+}
+
+// connect makes a single attempt to create, start and initialize a client
+func (m *ClientManager) connect(ctx context.Context, name string, config MCPConfig, attempt int) error {
 	// Create the client
-	client, err := CreateClient(config)
+	client, err := m.createClient(config)
 	if err != nil {
-		m.updateState(name, StateError, err, nil, 0)
+		m.updateState(name, StateError, err, nil, 0, attempt)
 		return fmt.Errorf("failed to create client for %s: %w", name, err)
 	}
 
 	// Start the client (required for stdio clients)
 	if err := client.Start(ctx); err != nil {
-		m.updateState(name, StateError, err, nil, 0)
+		m.updateState(name, StateError, err, nil, 0, attempt)
 		client.Close()
 		return fmt.Errorf("failed to start client %s: %w", name, err)
 	}
@@ -86,13 +134,13 @@ func (m *ClientManager) InitializeClient(ctx context.Context, name string, confi
 
 	_, err = client.Initialize(ctx, initRequest)
 	if err != nil {
-		m.updateState(name, StateError, err, nil, 0)
+		m.updateState(name, StateError, err, nil, 0, attempt)
 		client.Close()
 		return fmt.Errorf("failed to initialize MCP client %s: %w", name, err)
 	}
 
 	// Store the client
-	m.clients.Store(name, client)
+	m.registerClient(name, client)
 
 	// Count tools
 	toolsRequest := mcp.ListToolsRequest{}
@@ -100,15 +148,29 @@ func (m *ClientManager) InitializeClient(ctx context.Context, name string, confi
 	if err != nil {
 		// Non-fatal: client is initialized but we couldn't list tools
 		log.Printf("Warning: failed to list tools from %s: %v", name, err)
-		m.updateState(name, StateConnected, nil, client, 0)
+		m.updateState(name, StateConnected, nil, client, 0, attempt)
 	} else {
-		m.updateState(name, StateConnected, nil, client, len(result.Tools))
+		m.updateState(name, StateConnected, nil, client, len(result.Tools), attempt)
 		log.Printf("MCP client %s connected with %d tools", name, len(result.Tools))
 	}
 
 	return nil
 }
 
+// registerClient stores a client and routes its progress notifications
+func (m *ClientManager) registerClient(name string, client MCPClient) {
+	client.OnNotification(func(notification mcp.JSONRPCNotification) {
+		m.progress.handleNotification(name, notification)
+	})
+	m.clients.Store(name, client)
+}
+
+// WatchProgress registers handler for progress notifications and returns the
+// progress token to attach to a request, plus a func to stop watching
+func (m *ClientManager) WatchProgress(name string, handler ProgressHandler) (string, func()) {
+	return m.progress.register(name, handler)
+}
+
 // GetClient retrieves a client by name
 func (m *ClientManager) GetClient(name string) (MCPClient, error) {
 	value, ok := m.clients.Load(name)
@@ -167,16 +229,18 @@ func (m *ClientManager) CloseAll() {
 	})
 	m.clients = sync.Map{}
 	m.states = sync.Map{}
+	m.configs = sync.Map{}
 }
 
 // updateState updates the state of a client
-func (m *ClientManager) updateState(name string, state ClientState, err error, client MCPClient, toolCount int) {
+func (m *ClientManager) updateState(name string, state ClientState, err error, client MCPClient, toolCount int, attempts int) {
 	info := ClientInfo{
 		Name:      name,
 		State:     state,
 		Error:     err,
 		Client:    client,
 		ToolCount: toolCount,
+		Attempts:  attempts,
 	}
 	if state == StateConnected {
 		info.ConnectedAt = time.Now()
@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// flakyManager returns a manager whose clients fail to start the first failures times
+func flakyManager(failures int) (*ClientManager, *int) {
+	attempts := 0
+	manager := NewClientManager()
+	manager.createClient = func(config MCPConfig) (MCPClient, error) {
+		attempts++
+		current := attempts
+		return &fakeClient{start: func(ctx context.Context) error {
+			if current <= failures {
+				return errors.New("server not ready")
+			}
+			return nil
+		}}, nil
+	}
+	return manager, &attempts
+}
+
+func TestInitializeClientRetriesStartup(t *testing.T) {
+	manager, attempts := flakyManager(2)
+	config := MCPConfig{Type: MCPStdio, Command: "server", StartupRetries: 3, StartupBackoff: time.Millisecond}
+
+	if err := manager.InitializeClient(context.Background(), "slow", config); err != nil {
+		t.Fatalf("expected startup to succeed after retries, got %v", err)
+	}
+
+	info, ok := manager.GetState("slow")
+	if !ok || info.State != StateConnected {
+		t.Fatalf("expected connected state, got %+v", info)
+	}
+	if info.Attempts != 3 || *attempts != 3 {
+		t.Errorf("expected 3 attempts, got state=%d created=%d", info.Attempts, *attempts)
+	}
+}
+
+func TestInitializeClientGivesUpAfterRetries(t *testing.T) {
+	manager, attempts := flakyManager(10)
+	config := MCPConfig{Type: MCPStdio, Command: "server", StartupRetries: 1, StartupBackoff: time.Millisecond}
+
+	if err := manager.InitializeClient(context.Background(), "broken", config); err == nil {
+		t.Fatal("expected startup to fail")
+	}
+
+	info, _ := manager.GetState("broken")
+	if info.State != StateError || info.Attempts != 2 || *attempts != 2 {
+		t.Errorf("expected error state after 2 attempts, got state=%v attempts=%d created=%d", info.State, info.Attempts, *attempts)
+	}
+}
+
+func TestStartupPolicyDefaults(t *testing.T) {
+	retries, backoff := MCPConfig{}.startupPolicy()
+	if retries != defaultStartupRetries || backoff != defaultStartupBackoff {
+		t.Errorf("unexpected defaults: %d, %v", retries, backoff)
+	}
+
+	if retries, _ := (MCPConfig{StartupRetries: -1}).startupPolicy(); retries != 0 {
+		t.Errorf("expected negative retries to disable retrying, got %d", retries)
+	}
+}
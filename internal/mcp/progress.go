@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// progressNotificationMethod is the JSON-RPC method used by MCP servers to report progress
+const progressNotificationMethod = "notifications/progress"
+
+// ProgressUpdate is a single progress report emitted by an MCP server during a tool call
+type ProgressUpdate struct {
+	ServerName string
+	ToolName   string
+	Progress   float64
+	Total      float64 // 0 when the server does not know the total
+	Message    string
+}
+
+// String renders the update as a short human-readable status line
+func (p ProgressUpdate) String() string {
+	status := fmt.Sprintf("%.0f", p.Progress)
+	if p.Total > 0 {
+		status = fmt.Sprintf("%.0f/%.0f (%.0f%%)", p.Progress, p.Total, p.Progress/p.Total*100)
+	}
+	if p.Message != "" {
+		status += " - " + p.Message
+	}
+	return status
+}
+
+// ProgressHandler receives progress updates for an in-flight MCP tool call
+type ProgressHandler func(update ProgressUpdate)
+
+// progressRouter dispatches progress notifications to the listener registered for their token
+type progressRouter struct {
+	listeners sync.Map // map[string]ProgressHandler
+	counter   atomic.Uint64
+}
+
+// register creates a new progress token bound to handler and returns it with a release func
+func (r *progressRouter) register(prefix string, handler ProgressHandler) (string, func()) {
+	token := fmt.Sprintf("%s-%d", prefix, r.counter.Add(1))
+	r.listeners.Store(token, handler)
+	return token, func() { r.listeners.Delete(token) }
+}
+
+// handleNotification routes a notification to its listener if it is a known progress update
+func (r *progressRouter) handleNotification(serverName string, notification mcp.JSONRPCNotification) {
+	if notification.Method != progressNotificationMethod {
+		return
+	}
+
+	fields := notification.Params.AdditionalFields
+	token, ok := fields["progressToken"]
+	if !ok || token == nil {
+		return
+	}
+
+	value, ok := r.listeners.Load(fmt.Sprint(token))
+	if !ok {
+		return
+	}
+	handler, ok := value.(ProgressHandler)
+	if !ok || handler == nil {
+		return
+	}
+
+	update := ProgressUpdate{ServerName: serverName}
+	update.Progress, _ = toFloat(fields["progress"])
+	update.Total, _ = toFloat(fields["total"])
+	update.Message, _ = fields["message"].(string)
+	handler(update)
+}
+
+// toFloat converts JSON-decoded numeric values to float64
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
@@ -2,8 +2,10 @@ package mcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"os"
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -19,6 +21,7 @@ type MCPTool struct {
 	approver   agent.ToolApprover
 	clientFunc func() (MCPClient, error) // Function to create client on demand (deprecated)
 	manager    *ClientManager            // Client manager for connection reuse
+	onProgress ProgressHandler           // Receives progress updates during long-running calls
 }
 
 // NewMCPTool creates a new MCP tool adapter (deprecated - use NewMCPToolWithManager)
@@ -43,6 +46,43 @@ func NewMCPToolWithManager(serverName string, tool mcp.Tool, mcpConfig MCPConfig
 	}
 }
 
+// SetProgressHandler overrides how progress notifications are surfaced.
+// By default progress is printed as a status line on stderr, keeping stdout
+// for the agent's output.
+func (m *MCPTool) SetProgressHandler(handler ProgressHandler) {
+	m.onProgress = handler
+}
+
+// reportProgress forwards a progress update to the configured handler
+func (m *MCPTool) reportProgress(update ProgressUpdate) {
+	update.ToolName = m.tool.Name
+	if m.onProgress != nil {
+		m.onProgress(update)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "⏳ %s: %s\n", m.Name(), update)
+}
+
+// callTool calls the tool on client. When the server still rejects the
+// credentials after the client's own token refresh, the session itself may
+// be stale, so the manager reconnects the server and the call is made once more.
+func (m *MCPTool) callTool(ctx context.Context, client MCPClient, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	result, err := client.CallTool(ctx, request)
+	if err == nil || m.manager == nil || !isUnauthorized(err) {
+		return result, err
+	}
+
+	log.Printf("MCP server %s rejected the credentials, reconnecting", m.serverName)
+	if reconnectErr := m.manager.Reconnect(ctx, m.serverName); reconnectErr != nil {
+		return nil, fmt.Errorf("%w (reconnect failed: %v)", err, reconnectErr)
+	}
+	client, err = m.manager.GetClient(m.serverName)
+	if err != nil {
+		return nil, err
+	}
+	return client.CallTool(ctx, request)
+}
+
 // Name returns the tool name with MCP prefix
 func (m *MCPTool) Name() string {
 	return fmt.Sprintf("mcp_%s_%s", m.serverName, m.tool.Name)
@@ -65,8 +105,8 @@ func (m *MCPTool) ReadOnly() bool {
 func (m *MCPTool) Execute(args map[string]interface{}) (*tools.ToolResult, error) {
 	ctx := context.Background()
 
-	// Log the incoming arguments for debugging
-	log.Printf("MCP tool %s executing with args: %+v", m.Name(), args)
+	// Arguments may carry secrets, so only log them in debug mode
+	debugf("MCP tool %s executing with args: %+v", m.Name(), args)
 
 	// For now, skip approval for MCP tools
 	// TODO: Integrate with approval system properly
@@ -111,7 +151,7 @@ func (m *MCPTool) Execute(args map[string]interface{}) (*tools.ToolResult, error
 		for _, required := range m.tool.InputSchema.Required {
 			if _, exists := args[required]; !exists {
 				// Log detailed error for debugging
-				log.Printf("MCP tool %s missing required parameter '%s'. Provided args: %+v, Required: %v", 
+				debugf("MCP tool %s missing required parameter '%s'. Provided args: %+v, Required: %v", 
 					m.Name(), required, args, m.tool.InputSchema.Required)
 				return &tools.ToolResult{
 					LLMContent:    fmt.Sprintf("Missing required parameter '%s' for MCP tool %s. Required parameters: %v", 
@@ -128,15 +168,22 @@ func (m *MCPTool) Execute(args map[string]interface{}) (*tools.ToolResult, error
 	toolRequest.Params.Name = m.tool.Name
 	toolRequest.Params.Arguments = args
 
+	// Ask the server for progress notifications so long operations don't look hung
+	if m.manager != nil {
+		token, stop := m.manager.WatchProgress(m.serverName, m.reportProgress)
+		defer stop()
+		toolRequest.Params.Meta = &mcp.Meta{ProgressToken: token}
+	}
+
 	// Log the actual MCP request being sent
-	log.Printf("Sending MCP request to %s: tool=%s, args=%+v", m.serverName, m.tool.Name, args)
+	debugf("Sending MCP request to %s: tool=%s, args=%+v", m.serverName, m.tool.Name, args)
 
 	// Execute the tool
-	result, err := client.CallTool(ctx, toolRequest)
+	result, err := m.callTool(ctx, client, toolRequest)
 	if err != nil {
 		log.Printf("MCP tool execution error for %s: %v", m.Name(), err)
-		// Check if this is a validation error from the MCP server
-		if strings.Contains(err.Error(), "validation error") {
+		err = classifyCallError(err)
+		if errors.Is(err, tools.ErrValidation) {
 			return &tools.ToolResult{
 				LLMContent:    fmt.Sprintf("MCP parameter validation error: %v\nExpected parameters: %+v\nReceived: %+v", 
 					err, m.tool.InputSchema.Properties, args),
@@ -192,8 +239,22 @@ func (m *MCPTool) GetParameters() map[string]interface{} {
 	}
 	
 	// Log the schema for debugging
-	log.Printf("MCP tool %s schema: properties=%+v, required=%v", 
+	debugf("MCP tool %s schema: properties=%+v, required=%v", 
 		m.Name(), m.tool.InputSchema.Properties, m.tool.InputSchema.Required)
 	
 	return params
-}
\ No newline at end of file
+}
+
+// classifyCallError maps a failed tools/call onto the tools error kinds.
+// mcp-go surfaces JSON-RPC errors only as text, so this is the one place
+// that inspects the message.
+func classifyCallError(err error) error {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return tools.Classify(tools.ErrTimeout, err)
+	case strings.Contains(msg, "validation error"), strings.Contains(msg, "invalid params"):
+		return tools.Classify(tools.ErrValidation, err)
+	}
+	return err
+}
@@ -0,0 +1,165 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/trknhr/agenticode/internal/tools"
+)
+
+// fakeClient is an in-memory MCPClient used in tests
+type fakeClient struct {
+	handlers []func(notification mcp.JSONRPCNotification)
+	callTool func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	start    func(ctx context.Context) error
+}
+
+func (f *fakeClient) Initialize(ctx context.Context, request mcp.InitializeRequest) (*mcp.InitializeResult, error) {
+	return &mcp.InitializeResult{}, nil
+}
+
+func (f *fakeClient) ListTools(ctx context.Context, request mcp.ListToolsRequest) (*mcp.ListToolsResult, error) {
+	return &mcp.ListToolsResult{}, nil
+}
+
+func (f *fakeClient) CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return f.callTool(ctx, request)
+}
+
+func (f *fakeClient) Close() error { return nil }
+
+func (f *fakeClient) Start(ctx context.Context) error {
+	if f.start != nil {
+		return f.start(ctx)
+	}
+	return nil
+}
+
+func (f *fakeClient) OnNotification(handler func(notification mcp.JSONRPCNotification)) {
+	f.handlers = append(f.handlers, handler)
+}
+
+// notify delivers a notification to every registered handler
+func (f *fakeClient) notify(method string, fields map[string]any) {
+	notification := mcp.JSONRPCNotification{JSONRPC: mcp.JSONRPC_VERSION}
+	notification.Method = method
+	notification.Params.AdditionalFields = fields
+	for _, h := range f.handlers {
+		h(notification)
+	}
+}
+
+func TestMCPToolProgressNotifications(t *testing.T) {
+	fake := &fakeClient{}
+	fake.callTool = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+			t.Fatal("expected the request to carry a progress token")
+		}
+		token := request.Params.Meta.ProgressToken
+		fake.notify(progressNotificationMethod, map[string]any{"progressToken": token, "progress": 1.0, "total": 3.0, "message": "indexing"})
+		fake.notify(progressNotificationMethod, map[string]any{"progressToken": "someone-else", "progress": 99.0})
+		fake.notify(progressNotificationMethod, map[string]any{"progressToken": token, "progress": 3.0, "total": 3.0})
+		return mcp.NewToolResultText("done"), nil
+	}
+
+	manager := NewClientManager()
+	manager.registerClient("fake", fake)
+	manager.updateState("fake", StateConnected, nil, fake, 1, 1)
+
+	tool := NewMCPToolWithManager("fake", mcp.Tool{Name: "slow_op"}, MCPConfig{Type: MCPStdio, Command: "fake"}, nil, manager)
+
+	var updates []ProgressUpdate
+	tool.SetProgressHandler(func(update ProgressUpdate) {
+		updates = append(updates, update)
+	})
+
+	result, err := tool.Execute(map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if !strings.Contains(result.LLMContent, "done") {
+		t.Errorf("expected final result content, got %q", result.LLMContent)
+	}
+
+	if len(updates) != 2 {
+		t.Fatalf("expected 2 progress updates, got %d: %+v", len(updates), updates)
+	}
+	if updates[0].Progress != 1 || updates[0].Total != 3 || updates[0].Message != "indexing" {
+		t.Errorf("unexpected first update: %+v", updates[0])
+	}
+	if updates[0].ToolName != "slow_op" || updates[0].ServerName != "fake" {
+		t.Errorf("update not attributed to tool: %+v", updates[0])
+	}
+	if updates[1].Progress != 3 {
+		t.Errorf("unexpected second update: %+v", updates[1])
+	}
+
+	// Once the call returns, late notifications for its token are ignored
+	fake.notify(progressNotificationMethod, map[string]any{"progressToken": "fake-1", "progress": 5.0})
+	if len(updates) != 2 {
+		t.Errorf("expected no updates after the call finished, got %d", len(updates))
+	}
+}
+
+func TestMCPToolClassifiesValidationErrors(t *testing.T) {
+	fake := &fakeClient{}
+	fake.callTool = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, errors.New("request failed: Invalid params: query must be a string")
+	}
+	manager := NewClientManager()
+	manager.registerClient("fake", fake)
+	manager.updateState("fake", StateConnected, nil, fake, 1, 1)
+	tool := NewMCPToolWithManager("fake", mcp.Tool{Name: "search"}, MCPConfig{Type: MCPStdio, Command: "fake"}, nil, manager)
+
+	result, err := tool.Execute(map[string]interface{}{"query": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(result.Error, tools.ErrValidation) {
+		t.Errorf("expected ErrValidation, got %v", result.Error)
+	}
+	if !strings.HasPrefix(result.LLMContent, "MCP parameter validation error") {
+		t.Errorf("expected the schema hint, got %q", result.LLMContent)
+	}
+
+	if err := classifyCallError(fmt.Errorf("call: %w", context.DeadlineExceeded)); !errors.Is(err, tools.ErrTimeout) {
+		t.Errorf("expected ErrTimeout, got %v", err)
+	}
+}
+
+func TestMCPToolReconnectsWhenStillUnauthorized(t *testing.T) {
+	connects := 0
+	manager := NewClientManager()
+	manager.createClient = func(config MCPConfig) (MCPClient, error) {
+		connects++
+		session := connects
+		return &fakeClient{callTool: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if session == 1 {
+				return nil, fmt.Errorf("failed to send request: %w", &statusError{StatusCode: http.StatusUnauthorized})
+			}
+			return mcp.NewToolResultText("pong"), nil
+		}}, nil
+	}
+	config := MCPConfig{Type: MCPHttp, URL: "http://example.invalid/mcp"}
+	if err := manager.InitializeClient(context.Background(), "remote", config); err != nil {
+		t.Fatal(err)
+	}
+	tool := NewMCPToolWithManager("remote", mcp.Tool{Name: "ping"}, config, nil, manager)
+
+	result, err := tool.Execute(map[string]interface{}{})
+	if err != nil || result.Error != nil || !strings.Contains(result.LLMContent, "pong") {
+		t.Fatalf("expected the call to succeed after reconnecting, got %+v, %v", result, err)
+	}
+	if connects != 2 {
+		t.Errorf("expected one reconnect, got %d connections", connects)
+	}
+
+	if isUnauthorized(errors.New("request failed with status 401")) {
+		t.Error("expected only a status error to count as unauthorized")
+	}
+}
@@ -0,0 +1,61 @@
+// Package redact masks secrets before they are printed or sent to a model
+package redact
+
+import "strings"
+
+// Value masks a secret, keeping the last four characters of long values so
+// they can still be told apart. Empty values and environment references such
+// as $OPENAI_API_KEY are returned unchanged.
+func Value(s string) string {
+	if s == "" || strings.HasPrefix(s, "$") {
+		return s
+	}
+	if len(s) <= 8 {
+		return "****"
+	}
+	return "****" + s[len(s)-4:]
+}
+
+// Dotenv masks the values in dotenv-style content (KEY=value lines, with an
+// optional "export" prefix), keeping keys, comments and line numbers intact
+// so the shape of the configuration stays visible
+func Dotenv(content string) string {
+	lines := strings.Split(content, "\n")
+	var openQuote byte // Set while inside a quoted value spanning lines
+	for i, line := range lines {
+		if openQuote != 0 {
+			if strings.IndexByte(line, openQuote) >= 0 {
+				openQuote = 0
+			}
+			lines[i] = "****"
+			continue
+		}
+		lines[i], openQuote = dotenvLine(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// dotenvLine masks one line and returns the quote character when the value
+// opens a quoted string that continues on the next line
+func dotenvLine(line string) (string, byte) {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return line, 0
+	}
+	eq := strings.IndexByte(line, '=')
+	if eq < 0 {
+		return line, 0
+	}
+
+	key, value := line[:eq+1], strings.TrimSpace(line[eq+1:])
+	if value == "" {
+		return line, 0
+	}
+	if q := value[0]; q == '"' || q == '\'' || q == '`' {
+		if end := strings.IndexByte(value[1:], q); end >= 0 {
+			return key + string(q) + Value(value[1:end+1]) + string(q), 0
+		}
+		return key + string(q) + "****", q
+	}
+	return key + Value(value), 0
+}
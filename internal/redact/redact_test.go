@@ -0,0 +1,25 @@
+package redact
+
+import "testing"
+
+func TestValue(t *testing.T) {
+	cases := map[string]string{
+		"":                    "",
+		"$OPENAI_API_KEY":     "$OPENAI_API_KEY",
+		"short":               "****",
+		"sk-1234567890abcdef": "****cdef",
+	}
+	for in, want := range cases {
+		if got := Value(in); got != want {
+			t.Errorf("Value(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDotenvKeepsKeysAndLayout(t *testing.T) {
+	content := "# Database\nDB_HOST=localhost\nexport DB_PASSWORD=\"hunter2-very-secret\"\nEMPTY=\n\nPRIVATE_KEY='-----BEGIN KEY-----\nMIIEvQIBADANBg\n-----END KEY-----'\nPORT=5432\n"
+	want := "# Database\nDB_HOST=****host\nexport DB_PASSWORD=\"****cret\"\nEMPTY=\n\nPRIVATE_KEY='****\n****\n****\nPORT=****\n"
+	if got := Dotenv(content); got != want {
+		t.Errorf("unexpected masking:\n got %q\nwant %q", got, want)
+	}
+}
@@ -0,0 +1,181 @@
+package telemetry
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PrometheusSink keeps cumulative counters and writes them in the Prometheus
+// text exposition format, suitable for node_exporter's textfile collector.
+// Each run is one process, so every Record starts from the counts already in
+// the textfile; runs finishing at the same moment may still lose an update.
+type PrometheusSink struct {
+	path   string
+	prefix string
+
+	mu               sync.Mutex
+	runs             map[string]int // Keyed by status: success/failure
+	promptTokens     int
+	completionTokens int
+	durationSum      float64
+	toolCalls        map[string]int
+}
+
+// NewPrometheusSink creates a sink that rewrites the textfile at path after every run
+func NewPrometheusSink(path, prefix string) *PrometheusSink {
+	return &PrometheusSink{
+		path:      path,
+		prefix:    prefix,
+		runs:      map[string]int{"success": 0, "failure": 0},
+		toolCalls: make(map[string]int),
+	}
+}
+
+// Record adds the run to the counters in the textfile and rewrites it
+func (s *PrometheusSink) Record(metrics RunMetrics) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return err
+	}
+	if metrics.Success {
+		s.runs["success"]++
+	} else {
+		s.runs["failure"]++
+	}
+	s.promptTokens += metrics.PromptTokens
+	s.completionTokens += metrics.CompletionTokens
+	s.durationSum += metrics.Duration.Seconds()
+	for tool, count := range metrics.ToolCalls {
+		s.toolCalls[tool] += count
+	}
+
+	return s.write()
+}
+
+// Close is a no-op; the textfile is written on every Record
+func (s *PrometheusSink) Close() error {
+	return nil
+}
+
+// load replaces the counters with the values in the existing textfile, so
+// they keep counting across processes instead of restarting with each one
+func (s *PrometheusSink) load() error {
+	s.runs = map[string]int{"success": 0, "failure": 0}
+	s.promptTokens, s.completionTokens, s.durationSum = 0, 0, 0
+	s.toolCalls = make(map[string]int)
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read metrics: %w", err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		name, label, value, ok := parseSample(line)
+		if !ok || !strings.HasPrefix(name, s.prefix+"_") {
+			continue
+		}
+		switch strings.TrimPrefix(name, s.prefix+"_") {
+		case "runs_total":
+			if _, known := s.runs[label]; known {
+				s.runs[label] = int(value)
+			}
+		case "tokens_total":
+			switch label {
+			case "prompt":
+				s.promptTokens = int(value)
+			case "completion":
+				s.completionTokens = int(value)
+			}
+		case "run_duration_seconds_sum":
+			s.durationSum = value
+		case "tool_calls_total":
+			if label != "" {
+				s.toolCalls[label] = int(value)
+			}
+		}
+	}
+	return nil
+}
+
+// parseSample splits a sample line such as `name{key="value"} 3` into the
+// metric name, the value of its single label and the sample value
+func parseSample(line string) (name, label string, value float64, ok bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", "", 0, false
+	}
+	space := strings.LastIndexByte(line, ' ')
+	if space < 0 {
+		return "", "", 0, false
+	}
+	value, err := strconv.ParseFloat(line[space+1:], 64)
+	if err != nil {
+		return "", "", 0, false
+	}
+	name = line[:space]
+	if open := strings.IndexByte(name, '{'); open >= 0 && strings.HasSuffix(name, "}") {
+		if eq := strings.IndexByte(name, '='); eq > open {
+			label, _ = strconv.Unquote(name[eq+1 : len(name)-1])
+		}
+		name = name[:open]
+	}
+	return name, label, value, true
+}
+
+// write renders all counters and atomically replaces the textfile
+func (s *PrometheusSink) write() error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# HELP %s_runs_total Agent runs by outcome.\n", s.prefix)
+	fmt.Fprintf(&b, "# TYPE %s_runs_total counter\n", s.prefix)
+	for _, status := range []string{"success", "failure"} {
+		fmt.Fprintf(&b, "%s_runs_total{status=%q} %d\n", s.prefix, status, s.runs[status])
+	}
+
+	fmt.Fprintf(&b, "# HELP %s_tokens_total LLM tokens used by type.\n", s.prefix)
+	fmt.Fprintf(&b, "# TYPE %s_tokens_total counter\n", s.prefix)
+	fmt.Fprintf(&b, "%s_tokens_total{type=\"prompt\"} %d\n", s.prefix, s.promptTokens)
+	fmt.Fprintf(&b, "%s_tokens_total{type=\"completion\"} %d\n", s.prefix, s.completionTokens)
+
+	fmt.Fprintf(&b, "# HELP %s_run_duration_seconds Time spent in agent runs.\n", s.prefix)
+	fmt.Fprintf(&b, "# TYPE %s_run_duration_seconds summary\n", s.prefix)
+	fmt.Fprintf(&b, "%s_run_duration_seconds_sum %g\n", s.prefix, s.durationSum)
+	fmt.Fprintf(&b, "%s_run_duration_seconds_count %d\n", s.prefix, s.runs["success"]+s.runs["failure"])
+
+	fmt.Fprintf(&b, "# HELP %s_tool_calls_total Tool calls by tool name.\n", s.prefix)
+	fmt.Fprintf(&b, "# TYPE %s_tool_calls_total counter\n", s.prefix)
+	tools := make([]string, 0, len(s.toolCalls))
+	for tool := range s.toolCalls {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	for _, tool := range tools {
+		fmt.Fprintf(&b, "%s_tool_calls_total{tool=%q} %d\n", s.prefix, tool, s.toolCalls[tool])
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create metrics directory: %w", err)
+	}
+
+	// Write to a temp file first so collectors never read a partial file
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	return nil
+}
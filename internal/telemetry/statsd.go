@@ -0,0 +1,57 @@
+package telemetry
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// StatsDSink sends run metrics to a StatsD server over UDP
+type StatsDSink struct {
+	conn   net.Conn
+	prefix string
+}
+
+// NewStatsDSink creates a sink sending to the StatsD server at address
+func NewStatsDSink(address, prefix string) (*StatsDSink, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to statsd: %w", err)
+	}
+	return &StatsDSink{conn: conn, prefix: prefix}, nil
+}
+
+// Record sends one packet containing all metrics for the run
+func (s *StatsDSink) Record(metrics RunMetrics) error {
+	status := "failure"
+	if metrics.Success {
+		status = "success"
+	}
+
+	lines := []string{
+		fmt.Sprintf("%s.runs.%s:1|c", s.prefix, status),
+		fmt.Sprintf("%s.tokens.prompt:%d|c", s.prefix, metrics.PromptTokens),
+		fmt.Sprintf("%s.tokens.completion:%d|c", s.prefix, metrics.CompletionTokens),
+		fmt.Sprintf("%s.run.duration:%d|ms", s.prefix, metrics.Duration.Milliseconds()),
+	}
+
+	tools := make([]string, 0, len(metrics.ToolCalls))
+	for tool := range metrics.ToolCalls {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	for _, tool := range tools {
+		lines = append(lines, fmt.Sprintf("%s.tool_calls.%s:%d|c", s.prefix, tool, metrics.ToolCalls[tool]))
+	}
+
+	if _, err := s.conn.Write([]byte(strings.Join(lines, "\n"))); err != nil {
+		return fmt.Errorf("failed to send metrics: %w", err)
+	}
+	return nil
+}
+
+// Close closes the UDP connection
+func (s *StatsDSink) Close() error {
+	return s.conn.Close()
+}
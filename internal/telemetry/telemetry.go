@@ -0,0 +1,60 @@
+package telemetry
+
+import (
+	"fmt"
+	"time"
+)
+
+// RunMetrics describes a single agent run
+type RunMetrics struct {
+	Success          bool
+	Duration         time.Duration
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+	ToolCalls        map[string]int // Number of calls per tool name
+}
+
+// Sink receives metrics for completed runs
+type Sink interface {
+	Record(metrics RunMetrics) error
+	Close() error
+}
+
+// Config configures the telemetry sink. Nothing is recorded unless Enabled
+// is set, and data only goes to the file or address configured here.
+type Config struct {
+	Enabled bool   `mapstructure:"enabled" yaml:"enabled"`
+	Format  string `mapstructure:"format" yaml:"format"`   // "prometheus" or "statsd"
+	Path    string `mapstructure:"path" yaml:"path"`       // Textfile path for prometheus
+	Address string `mapstructure:"address" yaml:"address"` // host:port for statsd
+	Prefix  string `mapstructure:"prefix" yaml:"prefix"`   // Metric name prefix (default "agenticode")
+}
+
+// NewSink creates the sink described by config.
+// It returns nil without error when telemetry is disabled.
+func NewSink(config Config) (Sink, error) {
+	if !config.Enabled {
+		return nil, nil
+	}
+
+	prefix := config.Prefix
+	if prefix == "" {
+		prefix = "agenticode"
+	}
+
+	switch config.Format {
+	case "", "prometheus":
+		if config.Path == "" {
+			return nil, fmt.Errorf("telemetry.path is required for the prometheus format")
+		}
+		return NewPrometheusSink(config.Path, prefix), nil
+	case "statsd":
+		if config.Address == "" {
+			return nil, fmt.Errorf("telemetry.address is required for the statsd format")
+		}
+		return NewStatsDSink(config.Address, prefix)
+	default:
+		return nil, fmt.Errorf("unknown telemetry format: %s", config.Format)
+	}
+}
@@ -0,0 +1,116 @@
+package telemetry
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewSinkDisabled(t *testing.T) {
+	sink, err := NewSink(Config{Enabled: false, Format: "statsd"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sink != nil {
+		t.Fatal("expected no sink when telemetry is disabled")
+	}
+}
+
+func TestPrometheusSinkAccumulatesRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agenticode.prom")
+	sink, err := NewSink(Config{Enabled: true, Format: "prometheus", Path: path})
+	if err != nil {
+		t.Fatalf("failed to create sink: %v", err)
+	}
+
+	runs := []RunMetrics{
+		{Success: true, Duration: 2 * time.Second, PromptTokens: 100, CompletionTokens: 20, ToolCalls: map[string]int{"read": 2}},
+		{Success: false, Duration: time.Second, PromptTokens: 50, CompletionTokens: 5, ToolCalls: map[string]int{"read": 1, "run_shell": 1}},
+	}
+	for _, run := range runs {
+		if err := sink.Record(run); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("metrics file not written: %v", err)
+	}
+	output := string(data)
+
+	for _, want := range []string{
+		`agenticode_runs_total{status="success"} 1`,
+		`agenticode_runs_total{status="failure"} 1`,
+		`agenticode_tokens_total{type="prompt"} 150`,
+		`agenticode_tokens_total{type="completion"} 25`,
+		`agenticode_run_duration_seconds_sum 3`,
+		`agenticode_run_duration_seconds_count 2`,
+		`agenticode_tool_calls_total{tool="read"} 3`,
+		`agenticode_tool_calls_total{tool="run_shell"} 1`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("metrics output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestPrometheusSinkContinuesCountsFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agenticode.prom")
+	// Each process creates its own sink
+	for i := 0; i < 3; i++ {
+		sink := NewPrometheusSink(path, "agenticode")
+		if err := sink.Record(RunMetrics{Success: i != 1, Duration: time.Second, PromptTokens: 10, ToolCalls: map[string]int{`edit "x"`: 1}}); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	data, _ := os.ReadFile(path)
+	for _, want := range []string{
+		`agenticode_runs_total{status="success"} 2`,
+		`agenticode_runs_total{status="failure"} 1`,
+		`agenticode_tokens_total{type="prompt"} 30`,
+		`agenticode_run_duration_seconds_sum 3`,
+		`agenticode_run_duration_seconds_count 3`,
+		`agenticode_tool_calls_total{tool="edit \"x\""} 3`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("metrics output missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestStatsDSinkSendsPacket(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen on UDP: %v", err)
+	}
+	defer listener.Close()
+
+	sink, err := NewSink(Config{Enabled: true, Format: "statsd", Address: listener.LocalAddr().String(), Prefix: "test"})
+	if err != nil {
+		t.Fatalf("failed to create sink: %v", err)
+	}
+	defer sink.Close()
+
+	if err := sink.Record(RunMetrics{Success: true, Duration: 1500 * time.Millisecond, PromptTokens: 10, CompletionTokens: 3, ToolCalls: map[string]int{"grep": 2}}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	buf := make([]byte, 1024)
+	listener.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := listener.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("no packet received: %v", err)
+	}
+	packet := string(buf[:n])
+
+	for _, want := range []string{"test.runs.success:1|c", "test.tokens.prompt:10|c", "test.run.duration:1500|ms", "test.tool_calls.grep:2|c"} {
+		if !strings.Contains(packet, want) {
+			t.Errorf("packet missing %q:\n%s", want, packet)
+		}
+	}
+}
@@ -2,16 +2,29 @@ package tools
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"log"
-	"math/rand"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 )
 
-// generateSubAgentID creates a unique identifier for sub-agents
+// subAgentCounter numbers sub-agents in launch order within this process
+var subAgentCounter atomic.Uint64
+
+// generateSubAgentID creates a unique identifier for sub-agents. The counter
+// keeps IDs unique within a process and ordered in the logs; the random
+// suffix tells apart IDs from separate processes writing to the same log.
 func generateSubAgentID() string {
-	rand.Seed(time.Now().UnixNano())
-	return fmt.Sprintf("SA-%04d", rand.Intn(10000))
+	seq := subAgentCounter.Add(1)
+
+	suffix := make([]byte, 2)
+	if _, err := rand.Read(suffix); err != nil {
+		return fmt.Sprintf("SA-%04d", seq)
+	}
+	return fmt.Sprintf("SA-%04d-%s", seq, hex.EncodeToString(suffix))
 }
 
 // getSystemPromptForAgentType returns appropriate system prompt based on agent type
@@ -35,6 +48,11 @@ type AgentTool struct {
 	// We'll use interfaces to avoid circular dependencies
 	llmClient    interface{}
 	agentFactory func(llmClient interface{}, agentType string) (AgentInterface, error)
+
+	// depth is the nesting level of the agent that owns this tool (0 for the
+	// top-level agent); launches are refused once depth reaches maxDepth
+	depth    int
+	maxDepth int
 }
 
 // AgentInterface defines the minimal interface needed for sub-agents
@@ -46,10 +64,19 @@ type AgentInterface interface {
 type AgentExecutionResult struct {
 	Success        bool
 	Message        string
+	Summary        string // Concise findings for the parent agent (may be empty)
 	GeneratedFiles []GeneratedFile
 	Steps          []ExecutionStep
 }
 
+// DefaultMaxSubAgentDepth bounds how deeply sub-agents may nest: the
+// top-level agent's sub-agents are at depth 1, theirs at depth 2
+const DefaultMaxSubAgentDepth = 2
+
+// defaultMaxResultTokens bounds how much of a sub-agent's answer flows back
+// into the parent conversation
+const defaultMaxResultTokens = 1000
+
 type GeneratedFile struct {
 	Path    string
 	Content string
@@ -70,9 +97,17 @@ func NewAgentTool(llmClient interface{}, agentFactory func(interface{}, string)
 	return &AgentTool{
 		llmClient:    llmClient,
 		agentFactory: agentFactory,
+		maxDepth:     DefaultMaxSubAgentDepth,
 	}
 }
 
+// SetDepthLimit records the owning agent's depth and the deepest sub-agent
+// this tool may create
+func (t *AgentTool) SetDepthLimit(depth, maxDepth int) {
+	t.depth = depth
+	t.maxDepth = maxDepth
+}
+
 func (t *AgentTool) Name() string {
 	return "agent_tool"
 }
@@ -102,6 +137,10 @@ func (t *AgentTool) GetParameters() map[string]interface{} {
 				"description": "Type of agent: general-purpose, searcher, analyzer, executor (default: general-purpose)",
 				"enum":        []string{"general-purpose", "searcher", "analyzer", "executor"},
 			},
+			"max_result_tokens": map[string]interface{}{
+				"type":        "integer",
+				"description": fmt.Sprintf("Upper bound on the size of the summary returned to you (default %d)", defaultMaxResultTokens),
+			},
 		},
 		"required": []string{"description", "prompt"},
 	}
@@ -110,12 +149,12 @@ func (t *AgentTool) GetParameters() map[string]interface{} {
 func (t *AgentTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 	description, ok := args["description"].(string)
 	if !ok {
-		return nil, fmt.Errorf("description is required")
+		return nil, requiredArg("description")
 	}
 
 	prompt, ok := args["prompt"].(string)
 	if !ok {
-		return nil, fmt.Errorf("prompt is required")
+		return nil, requiredArg("prompt")
 	}
 
 	// Get agent type, default to general-purpose
@@ -124,6 +163,23 @@ func (t *AgentTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 		agentType = "general-purpose"
 	}
 
+	// Sub-agents may launch sub-agents of their own; stop before the nesting
+	// (and the cost) runs away
+	if t.depth >= t.maxDepth {
+		err := fmt.Errorf("cannot launch a sub-agent for task '%s': the maximum sub-agent depth of %d has been reached, complete the task yourself with the other tools", description, t.maxDepth)
+		log.Printf("Refusing to launch %s sub-agent: %v", agentType, err)
+		return &ToolResult{
+			LLMContent:    err.Error(),
+			ReturnDisplay: fmt.Sprintf("⛔ Sub-agent not launched: depth limit %d reached", t.maxDepth),
+			Error:         err,
+		}, nil
+	}
+
+	maxResultTokens := defaultMaxResultTokens
+	if n, ok := args["max_result_tokens"].(float64); ok && n > 0 {
+		maxResultTokens = int(n)
+	}
+
 	// Generate unique sub-agent ID
 	subAgentID := generateSubAgentID()
 
@@ -175,8 +231,20 @@ func (t *AgentTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 
 	log.Printf("[%s] ✅ Sub-agent execution COMPLETED in %v", subAgentID, duration)
 
-	// Build response based on execution result
-	llmContent := fmt.Sprintf("Sub-agent %s completed task '%s'.\n", subAgentID, description)
+	// Only the summary goes back to the parent; the step trace stays in the display
+	summary := result.Summary
+	if summary == "" {
+		summary = result.Message
+	}
+	status := "completed"
+	if !result.Success {
+		status = "did not complete"
+	}
+	llmContent := fmt.Sprintf("Sub-agent %s (%s) %s task '%s' [%d steps, %d files generated].",
+		subAgentID, agentType, status, description, len(result.Steps), len(result.GeneratedFiles))
+	if summary != "" {
+		llmContent += "\nFindings:\n" + truncateToTokens(summary, maxResultTokens)
+	}
 	displayContent := fmt.Sprintf("✅ Sub-agent %s completed: %s\n", subAgentID, description)
 
 	// Log result summary
@@ -184,10 +252,9 @@ func (t *AgentTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 	log.Printf("[%s]   - Success: %v", subAgentID, result.Success)
 	log.Printf("[%s]   - Total steps: %d", subAgentID, len(result.Steps))
 
-	if result.Message != "" {
-		log.Printf("[%s]   - Result message: %s", subAgentID, result.Message)
-		llmContent += fmt.Sprintf("Result: %s", result.Message)
-		displayContent += fmt.Sprintf("\n📋 Result:\n%s", result.Message)
+	if summary != "" {
+		log.Printf("[%s]   - Result summary: %s", subAgentID, summary)
+		displayContent += fmt.Sprintf("\n📋 Result:\n%s", summary)
 	}
 
 	// Log step details
@@ -196,7 +263,6 @@ func (t *AgentTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 		for i, step := range result.Steps {
 			log.Printf("[%s]   Step %d: %s (tool: %s)", subAgentID, i+1, step.Action, step.ToolName)
 		}
-		llmContent += fmt.Sprintf("\nExecuted %d steps", len(result.Steps))
 		displayContent += fmt.Sprintf("\n\n🔧 Execution summary: %d steps", len(result.Steps))
 	}
 
@@ -206,7 +272,6 @@ func (t *AgentTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 		for _, file := range result.GeneratedFiles {
 			log.Printf("[%s]     • %s", subAgentID, file.Path)
 		}
-		llmContent += fmt.Sprintf("\nGenerated %d files", len(result.GeneratedFiles))
 		displayContent += fmt.Sprintf("\n\n📄 Generated %d file(s):", len(result.GeneratedFiles))
 		for _, file := range result.GeneratedFiles {
 			displayContent += fmt.Sprintf("\n  • %s", file.Path)
@@ -221,3 +286,18 @@ func (t *AgentTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 		Error:         nil,
 	}, nil
 }
+
+// truncateToTokens cuts text to roughly maxTokens tokens (4 characters per token)
+func truncateToTokens(text string, maxTokens int) string {
+	maxChars := maxTokens * 4
+	if len(text) <= maxChars {
+		return text
+	}
+
+	// Back up to a rune boundary so multi-byte characters are not split
+	cut := maxChars
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + "\n... [truncated to ~" + fmt.Sprint(maxTokens) + " tokens]"
+}
@@ -0,0 +1,40 @@
+package tools
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestGenerateSubAgentIDIsUnique(t *testing.T) {
+	const workers = 16
+	const perWorker = 500
+
+	var mu sync.Mutex
+	seen := make(map[string]bool, workers*perWorker)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids := make([]string, perWorker)
+			for i := range ids {
+				ids[i] = generateSubAgentID()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, id := range ids {
+				if seen[id] {
+					t.Errorf("duplicate sub-agent ID %s", id)
+				}
+				seen[id] = true
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != workers*perWorker {
+		t.Errorf("expected %d unique IDs, got %d", workers*perWorker, len(seen))
+	}
+}
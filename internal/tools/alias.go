@@ -0,0 +1,171 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ToolAliasConfig is one entry of the tool_aliases config section
+type ToolAliasConfig struct {
+	Tool        string                 `mapstructure:"tool"`        // Existing tool the alias calls
+	Description string                 `mapstructure:"description"` // Shown to the model instead of the tool's own
+	Args        map[string]interface{} `mapstructure:"args"`        // Preset arguments, which the model cannot override
+}
+
+// AliasTool exposes an existing tool under another name with some of its
+// arguments preset, e.g. "test" for run_shell with the project's test command
+type AliasTool struct {
+	name        string
+	description string
+	target      Tool
+	preset      map[string]interface{}
+}
+
+// NewAliasTool creates an alias for target
+func NewAliasTool(name string, config ToolAliasConfig, target Tool) *AliasTool {
+	description := config.Description
+	if description == "" {
+		description = fmt.Sprintf("Alias for %s: %s", target.Name(), target.Description())
+	}
+	return &AliasTool{name: name, description: description, target: target, preset: config.Args}
+}
+
+// BuildAliasTools resolves the configured aliases against the available
+// tools. Alias names must not shadow a tool and must name an existing one.
+func BuildAliasTools(aliases map[string]ToolAliasConfig, available []Tool) ([]Tool, error) {
+	byName := make(map[string]Tool, len(available))
+	for _, tool := range available {
+		byName[tool.Name()] = tool
+	}
+
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var result []Tool
+	for _, name := range names {
+		config := aliases[name]
+		if _, exists := byName[name]; exists {
+			return nil, fmt.Errorf("tool alias %q has the same name as an existing tool", name)
+		}
+		target, ok := byName[config.Tool]
+		if !ok {
+			return nil, fmt.Errorf("tool alias %q refers to unknown tool %q", name, config.Tool)
+		}
+		result = append(result, NewAliasTool(name, config, target))
+	}
+	return result, nil
+}
+
+func (t *AliasTool) Name() string {
+	return t.name
+}
+
+func (t *AliasTool) Description() string {
+	return t.description
+}
+
+// Target returns the tool the alias calls
+func (t *AliasTool) Target() Tool {
+	return t.target
+}
+
+// Resolve returns the target and the arguments the alias calls it with
+func (t *AliasTool) Resolve(args map[string]interface{}) (Tool, map[string]interface{}) {
+	return t.target, t.mergeArgs(args)
+}
+
+func (t *AliasTool) ReadOnly() bool {
+	return t.target.ReadOnly()
+}
+
+// GetParameters returns the target's parameters without the preset ones
+func (t *AliasTool) GetParameters() map[string]interface{} {
+	params := make(map[string]interface{})
+	for key, value := range t.target.GetParameters() {
+		params[key] = value
+	}
+
+	if properties, ok := params["properties"].(map[string]interface{}); ok {
+		remaining := make(map[string]interface{}, len(properties))
+		for key, value := range properties {
+			if _, preset := t.preset[key]; !preset {
+				remaining[key] = value
+			}
+		}
+		params["properties"] = remaining
+	}
+	if required, ok := params["required"].([]string); ok {
+		var remaining []string
+		for _, key := range required {
+			if _, preset := t.preset[key]; !preset {
+				remaining = append(remaining, key)
+			}
+		}
+		params["required"] = remaining
+	}
+	return params
+}
+
+// Execute calls the target with the model's arguments and the presets
+func (t *AliasTool) Execute(args map[string]interface{}) (*ToolResult, error) {
+	return t.target.Execute(t.mergeArgs(args))
+}
+
+// ExecuteWithProgress passes progress through when the target reports it
+func (t *AliasTool) ExecuteWithProgress(args map[string]interface{}, report ProgressReporter) (*ToolResult, error) {
+	if progressTool, ok := t.target.(ProgressTool); ok {
+		return progressTool.ExecuteWithProgress(t.mergeArgs(args), report)
+	}
+	return t.Execute(args)
+}
+
+// DangerousCall checks the target's call, presets included
+func (t *AliasTool) DangerousCall(args map[string]interface{}) string {
+	if checker, ok := t.target.(DangerChecker); ok {
+		return checker.DangerousCall(t.mergeArgs(args))
+	}
+	return ""
+}
+
+// ConfirmationDetails shows which tool the alias runs and with what
+func (t *AliasTool) ConfirmationDetails(args map[string]interface{}) *ConfirmationDetails {
+	merged := t.mergeArgs(args)
+	details := &ConfirmationDetails{Title: fmt.Sprintf("%s (alias for %s)", t.name, t.target.Name())}
+	if detailer, ok := t.target.(ConfirmationDetailer); ok {
+		if target := detailer.ConfirmationDetails(merged); target != nil {
+			details.Preview = target.Title
+			if target.Preview != "" {
+				details.Preview += "\n" + target.Preview
+			}
+			return details
+		}
+	}
+
+	keys := make([]string, 0, len(merged))
+	for key := range merged {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("%s: %v", key, merged[key]))
+	}
+	details.Preview = strings.Join(lines, "\n")
+	return details
+}
+
+// mergeArgs overlays the presets on the model's arguments
+func (t *AliasTool) mergeArgs(args map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(args)+len(t.preset))
+	for key, value := range args {
+		merged[key] = value
+	}
+	for key, value := range t.preset {
+		merged[key] = value
+	}
+	return merged
+}
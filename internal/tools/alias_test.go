@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+// recordingTool remembers the arguments of its last call
+type recordingTool struct {
+	args map[string]interface{}
+}
+
+func (t *recordingTool) Name() string        { return "run_shell" }
+func (t *recordingTool) Description() string { return "Run a command" }
+func (t *recordingTool) ReadOnly() bool      { return false }
+func (t *recordingTool) GetParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"command": map[string]interface{}{"type": "string"},
+			"timeout": map[string]interface{}{"type": "integer"},
+		},
+		"required": []string{"command"},
+	}
+}
+func (t *recordingTool) Execute(args map[string]interface{}) (*ToolResult, error) {
+	t.args = args
+	return &ToolResult{LLMContent: "ok"}, nil
+}
+
+func TestAliasToolMergesPresetArgs(t *testing.T) {
+	target := &recordingTool{}
+	aliases, err := BuildAliasTools(map[string]ToolAliasConfig{
+		"test": {Tool: "run_shell", Description: "Run the tests", Args: map[string]interface{}{"command": "go test ./..."}},
+	}, []Tool{target})
+	if err != nil {
+		t.Fatal(err)
+	}
+	alias := aliases[0]
+	if alias.Name() != "test" || alias.Description() != "Run the tests" {
+		t.Errorf("unexpected alias %s: %s", alias.Name(), alias.Description())
+	}
+
+	params := alias.GetParameters()
+	properties := params["properties"].(map[string]interface{})
+	if _, ok := properties["command"]; ok || properties["timeout"] == nil {
+		t.Errorf("expected only the non-preset parameters, got %v", properties)
+	}
+	if required := params["required"].([]string); len(required) != 0 {
+		t.Errorf("expected no required parameters, got %v", required)
+	}
+
+	if _, err := alias.Execute(map[string]interface{}{"command": "rm -rf /", "timeout": 60}); err != nil {
+		t.Fatal(err)
+	}
+	if target.args["command"] != "go test ./..." || target.args["timeout"] != 60 {
+		t.Errorf("expected the preset command with the provided timeout, got %v", target.args)
+	}
+
+	details := alias.(ConfirmationDetailer).ConfirmationDetails(map[string]interface{}{"timeout": 60})
+	if !strings.Contains(details.Title, "alias for run_shell") || !strings.Contains(details.Preview, "command: go test ./...") {
+		t.Errorf("unexpected confirmation details %+v", details)
+	}
+}
+
+func TestBuildAliasToolsValidatesNames(t *testing.T) {
+	available := []Tool{&recordingTool{}}
+	if _, err := BuildAliasTools(map[string]ToolAliasConfig{"test": {Tool: "missing"}}, available); err == nil {
+		t.Error("expected an alias of an unknown tool to be rejected")
+	}
+	if _, err := BuildAliasTools(map[string]ToolAliasConfig{"run_shell": {Tool: "run_shell"}}, available); err == nil {
+		t.Error("expected an alias shadowing a tool to be rejected")
+	}
+}
@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"fmt"
+)
+
+// AskUserTool lets the agent ask the user a clarifying question. In
+// interactive sessions the agent's turn handler answers it from the terminal;
+// Execute only runs when nobody can respond, and then falls back to the
+// configured default answer.
+type AskUserTool struct {
+	defaultAnswer string
+}
+
+func NewAskUserTool() *AskUserTool {
+	return &AskUserTool{}
+}
+
+// SetDefaultAnswer sets the answer returned when no user can respond
+func (t *AskUserTool) SetDefaultAnswer(answer string) {
+	t.defaultAnswer = answer
+}
+
+func (t *AskUserTool) Name() string {
+	return "ask_user"
+}
+
+func (t *AskUserTool) Description() string {
+	return "Ask the user a clarifying question and wait for the answer. Use this when the request is ambiguous and guessing could waste work, not for routine confirmations"
+}
+
+func (t *AskUserTool) ReadOnly() bool {
+	return true
+}
+
+func (t *AskUserTool) GetParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"question": map[string]interface{}{
+				"type":        "string",
+				"description": "The question to ask the user",
+			},
+			"options": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "string",
+				},
+				"description": "Optional suggested answers the user can pick by number",
+			},
+		},
+		"required": []string{"question"},
+	}
+}
+
+func (t *AskUserTool) Execute(args map[string]interface{}) (*ToolResult, error) {
+	question, ok := args["question"].(string)
+	if !ok || question == "" {
+		return nil, requiredArg("question")
+	}
+
+	if t.defaultAnswer == "" {
+		return nil, fmt.Errorf("no user is available to answer %q; make a reasonable assumption, state it in your response, and continue", question)
+	}
+
+	return &ToolResult{
+		LLMContent:    fmt.Sprintf("No user is available to answer. Default answer: %s", t.defaultAnswer),
+		ReturnDisplay: fmt.Sprintf("❓ %s\n💬 %s (default answer)", question, t.defaultAnswer),
+		Error:         nil,
+	}, nil
+}
@@ -0,0 +1,301 @@
+package tools
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ASTEditTool performs structured edits on Go source files. Each operation is
+// located through the parsed syntax tree, and the result is gofmt'ed and must
+// parse before it is written, so a bad edit never leaves broken code behind.
+type ASTEditTool struct{}
+
+func NewASTEditTool() *ASTEditTool {
+	return &ASTEditTool{}
+}
+
+func (t *ASTEditTool) Name() string {
+	return "ast_edit"
+}
+
+func (t *ASTEditTool) Description() string {
+	return "Structured edits for Go files (add_import, add_method, replace_function_body). Prefer this over edit for .go files; the result is gofmt'ed and rejected if it does not parse"
+}
+
+func (t *ASTEditTool) ReadOnly() bool {
+	return false
+}
+
+func (t *ASTEditTool) GetParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"file_path": map[string]interface{}{
+				"type":        "string",
+				"description": "The Go file to edit",
+			},
+			"operation": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"add_import", "add_method", "replace_function_body"},
+				"description": "The structured edit to perform",
+			},
+			"import_path": map[string]interface{}{
+				"type":        "string",
+				"description": "add_import: the package path to import, e.g. \"net/http\"",
+			},
+			"import_name": map[string]interface{}{
+				"type":        "string",
+				"description": "add_import: optional package alias",
+			},
+			"type_name": map[string]interface{}{
+				"type":        "string",
+				"description": "add_method: the type to add the method to; replace_function_body: the receiver type of a method",
+			},
+			"function_name": map[string]interface{}{
+				"type":        "string",
+				"description": "replace_function_body: the function or method whose body is replaced",
+			},
+			"code": map[string]interface{}{
+				"type":        "string",
+				"description": "add_method: the full method declaration; replace_function_body: the new body statements (braces optional)",
+			},
+		},
+		"required": []string{"file_path", "operation"},
+	}
+}
+
+func (t *ASTEditTool) Execute(args map[string]interface{}) (*ToolResult, error) {
+	filePath, ok := args["file_path"].(string)
+	if !ok || filePath == "" {
+		return nil, requiredArg("file_path")
+	}
+	if !strings.HasSuffix(filePath, ".go") {
+		return nil, validationError("ast_edit only supports .go files; use edit for %s", filePath)
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fileError("read", filePath, err)
+	}
+
+	updated, summary, err := ApplyASTEdit(filePath, content, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(filePath, updated, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return &ToolResult{
+		LLMContent:    fmt.Sprintf("Successfully applied %s to %s: %s", args["operation"], filePath, summary),
+		ReturnDisplay: fmt.Sprintf("✅ **AST edit** `%s`\n\n%s", filePath, summary),
+		Error:         nil,
+	}, nil
+}
+
+// ApplyASTEdit applies a structured edit to Go source and returns the
+// formatted result without touching the file system.
+func ApplyASTEdit(filename string, src []byte, args map[string]interface{}) ([]byte, string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, "", fmt.Errorf("file does not parse before editing: %w", err)
+	}
+
+	operation, _ := args["operation"].(string)
+	var updated []byte
+	var summary string
+	switch operation {
+	case "add_import":
+		updated, summary, err = addImport(fset, file, src, args)
+	case "add_method":
+		updated, summary, err = addMethod(fset, file, src, args)
+	case "replace_function_body":
+		updated, summary, err = replaceFunctionBody(fset, file, src, args)
+	case "":
+		return nil, "", requiredArg("operation")
+	default:
+		return nil, "", validationError("unknown operation %q (expected add_import, add_method or replace_function_body)", operation)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	formatted, err := format.Source(updated)
+	if err != nil {
+		return nil, "", validationError("edit rejected, the result does not parse: %w", err)
+	}
+	return formatted, summary, nil
+}
+
+func addImport(fset *token.FileSet, file *ast.File, src []byte, args map[string]interface{}) ([]byte, string, error) {
+	importPath, _ := args["import_path"].(string)
+	importPath = strings.Trim(importPath, "\"` ")
+	if importPath == "" {
+		return nil, "", validationError("import_path is required for add_import")
+	}
+	name, _ := args["import_name"].(string)
+
+	for _, spec := range file.Imports {
+		if existing, _ := strconv.Unquote(spec.Path.Value); existing == importPath {
+			return nil, "", validationError("%s is already imported", importPath)
+		}
+	}
+
+	spec := strconv.Quote(importPath)
+	if name != "" {
+		spec = name + " " + spec
+	}
+
+	// Prefer extending an existing parenthesized import block
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		if gen.Lparen.IsValid() {
+			offset := fset.Position(gen.Rparen).Offset
+			return splice(src, offset, offset, "\t"+spec+"\n"), "added import " + spec, nil
+		}
+		// Turn a single import into a block holding both
+		start := fset.Position(gen.Specs[0].Pos()).Offset
+		end := fset.Position(gen.End()).Offset
+		existing := string(src[start:end])
+		return splice(src, fset.Position(gen.Pos()).Offset, end, "import (\n\t"+existing+"\n\t"+spec+"\n)"), "added import " + spec, nil
+	}
+
+	offset := fset.Position(file.Name.End()).Offset
+	return splice(src, offset, offset, "\n\nimport "+spec+"\n"), "added import " + spec, nil
+}
+
+func addMethod(fset *token.FileSet, file *ast.File, src []byte, args map[string]interface{}) ([]byte, string, error) {
+	typeName, _ := args["type_name"].(string)
+	code, _ := args["code"].(string)
+	if typeName == "" || strings.TrimSpace(code) == "" {
+		return nil, "", validationError("type_name and code are required for add_method")
+	}
+
+	// Parse the method on its own to validate it before touching the file
+	method, err := parser.ParseFile(token.NewFileSet(), "", "package p\n\n"+code, parser.ParseComments)
+	if err != nil {
+		return nil, "", validationError("code is not a valid method declaration: %w", err)
+	}
+	if len(method.Decls) != 1 {
+		return nil, "", validationError("code must contain exactly one method declaration")
+	}
+	fn, ok := method.Decls[0].(*ast.FuncDecl)
+	if !ok || fn.Recv == nil {
+		return nil, "", validationError("code must be a method declaration with a receiver")
+	}
+	if recv := receiverTypeName(fn); recv != typeName {
+		return nil, "", validationError("method receiver is %s, expected %s", recv, typeName)
+	}
+
+	// Insert after the type declaration or its last method
+	insertAt := token.NoPos
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok && ts.Name.Name == typeName {
+					insertAt = d.End()
+				}
+			}
+		case *ast.FuncDecl:
+			if d.Recv != nil && receiverTypeName(d) == typeName {
+				if d.Name.Name == fn.Name.Name {
+					return nil, "", validationError("%s already has a method named %s", typeName, fn.Name.Name)
+				}
+				insertAt = d.End()
+			}
+		}
+	}
+	if !insertAt.IsValid() {
+		return nil, "", validationError("type %s is not declared in this file", typeName)
+	}
+
+	offset := fset.Position(insertAt).Offset
+	summary := fmt.Sprintf("added method %s.%s", typeName, fn.Name.Name)
+	return splice(src, offset, offset, "\n\n"+strings.TrimSpace(code)+"\n"), summary, nil
+}
+
+func replaceFunctionBody(fset *token.FileSet, file *ast.File, src []byte, args map[string]interface{}) ([]byte, string, error) {
+	name, _ := args["function_name"].(string)
+	typeName, _ := args["type_name"].(string)
+	code, ok := args["code"].(string)
+	if name == "" || !ok {
+		return nil, "", validationError("function_name and code are required for replace_function_body")
+	}
+
+	var matches []*ast.FuncDecl
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != name || fn.Body == nil {
+			continue
+		}
+		if typeName != "" && receiverTypeName(fn) != typeName {
+			continue
+		}
+		matches = append(matches, fn)
+	}
+	switch len(matches) {
+	case 0:
+		if typeName != "" {
+			return nil, "", validationError("method %s.%s not found", typeName, name)
+		}
+		return nil, "", validationError("function %s not found", name)
+	case 1:
+	default:
+		return nil, "", validationError("%d functions named %s; set type_name to the receiver type", len(matches), name)
+	}
+
+	body := strings.TrimSpace(code)
+	if !strings.HasPrefix(body, "{") || !strings.HasSuffix(body, "}") {
+		body = "{\n" + body + "\n}"
+	}
+
+	fn := matches[0]
+	start := fset.Position(fn.Body.Lbrace).Offset
+	end := fset.Position(fn.Body.Rbrace).Offset + 1
+	summary := "replaced body of " + name
+	if typeName != "" {
+		summary = fmt.Sprintf("replaced body of %s.%s", typeName, name)
+	}
+	return splice(src, start, end, body), summary, nil
+}
+
+// receiverTypeName returns the base type name of a method receiver, without
+// pointer or type parameters
+func receiverTypeName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	expr := fn.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch e := expr.(type) {
+	case *ast.IndexExpr:
+		expr = e.X
+	case *ast.IndexListExpr:
+		expr = e.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+func splice(src []byte, start, end int, insert string) []byte {
+	out := make([]byte, 0, len(src)+len(insert))
+	out = append(out, src[:start]...)
+	out = append(out, insert...)
+	return append(out, src[end:]...)
+}
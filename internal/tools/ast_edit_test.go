@@ -0,0 +1,123 @@
+package tools
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const astEditSource = `package greet
+
+import "fmt"
+
+type Greeter struct {
+	Name string
+}
+
+func (g *Greeter) Greet() string {
+	return fmt.Sprintf("hello %s", g.Name)
+}
+`
+
+// typeCheck asserts that src compiles as a standalone package
+func typeCheck(t *testing.T, src string) {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "greet.go", src, 0)
+	if err != nil {
+		t.Fatalf("result does not parse: %v\n%s", err, src)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("greet", fset, []*ast.File{file}, nil); err != nil {
+		t.Fatalf("result does not compile: %v\n%s", err, src)
+	}
+}
+
+func writeGoFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "greet.go")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestASTEditAddImportAndReplaceBody(t *testing.T) {
+	tool := NewASTEditTool()
+	path := writeGoFile(t, astEditSource)
+
+	if _, err := tool.Execute(map[string]interface{}{
+		"file_path":   path,
+		"operation":   "add_import",
+		"import_path": "strings",
+	}); err != nil {
+		t.Fatalf("add_import failed: %v", err)
+	}
+
+	if _, err := tool.Execute(map[string]interface{}{
+		"file_path":     path,
+		"operation":     "replace_function_body",
+		"type_name":     "Greeter",
+		"function_name": "Greet",
+		"code":          `return fmt.Sprintf("hello %s", strings.ToUpper(g.Name))`,
+	}); err != nil {
+		t.Fatalf("replace_function_body failed: %v", err)
+	}
+
+	result, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(result), "import (\n\t\"fmt\"\n\t\"strings\"\n)") {
+		t.Errorf("expected a gofmt'ed import block, got:\n%s", result)
+	}
+	if !strings.Contains(string(result), "strings.ToUpper(g.Name)") {
+		t.Errorf("expected the new body, got:\n%s", result)
+	}
+	typeCheck(t, string(result))
+}
+
+func TestASTEditAddMethod(t *testing.T) {
+	path := writeGoFile(t, astEditSource)
+
+	_, err := NewASTEditTool().Execute(map[string]interface{}{
+		"file_path": path,
+		"operation": "add_method",
+		"type_name": "Greeter",
+		"code":      "func (g *Greeter) Reset() { g.Name = \"\" }",
+	})
+	if err != nil {
+		t.Fatalf("add_method failed: %v", err)
+	}
+
+	result, _ := os.ReadFile(path)
+	if !strings.Contains(string(result), "func (g *Greeter) Reset() { g.Name = \"\" }") {
+		t.Errorf("expected the method to be added, got:\n%s", result)
+	}
+	typeCheck(t, string(result))
+}
+
+func TestASTEditRejectsInvalidResult(t *testing.T) {
+	path := writeGoFile(t, astEditSource)
+
+	_, err := NewASTEditTool().Execute(map[string]interface{}{
+		"file_path":     path,
+		"operation":     "replace_function_body",
+		"function_name": "Greet",
+		"code":          "return \"unterminated",
+	})
+	if err == nil || !strings.Contains(err.Error(), "does not parse") {
+		t.Fatalf("expected the edit to be rejected, got %v", err)
+	}
+
+	result, _ := os.ReadFile(path)
+	if string(result) != astEditSource {
+		t.Errorf("file should be unchanged after a rejected edit, got:\n%s", result)
+	}
+}
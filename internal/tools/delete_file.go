@@ -0,0 +1,212 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TrashedFile is a file or directory delete_file moved into the trash
+type TrashedFile struct {
+	Path      string // Original path as given to delete_file
+	TrashPath string // Where it is kept until restored
+	DeletedAt time.Time
+}
+
+// Trash keeps the files delete_file removed during a session so they can be
+// restored. Entries are moved under dir, mirroring their path relative to
+// the working directory.
+type Trash struct {
+	mu      sync.Mutex
+	dir     string
+	entries []TrashedFile
+}
+
+// GlobalTrash is the trash shared by every delete_file tool in the session
+var GlobalTrash = NewTrash(filepath.Join(".agenticode", "trash", time.Now().Format("20060102-150405")))
+
+// NewTrash creates a trash that moves deleted files under dir
+func NewTrash(dir string) *Trash {
+	return &Trash{dir: dir}
+}
+
+// SetDir changes where newly deleted files are kept
+func (t *Trash) SetDir(dir string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.dir = dir
+}
+
+// Move puts path into the trash and returns its entry
+func (t *Trash) Move(path string) (TrashedFile, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return TrashedFile{}, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	trashDir, err := filepath.Abs(t.dir)
+	if err != nil {
+		return TrashedFile{}, err
+	}
+	if abs == trashDir || strings.HasPrefix(trashDir, abs+string(filepath.Separator)) {
+		return TrashedFile{}, pathNotAllowed(path, "cannot delete %s: it contains the trash directory", path)
+	}
+
+	// Keep the project layout; paths outside the working directory go
+	// under "_external"
+	rel := filepath.Join("_external", strings.TrimPrefix(abs, filepath.VolumeName(abs)))
+	if wd, err := os.Getwd(); err == nil {
+		if r, err := filepath.Rel(wd, abs); err == nil && r != ".." && !strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+			rel = r
+		}
+	}
+	target := filepath.Join(trashDir, rel)
+	// A path deleted twice in a session keeps both copies
+	for i := 1; ; i++ {
+		if _, err := os.Lstat(target); os.IsNotExist(err) {
+			break
+		}
+		target = fmt.Sprintf("%s.%d", filepath.Join(trashDir, rel), i)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return TrashedFile{}, fmt.Errorf("failed to create trash directory: %w", err)
+	}
+	if err := os.Rename(abs, target); err != nil {
+		return TrashedFile{}, fmt.Errorf("failed to move %s to the trash: %w", path, err)
+	}
+
+	entry := TrashedFile{Path: path, TrashPath: target, DeletedAt: time.Now()}
+	t.entries = append(t.entries, entry)
+	return entry, nil
+}
+
+// Restore moves the most recently trashed copy of path back to where it was.
+// It refuses to overwrite a file that has since been created there.
+func (t *Trash) Restore(path string) (TrashedFile, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i := len(t.entries) - 1; i >= 0; i-- {
+		entry := t.entries[i]
+		if filepath.Clean(entry.Path) != filepath.Clean(path) {
+			continue
+		}
+		if _, err := os.Lstat(entry.Path); err == nil {
+			return TrashedFile{}, fmt.Errorf("cannot restore %s: the path exists again", entry.Path)
+		}
+		if err := os.MkdirAll(filepath.Dir(entry.Path), 0755); err != nil {
+			return TrashedFile{}, fmt.Errorf("failed to restore %s: %w", entry.Path, err)
+		}
+		if err := os.Rename(entry.TrashPath, entry.Path); err != nil {
+			return TrashedFile{}, fmt.Errorf("failed to restore %s: %w", entry.Path, err)
+		}
+		t.entries = append(t.entries[:i], t.entries[i+1:]...)
+		return entry, nil
+	}
+	return TrashedFile{}, fmt.Errorf("%s is not in the trash", path)
+}
+
+// List returns the trashed entries, oldest first
+func (t *Trash) List() []TrashedFile {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]TrashedFile(nil), t.entries...)
+}
+
+// DeleteFileTool deletes files by moving them into the session trash, so
+// every deletion can be undone
+type DeleteFileTool struct {
+	trash *Trash // nil means GlobalTrash
+}
+
+// NewDeleteFileTool creates a new DeleteFileTool instance
+func NewDeleteFileTool() *DeleteFileTool {
+	return &DeleteFileTool{}
+}
+
+func (t *DeleteFileTool) Name() string {
+	return "delete_file"
+}
+
+func (t *DeleteFileTool) Description() string {
+	return "Delete a file, or a directory when recursive is true. It is moved into the session trash and can be restored"
+}
+
+func (t *DeleteFileTool) ReadOnly() bool {
+	return false
+}
+
+func (t *DeleteFileTool) GetParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "The file or directory to delete",
+			},
+			"recursive": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Allow deleting a directory with everything in it (default false)",
+			},
+		},
+		"required": []string{"path"},
+	}
+}
+
+func (t *DeleteFileTool) Execute(args map[string]interface{}) (*ToolResult, error) {
+	path, ok := args["path"].(string)
+	if !ok || path == "" {
+		return nil, requiredArg("path")
+	}
+	recursive, _ := args["recursive"].(bool)
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, newError(ErrFileNotFound, path, "cannot delete %s: %w", path, err)
+		}
+		return nil, fmt.Errorf("cannot delete %s: %w", path, err)
+	}
+	if info.IsDir() && !recursive {
+		return nil, validationError("%s is a directory; pass recursive: true to delete it with its contents", path)
+	}
+
+	entry, err := t.trashBin().Move(path)
+	if err != nil {
+		return nil, err
+	}
+
+	kind := "file"
+	if info.IsDir() {
+		kind = "directory"
+	}
+	return &ToolResult{
+		LLMContent:    fmt.Sprintf("Deleted %s %s (moved to %s; the user can restore it)", kind, path, entry.TrashPath),
+		ReturnDisplay: fmt.Sprintf("🗑️  Deleted `%s` (restore with `restore %s`)", path, path),
+	}, nil
+}
+
+// ConfirmationDetails names what will be deleted
+func (t *DeleteFileTool) ConfirmationDetails(args map[string]interface{}) *ConfirmationDetails {
+	path, _ := args["path"].(string)
+	recursive, _ := args["recursive"].(bool)
+	title := fmt.Sprintf("Delete %s (moved to the session trash)", path)
+	if recursive {
+		title = fmt.Sprintf("Delete %s and everything in it (moved to the session trash)", path)
+	}
+	return &ConfirmationDetails{Title: title}
+}
+
+func (t *DeleteFileTool) trashBin() *Trash {
+	if t.trash != nil {
+		return t.trash
+	}
+	return GlobalTrash
+}
@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDeleteFileMovesToTrashAndRestores(t *testing.T) {
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	os.MkdirAll("build", 0755)
+	os.WriteFile(filepath.Join("build", "out.txt"), []byte("artifact"), 0644)
+
+	trash := NewTrash(filepath.Join(".agenticode", "trash", "test"))
+	tool := &DeleteFileTool{trash: trash}
+
+	if _, err := tool.Execute(map[string]interface{}{"path": "build"}); err == nil || !strings.Contains(err.Error(), "recursive") {
+		t.Fatalf("expected a directory to need recursive, got %v", err)
+	}
+
+	path := filepath.Join("build", "out.txt")
+	if _, err := tool.Execute(map[string]interface{}{"path": path}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be gone, got %v", path, err)
+	}
+	trashed := filepath.Join(dir, ".agenticode", "trash", "test", "build", "out.txt")
+	if data, err := os.ReadFile(trashed); err != nil || string(data) != "artifact" {
+		t.Fatalf("expected the file in the trash at its relative path, got %q, %v", data, err)
+	}
+	if entries := trash.List(); len(entries) != 1 || entries[0].Path != path {
+		t.Errorf("unexpected trash entries %+v", entries)
+	}
+
+	if _, err := trash.Restore(path); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "artifact" {
+		t.Errorf("expected the file restored, got %q, %v", data, err)
+	}
+	if len(trash.List()) != 0 {
+		t.Error("expected the restored entry to leave the trash")
+	}
+	if _, err := trash.Restore(path); err == nil {
+		t.Error("expected a second restore to fail")
+	}
+
+	if _, err := tool.Execute(map[string]interface{}{"path": "build", "recursive": true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat("build"); !os.IsNotExist(err) {
+		t.Errorf("expected the directory to be deleted, got %v", err)
+	}
+}
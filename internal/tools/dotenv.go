@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/trknhr/agenticode/internal/redact"
+)
+
+// maskDotenv hides the values in dotenv files read by the agent. It is on by
+// default; users can turn it off with SetDotenvMasking.
+var maskDotenv atomic.Bool
+
+func init() {
+	maskDotenv.Store(true)
+}
+
+// SetDotenvMasking turns masking of dotenv values on or off
+func SetDotenvMasking(enabled bool) {
+	maskDotenv.Store(enabled)
+}
+
+// dotenvNoteLLM tells the model why it sees asterisks instead of values
+const dotenvNoteLLM = "(dotenv file: values are masked, keys are accurate)"
+
+// isDotenvFile reports whether path holds environment secrets: .env,
+// .env.local, production.env and the like. Templates such as .env.example
+// document the expected variables and are left readable.
+func isDotenvFile(path string) bool {
+	base := strings.ToLower(filepath.Base(path))
+	if base != ".env" && !strings.HasPrefix(base, ".env.") && !strings.HasSuffix(base, ".env") {
+		return false
+	}
+	for _, template := range []string{"example", "sample", "template", "dist"} {
+		if strings.Contains(base, template) {
+			return false
+		}
+	}
+	return true
+}
+
+// maskDotenvContent masks the content of a dotenv file, reporting whether it did
+func maskDotenvContent(path, content string) (string, bool) {
+	if !dotenvMasked(path) {
+		return content, false
+	}
+	return redact.Dotenv(content), true
+}
+
+// dotenvMasked reports whether the values in path are hidden from the model
+func dotenvMasked(path string) bool {
+	return maskDotenv.Load() && isDotenvFile(path)
+}
+
+// dotenvRangeError refuses a partial read of a dotenv file: a byte range can
+// start inside a value, where masking cannot tell it apart from other text
+func dotenvRangeError(path string) error {
+	return withRecovery(validationError("%s is a dotenv file and its values are masked, so it cannot be read by byte range", path),
+		"use read to see its keys with the values masked")
+}
+
+// maskDotenvDiff masks the lines of dotenv files in a unified diff. Lines
+// without an assignment may continue a multi-line value, so they are masked
+// whole.
+func maskDotenvDiff(diff string) string {
+	if !maskDotenv.Load() {
+		return diff
+	}
+	lines := strings.Split(diff, "\n")
+	inDotenv := false
+	for i, line := range lines {
+		if strings.HasPrefix(line, "diff --git ") {
+			// diff --git a/<path> b/<path>
+			fields := strings.Fields(line)
+			inDotenv = isDotenvFile(strings.TrimPrefix(fields[len(fields)-1], "b/"))
+			continue
+		}
+		if !inDotenv || line == "" || strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---") {
+			continue
+		}
+		marker, text := line[:1], line[1:]
+		if marker != "+" && marker != "-" && marker != " " {
+			continue // Hunk headers and "\ No newline at end of file"
+		}
+		trimmed := strings.TrimSpace(text)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+		case strings.Contains(text, "="):
+			lines[i] = marker + redact.Dotenv(text)
+		default:
+			lines[i] = marker + "****"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadFileMasksDotenvValues(t *testing.T) {
+	dir := t.TempDir()
+	env := filepath.Join(dir, ".env")
+	os.WriteFile(env, []byte("# API\nOPENAI_API_KEY=sk-live-abcdefghijklmnop\nexport DB_PASSWORD='hunter2'\n"), 0644)
+	example := filepath.Join(dir, ".env.example")
+	os.WriteFile(example, []byte("OPENAI_API_KEY=your-key-here\n"), 0644)
+
+	result, err := (&ReadFileTool{}).Execute(map[string]interface{}{"path": env})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, output := range []string{result.LLMContent, result.ReturnDisplay} {
+		if strings.Contains(output, "abcdefghijk") || strings.Contains(output, "hunter2") {
+			t.Errorf("expected secrets to be masked, got:\n%s", output)
+		}
+		if !strings.Contains(output, "OPENAI_API_KEY=****mnop") || !strings.Contains(output, "export DB_PASSWORD='****'") || !strings.Contains(output, "# API") {
+			t.Errorf("expected keys and comments to stay visible, got:\n%s", output)
+		}
+	}
+
+	result, err = (&ReadFileTool{}).Execute(map[string]interface{}{"path": example})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result.LLMContent, "your-key-here") {
+		t.Errorf("expected .env.example to be left readable, got %q", result.LLMContent)
+	}
+
+	SetDotenvMasking(false)
+	defer SetDotenvMasking(true)
+	result, _ = NewReadTool().Execute(map[string]interface{}{"file_path": env})
+	if !strings.Contains(result.LLMContent, "sk-live-abcdefghijklmnop") {
+		t.Errorf("expected values when masking is off, got %q", result.LLMContent)
+	}
+}
+
+func TestIsDotenvFile(t *testing.T) {
+	cases := map[string]bool{
+		".env":           true,
+		"app/.env.local": true,
+		"production.env": true,
+		".env.example":   false,
+		".env.sample":    false,
+		"environment.go": false,
+		"docs/dotenv.md": false,
+	}
+	for path, want := range cases {
+		if got := isDotenvFile(path); got != want {
+			t.Errorf("%s: expected %v", path, want)
+		}
+	}
+}
+
+func TestOtherContentToolsMaskDotenvValues(t *testing.T) {
+	env := filepath.Join(t.TempDir(), ".env")
+	os.WriteFile(env, []byte("OPENAI_API_KEY=sk-live-abcdefghijklmnop\nCERT=\"line one\nsecret-line-two\"\n"), 0644)
+	leaks := func(s string) bool {
+		return strings.Contains(s, "abcdefghijk") || strings.Contains(s, "secret-line-two")
+	}
+
+	pinned := &PinnedFiles{paths: make(map[string]bool)}
+	if _, err := pinned.Pin(env); err != nil {
+		t.Fatal(err)
+	}
+	if rendered := pinned.Render(); leaks(rendered) || !strings.Contains(rendered, "OPENAI_API_KEY=") {
+		t.Errorf("expected pinned .env values to be masked, got:\n%s", rendered)
+	}
+
+	chunks, _, err := readNumberedChunks(env, summarizeChunkBytes, maxSummarizeChunks)
+	if err != nil || len(chunks) != 1 || leaks(chunks[0].text) {
+		t.Errorf("expected summarize_file input to be masked, got %+v (%v)", chunks, err)
+	}
+
+	for _, tool := range []Tool{NewReadBytesTool(), NewWatchFileTool()} {
+		if result, err := tool.Execute(map[string]interface{}{"path": env}); err == nil {
+			t.Errorf("%s: expected a dotenv file to be refused, got %q", tool.Name(), result.LLMContent)
+		}
+	}
+
+	diff := "diff --git a/.env b/.env\n--- a/.env\n+++ b/.env\n@@ -1,3 +1,3 @@\n-OPENAI_API_KEY=sk-live-abcdefghijklmnop\n+OPENAI_API_KEY=sk-live-zzzzzzzzzzzzzzzz\n CERT=\"line one\n secret-line-two\"\n" +
+		"diff --git a/main.go b/main.go\n+key := \"abcdefghijk\"\n"
+	masked := maskDotenvDiff(diff)
+	if strings.Contains(masked, "sk-live-") || strings.Contains(masked, "secret-line-two") {
+		t.Errorf("expected the .env hunk to be masked, got:\n%s", masked)
+	}
+	if !strings.Contains(masked, "+key := \"abcdefghijk\"") {
+		t.Errorf("expected other files in the diff to be left alone, got:\n%s", masked)
+	}
+}
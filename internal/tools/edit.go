@@ -2,7 +2,6 @@ package tools
 
 import (
 	"fmt"
-	"os"
 	"strings"
 )
 
@@ -44,6 +43,10 @@ func (t *EditTool) GetParameters() map[string]interface{} {
 				"type":        "boolean",
 				"description": "Replace all occurrences (default false)",
 			},
+			"expected_replacements": map[string]interface{}{
+				"type":        "integer",
+				"description": "With replace_all, the number of occurrences you expect; the edit fails if the file has a different number",
+			},
 		},
 		"required": []string{"file_path", "old_string", "new_string"},
 	}
@@ -52,38 +55,47 @@ func (t *EditTool) GetParameters() map[string]interface{} {
 func (t *EditTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 	filePath, ok := args["file_path"].(string)
 	if !ok {
-		return nil, fmt.Errorf("file_path is required")
+		return nil, requiredArg("file_path")
 	}
 
 	oldString, ok := args["old_string"].(string)
 	if !ok {
-		return nil, fmt.Errorf("old_string is required")
+		return nil, requiredArg("old_string")
 	}
 
 	newString, ok := args["new_string"].(string)
 	if !ok {
-		return nil, fmt.Errorf("new_string is required")
+		return nil, requiredArg("new_string")
 	}
 
 	replaceAll, _ := args["replace_all"].(bool)
+	expected, hasExpected, err := expectedReplacements(args)
+	if err != nil {
+		return nil, err
+	}
 
 	// Read the file
-	content, err := os.ReadFile(filePath)
+	fileContent, enc, err := readTextFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, fileError("read", filePath, err)
 	}
 
-	fileContent := string(content)
 	originalContent := fileContent
 
 	// Check if old_string exists in the file
 	if !strings.Contains(fileContent, oldString) {
-		return nil, fmt.Errorf("old_string not found in file")
+		return nil, withRecovery(validationError("old_string not found in file"), recoverReread)
+	}
+
+	// Guard against replacing more (or fewer) places than the model intended
+	occurrences := strings.Count(fileContent, oldString)
+	if hasExpected && occurrences != expected {
+		return nil, withRecovery(validationError("expected %d replacement(s) but old_string occurs %d time(s) in the file; re-read the file and adjust the edit", expected, occurrences), recoverReread)
 	}
 
 	// Check if old_string is unique (when not replace_all)
-	if !replaceAll && strings.Count(fileContent, oldString) > 1 {
-		return nil, fmt.Errorf("old_string is not unique in the file. Use replace_all=true or provide more context")
+	if !replaceAll && occurrences > 1 {
+		return nil, withRecovery(validationError("old_string is not unique in the file. Use replace_all=true or provide more context"), recoverAmbiguous)
 	}
 
 	// Perform replacement
@@ -92,7 +104,7 @@ func (t *EditTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 
 	if replaceAll {
 		updatedContent = strings.ReplaceAll(fileContent, oldString, newString)
-		replacements = strings.Count(fileContent, oldString)
+		replacements = occurrences
 	} else {
 		updatedContent = strings.Replace(fileContent, oldString, newString, 1)
 		replacements = 1
@@ -100,18 +112,37 @@ func (t *EditTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 
 	// Check if content actually changed
 	if updatedContent == originalContent {
-		return nil, fmt.Errorf("no changes made - old_string and new_string might be identical")
+		return nil, validationError("no changes made - old_string and new_string might be identical")
 	}
 
 	// Write the updated content back
-	err = os.WriteFile(filePath, []byte(updatedContent), 0644)
+	encodingNote, err := writeTextFile(filePath, updatedContent, enc)
 	if err != nil {
 		return nil, fmt.Errorf("failed to write file: %w", err)
 	}
 
-	return &ToolResult{
-		LLMContent:    fmt.Sprintf("Successfully replaced %d occurrence(s) in %s", replacements, filePath),
+	result := &ToolResult{
+		LLMContent:    fmt.Sprintf("Successfully replaced %d occurrence(s) in %s%s", replacements, filePath, encodingNote),
 		ReturnDisplay: fmt.Sprintf("✅ **Edited** `%s`\n\nReplaced **%d occurrence(s)** of the specified string.", filePath, replacements),
 		Error:         nil,
-	}, nil
+	}
+	formatAfterWrite(filePath, result)
+	return result, nil
+}
+
+// expectedReplacements reads the optional expected_replacements argument
+func expectedReplacements(args map[string]interface{}) (int, bool, error) {
+	switch v := args["expected_replacements"].(type) {
+	case nil:
+		return 0, false, nil
+	case float64:
+		if v >= 1 && v == float64(int(v)) {
+			return int(v), true, nil
+		}
+	case int:
+		if v >= 1 {
+			return v, true, nil
+		}
+	}
+	return 0, false, validationError("expected_replacements must be a positive integer")
 }
@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"fmt"
+)
+
+// EditDiffTool applies a unified diff to a single existing file
+type EditDiffTool struct{}
+
+// NewEditDiffTool creates a new EditDiffTool instance
+func NewEditDiffTool() *EditDiffTool {
+	return &EditDiffTool{}
+}
+
+func (t *EditDiffTool) Name() string {
+	return "edit_diff"
+}
+
+func (t *EditDiffTool) Description() string {
+	return "Edit one existing file by applying a unified diff (@@ -start,count +start,count @@ hunks with ' ', '-' and '+' lines). Often more reliable than edit for changes spanning several places. Context and removed lines must match the file exactly"
+}
+
+func (t *EditDiffTool) ReadOnly() bool {
+	return false
+}
+
+func (t *EditDiffTool) GetParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"file_path": map[string]interface{}{
+				"type":        "string",
+				"description": "The file to modify",
+			},
+			"diff": map[string]interface{}{
+				"type":        "string",
+				"description": "A unified diff for this file only; ---/+++ headers are optional",
+			},
+		},
+		"required": []string{"file_path", "diff"},
+	}
+}
+
+func (t *EditDiffTool) Execute(args map[string]interface{}) (*ToolResult, error) {
+	filePath, ok := args["file_path"].(string)
+	if !ok || filePath == "" {
+		return nil, requiredArg("file_path")
+	}
+	diff, ok := args["diff"].(string)
+	if !ok || diff == "" {
+		return nil, requiredArg("diff")
+	}
+
+	content, enc, err := readTextFile(filePath)
+	if err != nil {
+		return nil, fileError("read", filePath, err)
+	}
+
+	updated, hunks, err := ApplyUnifiedDiff(filePath, content, diff)
+	if err != nil {
+		return nil, err
+	}
+	if updated == content {
+		return nil, validationError("no changes made - the diff does not change the file")
+	}
+
+	encodingNote, err := writeTextFile(filePath, updated, enc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write file: %w", err)
+	}
+
+	result := &ToolResult{
+		LLMContent:    fmt.Sprintf("Successfully applied %d hunk(s) to %s%s", hunks, filePath, encodingNote),
+		ReturnDisplay: fmt.Sprintf("✅ **Patched** `%s`\n\nApplied **%d hunk(s)**.", filePath, hunks),
+	}
+	formatAfterWrite(filePath, result)
+	return result, nil
+}
@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const editDiffOriginal = `package main
+
+import "fmt"
+
+func greet() {
+	fmt.Println("hello")
+}
+
+func helper() int {
+	return 1
+}
+
+func main() {
+	greet()
+}
+`
+
+func TestEditDiffAppliesTwoHunks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte(editDiffOriginal), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	diff := `--- a/main.go
++++ b/main.go
+@@ -4,5 +4,5 @@
+
+ func greet() {
+-	fmt.Println("hello")
++	fmt.Println("hello, world")
+ }
+
+@@ -13,3 +13,4 @@
+ func main() {
+ 	greet()
++	fmt.Println(helper())
+ }
+`
+	result, err := (&EditDiffTool{}).Execute(map[string]interface{}{"file_path": path, "diff": diff})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result.LLMContent, "2 hunk(s)") {
+		t.Errorf("expected both hunks reported, got %q", result.LLMContent)
+	}
+
+	got, _ := os.ReadFile(path)
+	want := strings.Replace(editDiffOriginal, `"hello"`, `"hello, world"`, 1)
+	want = strings.Replace(want, "\tgreet()\n}", "\tgreet()\n\tfmt.Println(helper())\n}", 1)
+	if string(got) != want {
+		t.Errorf("unexpected result:\n%s", got)
+	}
+}
+
+func TestEditDiffToleratesLineOffsets(t *testing.T) {
+	// The header says line 1, but the context sits at line 6
+	diff := "@@ -1,2 +1,2 @@\n func greet() {\n-\tfmt.Println(\"hello\")\n+\tfmt.Println(\"hi\")\n"
+	updated, _, err := ApplyUnifiedDiff("main.go", editDiffOriginal, diff)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(updated, `fmt.Println("hi")`) {
+		t.Errorf("expected the hunk to apply at its actual location, got:\n%s", updated)
+	}
+}
+
+func TestEditDiffValidation(t *testing.T) {
+	cases := []struct {
+		name string
+		diff string
+		want string
+	}{
+		{"mismatched context", "@@ -6,1 +6,1 @@\n-\tfmt.Println(\"bye\")\n+\tfmt.Println(\"hi\")\n", "does not match"},
+		{"wrong file", "--- a/other.go\n+++ b/other.go\n@@ -6,1 +6,1 @@\n-\tfmt.Println(\"hello\")\n+\tfmt.Println(\"hi\")\n", "not main.go"},
+		{"bad counts", "@@ -6,2 +6,2 @@\n-\tfmt.Println(\"hello\")\n+\tfmt.Println(\"hi\")\n", "header declares"},
+		{"no hunks", "just some text\n", "no hunks"},
+		{"second file", "--- a/main.go\n+++ b/main.go\n@@ -6,1 +6,1 @@\n-\tfmt.Println(\"hello\")\n+\tfmt.Println(\"hi\")\n--- a/other.go\n+++ b/other.go\n", "more than one file"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := ApplyUnifiedDiff("main.go", editDiffOriginal, tc.diff)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestEditDiffNoNewlineAtEnd(t *testing.T) {
+	diff := "@@ -1,2 +1,2 @@\n a\n-b\n+c\n\\ No newline at end of file\n"
+	updated, _, err := ApplyUnifiedDiff("f.txt", "a\nb\n", diff)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated != "a\nc" {
+		t.Errorf("expected no trailing newline, got %q", updated)
+	}
+}
@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEditExpectedReplacements(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	original := "foo()\nfoo()\nfoo()\n"
+	tool := NewEditTool()
+
+	os.WriteFile(path, []byte(original), 0644)
+	_, err := tool.Execute(map[string]interface{}{
+		"file_path": path, "old_string": "foo()", "new_string": "bar()",
+		"replace_all": true, "expected_replacements": float64(2),
+	})
+	if err == nil || !strings.Contains(err.Error(), "expected 2 replacement(s) but old_string occurs 3 time(s)") {
+		t.Fatalf("expected a count mismatch error, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Errorf("a failed edit must leave the file untouched, got %q", data)
+	}
+
+	result, err := tool.Execute(map[string]interface{}{
+		"file_path": path, "old_string": "foo()", "new_string": "bar()",
+		"replace_all": true, "expected_replacements": float64(3),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "bar()\nbar()\nbar()\n" || !strings.Contains(result.LLMContent, "3 occurrence(s)") {
+		t.Errorf("unexpected result %q with file %q", result.LLMContent, data)
+	}
+
+	if _, err := tool.Execute(map[string]interface{}{
+		"file_path": path, "old_string": "bar()", "new_string": "baz()",
+		"replace_all": true, "expected_replacements": 1.5,
+	}); err == nil {
+		t.Error("expected a fractional count to be rejected")
+	}
+}
@@ -0,0 +1,226 @@
+package tools
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// preserveEncoding makes the edit tools write a non-UTF-8 file back in the
+// encoding it was read in. Off by default: edited files are saved as UTF-8.
+var preserveEncoding atomic.Bool
+
+// SetPreserveEncoding turns keeping the original encoding on edit on or off
+func SetPreserveEncoding(enabled bool) {
+	preserveEncoding.Store(enabled)
+}
+
+var (
+	utf8BOM    = []byte{0xEF, 0xBB, 0xBF}
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// fileEncoding is the text encoding a file was detected to use
+type fileEncoding struct {
+	Name   string            // "UTF-8", "UTF-16LE", "UTF-16BE", "Windows-1252", "ISO-8859-1" or "unknown"
+	BOM    bool              // The file starts with a byte order mark
+	codec  encoding.Encoding // nil for UTF-8 and unknown encodings
+	opaque bool              // The encoding could not be told; bytes pass through as is
+}
+
+var (
+	utf8Encoding    = fileEncoding{Name: "UTF-8"}
+	unknownEncoding = fileEncoding{Name: "unknown", opaque: true}
+)
+
+// isUTF8 reports whether text in this encoding can be used as is. Files of
+// unknown encoding are too: edits then only change the bytes they replace.
+func (e fileEncoding) isUTF8() bool {
+	return e.codec == nil
+}
+
+// note tells the reader that the content they see was transcoded, or that it
+// is shown as raw bytes
+func (e fileEncoding) note() string {
+	if e.opaque {
+		return "(unrecognized encoding, shown as raw bytes)"
+	}
+	if e.isUTF8() {
+		return ""
+	}
+	return fmt.Sprintf("(decoded from %s)", e.Name)
+}
+
+// detectEncoding sniffs the byte order mark, then falls back to heuristics:
+// text with a NUL in every other byte is UTF-16, valid UTF-8 stays UTF-8, and
+// text that can only be a single-byte Western encoding is decoded as one.
+// Anything else, such as Shift_JIS, EUC-JP or GBK, is reported as unknown so
+// its bytes pass through untouched; so is other binary content.
+func detectEncoding(data []byte) fileEncoding {
+	switch {
+	case bytes.HasPrefix(data, utf8BOM):
+		return fileEncoding{Name: "UTF-8", BOM: true}
+	case bytes.HasPrefix(data, utf16LEBOM):
+		return fileEncoding{Name: "UTF-16LE", BOM: true, codec: unicode.UTF16(unicode.LittleEndian, unicode.UseBOM)}
+	case bytes.HasPrefix(data, utf16BEBOM):
+		return fileEncoding{Name: "UTF-16BE", BOM: true, codec: unicode.UTF16(unicode.BigEndian, unicode.UseBOM)}
+	}
+	// NUL is valid UTF-8, so UTF-16 has to be ruled out first
+	if le, ok := looksLikeUTF16(data); ok {
+		if le {
+			return fileEncoding{Name: "UTF-16LE", codec: unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)}
+		}
+		return fileEncoding{Name: "UTF-16BE", codec: unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM)}
+	}
+	if utf8.Valid(data) {
+		return utf8Encoding
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		return utf8Encoding
+	}
+	if !looksSingleByte(data) {
+		return unknownEncoding
+	}
+	// 0x80-0x9F are control characters in Latin-1 but punctuation such as
+	// curly quotes in Windows-1252, which is what they almost always are
+	for _, b := range data {
+		if b >= 0x80 && b <= 0x9F {
+			return fileEncoding{Name: "Windows-1252", codec: charmap.Windows1252}
+		}
+	}
+	return fileEncoding{Name: "ISO-8859-1", codec: charmap.ISO8859_1}
+}
+
+// looksSingleByte reports whether non-ASCII bytes appear only on their own, as
+// accented letters and punctuation do in Western text. Multi-byte encodings
+// such as Shift_JIS and GBK put high bytes next to each other, and some of
+// them use bytes that Windows-1252 leaves undefined.
+func looksSingleByte(data []byte) bool {
+	for i, b := range data {
+		if b < 0x80 {
+			continue
+		}
+		switch b {
+		case 0x81, 0x8D, 0x8F, 0x90, 0x9D:
+			return false
+		}
+		if i+1 < len(data) && data[i+1] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// looksLikeUTF16 spots BOM-less UTF-16 by its zero high bytes: mostly ASCII
+// text has a NUL in nearly every odd (little endian) or even (big endian) byte
+func looksLikeUTF16(data []byte) (littleEndian bool, ok bool) {
+	if len(data) < 4 || len(data)%2 != 0 {
+		return false, false
+	}
+	var evenZeros, oddZeros int
+	for i, b := range data {
+		if b != 0 {
+			continue
+		}
+		if i%2 == 0 {
+			evenZeros++
+		} else {
+			oddZeros++
+		}
+	}
+	pairs := len(data) / 2
+	switch {
+	case oddZeros*2 >= pairs && evenZeros == 0:
+		return true, true
+	case evenZeros*2 >= pairs && oddZeros == 0:
+		return false, true
+	}
+	return false, false
+}
+
+// decodeText returns data as UTF-8 text without a byte order mark, along with
+// the encoding it was in
+func decodeText(data []byte) (string, fileEncoding) {
+	enc := detectEncoding(data)
+	if enc.isUTF8() {
+		if enc.BOM {
+			data = data[len(utf8BOM):]
+		}
+		return string(data), enc
+	}
+	text, err := enc.codec.NewDecoder().Bytes(data)
+	if err != nil {
+		return string(data), utf8Encoding
+	}
+	return string(text), enc
+}
+
+// readTextFile reads a file and decodes it to UTF-8
+func readTextFile(path string) (string, fileEncoding, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", utf8Encoding, err
+	}
+	text, enc := decodeText(data)
+	return text, enc, nil
+}
+
+// ReadTextForPreview reads a file the way the edit tools do, so an approval
+// preview shows the text they will change. The returned note says how saving
+// the file changes its encoding, e.g. "converted from UTF-16LE to UTF-8", or
+// is empty. keepsEncoding is whether the tool honours SetPreserveEncoding
+// (the edit tools do, write_file does not).
+func ReadTextForPreview(path string, keepsEncoding bool) (string, string, error) {
+	text, enc, err := readTextFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	if enc.isUTF8() || (keepsEncoding && preserveEncoding.Load()) {
+		return text, "", nil
+	}
+	return text, fmt.Sprintf("converted from %s to UTF-8", enc.Name), nil
+}
+
+// encode turns text back into bytes for a file that was read in this
+// encoding. UTF-8 files keep their BOM; other encodings are kept only when
+// SetPreserveEncoding is on and are converted to UTF-8 otherwise.
+func (e fileEncoding) encode(text string) ([]byte, fileEncoding, error) {
+	if e.isUTF8() || !preserveEncoding.Load() {
+		if e.isUTF8() && e.BOM {
+			return append(append([]byte{}, utf8BOM...), text...), e, nil
+		}
+		return []byte(text), utf8Encoding, nil
+	}
+	data, err := e.codec.NewEncoder().Bytes([]byte(text))
+	if err != nil {
+		return nil, e, validationError("the new content has characters that cannot be saved as %s: %v", e.Name, err)
+	}
+	return data, e, nil
+}
+
+// writeTextFile writes text to a file that was read in encoding e and returns
+// a note for the tool result when the encoding is not plain UTF-8
+func writeTextFile(path, text string, e fileEncoding) (string, error) {
+	data, written, err := e.encode(text)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", err
+	}
+	switch {
+	case e.isUTF8():
+		return "", nil
+	case written.isUTF8():
+		return fmt.Sprintf(" (converted from %s to UTF-8)", e.Name), nil
+	default:
+		return fmt.Sprintf(" (kept %s encoding)", e.Name), nil
+	}
+}
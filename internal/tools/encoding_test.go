@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// utf16LEFixture is "héllo\r\nwörld\r\n" as written by Windows Notepad
+var utf16LEFixture = []byte{
+	0xFF, 0xFE,
+	'h', 0, 0xE9, 0, 'l', 0, 'l', 0, 'o', 0, '\r', 0, '\n', 0,
+	'w', 0, 0xF6, 0, 'r', 0, 'l', 0, 'd', 0, '\r', 0, '\n', 0,
+}
+
+// latin1Fixture is "café = naïve\n" in ISO-8859-1
+var latin1Fixture = []byte("caf\xe9 = na\xefve\n")
+
+// shiftJISFixture is "// こんにちは\nfunc main() {}\n" in Shift_JIS
+var shiftJISFixture = []byte("// \x82\xb1\x82\xf1\x82\xc9\x82\xbf\x82\xcd\nfunc main() {}\n")
+
+func writeFixture(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDecodeTextDetectsEncodings(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		text string
+		enc  string
+	}{
+		{"utf-16le with bom", utf16LEFixture, "héllo\r\nwörld\r\n", "UTF-16LE"},
+		{"utf-16be with bom", []byte{0xFE, 0xFF, 0, 'h', 0, 0xE9}, "hé", "UTF-16BE"},
+		{"utf-16le without bom", []byte{'a', 0, 'b', 0, 'c', 0, '\n', 0}, "abc\n", "UTF-16LE"},
+		{"latin-1", latin1Fixture, "café = naïve\n", "ISO-8859-1"},
+		{"windows-1252", []byte("\x93quoted\x94"), "“quoted”", "Windows-1252"},
+		{"utf-8", []byte("héllo"), "héllo", "UTF-8"},
+		{"utf-8 with bom", []byte("\xef\xbb\xbfhéllo"), "héllo", "UTF-8"},
+		{"shift_jis", shiftJISFixture, string(shiftJISFixture), "unknown"},
+		{"euc-jp", []byte("\xa4\xb3\xa4\xf3\xa4\xcb\xa4\xc1\xa4\xcf\n"), "\xa4\xb3\xa4\xf3\xa4\xcb\xa4\xc1\xa4\xcf\n", "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, enc := decodeText(tt.data)
+			if text != tt.text {
+				t.Errorf("text = %q, want %q", text, tt.text)
+			}
+			if enc.Name != tt.enc {
+				t.Errorf("encoding = %s, want %s", enc.Name, tt.enc)
+			}
+		})
+	}
+}
+
+func TestReadTranscodesToUTF8(t *testing.T) {
+	path := writeFixture(t, "windows.txt", utf16LEFixture)
+	result, err := NewReadTool().Execute(map[string]interface{}{"file_path": path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result.LLMContent, "héllo\r\nwörld") {
+		t.Errorf("expected transcoded text, got:\n%q", result.LLMContent)
+	}
+	if !strings.Contains(result.LLMContent, "(decoded from UTF-16LE)") {
+		t.Errorf("expected the source encoding to be noted, got:\n%s", result.LLMContent)
+	}
+
+	path = writeFixture(t, "latin1.txt", latin1Fixture)
+	result, err = NewReadFileTool().Execute(map[string]interface{}{"path": path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result.LLMContent, "café = naïve") || !strings.Contains(result.LLMContent, "(decoded from ISO-8859-1)") {
+		t.Errorf("expected transcoded Latin-1 text, got:\n%s", result.LLMContent)
+	}
+}
+
+func TestEditWritesUTF8UnlessPreservingEncoding(t *testing.T) {
+	edit := func(path string) *ToolResult {
+		t.Helper()
+		result, err := NewEditTool().Execute(map[string]interface{}{
+			"file_path":  path,
+			"old_string": "wörld",
+			"new_string": "wörld!",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	path := writeFixture(t, "converted.txt", utf16LEFixture)
+	result := edit(path)
+	data, _ := os.ReadFile(path)
+	if string(data) != "héllo\r\nwörld!\r\n" {
+		t.Errorf("expected the file to be saved as UTF-8, got %q", data)
+	}
+	if !strings.Contains(result.LLMContent, "converted from UTF-16LE to UTF-8") {
+		t.Errorf("expected a conversion note, got: %s", result.LLMContent)
+	}
+
+	SetPreserveEncoding(true)
+	defer SetPreserveEncoding(false)
+
+	path = writeFixture(t, "kept.txt", utf16LEFixture)
+	edit(path)
+	data, _ = os.ReadFile(path)
+	want := append(append([]byte{}, utf16LEFixture[:len(utf16LEFixture)-4]...), '!', 0, '\r', 0, '\n', 0)
+	if !bytes.Equal(data, want) {
+		t.Errorf("expected UTF-16LE with BOM to be kept, got % x", data)
+	}
+
+	// A file of unknown encoding keeps every byte the edit does not replace
+	path = writeFixture(t, "sjis.go", shiftJISFixture)
+	if _, err := NewEditTool().Execute(map[string]interface{}{
+		"file_path":  path,
+		"old_string": "func main() {}",
+		"new_string": "func main() { run() }",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if want := bytes.Replace(shiftJISFixture, []byte("func main() {}"), []byte("func main() { run() }"), 1); !bytes.Equal(data, want) {
+		t.Errorf("expected only the edited bytes to change, got % x", data)
+	}
+
+	path = writeFixture(t, "latin1.txt", latin1Fixture)
+	if _, err := NewEditTool().Execute(map[string]interface{}{
+		"file_path":  path,
+		"old_string": "café",
+		"new_string": "☕",
+	}); err == nil {
+		t.Error("expected an error for a character Latin-1 cannot hold")
+	}
+}
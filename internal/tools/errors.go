@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+)
+
+// Kinds of tool failure; match them with errors.Is
+var (
+	ErrFileNotFound   = errors.New("file not found")
+	ErrPathNotAllowed = errors.New("path not allowed")
+	ErrValidation     = errors.New("invalid arguments")
+	ErrTimeout        = errors.New("timed out")
+)
+
+// Error is a tool failure classified by Kind, so callers can branch on
+// errors.Is(err, ErrValidation) or errors.As(err, &toolErr) instead of
+// matching message text. The message is unchanged from what the model sees.
+type Error struct {
+	Kind     error  // One of the Err* kinds above
+	Path     string // File the failure is about, if any
+	Msg      string
+	Err      error  // Underlying cause, if any
+	Recovery string // Specific suggestion for RecoveryHint, if any
+}
+
+func (e *Error) Error() string {
+	return e.Msg
+}
+
+// Is reports whether target is the error's kind
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Classify marks err as a failure of the given kind, keeping its message.
+// It is for errors from outside this package, such as MCP servers.
+func Classify(kind, err error) error {
+	return &Error{Kind: kind, Msg: err.Error(), Err: err}
+}
+
+// newError formats a message like fmt.Errorf, keeping any %w cause
+func newError(kind error, path, format string, args ...interface{}) *Error {
+	err := fmt.Errorf(format, args...)
+	return &Error{Kind: kind, Path: path, Msg: err.Error(), Err: errors.Unwrap(err)}
+}
+
+// requiredArg reports a missing or mistyped required argument
+func requiredArg(name string) error {
+	return &Error{Kind: ErrValidation, Msg: name + " is required"}
+}
+
+// validationError reports arguments the tool cannot act on
+func validationError(format string, args ...interface{}) error {
+	return newError(ErrValidation, "", format, args...)
+}
+
+// fileNotFound reports a path that does not exist
+func fileNotFound(path string) error {
+	return &Error{Kind: ErrFileNotFound, Path: path, Msg: "file not found: " + path, Err: fs.ErrNotExist}
+}
+
+// pathNotAllowed reports a path the tool refuses to touch
+func pathNotAllowed(path, format string, args ...interface{}) error {
+	return newError(ErrPathNotAllowed, path, format, args...)
+}
+
+// fileError wraps a failed file operation as "failed to <action> file: ...",
+// classifying a missing file as ErrFileNotFound
+func fileError(action, path string, err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return newError(ErrFileNotFound, path, "failed to %s file: %w", action, err)
+	}
+	return fmt.Errorf("failed to %s file: %w", action, err)
+}
+
+// timeoutError classifies err as ErrTimeout when it is a deadline or network
+// timeout, keeping the message; other errors are returned as they are
+func timeoutError(err error) error {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return Classify(ErrTimeout, err)
+	}
+	return err
+}
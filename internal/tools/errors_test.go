@@ -0,0 +1,119 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadMissingFileIsFileNotFound(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.go")
+
+	_, err := NewReadTool().Execute(map[string]interface{}{"file_path": path})
+	if !errors.Is(err, ErrFileNotFound) {
+		t.Fatalf("expected ErrFileNotFound, got %v", err)
+	}
+	var toolErr *Error
+	if !errors.As(err, &toolErr) || toolErr.Path != path {
+		t.Errorf("expected the error to carry the path, got %+v", toolErr)
+	}
+	if err.Error() != "file not found: "+path {
+		t.Errorf("expected the message unchanged, got %q", err.Error())
+	}
+
+	_, err = NewEditTool().Execute(map[string]interface{}{"file_path": path, "old_string": "a", "new_string": "b"})
+	if !errors.Is(err, ErrFileNotFound) || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected edit of a missing file to be ErrFileNotFound, got %v", err)
+	}
+}
+
+func TestInvalidArgumentsAreValidationErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	os.WriteFile(path, []byte("package main\n"), 0644)
+
+	cases := []struct {
+		name string
+		tool Tool
+		args map[string]interface{}
+	}{
+		{"missing argument", NewReadTool(), map[string]interface{}{}},
+		{"directory", NewReadTool(), map[string]interface{}{"file_path": filepath.Dir(path)}},
+		{"old_string not found", NewEditTool(), map[string]interface{}{"file_path": path, "old_string": "func", "new_string": "var"}},
+		{"bad regex", NewGrepTool(), map[string]interface{}{"pattern": "("}},
+	}
+	for _, tc := range cases {
+		_, err := tc.tool.Execute(tc.args)
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("%s: expected ErrValidation, got %v", tc.name, err)
+		}
+		if errors.Is(err, ErrFileNotFound) {
+			t.Errorf("%s: expected a single kind, got %v", tc.name, err)
+		}
+	}
+
+	if _, err := NewReadTool().Execute(map[string]interface{}{}); err.Error() != "file_path is required" {
+		t.Errorf("expected the message unchanged, got %q", err.Error())
+	}
+}
+
+func TestDeletingTrashIsPathNotAllowed(t *testing.T) {
+	dir := t.TempDir()
+	tool := &DeleteFileTool{trash: NewTrash(filepath.Join(dir, "trash"))}
+
+	_, err := tool.Execute(map[string]interface{}{"path": dir, "recursive": true})
+	if !errors.Is(err, ErrPathNotAllowed) {
+		t.Fatalf("expected ErrPathNotAllowed, got %v", err)
+	}
+
+	_, err = tool.Execute(map[string]interface{}{"path": filepath.Join(dir, "missing")})
+	if !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("expected ErrFileNotFound for a missing path, got %v", err)
+	}
+}
+
+func TestTimeoutError(t *testing.T) {
+	err := timeoutError(fmt.Errorf("failed to fetch content: %w", context.DeadlineExceeded))
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected ErrTimeout wrapping the deadline, got %v", err)
+	}
+	if err := timeoutError(errors.New("HTTP 500")); errors.Is(err, ErrTimeout) {
+		t.Errorf("expected other errors to stay unclassified, got %v", err)
+	}
+}
+
+func TestRecoveryHintForCommonFailures(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(path, []byte("package main\n\nfunc a() {}\nfunc a() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		tool Tool
+		args map[string]interface{}
+		want string
+	}{
+		{"old_string not found", NewEditTool(), map[string]interface{}{"file_path": path, "old_string": "func b", "new_string": "func c"}, recoverReread},
+		{"old_string not unique", NewEditTool(), map[string]interface{}{"file_path": path, "old_string": "func a", "new_string": "func c"}, recoverAmbiguous},
+		{"multi_edit not found", NewMultiEditTool(), map[string]interface{}{"file_path": path, "edits": []interface{}{map[string]interface{}{"old_string": "func b", "new_string": "x"}}}, recoverReread},
+		{"file not found", NewReadTool(), map[string]interface{}{"file_path": filepath.Join(dir, "missing.go")}, kindRecovery[ErrFileNotFound]},
+		{"missing argument", NewReadTool(), map[string]interface{}{}, kindRecovery[ErrValidation]},
+	}
+	for _, tc := range cases {
+		_, err := tc.tool.Execute(tc.args)
+		if err == nil {
+			t.Fatalf("%s: expected an error", tc.name)
+		}
+		if got := RecoveryHint(err); got != tc.want {
+			t.Errorf("%s: expected recovery %q, got %q", tc.name, tc.want, got)
+		}
+	}
+
+	if got := RecoveryHint(fmt.Errorf("exit status 1")); got != "" {
+		t.Errorf("expected no recovery for an unclassified error, got %q", got)
+	}
+}
@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// formatTimeout bounds how long a formatter may run after a write
+const formatTimeout = 10 * time.Second
+
+var (
+	formattersMu sync.RWMutex
+	formatters   map[string]string // File extension (without dot) -> formatter command
+)
+
+// SetFormatOnWrite configures the formatters run after write_file, edit and
+// multi_edit succeed. Keys are file extensions with or without the leading
+// dot ("go", ".ts"); values are commands that receive the file path as their
+// last argument, e.g. "gofmt -w" or "prettier --write".
+func SetFormatOnWrite(commands map[string]string) {
+	formattersMu.Lock()
+	defer formattersMu.Unlock()
+
+	formatters = make(map[string]string, len(commands))
+	for ext, command := range commands {
+		ext = strings.ToLower(strings.TrimPrefix(ext, "."))
+		if ext != "" && strings.TrimSpace(command) != "" {
+			formatters[ext] = command
+		}
+	}
+}
+
+// formatterFor returns the formatter command configured for path, if any
+func formatterFor(path string) string {
+	formattersMu.RLock()
+	defer formattersMu.RUnlock()
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	return formatters[ext]
+}
+
+// formatAfterWrite runs the configured formatter on a freshly written file and
+// notes the outcome on the result. Formatting problems never fail the write.
+func formatAfterWrite(path string, result *ToolResult) {
+	command := formatterFor(path)
+	if command == "" {
+		return
+	}
+
+	fields := strings.Fields(command)
+	name := fields[0]
+	if _, err := exec.LookPath(name); err != nil {
+		result.ReturnDisplay += fmt.Sprintf("\n⚠️ Formatter `%s` is not installed; file left as written", name)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), formatTimeout)
+	defer cancel()
+
+	args := append(fields[1:], path)
+	output, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		detail := strings.TrimSpace(string(output))
+		if detail == "" {
+			detail = err.Error()
+		}
+		result.ReturnDisplay += fmt.Sprintf("\n⚠️ `%s` failed, file left as written: %s", name, detail)
+		result.LLMContent += fmt.Sprintf("\nNote: %s could not format the file: %s", name, detail)
+		return
+	}
+
+	result.ReturnDisplay += fmt.Sprintf("\n🎨 Formatted with `%s`", name)
+	result.LLMContent += fmt.Sprintf("\nThe file was reformatted with %s; read it again before editing formatted regions.", name)
+}
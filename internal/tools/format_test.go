@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteFileFormatsGoOnWrite(t *testing.T) {
+	if _, err := exec.LookPath("gofmt"); err != nil {
+		t.Skip("gofmt not available")
+	}
+	SetFormatOnWrite(map[string]string{".go": "gofmt -w"})
+	defer SetFormatOnWrite(nil)
+
+	path := filepath.Join(t.TempDir(), "main.go")
+	result, err := NewWriteFileTool().Execute(map[string]interface{}{
+		"path":    path,
+		"content": "package main\nfunc main(){\nprintln( \"hi\" )\n}\n",
+	})
+	if err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n"
+	if string(data) != want {
+		t.Errorf("expected gofmt'd output, got:\n%s", data)
+	}
+	if !strings.Contains(result.ReturnDisplay, "Formatted with `gofmt`") {
+		t.Errorf("expected a formatting note, got %q", result.ReturnDisplay)
+	}
+}
+
+func TestFormatFailureDoesNotFailWrite(t *testing.T) {
+	SetFormatOnWrite(map[string]string{"go": "gofmt -w", "ts": "definitely-not-a-formatter --write"})
+	defer SetFormatOnWrite(nil)
+
+	dir := t.TempDir()
+
+	// Missing formatter
+	result, err := NewWriteFileTool().Execute(map[string]interface{}{
+		"path":    filepath.Join(dir, "app.ts"),
+		"content": "const x=1\n",
+	})
+	if err != nil {
+		t.Fatalf("write should succeed without the formatter: %v", err)
+	}
+	if !strings.Contains(result.ReturnDisplay, "not installed") {
+		t.Errorf("expected a note about the missing formatter, got %q", result.ReturnDisplay)
+	}
+
+	// Formatter rejects the file
+	if _, err := exec.LookPath("gofmt"); err == nil {
+		path := filepath.Join(dir, "broken.go")
+		result, err = NewWriteFileTool().Execute(map[string]interface{}{
+			"path":    path,
+			"content": "package main\nfunc {\n",
+		})
+		if err != nil {
+			t.Fatalf("write should succeed when formatting fails: %v", err)
+		}
+		if !strings.Contains(result.ReturnDisplay, "failed, file left as written") {
+			t.Errorf("expected a formatting failure note, got %q", result.ReturnDisplay)
+		}
+		if data, _ := os.ReadFile(path); string(data) != "package main\nfunc {\n" {
+			t.Errorf("file should be left as written, got %q", data)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package tools
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+	"sync"
+)
+
+// maxGitDiffBytes caps the diff returned to the model
+const maxGitDiffBytes = 50 * 1024
+
+// recoverProtectedBranch points the model at the workflow protection expects
+const recoverProtectedBranch = "Create a feature branch first (e.g. run_shell with git switch -c <feature-branch>), then commit there."
+
+var (
+	protectedBranchesMu sync.RWMutex
+	protectedBranches   []string // Branch names or globs such as release/*
+)
+
+// SetProtectedBranches sets the branches git_commit refuses to commit to
+// (git.protected_branches). Entries may be globs such as "release/*".
+func SetProtectedBranches(branches []string) error {
+	for _, branch := range branches {
+		if _, err := path.Match(branch, ""); err != nil {
+			return fmt.Errorf("invalid protected branch pattern %q: %w", branch, err)
+		}
+	}
+	protectedBranchesMu.Lock()
+	protectedBranches = branches
+	protectedBranchesMu.Unlock()
+	return nil
+}
+
+// isProtectedBranch reports whether branch matches a protected branch
+func isProtectedBranch(branch string) bool {
+	protectedBranchesMu.RLock()
+	defer protectedBranchesMu.RUnlock()
+	for _, pattern := range protectedBranches {
+		if ok, _ := path.Match(pattern, branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// checkBranchProtection refuses to change a protected branch. A detached
+// HEAD has no branch and is not protected.
+func checkBranchProtection(dir string) error {
+	branch, err := runGit(dir, "branch", "--show-current")
+	if err != nil {
+		return err
+	}
+	if branch != "" && isProtectedBranch(branch) {
+		return withRecovery(validationError("refusing to commit to protected branch %s (git.protected_branches)", branch), recoverProtectedBranch)
+	}
+	return nil
+}
+
+// runGit runs git in dir (the working directory when empty) and returns its
+// trimmed stdout, or an error carrying stderr
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		detail := strings.TrimSpace(stderr.String())
+		if detail == "" {
+			detail = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], detail)
+	}
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
+
+// stringSlice reads an optional array of strings from tool arguments
+func stringSlice(value interface{}) []string {
+	items, _ := value.([]interface{})
+	var out []string
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// GitDiffTool shows uncommitted changes in the repository
+type GitDiffTool struct {
+	dir string // Repository directory; the working directory when empty
+}
+
+// NewGitDiffTool creates a new GitDiffTool instance
+func NewGitDiffTool() *GitDiffTool {
+	return &GitDiffTool{}
+}
+
+func (t *GitDiffTool) Name() string {
+	return "git_diff"
+}
+
+func (t *GitDiffTool) Description() string {
+	return "Show the git diff of uncommitted changes in the working tree (or staged changes), optionally limited to paths"
+}
+
+func (t *GitDiffTool) ReadOnly() bool {
+	return true
+}
+
+func (t *GitDiffTool) GetParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"staged": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Show staged changes instead of unstaged ones",
+			},
+			"paths": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+				"description": "Limit the diff to these paths",
+			},
+		},
+	}
+}
+
+func (t *GitDiffTool) Execute(args map[string]interface{}) (*ToolResult, error) {
+	gitArgs := []string{"diff"}
+	if staged, _ := args["staged"].(bool); staged {
+		gitArgs = append(gitArgs, "--cached")
+	}
+	if paths := stringSlice(args["paths"]); len(paths) > 0 {
+		gitArgs = append(append(gitArgs, "--"), paths...)
+	}
+
+	diff, err := runGit(t.dir, gitArgs...)
+	if err != nil {
+		return nil, err
+	}
+	if diff == "" {
+		return &ToolResult{
+			LLMContent:    "No changes.",
+			ReturnDisplay: "📄 _No changes._",
+		}, nil
+	}
+
+	diff = maskDotenvDiff(diff)
+	total := len(diff)
+	if total > maxGitDiffBytes {
+		diff = diff[:maxGitDiffBytes] + fmt.Sprintf("\n... (diff truncated, %d of %d bytes shown; pass paths to narrow it)", maxGitDiffBytes, total)
+	}
+	return &ToolResult{
+		LLMContent:    diff,
+		ReturnDisplay: fmt.Sprintf("📄 **git diff** (%d bytes)\n```diff\n%s\n```", total, diff),
+	}, nil
+}
+
+// GitCommitTool stages changes and commits them so the agent can checkpoint
+// its work. It refuses to commit while merge conflicts are unresolved or
+// when the current branch is protected.
+type GitCommitTool struct {
+	dir string // Repository directory; the working directory when empty
+}
+
+// NewGitCommitTool creates a new GitCommitTool instance
+func NewGitCommitTool() *GitCommitTool {
+	return &GitCommitTool{}
+}
+
+func (t *GitCommitTool) Name() string {
+	return "git_commit"
+}
+
+func (t *GitCommitTool) Description() string {
+	return "Stage the given paths (or all changes to tracked files when paths is omitted) and create a git commit with the message. Returns the new commit hash"
+}
+
+func (t *GitCommitTool) ReadOnly() bool {
+	return false
+}
+
+func (t *GitCommitTool) GetParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"message": map[string]interface{}{
+				"type":        "string",
+				"description": "The commit message",
+			},
+			"paths": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+				"description": "Paths to stage; omit to stage all changes to tracked files",
+			},
+		},
+		"required": []string{"message"},
+	}
+}
+
+func (t *GitCommitTool) Execute(args map[string]interface{}) (*ToolResult, error) {
+	message, _ := args["message"].(string)
+	if strings.TrimSpace(message) == "" {
+		return nil, requiredArg("message")
+	}
+
+	if err := checkBranchProtection(t.dir); err != nil {
+		return nil, err
+	}
+
+	conflicts, err := runGit(t.dir, "diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return nil, err
+	}
+	if conflicts != "" {
+		return nil, fmt.Errorf("refusing to commit with unresolved merge conflicts in:\n%s", conflicts)
+	}
+
+	paths := stringSlice(args["paths"])
+	if len(paths) > 0 {
+		_, err = runGit(t.dir, append([]string{"add", "--"}, paths...)...)
+	} else {
+		_, err = runGit(t.dir, "add", "--update")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	staged, err := runGit(t.dir, "diff", "--cached", "--name-only")
+	if err != nil {
+		return nil, err
+	}
+	if staged == "" {
+		return nil, fmt.Errorf("nothing to commit")
+	}
+
+	if _, err := runGit(t.dir, "commit", "--quiet", "-m", message); err != nil {
+		return nil, err
+	}
+	hash, err := runGit(t.dir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	files := strings.Split(staged, "\n")
+	subject := strings.SplitN(message, "\n", 2)[0]
+	return &ToolResult{
+		LLMContent:    fmt.Sprintf("Committed %d file(s) as %s", len(files), hash),
+		ReturnDisplay: fmt.Sprintf("✅ **Committed** `%s` %s\n\n%d file(s): %s", hash[:min(len(hash), 12)], subject, len(files), strings.Join(files, ", ")),
+	}, nil
+}
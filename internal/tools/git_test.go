@@ -0,0 +1,171 @@
+package tools
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestRepo creates a git repository with one committed file
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--quiet", "--initial-branch=main"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test"},
+	} {
+		if _, err := runGit(dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeRepoFile(t, dir, "a.txt", "one\n")
+	if _, err := runGit(dir, "add", "a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runGit(dir, "commit", "--quiet", "-m", "initial"); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func writeRepoFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGitDiffShowsWorkingTreeChanges(t *testing.T) {
+	dir := newTestRepo(t)
+	tool := &GitDiffTool{dir: dir}
+
+	result, err := tool.Execute(map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.LLMContent != "No changes." {
+		t.Errorf("expected no changes, got %q", result.LLMContent)
+	}
+
+	writeRepoFile(t, dir, "a.txt", "two\n")
+	result, err = tool.Execute(map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result.LLMContent, "-one") || !strings.Contains(result.LLMContent, "+two") {
+		t.Errorf("expected the change in the diff, got %q", result.LLMContent)
+	}
+}
+
+func TestGitCommitStagesPathsAndReturnsHash(t *testing.T) {
+	dir := newTestRepo(t)
+	tool := &GitCommitTool{dir: dir}
+
+	writeRepoFile(t, dir, "a.txt", "two\n")
+	writeRepoFile(t, dir, "b.txt", "new\n")
+	writeRepoFile(t, dir, "c.txt", "untracked\n")
+
+	result, err := tool.Execute(map[string]interface{}{
+		"message": "add b",
+		"paths":   []interface{}{"a.txt", "b.txt"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	head, _ := runGit(dir, "rev-parse", "HEAD")
+	if !strings.Contains(result.LLMContent, head) {
+		t.Errorf("expected the commit hash %s in %q", head, result.LLMContent)
+	}
+	files, _ := runGit(dir, "show", "--name-only", "--format=", "HEAD")
+	if files != "a.txt\nb.txt" {
+		t.Errorf("expected a.txt and b.txt committed, got %q", files)
+	}
+	if status, _ := runGit(dir, "status", "--porcelain"); status != "?? c.txt" {
+		t.Errorf("expected only c.txt left untracked, got %q", status)
+	}
+}
+
+func TestGitCommitWithoutPathsStagesTrackedChanges(t *testing.T) {
+	dir := newTestRepo(t)
+	tool := &GitCommitTool{dir: dir}
+
+	if _, err := tool.Execute(map[string]interface{}{"message": "empty"}); err == nil || !strings.Contains(err.Error(), "nothing to commit") {
+		t.Errorf("expected nothing to commit, got %v", err)
+	}
+
+	writeRepoFile(t, dir, "a.txt", "two\n")
+	writeRepoFile(t, dir, "untracked.txt", "x\n")
+	if _, err := tool.Execute(map[string]interface{}{"message": "update a"}); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := runGit(dir, "show", "--name-only", "--format=", "HEAD"); files != "a.txt" {
+		t.Errorf("expected only the tracked file committed, got %q", files)
+	}
+}
+
+func TestGitCommitRefusesMergeConflicts(t *testing.T) {
+	dir := newTestRepo(t)
+
+	runGit(dir, "checkout", "--quiet", "-b", "other")
+	writeRepoFile(t, dir, "a.txt", "other\n")
+	runGit(dir, "commit", "--quiet", "-am", "other")
+	runGit(dir, "checkout", "--quiet", "main")
+	writeRepoFile(t, dir, "a.txt", "main\n")
+	runGit(dir, "commit", "--quiet", "-am", "main")
+	if _, err := runGit(dir, "merge", "other"); err == nil {
+		t.Fatal("expected the merge to conflict")
+	}
+	before, _ := runGit(dir, "rev-parse", "HEAD")
+
+	_, err := (&GitCommitTool{dir: dir}).Execute(map[string]interface{}{"message": "resolve"})
+	if err == nil || !strings.Contains(err.Error(), "merge conflicts") || !strings.Contains(err.Error(), "a.txt") {
+		t.Fatalf("expected a merge conflict refusal naming a.txt, got %v", err)
+	}
+	if after, _ := runGit(dir, "rev-parse", "HEAD"); after != before {
+		t.Error("expected no commit to be created")
+	}
+}
+
+func TestGitCommitRefusesProtectedBranch(t *testing.T) {
+	if err := SetProtectedBranches([]string{"main", "release/*"}); err != nil {
+		t.Fatal(err)
+	}
+	defer SetProtectedBranches(nil)
+
+	dir := newTestRepo(t)
+	tool := &GitCommitTool{dir: dir}
+	before, _ := runGit(dir, "rev-parse", "HEAD")
+
+	writeRepoFile(t, dir, "a.txt", "two\n")
+	_, err := tool.Execute(map[string]interface{}{"message": "on main"})
+	if err == nil || !strings.Contains(err.Error(), "protected branch main") {
+		t.Fatalf("expected a commit on main to be refused, got %v", err)
+	}
+	if !strings.Contains(RecoveryHint(err), "feature branch") {
+		t.Errorf("expected the refusal to suggest a feature branch, got %q", RecoveryHint(err))
+	}
+	if after, _ := runGit(dir, "rev-parse", "HEAD"); after != before {
+		t.Error("expected no commit on the protected branch")
+	}
+
+	runGit(dir, "checkout", "--quiet", "-b", "release/1.0")
+	if _, err := tool.Execute(map[string]interface{}{"message": "on release"}); err == nil {
+		t.Error("expected a commit on a branch matching release/* to be refused")
+	}
+
+	runGit(dir, "checkout", "--quiet", "-b", "feature/update-a")
+	if _, err := tool.Execute(map[string]interface{}{"message": "on feature"}); err != nil {
+		t.Fatalf("expected the commit on a feature branch to proceed, got %v", err)
+	}
+	if subject, _ := runGit(dir, "log", "-1", "--format=%s"); subject != "on feature" {
+		t.Errorf("expected the feature branch commit, got %q", subject)
+	}
+}
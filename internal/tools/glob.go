@@ -46,7 +46,7 @@ func (t *GlobTool) GetParameters() map[string]interface{} {
 func (t *GlobTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 	pattern, ok := args["pattern"].(string)
 	if !ok {
-		return nil, fmt.Errorf("pattern is required")
+		return nil, requiredArg("pattern")
 	}
 
 	path, _ := args["path"].(string)
@@ -62,7 +62,7 @@ func (t *GlobTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 		fullPattern := filepath.Join(path, pattern)
 		globMatches, err := filepath.Glob(fullPattern)
 		if err != nil {
-			return nil, fmt.Errorf("invalid glob pattern: %w", err)
+			return nil, validationError("invalid glob pattern: %w", err)
 		}
 		matches = globMatches
 	} else {
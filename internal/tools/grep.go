@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/trknhr/agenticode/internal/redact"
 )
 
 type GrepTool struct{}
@@ -43,15 +45,25 @@ func (t *GrepTool) GetParameters() map[string]interface{} {
 				"type":        "string",
 				"description": "File pattern to include in the search (e.g. '*.js', '*.{ts,tsx}')",
 			},
+			"multiline": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Match across lines: the whole file is searched and '.' also matches newlines. Each match is reported at its starting line. Files over 1MB are skipped in this mode.",
+			},
 		},
 		"required": []string{"pattern"},
 	}
 }
 
 func (t *GrepTool) Execute(args map[string]interface{}) (*ToolResult, error) {
+	return t.ExecuteWithProgress(args, nil)
+}
+
+// ExecuteWithProgress searches like Execute, reporting the number of files
+// scanned as it walks the tree
+func (t *GrepTool) ExecuteWithProgress(args map[string]interface{}, report ProgressReporter) (*ToolResult, error) {
 	pattern, ok := args["pattern"].(string)
 	if !ok {
-		return nil, fmt.Errorf("pattern is required")
+		return nil, requiredArg("pattern")
 	}
 
 	path, _ := args["path"].(string)
@@ -60,15 +72,21 @@ func (t *GrepTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 	}
 
 	include, _ := args["include"].(string)
+	multiline, _ := args["multiline"].(bool)
 
 	// Compile the regex pattern
-	re, err := regexp.Compile(pattern)
+	expr := pattern
+	if multiline {
+		expr = "(?s)" + pattern
+	}
+	re, err := regexp.Compile(expr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid regex pattern: %w", err)
+		return nil, validationError("invalid regex pattern: %w", err)
 	}
 
 	var matches []map[string]interface{}
 	totalMatches := 0
+	scanned := 0
 
 	err = filepath.Walk(path, func(filePath string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -88,27 +106,26 @@ func (t *GrepTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 			}
 		}
 
-		// Search in file
-		file, err := os.Open(filePath)
-		if err != nil {
-			return nil // Skip files we can't open
+		scanned++
+		if scanned%progressEvery == 0 {
+			report.emit(fmt.Sprintf("searched %d files", scanned), scanned, 0)
 		}
-		defer file.Close()
 
-		scanner := bufio.NewScanner(file)
-		lineNum := 0
+		// Search in file
 		var fileMatches []map[string]interface{}
-
-		for scanner.Scan() {
-			lineNum++
-			line := scanner.Text()
-			if re.MatchString(line) {
-				fileMatches = append(fileMatches, map[string]interface{}{
-					"line_number": lineNum,
-					"line":        line,
-					"match":       re.FindString(line),
-				})
-				totalMatches++
+		if multiline {
+			if info.Size() > maxMultilineFileSize {
+				return nil // Too large to load whole
+			}
+			fileMatches = searchMultiline(filePath, re)
+		} else {
+			fileMatches = searchLines(filePath, re)
+		}
+		totalMatches += len(fileMatches)
+		if maskDotenv.Load() && isDotenvFile(filePath) {
+			for _, m := range fileMatches {
+				m["line"] = redact.Dotenv(m["line"].(string))
+				m["match"] = m["line"]
 			}
 		}
 
@@ -173,3 +190,58 @@ func (t *GrepTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 		Error:         nil,
 	}, nil
 }
+
+// maxMultilineFileSize caps the files loaded whole in multiline mode
+const maxMultilineFileSize = 1024 * 1024
+
+// searchLines matches re against each line of the file
+func searchLines(filePath string, re *regexp.Regexp) []map[string]interface{} {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil // Skip files we can't open
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	var fileMatches []map[string]interface{}
+
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if re.MatchString(line) {
+			fileMatches = append(fileMatches, map[string]interface{}{
+				"line_number": lineNum,
+				"line":        line,
+				"match":       re.FindString(line),
+			})
+		}
+	}
+
+	return fileMatches
+}
+
+// searchMultiline matches re against the whole file and reports each match
+// at the line where it starts
+func searchMultiline(filePath string, re *regexp.Regexp) []map[string]interface{} {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil // Skip files we can't open
+	}
+	content := string(data)
+
+	var fileMatches []map[string]interface{}
+	for _, loc := range re.FindAllStringIndex(content, -1) {
+		if loc[0] == loc[1] {
+			continue // Empty matches carry no information
+		}
+		match := content[loc[0]:loc[1]]
+		fileMatches = append(fileMatches, map[string]interface{}{
+			"line_number": strings.Count(content[:loc[0]], "\n") + 1,
+			"line":        strings.TrimRight(match, "\n"),
+			"match":       match,
+		})
+	}
+
+	return fileMatches
+}
@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGrepToolMultiline(t *testing.T) {
+	tool := NewGrepTool()
+	tmpDir := t.TempDir()
+	source := "package main\n\nfunc handle(w http.ResponseWriter,\n\tr *http.Request) {\n}\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pattern := `func handle\(w http\.ResponseWriter,\s+r \*http\.Request\)`
+
+	t.Run("line mode cannot match across lines", func(t *testing.T) {
+		result, err := tool.Execute(map[string]interface{}{"pattern": pattern, "path": tmpDir})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(result.LLMContent, "Found 0 matches") {
+			t.Errorf("expected no matches, got %q", result.LLMContent)
+		}
+	})
+
+	t.Run("multiline mode reports the starting line", func(t *testing.T) {
+		result, err := tool.Execute(map[string]interface{}{"pattern": pattern, "path": tmpDir, "multiline": true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(result.LLMContent, "Found 1 matches in 1 files") {
+			t.Fatalf("expected one match, got %q", result.LLMContent)
+		}
+		if !strings.Contains(result.LLMContent, "Line 3: func handle(w http.ResponseWriter,") {
+			t.Errorf("expected the match to start at line 3, got %q", result.LLMContent)
+		}
+	})
+
+	t.Run("multiline mode skips large files", func(t *testing.T) {
+		large := strings.Repeat("x", maxMultilineFileSize) + "\n" + source
+		if err := os.WriteFile(filepath.Join(tmpDir, "large.go"), []byte(large), 0644); err != nil {
+			t.Fatal(err)
+		}
+		result, err := tool.Execute(map[string]interface{}{"pattern": pattern, "path": tmpDir, "multiline": true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Contains(result.LLMContent, "large.go") {
+			t.Errorf("expected large.go to be skipped, got %q", result.LLMContent)
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/alecthomas/chroma/v2"
+	chromaformatters "github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// highlightStyle is the chroma style used for terminal output
+const highlightStyle = "monokai"
+
+// highlightDisplay enables ANSI syntax highlighting in file displays. It
+// defaults to on when stdout is a terminal and NO_COLOR is unset.
+var highlightDisplay atomic.Bool
+
+func init() {
+	highlightDisplay.Store(os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout))
+}
+
+// SetSyntaxHighlight turns ANSI syntax highlighting of displayed files on or off
+func SetSyntaxHighlight(enabled bool) {
+	highlightDisplay.Store(enabled)
+}
+
+// isTerminal reports whether f is a character device rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// languageByExt maps file extensions to Markdown code fence languages
+var languageByExt = map[string]string{
+	".go":    "go",
+	".py":    "python",
+	".pyi":   "python",
+	".js":    "javascript",
+	".mjs":   "javascript",
+	".cjs":   "javascript",
+	".jsx":   "jsx",
+	".ts":    "typescript",
+	".tsx":   "tsx",
+	".rs":    "rust",
+	".java":  "java",
+	".kt":    "kotlin",
+	".swift": "swift",
+	".c":     "c",
+	".h":     "c",
+	".cc":    "cpp",
+	".cpp":   "cpp",
+	".hpp":   "cpp",
+	".cs":    "csharp",
+	".rb":    "ruby",
+	".php":   "php",
+	".sh":    "bash",
+	".bash":  "bash",
+	".zsh":   "zsh",
+	".sql":   "sql",
+	".html":  "html",
+	".css":   "css",
+	".scss":  "scss",
+	".json":  "json",
+	".yaml":  "yaml",
+	".yml":   "yaml",
+	".toml":  "toml",
+	".xml":   "xml",
+	".md":    "markdown",
+	".proto": "protobuf",
+	".tf":    "hcl",
+	".lua":   "lua",
+}
+
+// languageByName covers files recognised by name rather than extension
+var languageByName = map[string]string{
+	"Dockerfile":  "dockerfile",
+	"Makefile":    "makefile",
+	"go.mod":      "go",
+	"CMakeLists":  "cmake",
+	"Jenkinsfile": "groovy",
+}
+
+// languageForPath returns the code fence language for a file, or "" when it
+// is not recognised
+func languageForPath(path string) string {
+	base := filepath.Base(path)
+	if lang, ok := languageByName[base]; ok {
+		return lang
+	}
+	return languageByExt[strings.ToLower(filepath.Ext(base))]
+}
+
+// highlight colors content for the terminal when highlighting is enabled,
+// returning it unchanged otherwise or when no lexer fits
+func highlight(content, path, lang string) string {
+	if !highlightDisplay.Load() {
+		return content
+	}
+
+	var lexer chroma.Lexer
+	if lang != "" {
+		lexer = lexers.Get(lang)
+	}
+	if lexer == nil {
+		lexer = lexers.Match(filepath.Base(path))
+	}
+	if lexer == nil {
+		return content
+	}
+
+	iterator, err := chroma.Coalesce(lexer).Tokenise(nil, content)
+	if err != nil {
+		return content
+	}
+	var b strings.Builder
+	if err := chromaformatters.TTY256.Format(&b, styles.Get(highlightStyle), iterator); err != nil {
+		return content
+	}
+	return b.String()
+}
+
+// fencedFile renders file content as a Markdown code block tagged with its
+// language, numbering lines from firstLine (0 leaves them unnumbered)
+func fencedFile(path, content string, firstLine int) string {
+	lang := languageForPath(path)
+	body := highlight(content, path, lang)
+	if firstLine > 0 {
+		lines := strings.Split(body, "\n")
+		for i, line := range lines {
+			lines[i] = fmt.Sprintf("%4d | %s", firstLine+i, line)
+		}
+		body = strings.Join(lines, "\n")
+	}
+	return "```" + lang + "\n" + body + "\n```"
+}
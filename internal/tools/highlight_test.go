@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLanguageForPath(t *testing.T) {
+	cases := map[string]string{
+		"main.go":            "go",
+		"scripts/build.py":   "python",
+		"web/app.TSX":        "tsx",
+		"src/lib.rs":         "rust",
+		"config/app.yml":     "yaml",
+		"deploy/Dockerfile":  "dockerfile",
+		"Makefile":           "makefile",
+		"notes.unknownthing": "",
+		"LICENSE":            "",
+	}
+	for path, want := range cases {
+		if got := languageForPath(path); got != want {
+			t.Errorf("%s: expected %q, got %q", path, want, got)
+		}
+	}
+}
+
+func TestFencedFileHighlightsOnlyWhenEnabled(t *testing.T) {
+	defer SetSyntaxHighlight(highlightDisplay.Load())
+	source := "package main\n\nfunc main() {}"
+
+	SetSyntaxHighlight(false)
+	plain := fencedFile("main.go", source, 1)
+	if !strings.HasPrefix(plain, "```go\n   1 | package main\n") || strings.Contains(plain, "\x1b[") {
+		t.Errorf("expected a plain go block with line numbers, got %q", plain)
+	}
+
+	SetSyntaxHighlight(true)
+	colored := fencedFile("main.go", source, 0)
+	if !strings.HasPrefix(colored, "```go\n") || !strings.Contains(colored, "\x1b[") {
+		t.Errorf("expected ANSI highlighting inside a go block, got %q", colored)
+	}
+}
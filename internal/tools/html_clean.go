@@ -0,0 +1,120 @@
+package tools
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// HTMLCleanOptions controls how fetched pages are simplified before they are
+// converted to markdown, trading layout fidelity for fewer tokens
+type HTMLCleanOptions struct {
+	FlattenTables    bool // Render each table row as a plain "a | b | c" line
+	StripBoilerplate bool // Drop navigation, headers, footers, sidebars and similar chrome
+	MaxNestingDepth  int  // Flatten lists and quotes nested deeper than this (0 = unlimited)
+}
+
+// DefaultHTMLCleanOptions returns the cleaning applied by web_fetch by default
+func DefaultHTMLCleanOptions() HTMLCleanOptions {
+	return HTMLCleanOptions{
+		FlattenTables:    true,
+		StripBoilerplate: true,
+		MaxNestingDepth:  3,
+	}
+}
+
+// boilerplateSelector matches page chrome by element and ARIA role
+const boilerplateSelector = "nav, header, footer, aside, noscript, iframe, " +
+	"[role=navigation], [role=banner], [role=contentinfo], [role=complementary], [aria-hidden=true]"
+
+// boilerplateTokens are class or id names, or their last word, that mark
+// page chrome
+var boilerplateTokens = map[string]bool{
+	"nav": true, "navbar": true, "navigation": true, "menu": true, "breadcrumb": true, "breadcrumbs": true,
+	"header": true, "footer": true, "sidebar": true, "cookie": true, "cookies": true, "banner": true,
+	"advert": true, "ads": true, "social": true, "share": true, "newsletter": true, "popup": true,
+}
+
+var attrTokenSplitter = regexp.MustCompile(`[\s_-]+`)
+
+// cleanHTML removes boilerplate and deep nesting from the parsed document in
+// place. Tables are flattened during conversion by tableRule.
+func cleanHTML(doc *goquery.Document, opts HTMLCleanOptions) {
+	doc.Find("script, style, template, svg").Remove()
+
+	if opts.StripBoilerplate {
+		doc.Find(boilerplateSelector).Each(func(_ int, s *goquery.Selection) {
+			// Article headers usually hold the title, so keep those
+			name := goquery.NodeName(s)
+			if (name == "header" || name == "footer") && s.ParentsFiltered("article, main").Length() > 0 {
+				return
+			}
+			s.Remove()
+		})
+		doc.Find("[class], [id]").Each(func(_ int, s *goquery.Selection) {
+			if isBoilerplate(s) {
+				s.Remove()
+			}
+		})
+	}
+
+	if opts.MaxNestingDepth > 0 {
+		// Outer elements come first, so flattening one also removes
+		// everything nested inside it
+		doc.Find("ul, ol, blockquote").Each(func(_ int, s *goquery.Selection) {
+			if s.Parents().Filter("ul, ol, blockquote").Length() >= opts.MaxNestingDepth {
+				s.ReplaceWithHtml("<p>" + html.EscapeString(collapseWhitespace(s.Text())) + "</p>")
+			}
+		})
+	}
+}
+
+// isBoilerplate reports whether an element's class or id marks it as page
+// chrome: "sidebar" and "site-sidebar" do, "menu-item" and "share-price" do
+// not. Elements holding or inside the main content are never removed.
+func isBoilerplate(s *goquery.Selection) bool {
+	switch goquery.NodeName(s) {
+	case "html", "body", "main", "article":
+		return false
+	}
+	if s.Find("main, article").Length() > 0 || s.ParentsFiltered("article").Length() > 0 {
+		return false
+	}
+
+	class, _ := s.Attr("class")
+	id, _ := s.Attr("id")
+	for _, name := range strings.Fields(strings.ToLower(class + " " + id)) {
+		words := attrTokenSplitter.Split(name, -1)
+		if boilerplateTokens[words[len(words)-1]] {
+			return true
+		}
+	}
+	return false
+}
+
+// tableRule renders each table row as one line of pipe-separated cells
+// instead of a markdown table
+var tableRule = md.Rule{
+	Filter: []string{"table"},
+	Replacement: func(_ string, table *goquery.Selection, _ *md.Options) *string {
+		var rows []string
+		table.Find("tr").Each(func(_ int, row *goquery.Selection) {
+			var cells []string
+			row.Find("th, td").Each(func(_ int, cell *goquery.Selection) {
+				cells = append(cells, collapseWhitespace(cell.Text()))
+			})
+			if len(cells) > 0 {
+				rows = append(rows, strings.Join(cells, " | "))
+			}
+		})
+		text := "\n\n" + strings.Join(rows, "\n") + "\n\n"
+		return &text
+	},
+}
+
+func collapseWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
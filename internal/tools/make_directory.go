@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+)
+
+type MakeDirectoryTool struct{}
+
+func NewMakeDirectoryTool() *MakeDirectoryTool {
+	return &MakeDirectoryTool{}
+}
+
+func (t *MakeDirectoryTool) Name() string {
+	return "make_directory"
+}
+
+func (t *MakeDirectoryTool) Description() string {
+	return "Create a directory (including missing parents when recursive is true), e.g. to scaffold a package layout"
+}
+
+func (t *MakeDirectoryTool) ReadOnly() bool {
+	return false
+}
+
+func (t *MakeDirectoryTool) GetParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "The directory path to create",
+			},
+			"recursive": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Create missing parent directories as needed (default true)",
+			},
+		},
+		"required": []string{"path"},
+	}
+}
+
+func (t *MakeDirectoryTool) Execute(args map[string]interface{}) (*ToolResult, error) {
+	path, ok := args["path"].(string)
+	if !ok || path == "" {
+		return nil, requiredArg("path")
+	}
+
+	recursive := true
+	if r, ok := args["recursive"].(bool); ok {
+		recursive = r
+	}
+
+	// Nothing to do if the directory is already there
+	if info, err := os.Stat(path); err == nil {
+		if !info.IsDir() {
+			return nil, validationError("path exists and is not a directory: %s", path)
+		}
+		return &ToolResult{
+			LLMContent:    fmt.Sprintf("Directory already exists: %s", path),
+			ReturnDisplay: fmt.Sprintf("📁 Directory already exists: `%s`", path),
+		}, nil
+	}
+
+	var err error
+	if recursive {
+		err = os.MkdirAll(path, 0755)
+	} else {
+		err = os.Mkdir(path, 0755)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	return &ToolResult{
+		LLMContent:    fmt.Sprintf("Successfully created directory %s", path),
+		ReturnDisplay: fmt.Sprintf("✅ Created directory: `%s`", path),
+	}, nil
+}
@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMakeDirectoryTool(t *testing.T) {
+	tool := NewMakeDirectoryTool()
+	tmpDir := t.TempDir()
+
+	t.Run("creates nested directories", func(t *testing.T) {
+		target := filepath.Join(tmpDir, "pkg", "internal", "service")
+
+		result, err := tool.Execute(map[string]interface{}{"path": target})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Error != nil {
+			t.Fatalf("unexpected result error: %v", result.Error)
+		}
+
+		info, err := os.Stat(target)
+		if err != nil {
+			t.Fatalf("directory was not created: %v", err)
+		}
+		if !info.IsDir() {
+			t.Fatalf("expected %s to be a directory", target)
+		}
+	})
+
+	t.Run("existing directory is not an error", func(t *testing.T) {
+		if _, err := tool.Execute(map[string]interface{}{"path": tmpDir}); err != nil {
+			t.Fatalf("unexpected error for existing directory: %v", err)
+		}
+	})
+
+	t.Run("non-recursive fails without parent", func(t *testing.T) {
+		target := filepath.Join(tmpDir, "missing", "child")
+		_, err := tool.Execute(map[string]interface{}{"path": target, "recursive": false})
+		if err == nil {
+			t.Fatal("expected error when parent directory is missing")
+		}
+	})
+
+	t.Run("path pointing at a file fails", func(t *testing.T) {
+		file := filepath.Join(tmpDir, "file.txt")
+		if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tool.Execute(map[string]interface{}{"path": file}); err == nil {
+			t.Fatal("expected error when path is a file")
+		}
+	})
+}
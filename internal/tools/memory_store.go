@@ -0,0 +1,150 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryEntry is a note the agent saved for later in the session
+type MemoryEntry struct {
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MemoryStore is a key-value scratchpad for the agent. It lives outside the
+// conversation, so notes survive compaction, and once loaded from a file it
+// saves every change there, so they survive restarts too.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]MemoryEntry
+	path    string // File the notes are saved to; empty keeps them in memory
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]MemoryEntry)}
+}
+
+// GlobalMemoryStore is the singleton instance for the session's notes
+var GlobalMemoryStore = NewMemoryStore()
+
+// Load reads the notes saved in path, which need not exist yet, and saves
+// every later change there (.agenticode/memory.json in the project)
+func (s *MemoryStore) Load(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make(map[string]MemoryEntry)
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read notes: %w", err)
+	}
+	if err == nil {
+		var saved []MemoryEntry
+		if err := json.Unmarshal(data, &saved); err != nil {
+			return fmt.Errorf("failed to parse notes in %s: %w", path, err)
+		}
+		for _, entry := range saved {
+			entries[entry.Key] = entry
+		}
+	}
+	s.entries = entries
+	s.path = path
+	return nil
+}
+
+// save writes all entries to the store's file, if it has one. The caller
+// holds the lock.
+func (s *MemoryStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+	entries := make([]MemoryEntry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to save notes: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to save notes: %w", err)
+	}
+	return nil
+}
+
+// Write stores value under key, replacing any previous value
+func (s *MemoryStore) Write(key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = MemoryEntry{Key: key, Value: value, UpdatedAt: time.Now()}
+	return s.save()
+}
+
+// Delete removes key and reports whether it existed
+func (s *MemoryStore) Delete(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.entries[key]; !exists {
+		return false, nil
+	}
+	delete(s.entries, key)
+	return true, s.save()
+}
+
+// Read returns the entry stored under key
+func (s *MemoryStore) Read(key string) (MemoryEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.entries[key]
+	return entry, exists
+}
+
+// ReadAll returns all entries sorted by key
+func (s *MemoryStore) ReadAll() []MemoryEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]MemoryEntry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries
+}
+
+// Clear removes all entries and detaches the store from its file, which is
+// left as it is (useful for testing)
+func (s *MemoryStore) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = make(map[string]MemoryEntry)
+	s.path = ""
+}
+
+// Format renders all entries as "- key: value" lines, or "" when empty
+func (s *MemoryStore) Format() string {
+	var b strings.Builder
+	for _, entry := range s.ReadAll() {
+		fmt.Fprintf(&b, "- %s: %s\n", entry.Key, entry.Value)
+	}
+	return b.String()
+}
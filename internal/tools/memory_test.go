@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMemoryWriteReadRoundTrip(t *testing.T) {
+	GlobalMemoryStore.Clear()
+	defer GlobalMemoryStore.Clear()
+
+	if _, err := NewMemoryWriteTool().Execute(map[string]interface{}{
+		"key":   "auth",
+		"value": "the auth logic lives in auth/jwt.go",
+	}); err != nil {
+		t.Fatalf("memory_write failed: %v", err)
+	}
+
+	reader := NewMemoryReadTool()
+	result, err := reader.Execute(map[string]interface{}{"key": "auth"})
+	if err != nil {
+		t.Fatalf("memory_read failed: %v", err)
+	}
+	if result.LLMContent != "the auth logic lives in auth/jwt.go" {
+		t.Errorf("unexpected note: %q", result.LLMContent)
+	}
+
+	result, err = reader.Execute(map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("memory_read failed: %v", err)
+	}
+	if !strings.Contains(result.LLMContent, "- auth: the auth logic lives in auth/jwt.go") {
+		t.Errorf("expected the note in the listing, got %q", result.LLMContent)
+	}
+
+	// An empty value deletes the note
+	if _, err := NewMemoryWriteTool().Execute(map[string]interface{}{"key": "auth", "value": ""}); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if _, err := reader.Execute(map[string]interface{}{"key": "auth"}); err == nil {
+		t.Error("expected the deleted note to be gone")
+	}
+}
+
+func TestMemoryOverwriteAndPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".agenticode", "memory.json")
+	store := NewMemoryStore()
+	if err := store.Load(path); err != nil {
+		t.Fatalf("loading a missing file should start empty, got %v", err)
+	}
+
+	if err := store.Write("api", "GET /users returns {id, name}"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Write("api", "GET /users returns {id, name, email}"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Write("config", "loaded in cmd/root.go"); err != nil {
+		t.Fatal(err)
+	}
+	if entry, ok := store.Read("api"); !ok || entry.Value != "GET /users returns {id, name, email}" {
+		t.Errorf("expected the note to be overwritten, got %+v", entry)
+	}
+
+	reloaded := NewMemoryStore()
+	if err := reloaded.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	entries := reloaded.ReadAll()
+	if len(entries) != 2 || entries[0].Key != "api" || entries[0].Value != "GET /users returns {id, name, email}" || entries[1].Key != "config" {
+		t.Errorf("expected both notes after a reload, got %+v", entries)
+	}
+
+	if deleted, err := reloaded.Delete("config"); err != nil || !deleted {
+		t.Fatalf("expected config to be deleted, got %v, %v", deleted, err)
+	}
+	again := NewMemoryStore()
+	if err := again.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := again.Read("config"); ok {
+		t.Error("expected the deletion to be saved")
+	}
+
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewMemoryStore().Load(path); err == nil {
+		t.Error("expected an error for a corrupt notes file")
+	}
+}
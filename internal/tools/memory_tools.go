@@ -0,0 +1,134 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MemoryWriteTool saves a note to the session scratchpad
+type MemoryWriteTool struct{}
+
+// NewMemoryWriteTool creates a new MemoryWriteTool instance
+func NewMemoryWriteTool() *MemoryWriteTool {
+	return &MemoryWriteTool{}
+}
+
+func (t *MemoryWriteTool) Name() string {
+	return "memory_write"
+}
+
+func (t *MemoryWriteTool) Description() string {
+	return "Save a note for later (e.g. where key logic lives or an API's shape). Notes survive conversation compaction and are kept for later sessions in this project. Writing an existing key replaces the note; an empty value deletes it"
+}
+
+func (t *MemoryWriteTool) ReadOnly() bool {
+	return false
+}
+
+func (t *MemoryWriteTool) GetParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"key": map[string]interface{}{
+				"type":        "string",
+				"description": "Short name for the note, e.g. \"auth_location\"",
+			},
+			"value": map[string]interface{}{
+				"type":        "string",
+				"description": "The note to remember; empty to delete the key",
+			},
+		},
+		"required": []string{"key", "value"},
+	}
+}
+
+func (t *MemoryWriteTool) Execute(args map[string]interface{}) (*ToolResult, error) {
+	key, _ := args["key"].(string)
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, requiredArg("key")
+	}
+	value, ok := args["value"].(string)
+	if !ok {
+		return nil, requiredArg("value")
+	}
+
+	if value == "" {
+		deleted, err := GlobalMemoryStore.Delete(key)
+		if err != nil {
+			return nil, err
+		}
+		if !deleted {
+			return nil, fmt.Errorf("no note named %q", key)
+		}
+		return &ToolResult{
+			LLMContent:    fmt.Sprintf("Deleted note %q", key),
+			ReturnDisplay: fmt.Sprintf("🧠 Forgot `%s`", key),
+		}, nil
+	}
+
+	if err := GlobalMemoryStore.Write(key, value); err != nil {
+		return nil, err
+	}
+	return &ToolResult{
+		LLMContent:    fmt.Sprintf("Saved note %q", key),
+		ReturnDisplay: fmt.Sprintf("🧠 Remembered `%s`: %s", key, value),
+	}, nil
+}
+
+// MemoryReadTool reads notes from the session scratchpad
+type MemoryReadTool struct{}
+
+// NewMemoryReadTool creates a new MemoryReadTool instance
+func NewMemoryReadTool() *MemoryReadTool {
+	return &MemoryReadTool{}
+}
+
+func (t *MemoryReadTool) Name() string {
+	return "memory_read"
+}
+
+func (t *MemoryReadTool) Description() string {
+	return "Read notes saved with memory_write. Omit key to list all notes"
+}
+
+func (t *MemoryReadTool) ReadOnly() bool {
+	return true
+}
+
+func (t *MemoryReadTool) GetParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"key": map[string]interface{}{
+				"type":        "string",
+				"description": "The note to read (optional)",
+			},
+		},
+	}
+}
+
+func (t *MemoryReadTool) Execute(args map[string]interface{}) (*ToolResult, error) {
+	if key, _ := args["key"].(string); key != "" {
+		entry, ok := GlobalMemoryStore.Read(key)
+		if !ok {
+			return nil, fmt.Errorf("no note named %q", key)
+		}
+		return &ToolResult{
+			LLMContent:    entry.Value,
+			ReturnDisplay: fmt.Sprintf("🧠 `%s`: %s", entry.Key, entry.Value),
+		}, nil
+	}
+
+	notes := GlobalMemoryStore.Format()
+	if notes == "" {
+		return &ToolResult{
+			LLMContent:    "No notes saved yet.",
+			ReturnDisplay: "🧠 _No notes saved yet._",
+		}, nil
+	}
+	return &ToolResult{
+		LLMContent:    "Saved notes:\n" + notes,
+		ReturnDisplay: "🧠 **Saved notes:**\n" + notes,
+	}, nil
+}
@@ -25,6 +25,20 @@ func (t *MultiEditTool) ReadOnly() bool {
 	return false
 }
 
+// DangerousCall flags file creations too large to be approved automatically
+func (t *MultiEditTool) DangerousCall(args map[string]interface{}) string {
+	edits, _ := args["edits"].([]interface{})
+	if len(edits) == 0 {
+		return ""
+	}
+	firstEdit, _ := edits[0].(map[string]interface{})
+	if oldString, _ := firstEdit["old_string"].(string); oldString != "" {
+		return ""
+	}
+	newString, _ := firstEdit["new_string"].(string)
+	return largeWriteWarning(len(newString))
+}
+
 func (t *MultiEditTool) GetParameters() map[string]interface{} {
 	return map[string]interface{}{
 		"type": "object",
@@ -52,6 +66,10 @@ func (t *MultiEditTool) GetParameters() map[string]interface{} {
 							"description": "Replace all occurrences of old_string (default false)",
 							"default":     false,
 						},
+						"expected_replacements": map[string]interface{}{
+							"type":        "integer",
+							"description": "With replace_all, the number of occurrences you expect; the edit fails if the file has a different number",
+						},
 					},
 					"required": []string{"old_string", "new_string"},
 				},
@@ -64,25 +82,25 @@ func (t *MultiEditTool) GetParameters() map[string]interface{} {
 func (t *MultiEditTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 	filePath, ok := args["file_path"].(string)
 	if !ok {
-		return nil, fmt.Errorf("file_path is required and must be a string")
+		return nil, validationError("file_path is required and must be a string")
 	}
 
 	editsRaw, ok := args["edits"]
 	if !ok {
-		return nil, fmt.Errorf("edits is required")
+		return nil, requiredArg("edits")
 	}
 
 	edits, ok := editsRaw.([]interface{})
 	if !ok {
-		return nil, fmt.Errorf("edits must be an array")
+		return nil, validationError("edits must be an array")
 	}
 
 	if len(edits) == 0 {
-		return nil, fmt.Errorf("edits array cannot be empty")
+		return nil, validationError("edits array cannot be empty")
 	}
 
 	// Read the file
-	content, err := os.ReadFile(filePath)
+	content, enc, err := readTextFile(filePath)
 	if err != nil {
 		// Check if file doesn't exist and first edit has empty old_string (file creation)
 		if os.IsNotExist(err) && len(edits) > 0 {
@@ -91,47 +109,53 @@ func (t *MultiEditTool) Execute(args map[string]interface{}) (*ToolResult, error
 				oldString, _ := firstEdit["old_string"].(string)
 				if oldString == "" {
 					// This is a file creation, start with empty content
-					content = []byte{}
+					content = ""
 				} else {
-					return nil, fmt.Errorf("failed to read file: %w", err)
+					return nil, fileError("read", filePath, err)
 				}
 			} else {
-				return nil, fmt.Errorf("failed to read file: %w", err)
+				return nil, fileError("read", filePath, err)
 			}
 		} else {
-			return nil, fmt.Errorf("failed to read file: %w", err)
+			return nil, fileError("read", filePath, err)
 		}
 	}
 
-	fileContent := string(content)
+	fileContent := content
 	originalContent := fileContent
 
 	// Track all replacements
 	totalReplacements := 0
+	created := false
 	editResults := []string{}
 
 	// Apply each edit in sequence
 	for i, editRaw := range edits {
 		edit, ok := editRaw.(map[string]interface{})
 		if !ok {
-			return nil, fmt.Errorf("edit at index %d must be an object", i)
+			return nil, validationError("edit at index %d must be an object", i)
 		}
 
 		oldString, ok := edit["old_string"].(string)
 		if !ok {
-			return nil, fmt.Errorf("old_string is required for edit at index %d", i)
+			return nil, validationError("old_string is required for edit at index %d", i)
 		}
 
 		newString, ok := edit["new_string"].(string)
 		if !ok {
-			return nil, fmt.Errorf("new_string is required for edit at index %d", i)
+			return nil, validationError("new_string is required for edit at index %d", i)
 		}
 
 		replaceAll, _ := edit["replace_all"].(bool)
+		expected, hasExpected, err := expectedReplacements(edit)
+		if err != nil {
+			return nil, fmt.Errorf("edit at index %d: %w", i, err)
+		}
 
 		// Special case for file creation
 		if i == 0 && oldString == "" && originalContent == "" {
 			fileContent = newString
+			created = true
 			editResults = append(editResults, "Created new file")
 			totalReplacements++
 			continue
@@ -139,18 +163,21 @@ func (t *MultiEditTool) Execute(args map[string]interface{}) (*ToolResult, error
 
 		// Check if old_string and new_string are the same
 		if oldString == newString {
-			return nil, fmt.Errorf("edit at index %d: old_string and new_string are identical", i)
+			return nil, validationError("edit at index %d: old_string and new_string are identical", i)
 		}
 
 		// Check if old_string exists in the current content
 		if !strings.Contains(fileContent, oldString) {
-			return nil, fmt.Errorf("edit at index %d: old_string not found in file", i)
+			return nil, withRecovery(validationError("edit at index %d: old_string not found in file", i), recoverReread)
 		}
 
 		// Check if old_string is unique (when not replace_all)
 		occurrences := strings.Count(fileContent, oldString)
+		if hasExpected && occurrences != expected {
+			return nil, withRecovery(validationError("edit at index %d: expected %d replacement(s) but old_string occurs %d time(s) in the file; re-read the file and adjust the edit", i, expected, occurrences), recoverReread)
+		}
 		if !replaceAll && occurrences > 1 {
-			return nil, fmt.Errorf("edit at index %d: old_string is not unique in the file (found %d occurrences). Use replace_all=true or provide more context", i, occurrences)
+			return nil, withRecovery(validationError("edit at index %d: old_string is not unique in the file (found %d occurrences). Use replace_all=true or provide more context", i, occurrences), recoverAmbiguous)
 		}
 
 		// Perform replacement
@@ -169,7 +196,13 @@ func (t *MultiEditTool) Execute(args map[string]interface{}) (*ToolResult, error
 
 	// Check if content actually changed
 	if fileContent == originalContent && originalContent != "" {
-		return nil, fmt.Errorf("no changes made after applying all edits")
+		return nil, validationError("no changes made after applying all edits")
+	}
+
+	if created {
+		if err := checkWriteSize(filePath, len(fileContent)); err != nil {
+			return nil, err
+		}
 	}
 
 	// Create directory if needed
@@ -182,7 +215,7 @@ func (t *MultiEditTool) Execute(args map[string]interface{}) (*ToolResult, error
 	}
 
 	// Write the updated content back
-	err = os.WriteFile(filePath, []byte(fileContent), 0644)
+	encodingNote, err := writeTextFile(filePath, fileContent, enc)
 	if err != nil {
 		return nil, fmt.Errorf("failed to write file: %w", err)
 	}
@@ -190,9 +223,11 @@ func (t *MultiEditTool) Execute(args map[string]interface{}) (*ToolResult, error
 	// Build result message
 	resultDetails := strings.Join(editResults, "\n")
 
-	return &ToolResult{
-		LLMContent:    fmt.Sprintf("Successfully applied %d edits to %s with %d total replacements", len(edits), filePath, totalReplacements),
+	result := &ToolResult{
+		LLMContent:    fmt.Sprintf("Successfully applied %d edits to %s with %d total replacements%s", len(edits), filePath, totalReplacements, encodingNote),
 		ReturnDisplay: fmt.Sprintf("✅ **Multi-edited** `%s`\n\nApplied **%d edits** with **%d total replacements**:\n%s", filePath, len(edits), totalReplacements, resultDetails),
 		Error:         nil,
-	}, nil
+	}
+	formatAfterWrite(filePath, result)
+	return result, nil
 }
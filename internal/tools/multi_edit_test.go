@@ -3,6 +3,7 @@ package tools
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -184,3 +185,27 @@ Hi again`
 		}
 	})
 }
+
+func TestMultiEditExpectedReplacements(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.txt")
+	os.WriteFile(path, []byte("a=1\na=1\n"), 0644)
+	edit := func(expected float64) error {
+		_, err := NewMultiEditTool().Execute(map[string]interface{}{
+			"file_path": path,
+			"edits": []interface{}{
+				map[string]interface{}{"old_string": "a=1", "new_string": "a=2", "replace_all": true, "expected_replacements": expected},
+			},
+		})
+		return err
+	}
+
+	if err := edit(1); err == nil || !strings.Contains(err.Error(), "expected 1 replacement(s) but old_string occurs 2 time(s)") {
+		t.Fatalf("expected a count mismatch error, got %v", err)
+	}
+	if err := edit(2); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "a=2\na=2\n" {
+		t.Errorf("unexpected content %q", data)
+	}
+}
@@ -0,0 +1,233 @@
+package tools
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// diffHunk is one "@@ -a,b +c,d @@" section of a unified diff. Lines keep
+// their leading ' ', '-' or '+' marker.
+type diffHunk struct {
+	oldStart, oldLines int
+	newStart, newLines int
+	lines              []string
+	noNewlineAtEnd     bool // The new side ends without a trailing newline
+}
+
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// parseUnifiedDiff parses a unified diff for a single file. It returns the
+// hunks and the file names from the ---/+++ headers, which are optional.
+func parseUnifiedDiff(diff string) ([]diffHunk, []string, error) {
+	var hunks []diffHunk
+	var names []string
+	var current *diffHunk
+
+	lines := strings.Split(strings.TrimRight(diff, "\n"), "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			m := hunkHeader.FindStringSubmatch(line)
+			if m == nil {
+				return nil, nil, fmt.Errorf("line %d: malformed hunk header %q", i+1, line)
+			}
+			hunks = append(hunks, diffHunk{
+				oldStart: atoiOr(m[1], 0), oldLines: atoiOr(m[2], 1),
+				newStart: atoiOr(m[3], 0), newLines: atoiOr(m[4], 1),
+			})
+			current = &hunks[len(hunks)-1]
+		case current == nil:
+			// Headers before the first hunk
+			if strings.HasPrefix(line, "--- ") || strings.HasPrefix(line, "+++ ") {
+				names = append(names, diffFileName(line[4:]))
+			} else if strings.HasPrefix(line, "diff --git ") && len(names) > 0 {
+				return nil, nil, fmt.Errorf("the diff touches more than one file")
+			}
+		case strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file" applies to the line before it
+			if i > 0 && !strings.HasPrefix(lines[i-1], "-") {
+				current.noNewlineAtEnd = true
+			}
+		case line == "":
+			// Editors and models often strip the space from blank context lines
+			current.lines = append(current.lines, " ")
+		case line[0] == ' ' || line[0] == '-' || line[0] == '+':
+			if strings.HasPrefix(line, "--- ") || strings.HasPrefix(line, "+++ ") {
+				if hunkComplete(current) {
+					return nil, nil, fmt.Errorf("the diff touches more than one file")
+				}
+			}
+			current.lines = append(current.lines, line)
+		default:
+			if strings.HasPrefix(line, "diff ") {
+				return nil, nil, fmt.Errorf("the diff touches more than one file")
+			}
+			return nil, nil, fmt.Errorf("line %d: unexpected line in hunk %q", i+1, line)
+		}
+	}
+
+	if len(hunks) == 0 {
+		return nil, nil, fmt.Errorf("no hunks found; expected @@ -start,count +start,count @@ headers")
+	}
+	for i, h := range hunks {
+		if !hunkComplete(&h) {
+			oldCount, newCount := hunkCounts(&h)
+			return nil, nil, fmt.Errorf("hunk %d header declares -%d +%d lines but the body has -%d +%d", i+1, h.oldLines, h.newLines, oldCount, newCount)
+		}
+	}
+	return hunks, names, nil
+}
+
+func atoiOr(s string, fallback int) int {
+	if s == "" {
+		return fallback
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// diffFileName strips the a/ or b/ prefix and any timestamp from a header name
+func diffFileName(name string) string {
+	if tab := strings.IndexByte(name, '\t'); tab >= 0 {
+		name = name[:tab]
+	}
+	name = strings.TrimSpace(name)
+	if strings.HasPrefix(name, "a/") || strings.HasPrefix(name, "b/") {
+		name = name[2:]
+	}
+	return name
+}
+
+// hunkCounts returns how many old and new lines the hunk body holds
+func hunkCounts(h *diffHunk) (oldCount, newCount int) {
+	for _, line := range h.lines {
+		switch line[0] {
+		case ' ':
+			oldCount++
+			newCount++
+		case '-':
+			oldCount++
+		case '+':
+			newCount++
+		}
+	}
+	return oldCount, newCount
+}
+
+func hunkComplete(h *diffHunk) bool {
+	oldCount, newCount := hunkCounts(h)
+	return oldCount == h.oldLines && newCount == h.newLines
+}
+
+// applyHunks applies parsed hunks to content. Every context and removed line
+// must match the file exactly; a hunk may sit at a different line than its
+// header says (the nearest match wins), but never before the previous hunk.
+func applyHunks(content string, hunks []diffHunk) (string, error) {
+	trailingNewline := strings.HasSuffix(content, "\n")
+	var original []string
+	if content != "" {
+		original = strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	}
+
+	var out []string
+	cursor := 0
+	for i, h := range hunks {
+		var oldBlock, newBlock []string
+		for _, line := range h.lines {
+			if line[0] != '+' {
+				oldBlock = append(oldBlock, line[1:])
+			}
+			if line[0] != '-' {
+				newBlock = append(newBlock, line[1:])
+			}
+		}
+
+		pos := h.oldStart - 1
+		if h.oldLines == 0 {
+			// Pure insertions go after line oldStart
+			pos = h.oldStart
+		}
+		if len(oldBlock) > 0 {
+			pos = findBlock(original, oldBlock, cursor, pos)
+		}
+		if pos < cursor || pos > len(original) {
+			return "", fmt.Errorf("hunk %d (@@ -%d,%d) does not match the file; read it again and regenerate the diff", i+1, h.oldStart, h.oldLines)
+		}
+
+		out = append(out, original[cursor:pos]...)
+		out = append(out, newBlock...)
+		cursor = pos + len(oldBlock)
+
+		if i == len(hunks)-1 && cursor == len(original) {
+			trailingNewline = !h.noNewlineAtEnd
+		}
+	}
+	out = append(out, original[cursor:]...)
+
+	result := strings.Join(out, "\n")
+	if trailingNewline && len(out) > 0 {
+		result += "\n"
+	}
+	return result, nil
+}
+
+// findBlock returns the start of the occurrence of block at or after from
+// that is closest to want, or -1 when there is none
+func findBlock(lines, block []string, from, want int) int {
+	best := -1
+	for start := from; start+len(block) <= len(lines); start++ {
+		match := true
+		for j, line := range block {
+			if lines[start+j] != line {
+				match = false
+				break
+			}
+		}
+		if match && (best < 0 || abs(start-want) < abs(best-want)) {
+			best = start
+		}
+	}
+	return best
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// ApplyUnifiedDiff applies a single-file unified diff to content. File names
+// in ---/+++ headers, when present, must refer to filePath.
+func ApplyUnifiedDiff(filePath, content, diff string) (string, int, error) {
+	hunks, names, err := parseUnifiedDiff(diff)
+	if err != nil {
+		return "", 0, err
+	}
+	for _, name := range names {
+		if name == "/dev/null" {
+			return "", 0, fmt.Errorf("edit_diff only modifies existing files; use write_file to create or delete files")
+		}
+		if !sameDiffPath(name, filePath) {
+			return "", 0, fmt.Errorf("the diff is for %s, not %s", name, filePath)
+		}
+	}
+
+	updated, err := applyHunks(content, hunks)
+	if err != nil {
+		return "", 0, err
+	}
+	return updated, len(hunks), nil
+}
+
+// sameDiffPath reports whether a diff header name refers to filePath. Header
+// names are usually relative to the repository root, so a matching suffix is
+// enough.
+func sameDiffPath(name, filePath string) bool {
+	name = filepath.ToSlash(filepath.Clean(name))
+	filePath = filepath.ToSlash(filepath.Clean(filePath))
+	return name == filePath || strings.HasSuffix(filePath, "/"+name) || strings.HasSuffix(name, "/"+filePath)
+}
@@ -0,0 +1,192 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultPinnedFileLimit caps how much of each pinned file is shown
+	DefaultPinnedFileLimit = 8 * 1024
+	// DefaultPinnedTotalLimit caps the combined size of all pinned files
+	DefaultPinnedTotalLimit = 32 * 1024
+)
+
+// PinnedFiles is the session's working set: files whose current contents are
+// shown to the model on every turn. Contents are read from disk each time they
+// are rendered, so edits are always reflected.
+type PinnedFiles struct {
+	mu    sync.Mutex
+	paths map[string]bool
+}
+
+// GlobalPinnedFiles is the singleton working set for the session
+var GlobalPinnedFiles = &PinnedFiles{
+	paths: make(map[string]bool),
+}
+
+// Pin adds a file to the working set and returns the path it was stored under
+func (p *PinnedFiles) Pin(path string) (string, error) {
+	path = filepath.Clean(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot pin %s: %w", path, err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("cannot pin %s: is a directory", path)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paths[path] = true
+	return path, nil
+}
+
+// Unpin removes a file from the working set and reports whether it was pinned
+func (p *PinnedFiles) Unpin(path string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	path = filepath.Clean(path)
+	pinned := p.paths[path]
+	delete(p.paths, path)
+	return pinned
+}
+
+// List returns the pinned paths in sorted order
+func (p *PinnedFiles) List() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	paths := make([]string, 0, len(p.paths))
+	for path := range p.paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Clear unpins every file
+func (p *PinnedFiles) Clear() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.paths = make(map[string]bool)
+}
+
+// Render reads the current contents of every pinned file and formats them for
+// the model, or returns "" when nothing is pinned. Each file is capped at
+// DefaultPinnedFileLimit bytes and the whole block at DefaultPinnedTotalLimit.
+func (p *PinnedFiles) Render() string {
+	paths := p.List()
+	if len(paths) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("Pinned files (current contents, refreshed every turn; no need to read them again):\n")
+	remaining := DefaultPinnedTotalLimit
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(&b, "\n--- %s ---\n(unavailable: %v)\n", path, err)
+			continue
+		}
+
+		limit := DefaultPinnedFileLimit
+		if remaining < limit {
+			limit = remaining
+		}
+		if limit <= 0 {
+			fmt.Fprintf(&b, "\n--- %s ---\n(omitted: pinned files exceed %d bytes)\n", path, DefaultPinnedTotalLimit)
+			continue
+		}
+
+		text, _ := decodeText(content)
+		if masked, ok := maskDotenvContent(path, text); ok {
+			text = dotenvNoteLLM + "\n" + masked
+		}
+		truncated := false
+		if len(text) > limit {
+			text = text[:limit]
+			truncated = true
+		}
+		remaining -= len(text)
+
+		fmt.Fprintf(&b, "\n--- %s ---\n%s", path, text)
+		if !strings.HasSuffix(text, "\n") {
+			b.WriteString("\n")
+		}
+		if truncated {
+			fmt.Fprintf(&b, "(truncated: showing %d of %d bytes; use read for the rest)\n", len(text), len(content))
+		}
+	}
+	return b.String()
+}
+
+// PinFileTool lets the agent add or remove files from the pinned working set
+type PinFileTool struct{}
+
+// NewPinFileTool creates a new PinFileTool instance
+func NewPinFileTool() *PinFileTool {
+	return &PinFileTool{}
+}
+
+func (t *PinFileTool) Name() string {
+	return "pin_file"
+}
+
+func (t *PinFileTool) Description() string {
+	return "Pin a file so its current contents are shown to you every turn, even after edits and compaction. Use for the few files a task centers on. Set unpin to remove it"
+}
+
+func (t *PinFileTool) ReadOnly() bool {
+	return true
+}
+
+func (t *PinFileTool) GetParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "The file to pin or unpin",
+			},
+			"unpin": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Remove the file from the pinned set instead of adding it",
+			},
+		},
+		"required": []string{"path"},
+	}
+}
+
+func (t *PinFileTool) Execute(args map[string]interface{}) (*ToolResult, error) {
+	path, _ := args["path"].(string)
+	if strings.TrimSpace(path) == "" {
+		return nil, requiredArg("path")
+	}
+
+	if unpin, _ := args["unpin"].(bool); unpin {
+		if !GlobalPinnedFiles.Unpin(path) {
+			return nil, fmt.Errorf("%s is not pinned", path)
+		}
+		return &ToolResult{
+			LLMContent:    fmt.Sprintf("Unpinned %s", path),
+			ReturnDisplay: fmt.Sprintf("📌 Unpinned `%s`", path),
+		}, nil
+	}
+
+	pinned, err := GlobalPinnedFiles.Pin(path)
+	if err != nil {
+		return nil, err
+	}
+	return &ToolResult{
+		LLMContent:    fmt.Sprintf("Pinned %s; its current contents will be included every turn", pinned),
+		ReturnDisplay: fmt.Sprintf("📌 Pinned `%s`", pinned),
+	}, nil
+}
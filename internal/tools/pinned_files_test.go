@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPinnedFilesRenderCapsLargeFiles(t *testing.T) {
+	pinned := &PinnedFiles{paths: make(map[string]bool)}
+	dir := t.TempDir()
+
+	small := filepath.Join(dir, "small.txt")
+	large := filepath.Join(dir, "large.txt")
+	os.WriteFile(small, []byte("hello\n"), 0644)
+	os.WriteFile(large, []byte(strings.Repeat("x", DefaultPinnedFileLimit+100)), 0644)
+
+	for _, path := range []string{small, large} {
+		if _, err := pinned.Pin(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := pinned.Pin(dir); err == nil {
+		t.Error("expected pinning a directory to fail")
+	}
+
+	rendered := pinned.Render()
+	if !strings.Contains(rendered, "--- "+small+" ---\nhello\n") {
+		t.Errorf("expected the small file in full, got %q", rendered[:200])
+	}
+	if !strings.Contains(rendered, "truncated: showing 8192 of 8292 bytes") {
+		t.Error("expected the large file to be truncated")
+	}
+
+	if !pinned.Unpin(large) || pinned.Unpin(large) {
+		t.Error("expected unpin to succeed once")
+	}
+	pinned.Clear()
+	if pinned.Render() != "" {
+		t.Error("expected nothing to render after clear")
+	}
+}
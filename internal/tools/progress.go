@@ -0,0 +1,27 @@
+package tools
+
+// ProgressUpdate describes how far a long-running tool call has got
+type ProgressUpdate struct {
+	Message string // What the tool is doing, e.g. the current file
+	Done    int    // Units of work finished
+	Total   int    // Units of work in all; 0 when unknown
+}
+
+// ProgressReporter receives a tool's progress updates, in order
+type ProgressReporter func(update ProgressUpdate)
+
+// ProgressTool is implemented by tools that can report progress while they
+// run. Execute behaves the same as ExecuteWithProgress with a nil reporter.
+type ProgressTool interface {
+	ExecuteWithProgress(args map[string]interface{}, report ProgressReporter) (*ToolResult, error)
+}
+
+// progressEvery is how many files a walking tool handles between updates
+const progressEvery = 50
+
+// emit sends an update when a reporter is set
+func (r ProgressReporter) emit(message string, done, total int) {
+	if r != nil {
+		r(ProgressUpdate{Message: message, Done: done, Total: total})
+	}
+}
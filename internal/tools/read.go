@@ -12,10 +12,12 @@ import (
 //	{
 //	  // The absolute path to the file to read
 //	  file_path: string;
-//	  // The line number to start reading from. Only provide if the file is too large to read at once
+//	  // Number of lines to skip, e.g. the offset named in a page footer
 //	  offset?: number;
-//	  // The number of lines to read. Only provide if the file is too large to read at once.
+//	  // The number of lines to read (defaults to the page size)
 //	  limit?: number;
+//	  // Token from the previous page's footer
+//	  continuation?: string;
 //	}
 type ReadTool struct{}
 
@@ -36,23 +38,15 @@ func (t *ReadTool) ReadOnly() bool {
 }
 
 func (t *ReadTool) GetParameters() map[string]interface{} {
+	properties := readPagingParameters()
+	properties["file_path"] = map[string]interface{}{
+		"type":        "string",
+		"description": "The absolute path to the file to read",
+	}
 	return map[string]interface{}{
-		"type": "object",
-		"properties": map[string]interface{}{
-			"file_path": map[string]interface{}{
-				"type":        "string",
-				"description": "The absolute path to the file to read",
-			},
-			"offset": map[string]interface{}{
-				"type":        "integer",
-				"description": "The line number to start reading from. Only provide if the file is too large to read at once",
-			},
-			"limit": map[string]interface{}{
-				"type":        "integer",
-				"description": "The number of lines to read. Only provide if the file is too large to read at once",
-			},
-		},
-		"required": []string{"file_path"},
+		"type":       "object",
+		"properties": properties,
+		"required":   []string{"file_path"},
 	}
 }
 
@@ -60,7 +54,7 @@ func (t *ReadTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 	// Get the file path
 	path, ok := args["file_path"].(string)
 	if !ok {
-		return nil, fmt.Errorf("file_path is required")
+		return nil, requiredArg("file_path")
 	}
 
 	// Convert to absolute path if needed
@@ -76,30 +70,44 @@ func (t *ReadTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 	info, err := os.Stat(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("file not found: %s", path)
+			return nil, fileNotFound(path)
 		}
 		return nil, fmt.Errorf("failed to stat file: %w", err)
 	}
 
 	// Check if it's a directory
 	if info.IsDir() {
-		return nil, fmt.Errorf("path is a directory, not a file: %s", path)
+		return nil, validationError("path is a directory, not a file: %s", path)
 	}
 
 	// Read the file
-	content, err := os.ReadFile(path)
+	content, enc, err := readTextFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, fileError("read", path, err)
 	}
 
-	contentStr := string(content)
+	contentStr, masked := maskDotenvContent(path, content)
 	fileSize := info.Size()
+	note := ""
+	if masked {
+		note = " " + dotenvNoteLLM
+	}
+	if enc.note() != "" {
+		note += " " + enc.note()
+	}
 
-	// Build simple LLM content
-	llmContent := fmt.Sprintf("Content of %s:\n%s", path, contentStr)
+	page, err := pageFile(t.Name(), contentStr, args)
+	if err != nil {
+		return nil, err
+	}
 
-	// Build simple display content
-	displayContent := fmt.Sprintf("📄 **%s** (%d bytes)\n\n%s", path, fileSize, contentStr)
+	// Build simple LLM content
+	llmContent := fmt.Sprintf("Content of %s%s:\n%s", path, note, page.Content)
+	displayContent := fmt.Sprintf("📄 **%s** (%d bytes)%s\n%s", path, fileSize, note, fencedFile(path, page.Content, 0))
+	if page.Footer != "" {
+		llmContent += "\n" + page.Footer
+		displayContent += "\n" + page.Footer
+	}
 
 	return &ToolResult{
 		LLMContent:    llmContent,
@@ -0,0 +1,179 @@
+package tools
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// defaultReadBytesLength is used when read_bytes is called without a length
+	defaultReadBytesLength = 256
+	// maxReadBytesLength caps one read_bytes call; a hex dump is ~4x the input
+	maxReadBytesLength = 4096
+)
+
+// ReadBytesTool reads a byte range of a file, for regions where line numbers
+// mean nothing: binary headers, minified files or a known offset in a log
+type ReadBytesTool struct{}
+
+// NewReadBytesTool creates a new ReadBytesTool instance
+func NewReadBytesTool() *ReadBytesTool {
+	return &ReadBytesTool{}
+}
+
+func (t *ReadBytesTool) Name() string {
+	return "read_bytes"
+}
+
+func (t *ReadBytesTool) Description() string {
+	return "Read a byte range of a file. Text is returned as is; binary content is shown as a hex dump with an ASCII column"
+}
+
+func (t *ReadBytesTool) ReadOnly() bool {
+	return true
+}
+
+func (t *ReadBytesTool) GetParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "The file to read",
+			},
+			"offset": map[string]interface{}{
+				"type":        "integer",
+				"description": "Byte offset to start at; negative values count from the end of the file",
+			},
+			"length": map[string]interface{}{
+				"type":        "integer",
+				"description": fmt.Sprintf("Number of bytes to read (default %d, max %d)", defaultReadBytesLength, maxReadBytesLength),
+			},
+			"hex": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Always show a hex dump, even for text",
+			},
+		},
+		"required": []string{"path"},
+	}
+}
+
+func (t *ReadBytesTool) Execute(args map[string]interface{}) (*ToolResult, error) {
+	path, _ := args["path"].(string)
+	if path == "" {
+		return nil, requiredArg("path")
+	}
+	offset := int64(0)
+	if v, ok := args["offset"].(float64); ok {
+		offset = int64(v)
+	}
+	length := int64(defaultReadBytesLength)
+	if v, ok := args["length"].(float64); ok && v > 0 {
+		length = int64(v)
+	}
+	if length > maxReadBytesLength {
+		length = maxReadBytesLength
+	}
+	forceHex, _ := args["hex"].(bool)
+	if dotenvMasked(path) {
+		return nil, dotenvRangeError(path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fileError("open", path, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+	size := info.Size()
+	if offset < 0 {
+		offset += size
+		if offset < 0 {
+			offset = 0
+		}
+	}
+	if offset > size {
+		return nil, validationError("offset %d is past the end of the file (%d bytes)", offset, size)
+	}
+	if offset+length > size {
+		length = size - offset
+	}
+
+	data := make([]byte, length)
+	n, err := file.ReadAt(data, offset)
+	if err != nil && err != io.EOF {
+		return nil, fileError("read", path, err)
+	}
+	data = data[:n]
+
+	header := fmt.Sprintf("Bytes %d-%d of %s (%d bytes total)", offset, offset+int64(n), path, size)
+	display := fmt.Sprintf("🔢 `%s`: bytes %d-%d", path, offset, offset+int64(n))
+	if !forceHex && isPrintableText(data) {
+		return &ToolResult{
+			LLMContent:    header + ":\n" + string(data),
+			ReturnDisplay: display,
+		}, nil
+	}
+	return &ToolResult{
+		LLMContent:    header + ", hex dump:\n" + HexDump(data, offset),
+		ReturnDisplay: display + " (hex)",
+	}, nil
+}
+
+// HexDump formats data like `hexdump -C`, numbering lines from base
+func HexDump(data []byte, base int64) string {
+	var b strings.Builder
+	for start := 0; start < len(data); start += 16 {
+		end := start + 16
+		if end > len(data) {
+			end = len(data)
+		}
+		line := data[start:end]
+
+		fmt.Fprintf(&b, "%08x  ", base+int64(start))
+		for i := 0; i < 16; i++ {
+			if i < len(line) {
+				fmt.Fprintf(&b, "%02x ", line[i])
+			} else {
+				b.WriteString("   ")
+			}
+			if i == 7 {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(" |")
+		for _, c := range line {
+			if c >= 0x20 && c < 0x7f {
+				b.WriteByte(c)
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteString("|\n")
+	}
+	return b.String()
+}
+
+// isPrintableText reports whether data is UTF-8 without control characters
+// other than tabs and line breaks. A rune cut off at the end of the range
+// does not count against it.
+func isPrintableText(data []byte) bool {
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if r == utf8.RuneError && size <= 1 {
+			return len(data)-i < utf8.UTFMax && !utf8.FullRune(data[i:])
+		}
+		if r < 0x20 && r != '\n' && r != '\r' && r != '\t' || r == 0x7f {
+			return false
+		}
+		i += size
+	}
+	return true
+}
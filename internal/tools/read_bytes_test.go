@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadBytesRendersHexDump(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "image.png")
+	header := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR\x00\x00\x01\x00")
+	if err := os.WriteFile(path, header, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := NewReadBytesTool().Execute(map[string]interface{}{
+		"path":   path,
+		"offset": float64(1),
+		"length": float64(18),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Bytes 1-19 of " + path + " (20 bytes total), hex dump:\n" +
+		"00000001  50 4e 47 0d 0a 1a 0a 00  00 00 0d 49 48 44 52 00  |PNG........IHDR.|\n" +
+		"00000011  00 01                                             |..|\n"
+	if result.LLMContent != want {
+		t.Errorf("unexpected dump:\n%s\nwant:\n%s", result.LLMContent, want)
+	}
+}
+
+func TestReadBytesReturnsTextRegionsAsText(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.min.js")
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", 100)+"function héllo(){}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tool := NewReadBytesTool()
+
+	result, err := tool.Execute(map[string]interface{}{"path": path, "offset": float64(-19)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(result.LLMContent, ":\nfunction héllo(){}") {
+		t.Errorf("expected the tail as text, got %q", result.LLMContent)
+	}
+
+	if _, err := tool.Execute(map[string]interface{}{"path": path, "offset": float64(500)}); err == nil {
+		t.Error("expected an offset past the end to fail")
+	}
+}
@@ -4,13 +4,33 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 )
 
-type ReadManyFilesTool struct{}
+// DefaultReadManyFilesLimit caps how many files a single call reads
+const DefaultReadManyFilesLimit = 50
+
+type ReadManyFilesTool struct {
+	maxFiles int
+}
 
 func NewReadManyFilesTool() *ReadManyFilesTool {
-	return &ReadManyFilesTool{}
+	return &ReadManyFilesTool{maxFiles: DefaultReadManyFilesLimit}
+}
+
+// SetMaxFiles changes how many files a single call may read. Values <= 0
+// restore the default.
+func (t *ReadManyFilesTool) SetMaxFiles(n int) {
+	t.maxFiles = n
+}
+
+func (t *ReadManyFilesTool) limit() int {
+	if t.maxFiles <= 0 {
+		return DefaultReadManyFilesLimit
+	}
+	return t.maxFiles
 }
 
 func (t *ReadManyFilesTool) Name() string {
@@ -48,6 +68,11 @@ func (t *ReadManyFilesTool) GetParameters() map[string]interface{} {
 }
 
 func (t *ReadManyFilesTool) Execute(args map[string]interface{}) (*ToolResult, error) {
+	return t.ExecuteWithProgress(args, nil)
+}
+
+// ExecuteWithProgress reads like Execute, reporting each file as it is read
+func (t *ReadManyFilesTool) ExecuteWithProgress(args map[string]interface{}, report ProgressReporter) (*ToolResult, error) {
 	// Accept either "paths" (array) or "patterns" (array of glob patterns)
 	var filePaths []string
 
@@ -59,6 +84,7 @@ func (t *ReadManyFilesTool) Execute(args map[string]interface{}) (*ToolResult, e
 			}
 		}
 	}
+	explicitCount := len(filePaths)
 
 	// Check for glob patterns
 	if patterns, ok := args["patterns"].([]interface{}); ok {
@@ -75,21 +101,40 @@ func (t *ReadManyFilesTool) Execute(args map[string]interface{}) (*ToolResult, e
 
 	// If neither paths nor patterns provided
 	if len(filePaths) == 0 {
-		return nil, fmt.Errorf("either 'paths' or 'patterns' array is required")
+		return nil, validationError("either 'paths' or 'patterns' array is required")
 	}
 
-	// Remove duplicates
-	uniquePaths := make(map[string]bool)
-	for _, path := range filePaths {
-		uniquePaths[path] = true
+	// Remove duplicates, keeping explicit paths ahead of glob matches
+	var uniquePaths, globMatches []string
+	seen := make(map[string]bool)
+	for i, path := range filePaths {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		if i < explicitCount {
+			uniquePaths = append(uniquePaths, path)
+		} else {
+			globMatches = append(globMatches, path)
+		}
+	}
+	sortByRecency(globMatches)
+	uniquePaths = append(uniquePaths, globMatches...)
+
+	// Cap the number of files so broad globs cannot flood the context
+	matched := len(uniquePaths)
+	limit := t.limit()
+	if matched > limit {
+		uniquePaths = uniquePaths[:limit]
 	}
 
-	// Read all files
+	// Read the selected files
 	var results []map[string]interface{}
 	var errors []string
 
-	for path := range uniquePaths {
-		content, err := os.ReadFile(path)
+	for i, path := range uniquePaths {
+		report.emit(path, i, len(uniquePaths))
+		content, enc, err := readTextFile(path)
 		if err != nil {
 			errors = append(errors, fmt.Sprintf("%s: %v", path, err))
 			continue
@@ -101,10 +146,13 @@ func (t *ReadManyFilesTool) Execute(args map[string]interface{}) (*ToolResult, e
 			continue
 		}
 
+		text, masked := maskDotenvContent(path, content)
 		results = append(results, map[string]interface{}{
-			"path":    path,
-			"content": string(content),
-			"size":    info.Size(),
+			"path":     path,
+			"content":  text,
+			"masked":   masked,
+			"encoding": enc.note(),
+			"size":     info.Size(),
 		})
 	}
 
@@ -115,11 +163,21 @@ func (t *ReadManyFilesTool) Execute(args map[string]interface{}) (*ToolResult, e
 		llmContent.WriteString(fmt.Sprintf(" (%d errors)", len(errors)))
 	}
 	llmContent.WriteString(":\n")
+	if matched > limit {
+		llmContent.WriteString(fmt.Sprintf("Note: matched %d files, reading first %d (explicit paths first, then most recently modified). Narrow the patterns to read others.\n", matched, limit))
+	}
 
 	for _, result := range results {
 		path := result["path"].(string)
 		content := result["content"].(string)
-		llmContent.WriteString(fmt.Sprintf("\n=== %s ===\n%s\n", path, content))
+		header := path
+		if result["masked"].(bool) {
+			header += " " + dotenvNoteLLM
+		}
+		if note := result["encoding"].(string); note != "" {
+			header += " " + note
+		}
+		llmContent.WriteString(fmt.Sprintf("\n=== %s ===\n%s\n", header, content))
 	}
 
 	if len(errors) > 0 {
@@ -136,6 +194,9 @@ func (t *ReadManyFilesTool) Execute(args map[string]interface{}) (*ToolResult, e
 		displayContent.WriteString(fmt.Sprintf(" (⚠️ %d errors)", len(errors)))
 	}
 	displayContent.WriteString("\n\n")
+	if matched > limit {
+		displayContent.WriteString(fmt.Sprintf("*Matched %d files, reading first %d*\n\n", matched, limit))
+	}
 
 	for _, result := range results {
 		path := result["path"].(string)
@@ -145,13 +206,8 @@ func (t *ReadManyFilesTool) Execute(args map[string]interface{}) (*ToolResult, e
 
 		displayContent.WriteString(fmt.Sprintf("### 📄 %s\n", path))
 		displayContent.WriteString(fmt.Sprintf("*%d lines, %d bytes*\n", lines, size))
-		displayContent.WriteString("```\n")
-
-		// Add line numbers for display
-		for i, line := range strings.Split(content, "\n") {
-			displayContent.WriteString(fmt.Sprintf("%4d | %s\n", i+1, line))
-		}
-		displayContent.WriteString("```\n\n")
+		displayContent.WriteString(fencedFile(path, content, 1))
+		displayContent.WriteString("\n\n")
 	}
 
 	if len(errors) > 0 {
@@ -167,3 +223,21 @@ func (t *ReadManyFilesTool) Execute(args map[string]interface{}) (*ToolResult, e
 		Error:         nil,
 	}, nil
 }
+
+// sortByRecency orders paths by modification time, newest first. Files that
+// cannot be stat'ed sort last so their errors are still reported if they fit.
+func sortByRecency(paths []string) {
+	modTimes := make(map[string]time.Time, len(paths))
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			modTimes[path] = info.ModTime()
+		}
+	}
+	sort.SliceStable(paths, func(i, j int) bool {
+		ti, tj := modTimes[paths[i]], modTimes[paths[j]]
+		if !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return paths[i] < paths[j]
+	})
+}
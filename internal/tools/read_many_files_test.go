@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadManyFilesCapsMatchedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 5; i++ {
+		path := filepath.Join(tmpDir, fmt.Sprintf("file%d.txt", i))
+		if err := os.WriteFile(path, []byte(fmt.Sprintf("content %d", i)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		// file4 is the most recently modified
+		modTime := base.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tool := NewReadManyFilesTool()
+	tool.SetMaxFiles(2)
+
+	result, err := tool.Execute(map[string]interface{}{
+		"paths":    []interface{}{filepath.Join(tmpDir, "file0.txt")},
+		"patterns": []interface{}{filepath.Join(tmpDir, "*.txt")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(result.LLMContent, "Read 2 files") {
+		t.Errorf("expected exactly 2 files to be read, got %q", result.LLMContent)
+	}
+	if !strings.Contains(result.LLMContent, "matched 5 files, reading first 2") {
+		t.Errorf("expected a note about the cap, got %q", result.LLMContent)
+	}
+	// The explicit path comes first, then the newest glob match
+	if !strings.Contains(result.LLMContent, "content 0") || !strings.Contains(result.LLMContent, "content 4") {
+		t.Errorf("expected file0 and file4 to be read, got %q", result.LLMContent)
+	}
+	if strings.Contains(result.LLMContent, "content 3") {
+		t.Errorf("expected older matches to be skipped, got %q", result.LLMContent)
+	}
+}
+
+func TestReadManyFilesNoNoteUnderLimit(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "a.txt")
+	if err := os.WriteFile(path, []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := (&ReadManyFilesTool{}).Execute(map[string]interface{}{
+		"patterns": []interface{}{filepath.Join(tmpDir, "*.txt")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(result.LLMContent, "matched") {
+		t.Errorf("did not expect a cap note, got %q", result.LLMContent)
+	}
+}
@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultReadMaxLines is how many lines read and read_file return when the
+// model does not ask for a range
+const DefaultReadMaxLines = 2000
+
+var readMaxLines atomic.Int64
+
+func init() {
+	readMaxLines.Store(DefaultReadMaxLines)
+}
+
+// SetReadMaxLines sets how many lines a read returns by default; longer
+// files are paged (n <= 0 restores the default)
+func SetReadMaxLines(n int) {
+	if n <= 0 {
+		n = DefaultReadMaxLines
+	}
+	readMaxLines.Store(int64(n))
+}
+
+// readPagingParameters are the paging parameters shared by read and read_file
+func readPagingParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"offset": map[string]interface{}{
+			"type":        "integer",
+			"description": "Number of lines to skip before reading (default 0). Large files are returned in pages; the footer says which offset continues",
+		},
+		"limit": map[string]interface{}{
+			"type":        "integer",
+			"description": "The number of lines to read (defaults to the page size)",
+		},
+		"continuation": map[string]interface{}{
+			"type":        "string",
+			"description": "The continuation token from the previous page's footer; reads the next page and warns if the file changed in between",
+		},
+	}
+}
+
+// filePage is the part of a file a read returns
+type filePage struct {
+	Content   string // The selected lines
+	FirstLine int    // 1-based number of the first returned line
+	Footer    string // Paging note for the model; empty when the whole file was returned
+}
+
+// pageFile selects the lines a read asked for. Without a range, files longer
+// than the page size return their first page plus a footer naming the offset
+// and continuation token for the next one.
+func pageFile(toolName, content string, args map[string]interface{}) (filePage, error) {
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	total := len(lines)
+	fingerprint := contentFingerprint(content)
+
+	offset, hasOffset := intArg(args, "offset")
+	var notes []string
+	if token, _ := args["continuation"].(string); token != "" {
+		tokenOffset, tokenFingerprint, err := parseContinuation(token)
+		if err != nil {
+			return filePage{}, err
+		}
+		if !hasOffset {
+			offset = tokenOffset
+		}
+		if tokenFingerprint != fingerprint {
+			notes = append(notes, "the file changed since the previous page; line numbers may have shifted")
+		}
+	}
+	if offset < 0 {
+		return filePage{}, validationError("offset must not be negative")
+	}
+	if offset > total {
+		return filePage{}, validationError("offset %d is past the end of the file (%d lines)", offset, total)
+	}
+
+	limit, hasLimit := intArg(args, "limit")
+	if !hasLimit || limit <= 0 {
+		limit = int(readMaxLines.Load())
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+
+	page := filePage{Content: strings.Join(lines[offset:end], ""), FirstLine: offset + 1}
+	if offset > 0 || end < total {
+		notes = append(notes, fmt.Sprintf("file has %d lines; showing lines %d-%d", total, offset+1, end))
+	}
+	if end < total {
+		notes = append(notes, fmt.Sprintf("call %s with offset=%d or continuation=%q to continue", toolName, end, continuationToken(end, fingerprint)))
+	}
+	if len(notes) > 0 {
+		page.Footer = "[" + strings.Join(notes, "; ") + "]"
+	}
+	return page, nil
+}
+
+// continuationToken encodes the next offset with a fingerprint of the file
+func continuationToken(offset int, fingerprint string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset) + ":" + fingerprint))
+}
+
+func parseContinuation(token string) (int, string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		if offset, fingerprint, ok := strings.Cut(string(data), ":"); ok {
+			if n, err := strconv.Atoi(offset); err == nil {
+				return n, fingerprint, nil
+			}
+		}
+	}
+	return 0, "", validationError("invalid continuation token %q", token)
+}
+
+// contentFingerprint identifies a version of a file's content
+func contentFingerprint(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:6])
+}
+
+// intArg reads an integer argument, which arrives as float64 from JSON
+func intArg(args map[string]interface{}, key string) (int, bool) {
+	switch v := args[key].(type) {
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	}
+	return 0, false
+}
@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestReadPagesLargeFiles(t *testing.T) {
+	SetReadMaxLines(10)
+	defer SetReadMaxLines(0)
+
+	var b strings.Builder
+	for i := 1; i <= 25; i++ {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	path := filepath.Join(t.TempDir(), "big.txt")
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := NewReadTool().Execute(map[string]interface{}{"file_path": path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result.LLMContent, "line 10\n") || strings.Contains(result.LLMContent, "line 11\n") {
+		t.Errorf("expected the first 10 lines, got:\n%s", result.LLMContent)
+	}
+	if !strings.Contains(result.LLMContent, "[file has 25 lines; showing lines 1-10; call read with offset=10") {
+		t.Errorf("expected a continuation footer, got:\n%s", result.LLMContent)
+	}
+
+	result, err = NewReadTool().Execute(map[string]interface{}{"file_path": path, "offset": float64(10)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(strings.SplitN(result.LLMContent, "\n", 2)[1], "line 11\n") || strings.Contains(result.LLMContent, "line 21\n") {
+		t.Errorf("expected lines 11-20, got:\n%s", result.LLMContent)
+	}
+
+	// The continuation token from read_file's footer reads the final page
+	result, err = NewReadFileTool().Execute(map[string]interface{}{"path": path, "offset": float64(10)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := regexp.MustCompile(`continuation="([^"]+)"`).FindStringSubmatch(result.LLMContent)
+	if token == nil {
+		t.Fatalf("expected a continuation token, got:\n%s", result.LLMContent)
+	}
+	result, err = NewReadFileTool().Execute(map[string]interface{}{"path": path, "continuation": token[1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result.LLMContent, "line 21\n") || !strings.Contains(result.LLMContent, "line 25\n") || strings.Contains(result.LLMContent, "line 20\n") {
+		t.Errorf("expected lines 21-25, got:\n%s", result.LLMContent)
+	}
+	if !strings.Contains(result.LLMContent, "showing lines 21-25]") || strings.Contains(result.LLMContent, "to continue") {
+		t.Errorf("expected a final-page footer, got:\n%s", result.LLMContent)
+	}
+	if !strings.Contains(result.ReturnDisplay, "  21 | line 21") {
+		t.Errorf("expected display line numbers to follow the offset, got:\n%s", result.ReturnDisplay)
+	}
+
+	// A token from an older version of the file warns that lines may have moved
+	os.WriteFile(path, []byte("inserted\n"+b.String()), 0644)
+	result, err = NewReadFileTool().Execute(map[string]interface{}{"path": path, "continuation": token[1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result.LLMContent, "the file changed since the previous page") {
+		t.Errorf("expected a changed-file warning, got:\n%s", result.LLMContent)
+	}
+}
+
+func TestReadSmallFileHasNoFooter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "small.txt")
+	os.WriteFile(path, []byte("one\ntwo\n"), 0644)
+
+	result, err := NewReadTool().Execute(map[string]interface{}{"file_path": path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(result.LLMContent, "[file has") {
+		t.Errorf("expected no footer for a small file, got:\n%s", result.LLMContent)
+	}
+	if _, err := NewReadTool().Execute(map[string]interface{}{"file_path": path, "continuation": "???"}); err == nil {
+		t.Error("expected an invalid continuation token to be rejected")
+	}
+}
@@ -0,0 +1,40 @@
+package tools
+
+import "errors"
+
+// Recovery suggestions for failures with a more specific fix than their kind
+const (
+	recoverReread    = "Re-read the file with read_file and copy old_string exactly as it appears now, including whitespace and indentation."
+	recoverAmbiguous = "Add surrounding lines to old_string so it matches exactly once, or set replace_all=true if every occurrence should change."
+)
+
+// kindRecovery suggests a way forward for each kind of failure
+var kindRecovery = map[error]string{
+	ErrFileNotFound:   "Check the path: list the directory with list_files or find the file with glob, then retry with the correct path.",
+	ErrPathNotAllowed: "Use a different path; this one will be refused again.",
+	ErrTimeout:        "Narrow the operation (a more specific path, pattern or command) instead of repeating it unchanged.",
+	ErrValidation:     "Fix the arguments as the error describes; the same call will fail again.",
+}
+
+// withRecovery attaches a specific recovery suggestion to a classified error
+func withRecovery(err error, hint string) error {
+	var toolErr *Error
+	if errors.As(err, &toolErr) {
+		toolErr.Recovery = hint
+	}
+	return err
+}
+
+// RecoveryHint suggests how the model can recover from a failed tool call:
+// the suggestion the tool attached, otherwise the one for the failure's
+// kind. Unclassified errors get none.
+func RecoveryHint(err error) string {
+	var toolErr *Error
+	if !errors.As(err, &toolErr) {
+		return ""
+	}
+	if toolErr.Recovery != "" {
+		return toolErr.Recovery
+	}
+	return kindRecovery[toolErr.Kind]
+}
@@ -0,0 +1,389 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// testTimeout bounds a single run_tests invocation
+	testTimeout = 10 * time.Minute
+	// maxFailureMessage caps the output kept for each failing test
+	maxFailureMessage = 2000
+	// maxTestDisplay caps the raw output shown to the user
+	maxTestDisplay = 20000
+)
+
+// TestFailure is a failing test and the output explaining why
+type TestFailure struct {
+	Name    string
+	Message string
+}
+
+// TestSummary is the structured result of a test run
+type TestSummary struct {
+	Framework string // "go", "pytest", or "" when the output was not recognized
+	Total     int
+	Passed    int
+	Failed    int
+	Skipped   int
+	Failures  []TestFailure
+}
+
+// RunTestsTool runs the project's tests and summarizes the results
+type RunTestsTool struct{}
+
+// NewRunTestsTool creates a new RunTestsTool instance
+func NewRunTestsTool() *RunTestsTool {
+	return &RunTestsTool{}
+}
+
+func (t *RunTestsTool) Name() string {
+	return "run_tests"
+}
+
+func (t *RunTestsTool) Description() string {
+	return "Run the project's tests and get a structured summary (pass/fail counts and each failing test with its message). Detects Go, npm and pytest projects, or runs the given command. Prefer this over run_shell for tests"
+}
+
+func (t *RunTestsTool) ReadOnly() bool {
+	return false
+}
+
+// DangerousCall flags an explicit command matching the dangerous shell
+// patterns, as run_shell does; detected test commands are never dangerous
+func (t *RunTestsTool) DangerousCall(args map[string]interface{}) string {
+	command, _ := args["command"].(string)
+	return DangerousShellCommand(command)
+}
+
+func (t *RunTestsTool) GetParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"command": map[string]interface{}{
+				"type":        "string",
+				"description": "Test command to run instead of the detected one, e.g. \"go test -json ./internal/...\" or \"pytest tests/test_api.py\"",
+			},
+			"working_directory": map[string]interface{}{
+				"type":        "string",
+				"description": "Directory to run the tests in (defaults to the current directory)",
+			},
+		},
+	}
+}
+
+func (t *RunTestsTool) Execute(args map[string]interface{}) (*ToolResult, error) {
+	dir, _ := args["working_directory"].(string)
+	command, _ := args["command"].(string)
+	if strings.TrimSpace(command) == "" {
+		detected, err := DetectTestCommand(dir)
+		if err != nil {
+			return nil, err
+		}
+		command = detected
+	}
+	// An explicit command runs through the shell, so it gets run_shell's checks
+	if reason := ForbiddenShellCommand(command); reason != "" {
+		return nil, fmt.Errorf("forbidden command blocked (%s): %s", reason, command)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	runErr := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, newError(ErrTimeout, "", "tests timed out after %s: %s", testTimeout, command)
+	}
+
+	summary, raw := ParseTestOutput(output.String())
+	if summary.Framework == "" {
+		// Unrecognized output: fall back to the exit status
+		summary.Failed = 0
+		if runErr != nil {
+			summary.Failed = 1
+		}
+	}
+
+	llmContent := fmt.Sprintf("Ran: %s\n%s", command, summary.Format())
+	if summary.Framework == "" {
+		llmContent += "\nOutput (format not recognized):\n" + tail(cleanShellOutput(raw), maxFailureMessage)
+	} else if runErr != nil && summary.Failed == 0 {
+		// e.g. a build failure before any test ran
+		llmContent += fmt.Sprintf("\nThe command failed (%v) without failing tests:\n%s", runErr, tail(cleanShellOutput(raw), maxFailureMessage))
+	}
+
+	status := "✅"
+	if runErr != nil || summary.Failed > 0 {
+		status = "❌"
+	}
+	display := fmt.Sprintf("%s **Tests**: `%s`\n%s\n```\n%s\n```", status, command, summary.Format(), tail(raw, maxTestDisplay))
+
+	return &ToolResult{
+		LLMContent:    llmContent,
+		ReturnDisplay: display,
+	}, nil
+}
+
+// DetectTestCommand picks the test command for the project in dir
+func DetectTestCommand(dir string) (string, error) {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+
+	switch {
+	case exists("go.mod"):
+		return "go test -json ./...", nil
+	case exists("package.json"):
+		return "npm test", nil
+	case exists("pytest.ini"), exists("conftest.py"), exists("pyproject.toml"), exists("setup.py"), exists("tests"):
+		return "pytest -rfE", nil
+	}
+	return "", fmt.Errorf("could not detect the project's test framework; pass command explicitly")
+}
+
+// ParseTestOutput extracts a summary from go test -json, go test or pytest
+// output. It also returns the human-readable output: for test2json input
+// this is the reassembled test output rather than the JSON events.
+func ParseTestOutput(output string) (TestSummary, string) {
+	if summary, raw, ok := parseGoTestJSON(output); ok {
+		return summary, raw
+	}
+	if summary, ok := parsePytest(output); ok {
+		return summary, output
+	}
+	if summary, ok := parseGoTestText(output); ok {
+		return summary, output
+	}
+	return TestSummary{}, output
+}
+
+// goTestEvent is one line of test2json output
+type goTestEvent struct {
+	Action  string
+	Package string
+	Test    string
+	Output  string
+}
+
+func parseGoTestJSON(output string) (TestSummary, string, bool) {
+	summary := TestSummary{Framework: "go"}
+	var raw strings.Builder
+	testOutput := make(map[string]*strings.Builder)
+	events := 0
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		var event goTestEvent
+		if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &event) != nil || event.Action == "" {
+			// Build errors and other tools' output are not JSON
+			raw.WriteString(line + "\n")
+			continue
+		}
+		events++
+		raw.WriteString(event.Output)
+
+		if event.Test == "" {
+			continue
+		}
+		key := event.Package + "." + event.Test
+		switch event.Action {
+		case "output":
+			if testOutput[key] == nil {
+				testOutput[key] = &strings.Builder{}
+			}
+			testOutput[key].WriteString(event.Output)
+		case "pass":
+			summary.Passed++
+		case "skip":
+			summary.Skipped++
+		case "fail":
+			summary.Failed++
+			message := ""
+			if b := testOutput[key]; b != nil {
+				message = b.String()
+			}
+			summary.Failures = append(summary.Failures, TestFailure{
+				Name:    event.Test,
+				Message: goFailureMessage(message),
+			})
+		}
+	}
+
+	if events == 0 {
+		return TestSummary{}, output, false
+	}
+	summary.Total = summary.Passed + summary.Failed + summary.Skipped
+	return summary, raw.String(), true
+}
+
+// goFailureMessage drops the === RUN and --- FAIL framing around a test's output
+func goFailureMessage(output string) string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "=== ") || strings.HasPrefix(trimmed, "--- FAIL") {
+			continue
+		}
+		lines = append(lines, trimmed)
+	}
+	return tail(strings.Join(lines, "\n"), maxFailureMessage)
+}
+
+var goTestResultLine = regexp.MustCompile(`^\s*--- (PASS|FAIL|SKIP): (\S+)`)
+
+// parseGoTestText handles plain go test -v output
+func parseGoTestText(output string) (TestSummary, bool) {
+	summary := TestSummary{Framework: "go"}
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		m := goTestResultLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		switch m[1] {
+		case "PASS":
+			summary.Passed++
+		case "SKIP":
+			summary.Skipped++
+		case "FAIL":
+			summary.Failed++
+			// The indented lines that follow hold the failure messages
+			var message []string
+			for _, next := range lines[i+1:] {
+				if !strings.HasPrefix(next, "    ") || goTestResultLine.MatchString(next) {
+					break
+				}
+				message = append(message, strings.TrimSpace(next))
+			}
+			summary.Failures = append(summary.Failures, TestFailure{
+				Name:    m[2],
+				Message: tail(strings.Join(message, "\n"), maxFailureMessage),
+			})
+		}
+	}
+	summary.Total = summary.Passed + summary.Failed + summary.Skipped
+	return summary, summary.Total > 0
+}
+
+var (
+	pytestFinalLine = regexp.MustCompile(`^=*\s*(.*\b(passed|failed|skipped|error|errors|no tests ran)\b.*) in [\d.]+s`)
+	pytestCount     = regexp.MustCompile(`(\d+) (passed|failed|skipped|errors?)`)
+	pytestSummary   = regexp.MustCompile(`^(FAILED|ERROR) (\S+)(?: - (.*))?$`)
+	pytestSection   = regexp.MustCompile(`^_{3,} (.+?) _{3,}$`)
+)
+
+// parsePytest reads pytest's final counts line, the short test summary
+// (-rfE) for failing test names, and the failure sections for messages
+func parsePytest(output string) (TestSummary, bool) {
+	summary := TestSummary{Framework: "pytest"}
+	found := false
+	sections := make(map[string][]string)
+	current := ""
+
+	for _, line := range strings.Split(output, "\n") {
+		if m := pytestFinalLine.FindStringSubmatch(line); m != nil {
+			found = true
+			for _, c := range pytestCount.FindAllStringSubmatch(m[1], -1) {
+				n := atoiOr(c[1], 0)
+				switch c[2] {
+				case "passed":
+					summary.Passed = n
+				case "failed", "error", "errors":
+					summary.Failed += n
+				case "skipped":
+					summary.Skipped = n
+				}
+			}
+			continue
+		}
+		if m := pytestSummary.FindStringSubmatch(line); m != nil {
+			summary.Failures = append(summary.Failures, TestFailure{Name: m[2], Message: m[3]})
+			current = ""
+			continue
+		}
+		if m := pytestSection.FindStringSubmatch(line); m != nil {
+			current = m[1]
+			continue
+		}
+		if strings.HasPrefix(line, "====") {
+			current = ""
+			continue
+		}
+		if current != "" {
+			sections[current] = append(sections[current], line)
+		}
+	}
+	if !found {
+		return TestSummary{}, false
+	}
+
+	// Prefer the "E" lines of the failure section over the one-line summary
+	for i, failure := range summary.Failures {
+		// Sections are titled "test_name" or "Class.test_name"
+		lines := sections[failure.Name[strings.LastIndex(failure.Name, "::")+2:]]
+		if sep := strings.Index(failure.Name, "::"); sep >= 0 && lines == nil {
+			lines = sections[strings.ReplaceAll(failure.Name[sep+2:], "::", ".")]
+		}
+		var errors []string
+		for _, line := range lines {
+			if strings.HasPrefix(line, "E ") {
+				errors = append(errors, strings.TrimSpace(line[1:]))
+			}
+		}
+		if len(errors) > 0 {
+			summary.Failures[i].Message = tail(strings.Join(errors, "\n"), maxFailureMessage)
+		}
+	}
+	summary.Total = summary.Passed + summary.Failed + summary.Skipped
+	return summary, true
+}
+
+// Format renders the summary for the model
+func (s TestSummary) Format() string {
+	if s.Framework == "" {
+		if s.Failed > 0 {
+			return "Result: FAILED (exit status non-zero)"
+		}
+		return "Result: PASSED"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Total: %d, Passed: %d, Failed: %d, Skipped: %d", s.Total, s.Passed, s.Failed, s.Skipped)
+	if len(s.Failures) > 0 {
+		b.WriteString("\nFailing tests:")
+		for _, f := range s.Failures {
+			fmt.Fprintf(&b, "\n- %s", f.Name)
+			if f.Message != "" {
+				b.WriteString("\n  " + strings.ReplaceAll(f.Message, "\n", "\n  "))
+			}
+		}
+	}
+	return b.String()
+}
+
+// tail keeps the last max bytes of s, where test failures usually are
+func tail(s string, max int) string {
+	s = strings.TrimRight(s, "\n")
+	if len(s) <= max {
+		return s
+	}
+	return "...\n" + s[len(s)-max:]
+}
@@ -0,0 +1,119 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const goTestJSONFixture = `{"Action":"start","Package":"example.com/calc"}
+{"Action":"run","Package":"example.com/calc","Test":"TestAdd"}
+{"Action":"output","Package":"example.com/calc","Test":"TestAdd","Output":"=== RUN   TestAdd\n"}
+{"Action":"output","Package":"example.com/calc","Test":"TestAdd","Output":"--- PASS: TestAdd (0.00s)\n"}
+{"Action":"pass","Package":"example.com/calc","Test":"TestAdd","Elapsed":0}
+{"Action":"run","Package":"example.com/calc","Test":"TestDivide"}
+{"Action":"output","Package":"example.com/calc","Test":"TestDivide","Output":"=== RUN   TestDivide\n"}
+{"Action":"output","Package":"example.com/calc","Test":"TestDivide","Output":"    calc_test.go:21: Divide(1, 0) = 0, want error\n"}
+{"Action":"output","Package":"example.com/calc","Test":"TestDivide","Output":"--- FAIL: TestDivide (0.00s)\n"}
+{"Action":"fail","Package":"example.com/calc","Test":"TestDivide","Elapsed":0}
+{"Action":"run","Package":"example.com/calc","Test":"TestPow"}
+{"Action":"output","Package":"example.com/calc","Test":"TestPow","Output":"--- SKIP: TestPow (0.00s)\n"}
+{"Action":"skip","Package":"example.com/calc","Test":"TestPow","Elapsed":0}
+{"Action":"output","Package":"example.com/calc","Output":"FAIL\n"}
+{"Action":"fail","Package":"example.com/calc","Elapsed":0.01}
+`
+
+const pytestFixture = `============================= test session starts ==============================
+collected 3 items
+
+tests/test_api.py .F.                                                     [100%]
+
+=================================== FAILURES ===================================
+_____________________________ test_create_user ______________________________
+
+    def test_create_user():
+        resp = client.post("/users", json={"name": "a"})
+>       assert resp.status_code == 201
+E       assert 400 == 201
+E        +  where 400 = <Response [400]>.status_code
+
+tests/test_api.py:12: AssertionError
+=========================== short test summary info ============================
+FAILED tests/test_api.py::test_create_user - assert 400 == 201
+========================= 1 failed, 2 passed in 0.42s ==========================
+`
+
+func TestParseGoTestJSON(t *testing.T) {
+	summary, raw := ParseTestOutput(goTestJSONFixture)
+
+	if summary.Framework != "go" || summary.Total != 3 || summary.Passed != 1 || summary.Failed != 1 || summary.Skipped != 1 {
+		t.Fatalf("unexpected counts: %+v", summary)
+	}
+	if len(summary.Failures) != 1 || summary.Failures[0].Name != "TestDivide" {
+		t.Fatalf("expected TestDivide to fail, got %+v", summary.Failures)
+	}
+	if summary.Failures[0].Message != "calc_test.go:21: Divide(1, 0) = 0, want error" {
+		t.Errorf("unexpected failure message %q", summary.Failures[0].Message)
+	}
+	if strings.Contains(raw, `"Action"`) || !strings.Contains(raw, "--- FAIL: TestDivide") {
+		t.Errorf("expected the reassembled test output, got %q", raw)
+	}
+}
+
+func TestParsePytestOutput(t *testing.T) {
+	summary, _ := ParseTestOutput(pytestFixture)
+
+	if summary.Framework != "pytest" || summary.Total != 3 || summary.Passed != 2 || summary.Failed != 1 {
+		t.Fatalf("unexpected counts: %+v", summary)
+	}
+	if len(summary.Failures) != 1 || summary.Failures[0].Name != "tests/test_api.py::test_create_user" {
+		t.Fatalf("unexpected failures: %+v", summary.Failures)
+	}
+	want := "assert 400 == 201\n+  where 400 = <Response [400]>.status_code"
+	if summary.Failures[0].Message != want {
+		t.Errorf("expected the E lines as the message, got %q", summary.Failures[0].Message)
+	}
+
+	formatted := summary.Format()
+	if !strings.Contains(formatted, "Total: 3, Passed: 2, Failed: 1") || !strings.Contains(formatted, "- tests/test_api.py::test_create_user") {
+		t.Errorf("unexpected formatted summary:\n%s", formatted)
+	}
+}
+
+func TestParseGoTestTextOutput(t *testing.T) {
+	output := "=== RUN   TestA\n--- PASS: TestA (0.00s)\n=== RUN   TestB\n    b_test.go:9: got 1, want 2\n--- FAIL: TestB (0.00s)\nFAIL\n"
+	summary, _ := ParseTestOutput(output)
+	if summary.Passed != 1 || summary.Failed != 1 || len(summary.Failures) != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+}
+
+func TestDetectTestCommand(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := DetectTestCommand(dir); err == nil {
+		t.Error("expected detection to fail in an empty directory")
+	}
+
+	os.WriteFile(filepath.Join(dir, "pytest.ini"), nil, 0644)
+	if cmd, _ := DetectTestCommand(dir); !strings.HasPrefix(cmd, "pytest") {
+		t.Errorf("expected pytest, got %q", cmd)
+	}
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/x\n"), 0644)
+	if cmd, _ := DetectTestCommand(dir); cmd != "go test -json ./..." {
+		t.Errorf("expected go test, got %q", cmd)
+	}
+}
+
+func TestRunTestsAppliesShellChecks(t *testing.T) {
+	tool := NewRunTestsTool()
+	if warning := tool.DangerousCall(map[string]interface{}{"command": "go test ./... && sudo rm -rf build"}); warning == "" {
+		t.Error("expected an explicit dangerous command to need confirmation")
+	}
+	if warning := tool.DangerousCall(map[string]interface{}{}); warning != "" {
+		t.Errorf("expected the detected command not to be flagged, got %q", warning)
+	}
+	if _, err := tool.Execute(map[string]interface{}{"command": "rm -rf /"}); err == nil || !strings.Contains(err.Error(), "forbidden") {
+		t.Errorf("expected a forbidden command to be blocked, got %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// NewSearchProvider creates a search backend by name: "brave", "serpapi" or "bing"
+func NewSearchProvider(name, apiKey string) (SearchProvider, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("an API key is required for the %s search provider", name)
+	}
+	switch name {
+	case "brave":
+		return &braveSearch{apiKey: apiKey, endpoint: "https://api.search.brave.com/res/v1/web/search"}, nil
+	case "serpapi":
+		return &serpAPISearch{apiKey: apiKey, endpoint: "https://serpapi.com/search.json"}, nil
+	case "bing":
+		return &bingSearch{apiKey: apiKey, endpoint: "https://api.bing.microsoft.com/v7.0/search"}, nil
+	default:
+		return nil, fmt.Errorf("unknown search provider %q (expected brave, serpapi or bing)", name)
+	}
+}
+
+// getSearchJSON performs a GET request and decodes the JSON response into out.
+// Errors name only the endpoint, since params may carry an API key.
+func getSearchJSON(ctx context.Context, endpoint string, params url.Values, headers map[string]string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = endpoint
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, body)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+type braveSearch struct {
+	apiKey   string
+	endpoint string
+}
+
+func (b *braveSearch) Name() string { return "Brave" }
+
+func (b *braveSearch) Search(ctx context.Context, query string, limit int) ([]SearchResult, error) {
+	var resp struct {
+		Web struct {
+			Results []struct {
+				Title       string `json:"title"`
+				URL         string `json:"url"`
+				Description string `json:"description"`
+			} `json:"results"`
+		} `json:"web"`
+	}
+	params := url.Values{"q": {query}, "count": {strconv.Itoa(limit)}}
+	if err := getSearchJSON(ctx, b.endpoint, params, map[string]string{"X-Subscription-Token": b.apiKey}, &resp); err != nil {
+		return nil, err
+	}
+
+	results := make([]SearchResult, 0, len(resp.Web.Results))
+	for _, r := range resp.Web.Results {
+		results = append(results, SearchResult{Title: r.Title, URL: r.URL, Snippet: r.Description})
+	}
+	return results, nil
+}
+
+type serpAPISearch struct {
+	apiKey   string
+	endpoint string
+}
+
+func (s *serpAPISearch) Name() string { return "SerpAPI" }
+
+func (s *serpAPISearch) Search(ctx context.Context, query string, limit int) ([]SearchResult, error) {
+	var resp struct {
+		OrganicResults []struct {
+			Title   string `json:"title"`
+			Link    string `json:"link"`
+			Snippet string `json:"snippet"`
+		} `json:"organic_results"`
+	}
+	params := url.Values{"q": {query}, "num": {strconv.Itoa(limit)}, "engine": {"google"}, "api_key": {s.apiKey}}
+	if err := getSearchJSON(ctx, s.endpoint, params, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	results := make([]SearchResult, 0, len(resp.OrganicResults))
+	for _, r := range resp.OrganicResults {
+		results = append(results, SearchResult{Title: r.Title, URL: r.Link, Snippet: r.Snippet})
+	}
+	return results, nil
+}
+
+type bingSearch struct {
+	apiKey   string
+	endpoint string
+}
+
+func (b *bingSearch) Name() string { return "Bing" }
+
+func (b *bingSearch) Search(ctx context.Context, query string, limit int) ([]SearchResult, error) {
+	var resp struct {
+		WebPages struct {
+			Value []struct {
+				Name    string `json:"name"`
+				URL     string `json:"url"`
+				Snippet string `json:"snippet"`
+			} `json:"value"`
+		} `json:"webPages"`
+	}
+	params := url.Values{"q": {query}, "count": {strconv.Itoa(limit)}}
+	if err := getSearchJSON(ctx, b.endpoint, params, map[string]string{"Ocp-Apim-Subscription-Key": b.apiKey}, &resp); err != nil {
+		return nil, err
+	}
+
+	results := make([]SearchResult, 0, len(resp.WebPages.Value))
+	for _, r := range resp.WebPages.Value {
+		results = append(results, SearchResult{Title: r.Name, URL: r.URL, Snippet: r.Snippet})
+	}
+	return results, nil
+}
@@ -0,0 +1,130 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// envNamePattern matches names a POSIX shell accepts as variables
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// dangerousEnvNames change which programs a command runs or inject code into
+// it, so setting them makes an otherwise harmless command dangerous
+var dangerousEnvNames = map[string]bool{
+	"PATH":              true,
+	"BASH_ENV":          true,
+	"ENV":               true,
+	"SHELLOPTS":         true,
+	"BASHOPTS":          true,
+	"IFS":               true,
+	"PS4":               true,
+	"PROMPT_COMMAND":    true,
+	"GOFLAGS":           true,
+	"NODE_OPTIONS":      true,
+	"PYTHONPATH":        true,
+	"PYTHONSTARTUP":     true,
+	"PERL5OPT":          true,
+	"PERL5LIB":          true,
+	"RUBYOPT":           true,
+	"RUBYLIB":           true,
+	"JAVA_TOOL_OPTIONS": true,
+	"GIT_SSH_COMMAND":   true,
+	"GIT_EXEC_PATH":     true,
+}
+
+// dangerousEnvPrefixes cover the dynamic loader's variables
+var dangerousEnvPrefixes = []string{"LD_", "DYLD_"}
+
+// dangerousShellEnv returns why a call's env argument needs explicit
+// confirmation, or ""
+func dangerousShellEnv(env map[string]interface{}) string {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		upper := strings.ToUpper(name)
+		if dangerousEnvNames[upper] {
+			return fmt.Sprintf("sets %s, which changes what the command runs", name)
+		}
+		for _, prefix := range dangerousEnvPrefixes {
+			if strings.HasPrefix(upper, prefix) {
+				return fmt.Sprintf("sets the loader variable %s", name)
+			}
+		}
+	}
+	return ""
+}
+
+var (
+	shellEnvMu sync.RWMutex
+	shellEnv   []string // KEY=value pairs added to every run_shell command
+)
+
+// SetShellEnv sets the KEY=value variables every run_shell command gets on
+// top of the inherited environment (tools.run_shell.env). Nil clears them.
+func SetShellEnv(vars []string) error {
+	env := make([]string, 0, len(vars))
+	for _, v := range vars {
+		name, _, ok := strings.Cut(v, "=")
+		if !ok || !envNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid run_shell environment entry %q (expected NAME=value)", v)
+		}
+		env = append(env, v)
+	}
+	shellEnvMu.Lock()
+	shellEnv = env
+	shellEnvMu.Unlock()
+	return nil
+}
+
+// shellEnvArg reads the optional env argument of run_shell. Numbers and
+// booleans are accepted and passed in their usual text form.
+func shellEnvArg(args map[string]interface{}) (map[string]string, error) {
+	raw, ok := args["env"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	values, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, validationError("env must be an object mapping variable names to values")
+	}
+	env := make(map[string]string, len(values))
+	for name, value := range values {
+		if !envNamePattern.MatchString(name) {
+			return nil, validationError("invalid environment variable name %q", name)
+		}
+		switch v := value.(type) {
+		case string:
+			env[name] = v
+		case float64, bool:
+			env[name] = fmt.Sprint(v)
+		default:
+			return nil, validationError("environment variable %s must be a string", name)
+		}
+	}
+	return env, nil
+}
+
+// shellCommandEnv is the environment for a command: the process environment,
+// then the configured variables, then the call's own, later ones winning
+func shellCommandEnv(callEnv map[string]string) []string {
+	shellEnvMu.RLock()
+	env := append(os.Environ(), shellEnv...)
+	shellEnvMu.RUnlock()
+
+	names := make([]string, 0, len(callEnv))
+	for name := range callEnv {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		env = append(env, name+"="+callEnv[name])
+	}
+	return env
+}
@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRunShellPassesEnv(t *testing.T) {
+	if err := SetShellEnv([]string{"AGENTICODE_TEST_MODE=config", "AGENTICODE_TEST_LEVEL=1"}); err != nil {
+		t.Fatal(err)
+	}
+	defer SetShellEnv(nil)
+
+	result, err := NewRunShellTool().Execute(map[string]interface{}{
+		"command": `echo "$NODE_ENV $AGENTICODE_TEST_MODE $AGENTICODE_TEST_LEVEL $RETRIES"`,
+		"env":     map[string]interface{}{"NODE_ENV": "test", "AGENTICODE_TEST_LEVEL": "2", "RETRIES": float64(3)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Call variables win over configured ones
+	if !strings.Contains(result.LLMContent, "test config 2 3\n") {
+		t.Errorf("expected the variables in the command's environment, got %q", result.LLMContent)
+	}
+}
+
+func TestRunShellRejectsInvalidEnvNames(t *testing.T) {
+	for _, env := range []interface{}{
+		map[string]interface{}{"BAD-NAME": "x"},
+		map[string]interface{}{"1ST": "x"},
+		map[string]interface{}{"OK": []interface{}{"x"}},
+		"NODE_ENV=test",
+	} {
+		_, err := NewRunShellTool().Execute(map[string]interface{}{"command": "true", "env": env})
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("%v: expected a validation error, got %v", env, err)
+		}
+	}
+	if err := SetShellEnv([]string{"NODE ENV=test"}); err == nil {
+		t.Error("expected an invalid configured name to be rejected")
+	}
+}
+
+func TestRunShellFlagsLoaderAndPathEnv(t *testing.T) {
+	tool := NewRunShellTool()
+	for _, name := range []string{"PATH", "LD_PRELOAD", "DYLD_INSERT_LIBRARIES", "BASH_ENV", "GOFLAGS"} {
+		args := map[string]interface{}{"command": "go test ./...", "env": map[string]interface{}{name: "x"}}
+		if tool.DangerousCall(args) == "" {
+			t.Errorf("expected setting %s to need confirmation", name)
+		}
+	}
+	if reason := tool.DangerousCall(map[string]interface{}{"command": "go test ./...", "env": map[string]interface{}{"NODE_ENV": "test"}}); reason != "" {
+		t.Errorf("expected an ordinary variable to be allowed, got %q", reason)
+	}
+}
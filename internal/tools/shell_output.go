@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// maxShellOutputBytes caps each output stream of a command sent to the model
+	maxShellOutputBytes = 32 * 1024
+	// shellOutputHead is how much of the start of long output is kept; the
+	// rest of the budget goes to the end, where errors and summaries are
+	shellOutputHead = 8 * 1024
+)
+
+// ansiEscape matches CSI sequences (colors, cursor movement), OSC sequences
+// (titles, hyperlinks) and the remaining two-byte escapes
+var ansiEscape = regexp.MustCompile(`\x1b(\[[0-9;?]*[ -/]*[@-~]|\][^\x07\x1b]*(\x07|\x1b\\)|[@-Z\\-_])`)
+
+// StripANSI removes terminal escape sequences, e.g. syntax highlighting,
+// from text meant for a file rather than the terminal
+func StripANSI(s string) string {
+	return ansiEscape.ReplaceAllString(s, "")
+}
+
+// cleanShellOutput makes terminal output readable as plain text: escape
+// sequences are removed and a line rewritten with carriage returns (progress
+// bars, spinners) is reduced to what was written last
+func cleanShellOutput(s string) string {
+	s = StripANSI(s)
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	if !strings.Contains(s, "\r") {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		// A trailing \r only moves the cursor, so the line before it stays
+		line = strings.TrimRight(line, "\r")
+		if j := strings.LastIndexByte(line, '\r'); j >= 0 {
+			line = line[j+1:]
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// limitShellOutput keeps the head and tail of output longer than
+// maxShellOutputBytes, cut at line boundaries, and says how much was left out
+func limitShellOutput(s string) string {
+	if len(s) <= maxShellOutputBytes {
+		return s
+	}
+	head := s[:shellOutputHead]
+	if i := strings.LastIndexByte(head, '\n'); i >= 0 {
+		head = head[:i+1]
+	}
+	tail := s[len(s)-(maxShellOutputBytes-shellOutputHead):]
+	if i := strings.IndexByte(tail, '\n'); i >= 0 {
+		tail = tail[i+1:]
+	}
+	omitted := s[len(head) : len(s)-len(tail)]
+	return fmt.Sprintf("%s... (%d lines, %d bytes omitted) ...\n%s", head, strings.Count(omitted, "\n"), len(omitted), tail)
+}
+
+// shellOutputForLLM prepares captured command output for the model
+func shellOutputForLLM(s string) string {
+	return limitShellOutput(cleanShellOutput(s))
+}
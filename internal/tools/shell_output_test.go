@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCleanShellOutput(t *testing.T) {
+	raw := "\x1b[1m\x1b[32m✓\x1b[0m build ok\r\n" +
+		"Downloading  10%\rDownloading  55%\rDownloading 100%\n" +
+		"\x1b]8;;https://example.com\x07link\x1b]8;;\x07 \x1b[31mFAIL\x1b[0m parser_test.go\n" +
+		"spinner |\r\x1b[2Kspinner /\r\x1b[2Kdone\r\n"
+	want := "✓ build ok\nDownloading 100%\nlink FAIL parser_test.go\ndone\n"
+	if got := cleanShellOutput(raw); got != want {
+		t.Errorf("unexpected cleaned output:\n got %q\nwant %q", got, want)
+	}
+
+	result, err := NewRunShellTool().Execute(map[string]interface{}{"command": `printf '\033[31mred\033[0m\r\n50%%\r100%%\n'`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(result.LLMContent, "Stdout:\nred\n100%\n") {
+		t.Errorf("expected clean output for the model, got %q", result.LLMContent)
+	}
+	if !strings.Contains(result.ReturnDisplay, "\x1b[31m") {
+		t.Errorf("expected the display to keep colors, got %q", result.ReturnDisplay)
+	}
+}
+
+func TestLimitShellOutputKeepsHeadAndTail(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 5000; i++ {
+		b.WriteString("line of build output\n")
+	}
+	b.WriteString("FAIL: the error at the end\n")
+	output := "first line\n" + b.String()
+
+	limited := limitShellOutput(output)
+	if len(limited) > maxShellOutputBytes+100 {
+		t.Errorf("expected the output to be capped, got %d bytes", len(limited))
+	}
+	if !strings.HasPrefix(limited, "first line\n") || !strings.HasSuffix(limited, "FAIL: the error at the end\n") {
+		t.Error("expected the head and tail to survive")
+	}
+	if !strings.Contains(limited, "lines,") || !strings.Contains(limited, "bytes omitted) ...\nline of build output\n") {
+		t.Errorf("expected an omission marker on a line boundary")
+	}
+	if short := "ok\n"; limitShellOutput(short) != short {
+		t.Error("expected short output unchanged")
+	}
+}
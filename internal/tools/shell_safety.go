@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// shellPattern flags shell commands matching re
+type shellPattern struct {
+	re     *regexp.Regexp
+	reason string
+}
+
+// forbiddenShellPatterns are never run, whoever approves them
+var forbiddenShellPatterns = []shellPattern{
+	{regexp.MustCompile(`:\(\)\s*\{\s*:\s*\|\s*:\s*&\s*\}\s*;\s*:`), "fork bomb"},
+	{regexp.MustCompile(`(?i)\brm\s+(-[a-z]+\s+)*(--no-preserve-root\s+)?(-[a-z]+\s+)*/\*?(\s|;|&|$)`), "deletes the root filesystem"},
+	{regexp.MustCompile(`(?i)\bmkfs(\.\w+)?\s+\S*/dev/`), "formats a disk"},
+}
+
+// DefaultDangerousShellPatterns are the commands that need explicit
+// confirmation unless tools.run_shell.dangerous_patterns replaces them
+var DefaultDangerousShellPatterns = []string{
+	`\brm\s+-[a-z]*(r[a-z]*f|f[a-z]*r)`,
+	`\bsudo\b`,
+	`\bchmod\s+(-r\s+)?777\b`,
+	`\b(curl|wget)\b[^|]*\|\s*(ba|z)?sh\b`,
+}
+
+var (
+	dangerousMu       sync.RWMutex
+	dangerousPatterns = mustCompileShellPatterns(DefaultDangerousShellPatterns)
+)
+
+// SetDangerousShellPatterns replaces the regexes (matched case-insensitively)
+// that make a shell command require explicit confirmation. Nil restores the
+// defaults; an empty list turns the check off.
+func SetDangerousShellPatterns(patterns []string) error {
+	if patterns == nil {
+		patterns = DefaultDangerousShellPatterns
+	}
+	compiled, err := compileShellPatterns(patterns)
+	if err != nil {
+		return err
+	}
+	dangerousMu.Lock()
+	dangerousPatterns = compiled
+	dangerousMu.Unlock()
+	return nil
+}
+
+// ForbiddenShellCommand returns why a command may never run, or ""
+func ForbiddenShellCommand(command string) string {
+	for _, p := range forbiddenShellPatterns {
+		if p.re.MatchString(command) {
+			return p.reason
+		}
+	}
+	return ""
+}
+
+// DangerousShellCommand returns why a command needs explicit confirmation
+// from the user, or ""
+func DangerousShellCommand(command string) string {
+	dangerousMu.RLock()
+	defer dangerousMu.RUnlock()
+	for _, p := range dangerousPatterns {
+		if p.re.MatchString(command) {
+			return p.reason
+		}
+	}
+	return ""
+}
+
+func compileShellPatterns(patterns []string) ([]shellPattern, error) {
+	compiled := make([]shellPattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(`(?i)` + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dangerous shell pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, shellPattern{re: re, reason: fmt.Sprintf("matches dangerous pattern %q", pattern)})
+	}
+	return compiled, nil
+}
+
+func mustCompileShellPatterns(patterns []string) []shellPattern {
+	compiled, err := compileShellPatterns(patterns)
+	if err != nil {
+		panic(err)
+	}
+	return compiled
+}
@@ -0,0 +1,284 @@
+package tools
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxScopeFiles stops counting in very large directory trees
+const maxScopeFiles = 10000
+
+// ShellScope is a best-effort estimate of the files one rm, mv, cp, chmod or
+// chown in a shell command would touch
+type ShellScope struct {
+	Verb        string
+	Paths       []string // Existing operands after glob expansion
+	Files       int      // Files in Paths, including those inside directories the command recurses into
+	Truncated   bool     // Counting stopped at maxScopeFiles
+	Destination string   // Target of mv and cp
+}
+
+func (s ShellScope) String() string {
+	files := fmt.Sprintf("%d file", s.Files)
+	if s.Truncated {
+		files = fmt.Sprintf("more than %d file", s.Files)
+	}
+	if s.Files != 1 || s.Truncated {
+		files += "s"
+	}
+	paths := "path"
+	if len(s.Paths) != 1 {
+		paths += "s"
+	}
+	summary := fmt.Sprintf("%s: this will affect %s in %d %s", s.Verb, files, len(s.Paths), paths)
+	if s.Destination != "" {
+		summary += " (to " + s.Destination + ")"
+	}
+	return summary
+}
+
+// shellWord is one word of a command; quoted words are never glob-expanded
+type shellWord struct {
+	text   string
+	quoted bool
+}
+
+// EstimateShellScope reports what the recognizable file commands in command
+// would touch when run in dir. Commands using variables, substitutions or
+// options it does not understand are skipped rather than guessed at, and
+// nothing after a change of directory is estimated since dir no longer
+// applies.
+func EstimateShellScope(command, dir string) []ShellScope {
+	var scopes []ShellScope
+	for _, cmd := range splitShellCommand(command) {
+		words := stripSudo(cmd.words)
+		if len(words) > 0 && !words[0].quoted && changesDir(words[0].text) {
+			break
+		}
+		if cmd.unknown {
+			continue
+		}
+		if scope, ok := estimateScope(words, dir); ok {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+func stripSudo(words []shellWord) []shellWord {
+	if len(words) > 0 && words[0].text == "sudo" {
+		return words[1:]
+	}
+	return words
+}
+
+func changesDir(verb string) bool {
+	return verb == "cd" || verb == "pushd" || verb == "popd"
+}
+
+// estimateScope reports false when it cannot tell what the command touches,
+// including when none of its operands exist, so a count of zero is never
+// offered as reassurance
+func estimateScope(words []shellWord, dir string) (ShellScope, bool) {
+	if len(words) < 2 {
+		return ShellScope{}, false
+	}
+
+	scope := ShellScope{Verb: words[0].text}
+	var flags []string
+	var operands []shellWord
+	for i, w := range words[1:] {
+		if w.text == "--" && !w.quoted {
+			operands = append(operands, words[i+2:]...)
+			break
+		}
+		if strings.HasPrefix(w.text, "-") && !w.quoted {
+			flags = append(flags, w.text)
+		} else {
+			operands = append(operands, w)
+		}
+	}
+
+	var recursive bool
+	switch scope.Verb {
+	case "rm":
+		recursive = hasFlag(flags, "--recursive", 'r', 'R')
+	case "chmod", "chown":
+		recursive = hasFlag(flags, "--recursive", 'R')
+		if len(operands) < 2 {
+			return ShellScope{}, false
+		}
+		operands = operands[1:] // Mode or owner
+	case "mv", "cp":
+		if hasFlag(flags, "--target-directory", 't') || len(operands) < 2 {
+			return ShellScope{}, false
+		}
+		scope.Destination = operands[len(operands)-1].text
+		operands = operands[:len(operands)-1]
+		// A moved directory takes its whole tree along
+		recursive = scope.Verb == "mv" || hasFlag(flags, "--recursive", 'r', 'R') || hasFlag(flags, "--archive", 'a')
+	default:
+		return ShellScope{}, false
+	}
+
+	for _, operand := range operands {
+		if !operand.quoted && strings.HasPrefix(operand.text, "~") {
+			return ShellScope{}, false // Home directory, not dir
+		}
+	}
+	for _, operand := range operands {
+		for _, path := range expandOperand(operand, dir) {
+			info, err := os.Lstat(resolveIn(dir, path))
+			if err != nil {
+				continue // Nothing there to touch
+			}
+			scope.Paths = append(scope.Paths, path)
+			if !info.IsDir() {
+				scope.Files++
+			} else if recursive {
+				scope.Truncated = countFiles(resolveIn(dir, path), &scope.Files) || scope.Truncated
+			}
+			if scope.Truncated {
+				return scope, true
+			}
+		}
+	}
+	if scope.Files == 0 {
+		return ShellScope{}, false
+	}
+	return scope, true
+}
+
+// hasFlag reports whether flags contain long, or a short option cluster
+// with any of the given letters
+func hasFlag(flags []string, long string, short ...rune) bool {
+	for _, flag := range flags {
+		if flag == long {
+			return true
+		}
+		if strings.HasPrefix(flag, "--") {
+			continue
+		}
+		for _, letter := range short {
+			if strings.ContainsRune(flag[1:], letter) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// expandOperand applies the shell's glob expansion to an unquoted word; a
+// pattern without matches is passed through literally, as sh does
+func expandOperand(word shellWord, dir string) []string {
+	if word.quoted || !strings.ContainsAny(word.text, "*?[") {
+		return []string{word.text}
+	}
+	matches, err := filepath.Glob(resolveIn(dir, word.text))
+	if err != nil || len(matches) == 0 {
+		return []string{word.text}
+	}
+	if !filepath.IsAbs(word.text) && dir != "" {
+		for i, match := range matches {
+			if rel, err := filepath.Rel(dir, match); err == nil {
+				matches[i] = rel
+			}
+		}
+	}
+	return matches
+}
+
+func resolveIn(dir, path string) string {
+	if filepath.IsAbs(path) || dir == "" {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// countFiles adds the non-directories under root to count and reports
+// whether it stopped at maxScopeFiles
+func countFiles(root string, count *int) bool {
+	truncated := false
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		*count++
+		if *count >= maxScopeFiles {
+			truncated = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return truncated
+}
+
+// shellCommand is one simple command; unknown commands contain expansions
+// ($, backquotes), subshells or redirections, so their operands cannot be
+// known in advance
+type shellCommand struct {
+	words   []shellWord
+	unknown bool
+}
+
+// splitShellCommand splits a command line into the words of each simple
+// command, separated by ;, &, | and newlines
+func splitShellCommand(command string) []shellCommand {
+	var commands []shellCommand
+	var words []shellWord
+	var word strings.Builder
+	inWord, quoted, unknown := false, false, false
+
+	endWord := func() {
+		if inWord {
+			words = append(words, shellWord{text: word.String(), quoted: quoted})
+		}
+		word.Reset()
+		inWord, quoted = false, false
+	}
+	endCommand := func() {
+		endWord()
+		if len(words) > 0 {
+			commands = append(commands, shellCommand{words: words, unknown: unknown})
+		}
+		words, unknown = nil, false
+	}
+
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch c {
+		case ' ', '\t':
+			endWord()
+		case ';', '&', '|', '\n':
+			endCommand()
+		case '$', '`', '<', '>', '(', ')':
+			unknown = true
+		case '\\':
+			if i+1 < len(command) {
+				i++
+				word.WriteByte(command[i])
+				inWord, quoted = true, true
+			}
+		case '\'', '"':
+			end := strings.IndexByte(command[i+1:], c)
+			if end < 0 {
+				return commands // Unterminated quote
+			}
+			text := command[i+1 : i+1+end]
+			if c == '"' && strings.ContainsAny(text, "$`") {
+				unknown = true
+			}
+			word.WriteString(text)
+			inWord, quoted = true, true
+			i += end + 1
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	endCommand()
+	return commands
+}
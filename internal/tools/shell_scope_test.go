@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEstimateShellScopeCountsRmTargets(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "build", "sub"), 0755)
+	for _, name := range []string{"a.log", "b.log", "keep.txt", "build/x.o", "build/y.o", "build/sub/z.o"} {
+		os.WriteFile(filepath.Join(dir, name), nil, 0644)
+	}
+
+	scopes := EstimateShellScope("rm -rf build *.log missing.log", dir)
+	if len(scopes) != 1 {
+		t.Fatalf("expected one scope, got %+v", scopes)
+	}
+	scope := scopes[0]
+	if len(scope.Paths) != 3 || scope.Files != 5 {
+		t.Fatalf("expected 5 files in 3 paths, got %+v", scope)
+	}
+	if got := scope.String(); got != "rm: this will affect 5 files in 3 paths" {
+		t.Errorf("unexpected summary %q", got)
+	}
+
+	// Without -r, rm does not descend into the directory
+	if scopes := EstimateShellScope("rm build a.log", dir); len(scopes) != 1 || scopes[0].Files != 1 {
+		t.Errorf("expected only a.log to count, got %+v", scopes)
+	}
+	// Quoted globs are not expanded
+	if scopes := EstimateShellScope(`rm "*.log"`, dir); len(scopes) != 0 {
+		t.Errorf("expected a quoted glob to match nothing, got %+v", scopes)
+	}
+	if scopes := EstimateShellScope("go build ./... && mv *.log build", dir); len(scopes) != 1 || scopes[0].Files != 2 || scopes[0].Destination != "build" {
+		t.Errorf("expected mv of two logs into build, got %+v", scopes)
+	}
+}
+
+func TestEstimateShellScopeSkipsUnknownOperands(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.log"), nil, 0644)
+	for _, command := range []string{
+		"rm -rf $BUILD_DIR", "rm $(cat list.txt)", "ls -la", "cp -t out a b", "rm 'unterminated",
+		"rm -rf ~/project a.log", "rm missing.log", "cd internal && rm -rf *", "cd $TMP; rm a.log", "(cd sub && rm a.log)",
+	} {
+		if scopes := EstimateShellScope(command, dir); len(scopes) != 0 {
+			t.Errorf("%q: expected no estimate, got %+v", command, scopes)
+		}
+	}
+}
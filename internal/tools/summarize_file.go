@@ -0,0 +1,202 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// summarizeChunkBytes is how much of the file goes into one LLM call
+	summarizeChunkBytes = 24 * 1024
+	// maxSummarizeChunks bounds the cost of summarizing one file
+	maxSummarizeChunks = 20
+	// summarizeTimeout applies to each LLM call
+	summarizeTimeout = 60 * time.Second
+)
+
+// SummarizeFileTool asks an LLM for a structured summary of a file that is
+// too large to read into context. Large files are summarized in chunks and
+// the chunk summaries are then merged.
+type SummarizeFileTool struct {
+	llmClient LLMProcessor
+}
+
+// NewSummarizeFileTool creates a summarize_file tool that uses llmClient
+func NewSummarizeFileTool(llmClient interface{}) *SummarizeFileTool {
+	tool := &SummarizeFileTool{}
+	if client, ok := llmClient.(LLMProcessor); ok {
+		tool.llmClient = client
+	}
+	return tool
+}
+
+func (t *SummarizeFileTool) Name() string {
+	return "summarize_file"
+}
+
+func (t *SummarizeFileTool) Description() string {
+	return "Summarize a large file: its purpose, key functions and types, and notable sections with line ranges. Use it instead of reading a big file whole, then read only the lines you need"
+}
+
+func (t *SummarizeFileTool) ReadOnly() bool {
+	return true
+}
+
+func (t *SummarizeFileTool) GetParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "The file to summarize",
+			},
+			"focus": map[string]interface{}{
+				"type":        "string",
+				"description": "Optional: what you are looking for, so the summary covers it in more detail",
+			},
+		},
+		"required": []string{"path"},
+	}
+}
+
+func (t *SummarizeFileTool) Execute(args map[string]interface{}) (*ToolResult, error) {
+	path, _ := args["path"].(string)
+	if path == "" {
+		return nil, requiredArg("path")
+	}
+	focus, _ := args["focus"].(string)
+	if t.llmClient == nil {
+		return nil, fmt.Errorf("summarize_file needs an LLM client")
+	}
+
+	chunks, totalLines, err := readNumberedChunks(path, summarizeChunkBytes, maxSummarizeChunks)
+	if err != nil {
+		return nil, err
+	}
+	if totalLines == 0 {
+		return &ToolResult{
+			LLMContent:    fmt.Sprintf("%s is empty", path),
+			ReturnDisplay: fmt.Sprintf("📄 `%s` is empty", path),
+		}, nil
+	}
+
+	var summary string
+	if len(chunks) == 1 {
+		summary, err = t.ask(chunks[0].text, summarizeFilePrompt(path, focus))
+	} else {
+		partials := make([]string, 0, len(chunks))
+		for _, chunk := range chunks {
+			partial, err := t.ask(chunk.text, summarizeChunkPrompt(path, chunk.first, chunk.last, totalLines, focus))
+			if err != nil {
+				return nil, err
+			}
+			partials = append(partials, fmt.Sprintf("Lines %d-%d:\n%s", chunk.first, chunk.last, partial))
+		}
+		summary, err = t.ask(strings.Join(partials, "\n\n"), mergeSummariesPrompt(path, totalLines, focus))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &ToolResult{
+		LLMContent:    fmt.Sprintf("Summary of %s (%d lines):\n%s", path, totalLines, summary),
+		ReturnDisplay: fmt.Sprintf("📄 Summarized `%s` (%d lines, %d chunks)", path, totalLines, len(chunks)),
+	}, nil
+}
+
+func (t *SummarizeFileTool) ask(content, prompt string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), summarizeTimeout)
+	defer cancel()
+	summary, err := t.llmClient.ProcessContent(ctx, content, prompt)
+	if err != nil {
+		return "", timeoutError(fmt.Errorf("failed to summarize: %w", err))
+	}
+	return summary, nil
+}
+
+// numberedChunk is a run of file lines prefixed with their line numbers
+type numberedChunk struct {
+	first, last int
+	text        string
+}
+
+// readNumberedChunks streams the file into chunks of about chunkBytes,
+// numbering each line so summaries can cite line ranges
+func readNumberedChunks(path string, chunkBytes, maxChunks int) ([]numberedChunk, int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, fileError("open", path, err)
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if dotenvMasked(path) {
+		// Mask the whole file so values spanning lines stay hidden
+		content, err := io.ReadAll(io.LimitReader(file, int64(chunkBytes*maxChunks)+1))
+		if err != nil {
+			return nil, 0, fileError("read", path, err)
+		}
+		masked, _ := maskDotenvContent(path, string(content))
+		reader = strings.NewReader(masked)
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var chunks []numberedChunk
+	var current strings.Builder
+	first, line := 1, 0
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, numberedChunk{first: first, last: line, text: current.String()})
+			current.Reset()
+		}
+		first = line + 1
+	}
+
+	for scanner.Scan() {
+		line++
+		fmt.Fprintf(&current, "%6d| %s\n", line, scanner.Text())
+		if current.Len() >= chunkBytes {
+			flush()
+			if len(chunks) >= maxChunks {
+				return nil, 0, validationError("%s is too large to summarize (over %d bytes); use grep to find the relevant part", path, chunkBytes*maxChunks)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fileError("read", path, err)
+	}
+	flush()
+	return chunks, line, nil
+}
+
+const summaryFormat = `Use this format:
+Purpose: one or two sentences.
+Key definitions: the main functions, types and constants, each with its line range.
+Notable sections: other important parts (configuration, error handling, TODOs) with line ranges.
+Be concise and only use line numbers shown in the content.`
+
+func summarizeFilePrompt(path, focus string) string {
+	return fmt.Sprintf("The content above is the file %s with line numbers. Summarize it.\n%s%s", path, summaryFormat, focusNote(focus))
+}
+
+func summarizeChunkPrompt(path string, first, last, total int, focus string) string {
+	return fmt.Sprintf("The content above is lines %d-%d of the %d-line file %s. Summarize this part.\n%s%s", first, last, total, path, summaryFormat, focusNote(focus))
+}
+
+func mergeSummariesPrompt(path string, total int, focus string) string {
+	return fmt.Sprintf("The content above is summaries of consecutive parts of the %d-line file %s. Merge them into one summary of the whole file, keeping the line ranges.\n%s%s", total, path, summaryFormat, focusNote(focus))
+}
+
+func focusNote(focus string) string {
+	if focus == "" {
+		return ""
+	}
+	return fmt.Sprintf("\nCover anything related to %q in more detail.", focus)
+}
@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// funcListingLLM "summarizes" by listing the Go functions it sees
+type funcListingLLM struct {
+	prompts []string
+}
+
+var funcName = regexp.MustCompile(`func (\w+)`)
+
+func (l *funcListingLLM) ProcessContent(ctx context.Context, content, prompt string) (string, error) {
+	l.prompts = append(l.prompts, prompt)
+	if strings.Contains(prompt, "Merge them") {
+		return "Merged:\n" + content, nil
+	}
+	var names []string
+	for _, m := range funcName.FindAllStringSubmatch(content, -1) {
+		names = append(names, m[1])
+	}
+	return "Key definitions: " + strings.Join(names, ", "), nil
+}
+
+func TestSummarizeFileChunksLargeFiles(t *testing.T) {
+	var src strings.Builder
+	src.WriteString("package big\n\n")
+	for i := 0; i < 800; i++ {
+		fmt.Fprintf(&src, "// Handler%d handles case %d\nfunc Handler%d(x int) int {\n\treturn x + %d\n}\n\n", i, i, i, i)
+	}
+	path := filepath.Join(t.TempDir(), "big.go")
+	if err := os.WriteFile(path, []byte(src.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	llm := &funcListingLLM{}
+	result, err := NewSummarizeFileTool(llm).Execute(map[string]interface{}{"path": path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(llm.prompts) < 3 || !strings.Contains(llm.prompts[len(llm.prompts)-1], "Merge them") {
+		t.Fatalf("expected several chunk calls and a merge, got %d calls", len(llm.prompts))
+	}
+	if !strings.Contains(llm.prompts[0], "lines 1-") {
+		t.Errorf("expected chunk prompts to carry line ranges, got %q", llm.prompts[0])
+	}
+	for _, name := range []string{"Handler0", "Handler400", "Handler799"} {
+		if !strings.Contains(result.LLMContent, name) {
+			t.Errorf("expected the summary to mention %s", name)
+		}
+	}
+}
+
+func TestSummarizeFileRequiresLLM(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "small.txt")
+	os.WriteFile(path, []byte("hello\n"), 0644)
+
+	if _, err := NewSummarizeFileTool(nil).Execute(map[string]interface{}{"path": path}); err == nil {
+		t.Error("expected an error without an LLM client")
+	}
+}
@@ -37,7 +37,6 @@ func (t *TodoReadTool) GetParameters() map[string]interface{} {
 func (t *TodoReadTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 	// Get all todos
 	todos := GlobalTodoStore.ReadAll()
-	fmt.Println("=====================todo_read=================")
 
 	// Sort by creation time for consistent ordering
 	sort.Slice(todos, func(i, j int) bool {
@@ -60,7 +60,7 @@ func (t *TodoWriteTool) GetParameters() map[string]interface{} {
 func (t *TodoWriteTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 	rawItems, ok := args["items"]
 	if !ok {
-		return nil, fmt.Errorf("missing required parameter 'items'")
+		return nil, validationError("missing required parameter 'items'")
 	}
 
 	// Convert the raw items to JSON and back to properly typed structs
@@ -71,17 +71,17 @@ func (t *TodoWriteTool) Execute(args map[string]interface{}) (*ToolResult, error
 
 	var items []TodoItem
 	if err := json.Unmarshal(jsonBytes, &items); err != nil {
-		return nil, fmt.Errorf("failed to parse todo items: %w", err)
+		return nil, validationError("failed to parse todo items: %w", err)
 	}
 
 	if len(items) == 0 {
-		return nil, fmt.Errorf("items array cannot be empty")
+		return nil, validationError("items array cannot be empty")
 	}
 
 	// Validate items
 	for i, item := range items {
 		if item.Title == "" {
-			return nil, fmt.Errorf("item %d: title cannot be empty", i)
+			return nil, validationError("item %d: title cannot be empty", i)
 		}
 		if item.State == "" {
 			items[i].State = TodoPending // Default to pending if not specified
@@ -91,7 +91,7 @@ func (t *TodoWriteTool) Execute(args map[string]interface{}) (*ToolResult, error
 		case TodoPending, TodoInProgress, TodoCompleted:
 			// Valid state
 		default:
-			return nil, fmt.Errorf("item %d: invalid state '%s'", i, item.State)
+			return nil, validationError("item %d: invalid state '%s'", i, item.State)
 		}
 	}
 
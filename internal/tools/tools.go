@@ -17,6 +17,25 @@ type Tool interface {
 	GetParameters() map[string]interface{}
 }
 
+// ConfirmationDetailer is implemented by tools that describe their own calls
+// in approval prompts; other tools get a generic rendering of their arguments
+type ConfirmationDetailer interface {
+	ConfirmationDetails(args map[string]interface{}) *ConfirmationDetails
+}
+
+// DangerChecker is implemented by tools whose calls can be destructive enough
+// that only an explicit yes from the user may run them
+type DangerChecker interface {
+	// DangerousCall returns why the call needs that confirmation, or ""
+	DangerousCall(args map[string]interface{}) string
+}
+
+// ConfirmationDetails is a tool's own summary of a pending call
+type ConfirmationDetails struct {
+	Title   string // One line, e.g. "Move a.txt → b.txt"
+	Preview string // Optional multi-line body, e.g. the query to run
+}
+
 // ToolResult represents the result of a tool execution
 type ToolResult struct {
 	// LLMContent is the factual content to be included in the LLM history
@@ -25,6 +44,9 @@ type ToolResult struct {
 	ReturnDisplay string
 	// Error indicates if the tool execution failed
 	Error error
+	// Recovery suggests how to recover from Error; it is added to the
+	// tool response after the error message
+	Recovery string
 }
 
 type WriteFileTool struct{}
@@ -45,15 +67,24 @@ func (t *WriteFileTool) ReadOnly() bool {
 	return false
 }
 
+// DangerousCall flags writes too large to be approved automatically
+func (t *WriteFileTool) DangerousCall(args map[string]interface{}) string {
+	content, _ := args["content"].(string)
+	return largeWriteWarning(len(content))
+}
+
 func (t *WriteFileTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 	path, ok := args["path"].(string)
 	if !ok {
-		return nil, fmt.Errorf("path is required")
+		return nil, requiredArg("path")
 	}
 
 	content, ok := args["content"].(string)
 	if !ok {
-		return nil, fmt.Errorf("content is required")
+		return nil, requiredArg("content")
+	}
+	if err := checkWriteSize(path, len(content)); err != nil {
+		return nil, err
 	}
 
 	dir := filepath.Dir(path)
@@ -68,11 +99,13 @@ func (t *WriteFileTool) Execute(args map[string]interface{}) (*ToolResult, error
 	// Count lines in the content
 	lines := strings.Count(content, "\n") + 1
 
-	return &ToolResult{
+	result := &ToolResult{
 		LLMContent:    fmt.Sprintf("Successfully wrote %d lines to %s", lines, path),
 		ReturnDisplay: fmt.Sprintf("✅ Created file: `%s` (%d lines)", path, lines),
 		Error:         nil,
-	}, nil
+	}
+	formatAfterWrite(path, result)
+	return result, nil
 }
 
 func (t *WriteFileTool) GetParameters() map[string]interface{} {
@@ -110,39 +143,52 @@ func (t *RunShellTool) ReadOnly() bool {
 	return false
 }
 
+// DangerousCall flags commands matching the dangerous shell patterns and
+// environment variables that change which programs or code the shell runs
+func (t *RunShellTool) DangerousCall(args map[string]interface{}) string {
+	command, _ := args["command"].(string)
+	if reason := DangerousShellCommand(command); reason != "" {
+		return reason
+	}
+	env, _ := args["env"].(map[string]interface{})
+	return dangerousShellEnv(env)
+}
+
 func (t *RunShellTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 	command, ok := args["command"].(string)
 	if !ok {
-		return nil, fmt.Errorf("command is required")
+		return nil, requiredArg("command")
 	}
 
-	// Security: Basic command validation
-	dangerousCommands := []string{"rm -rf", "sudo", "chmod 777", "curl | sh", "wget | sh"}
-	lowerCommand := strings.ToLower(command)
-	for _, dangerous := range dangerousCommands {
-		if strings.Contains(lowerCommand, dangerous) {
-			return nil, fmt.Errorf("potentially dangerous command blocked: %s", command)
-		}
+	// Security: dangerous commands are gated at approval (see DangerousCall);
+	// a few are never allowed at all
+	if reason := ForbiddenShellCommand(command); reason != "" {
+		return nil, fmt.Errorf("forbidden command blocked (%s): %s", reason, command)
+	}
+	env, err := shellEnvArg(args)
+	if err != nil {
+		return nil, err
 	}
 
 	// Execute command
 	cmd := exec.Command("sh", "-c", command)
+	cmd.Env = shellCommandEnv(env)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	err = cmd.Run()
 
 	stdoutStr := stdout.String()
 	stderrStr := stderr.String()
 
-	// Build LLM content
+	// Build LLM content; the display keeps the colors for the terminal
 	llmContent := fmt.Sprintf("Executed: %s", command)
 	if stdoutStr != "" {
-		llmContent += fmt.Sprintf("\nStdout:\n%s", stdoutStr)
+		llmContent += fmt.Sprintf("\nStdout:\n%s", shellOutputForLLM(stdoutStr))
 	}
 	if stderrStr != "" {
-		llmContent += fmt.Sprintf("\nStderr:\n%s", stderrStr)
+		llmContent += fmt.Sprintf("\nStderr:\n%s", shellOutputForLLM(stderrStr))
 	}
 	if err != nil {
 		llmContent += fmt.Sprintf("\nError: %v", err)
@@ -178,6 +224,11 @@ func (t *RunShellTool) GetParameters() map[string]interface{} {
 				"type":        "string",
 				"description": "The shell command to execute",
 			},
+			"env": map[string]interface{}{
+				"type":                 "object",
+				"description":          "Environment variables to set for this command, e.g. {\"NODE_ENV\": \"test\"}; use this instead of export",
+				"additionalProperties": map[string]interface{}{"type": "string"},
+			},
 		},
 		"required": []string{"command"},
 	}
@@ -202,41 +253,54 @@ func (t *ReadFileTool) ReadOnly() bool {
 }
 
 func (t *ReadFileTool) GetParameters() map[string]interface{} {
+	properties := readPagingParameters()
+	properties["path"] = map[string]interface{}{
+		"type":        "string",
+		"description": "The file path to read",
+	}
 	return map[string]interface{}{
-		"type": "object",
-		"properties": map[string]interface{}{
-			"path": map[string]interface{}{
-				"type":        "string",
-				"description": "The file path to read",
-			},
-		},
-		"required": []string{"path"},
+		"type":       "object",
+		"properties": properties,
+		"required":   []string{"path"},
 	}
 }
 
 func (t *ReadFileTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 	path, ok := args["path"].(string)
 	if !ok {
-		return nil, fmt.Errorf("path is required")
+		return nil, requiredArg("path")
 	}
 
-	content, err := os.ReadFile(path)
+	content, enc, err := readTextFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, fileError("read", path, err)
 	}
 
-	contentStr := string(content)
+	contentStr, masked := maskDotenvContent(path, content)
 	lines := strings.Count(contentStr, "\n") + 1
+	note := ""
+	if masked {
+		note = " " + dotenvNoteLLM
+	}
+	if enc.note() != "" {
+		note += " " + enc.note()
+	}
 
-	// For display, show line numbers
-	var displayLines []string
-	for i, line := range strings.Split(contentStr, "\n") {
-		displayLines = append(displayLines, fmt.Sprintf("%4d | %s", i+1, line))
+	page, err := pageFile(t.Name(), contentStr, args)
+	if err != nil {
+		return nil, err
+	}
+
+	// For display, show line numbers in a code block tagged with the language
+	llmContent := fmt.Sprintf("File content of %s%s:\n%s", path, note, page.Content)
+	displayContent := fmt.Sprintf("📄 **%s** (%d lines)%s:\n%s", path, lines, note, fencedFile(path, page.Content, page.FirstLine))
+	if page.Footer != "" {
+		llmContent += "\n" + page.Footer
+		displayContent += "\n" + page.Footer
 	}
-	displayContent := fmt.Sprintf("📄 **%s** (%d lines):\n```\n%s\n```", path, lines, strings.Join(displayLines, "\n"))
 
 	return &ToolResult{
-		LLMContent:    fmt.Sprintf("File content of %s:\n%s", path, contentStr),
+		LLMContent:    llmContent,
 		ReturnDisplay: displayContent,
 		Error:         nil,
 	}, nil
@@ -353,6 +417,7 @@ func GetDefaultTools() []Tool {
 	return []Tool{
 		&WriteFileTool{},
 		&RunShellTool{},
+		&RunTestsTool{},
 		&ReadTool{},
 		&ReadFileTool{},
 		&ListFilesTool{},
@@ -360,10 +425,22 @@ func GetDefaultTools() []Tool {
 		&GlobTool{},
 		&EditTool{},
 		&MultiEditTool{},
+		&EditDiffTool{},
+		&ASTEditTool{},
+		&MakeDirectoryTool{},
+		&DeleteFileTool{},
 		&ReadManyFilesTool{},
+		&WatchFileTool{},
+		&ReadBytesTool{},
 		&ApplyPatchTool{},
 		&TodoWriteTool{},
 		&TodoReadTool{},
+		&AskUserTool{},
+		&MemoryWriteTool{},
+		&MemoryReadTool{},
+		&PinFileTool{},
+		&GitDiffTool{},
+		&GitCommitTool{},
 	}
 }
 
@@ -376,6 +453,7 @@ func GetDefaultToolsWithLLM(llmClient interface{}) []Tool {
 		// We need to import llm package here, but to avoid circular dependency,
 		// we'll use interface{} and type assertion in web_fetch
 		tools = append(tools, NewWebFetchTool(llmClient))
+		tools = append(tools, NewSummarizeFileTool(llmClient))
 	}
 
 	return tools
@@ -0,0 +1,138 @@
+package tools
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	// maxWatchBytes caps how much new content one watch_file call returns
+	maxWatchBytes = 32 * 1024
+	// watchInitialTail is how much of an existing file the first call shows
+	watchInitialTail = 4 * 1024
+)
+
+// WatchFileTool returns the content appended to a file since the previous
+// call, so the agent can poll a log without re-reading all of it
+type WatchFileTool struct {
+	mu      sync.Mutex
+	offsets map[string]int64 // Absolute path -> bytes already returned
+}
+
+// NewWatchFileTool creates a new WatchFileTool instance
+func NewWatchFileTool() *WatchFileTool {
+	return &WatchFileTool{}
+}
+
+func (t *WatchFileTool) Name() string {
+	return "watch_file"
+}
+
+func (t *WatchFileTool) Description() string {
+	return "Read what was appended to a file (e.g. a server log) since the last watch_file call on it. The first call shows the end of the file"
+}
+
+func (t *WatchFileTool) ReadOnly() bool {
+	return true
+}
+
+func (t *WatchFileTool) GetParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "The file to watch",
+			},
+			"from_start": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Read from the beginning of the file instead of continuing from the last call",
+			},
+		},
+		"required": []string{"path"},
+	}
+}
+
+func (t *WatchFileTool) Execute(args map[string]interface{}) (*ToolResult, error) {
+	path, _ := args["path"].(string)
+	if path == "" {
+		return nil, requiredArg("path")
+	}
+	fromStart, _ := args["from_start"].(bool)
+	if dotenvMasked(path) {
+		return nil, dotenvRangeError(path)
+	}
+
+	key, err := filepath.Abs(path)
+	if err != nil {
+		key = filepath.Clean(path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fileError("open", path, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+	size := info.Size()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.offsets == nil {
+		t.offsets = make(map[string]int64)
+	}
+
+	var notes []string
+	offset, seen := t.offsets[key]
+	switch {
+	case fromStart:
+		offset = 0
+	case !seen:
+		// Start near the end of an existing file; older output is rarely relevant
+		if size > watchInitialTail {
+			offset = size - watchInitialTail
+			notes = append(notes, fmt.Sprintf("showing the last %d of %d bytes", watchInitialTail, size))
+		}
+	case size < offset:
+		// The file was truncated or rotated
+		offset = 0
+		notes = append(notes, "file was truncated, reading from the start")
+	}
+
+	length := size - offset
+	if length > maxWatchBytes {
+		length = maxWatchBytes
+	}
+	content := make([]byte, length)
+	if _, err := file.ReadAt(content, offset); err != nil && err != io.EOF {
+		return nil, fileError("read", path, err)
+	}
+	t.offsets[key] = offset + length
+
+	if remaining := size - offset - length; remaining > 0 {
+		notes = append(notes, fmt.Sprintf("%d more bytes pending, call again to continue", remaining))
+	}
+	note := ""
+	if len(notes) > 0 {
+		note = " (" + strings.Join(notes, "; ") + ")"
+	}
+
+	if length == 0 {
+		return &ToolResult{
+			LLMContent:    fmt.Sprintf("No new content in %s%s", path, note),
+			ReturnDisplay: fmt.Sprintf("👀 `%s`: no new content", path),
+		}, nil
+	}
+	return &ToolResult{
+		LLMContent:    fmt.Sprintf("New content in %s%s:\n%s", path, note, content),
+		ReturnDisplay: fmt.Sprintf("👀 `%s`: %d new bytes%s", path, length, note),
+	}, nil
+}
@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWatchFileReturnsOnlyAppendedContent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	if err := os.WriteFile(path, []byte("starting\nlistening on :8080\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tool := NewWatchFileTool()
+
+	first, err := tool.Execute(map[string]interface{}{"path": path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(first.LLMContent, "listening on :8080") {
+		t.Errorf("expected the existing content on the first call, got %q", first.LLMContent)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("GET /health 200\n")
+	f.Close()
+
+	second, err := tool.Execute(map[string]interface{}{"path": path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(second.LLMContent, "GET /health 200") || strings.Contains(second.LLMContent, "listening") {
+		t.Errorf("expected only the appended line, got %q", second.LLMContent)
+	}
+
+	third, err := tool.Execute(map[string]interface{}{"path": path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(third.LLMContent, "No new content") {
+		t.Errorf("expected no new content, got %q", third.LLMContent)
+	}
+}
+
+func TestWatchFileRestartsAfterTruncation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	os.WriteFile(path, []byte("old line one\nold line two\n"), 0644)
+	tool := NewWatchFileTool()
+	if _, err := tool.Execute(map[string]interface{}{"path": path}); err != nil {
+		t.Fatal(err)
+	}
+
+	os.WriteFile(path, []byte("new\n"), 0644)
+	result, err := tool.Execute(map[string]interface{}{"path": path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result.LLMContent, "truncated") || !strings.HasSuffix(result.LLMContent, "new\n") {
+		t.Errorf("expected the rotated file to be read from the start, got %q", result.LLMContent)
+	}
+}
@@ -6,11 +6,13 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
 
 	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/PuerkitoBio/goquery"
 )
 
 const (
@@ -25,9 +27,10 @@ type LLMProcessor interface {
 }
 
 type WebFetchTool struct {
-	cache      map[string]cacheEntry
-	cacheMutex sync.RWMutex
-	llmClient  LLMProcessor
+	cache        map[string]cacheEntry
+	cacheMutex   sync.RWMutex
+	llmClient    LLMProcessor
+	cleanOptions HTMLCleanOptions
 }
 
 type cacheEntry struct {
@@ -37,7 +40,8 @@ type cacheEntry struct {
 
 func NewWebFetchTool(llmClient interface{}) *WebFetchTool {
 	tool := &WebFetchTool{
-		cache: make(map[string]cacheEntry),
+		cache:        make(map[string]cacheEntry),
+		cleanOptions: DefaultHTMLCleanOptions(),
 	}
 
 	// Type assert the llmClient
@@ -51,6 +55,11 @@ func NewWebFetchTool(llmClient interface{}) *WebFetchTool {
 	return tool
 }
 
+// SetCleanOptions changes how fetched HTML is simplified before conversion
+func (t *WebFetchTool) SetCleanOptions(opts HTMLCleanOptions) {
+	t.cleanOptions = opts
+}
+
 func (t *WebFetchTool) Name() string {
 	return "web_fetch"
 }
@@ -83,18 +92,18 @@ func (t *WebFetchTool) GetParameters() map[string]interface{} {
 func (t *WebFetchTool) Execute(args map[string]interface{}) (*ToolResult, error) {
 	urlStr, ok := args["url"].(string)
 	if !ok {
-		return nil, fmt.Errorf("url is required and must be a string")
+		return nil, validationError("url is required and must be a string")
 	}
 
 	prompt, ok := args["prompt"].(string)
 	if !ok {
-		return nil, fmt.Errorf("prompt is required and must be a string")
+		return nil, validationError("prompt is required and must be a string")
 	}
 
 	// Validate and clean URL
 	cleanedURL, err := t.validateURL(urlStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid URL: %w", err)
+		return nil, validationError("invalid URL: %w", err)
 	}
 
 	// Check cache first
@@ -103,7 +112,7 @@ func (t *WebFetchTool) Execute(args map[string]interface{}) (*ToolResult, error)
 		// Fetch content
 		content, err = t.fetchContent(cleanedURL)
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch content: %w", err)
+			return nil, timeoutError(fmt.Errorf("failed to fetch content: %w", err))
 		}
 
 		// Cache the content
@@ -113,7 +122,7 @@ func (t *WebFetchTool) Execute(args map[string]interface{}) (*ToolResult, error)
 	// Process with LLM
 	result, err := t.processWithLLM(content, prompt)
 	if err != nil {
-		return nil, fmt.Errorf("failed to process content: %w", err)
+		return nil, timeoutError(fmt.Errorf("failed to process content: %w", err))
 	}
 
 	// Prepare return display
@@ -159,12 +168,12 @@ func (t *WebFetchTool) validateURL(urlStr string) (string, error) {
 
 	// Validate scheme
 	if u.Scheme != "https" && u.Scheme != "http" {
-		return "", fmt.Errorf("only HTTP/HTTPS URLs are supported")
+		return "", validationError("only HTTP/HTTPS URLs are supported")
 	}
 
 	// Ensure host is present
 	if u.Host == "" {
-		return "", fmt.Errorf("URL must have a valid host")
+		return "", validationError("URL must have a valid host")
 	}
 
 	return u.String(), nil
@@ -209,8 +218,7 @@ func (t *WebFetchTool) fetchContent(url string) (string, error) {
 	htmlContent := string(body)
 
 	// Convert HTML to markdown
-	converter := md.NewConverter("", true, nil)
-	markdown, err := converter.ConvertString(htmlContent)
+	markdown, err := t.htmlToMarkdown(htmlContent)
 	if err != nil {
 		// If conversion fails, try to extract text content
 		markdown = t.extractTextContent(htmlContent)
@@ -227,6 +235,27 @@ func (t *WebFetchTool) fetchContent(url string) (string, error) {
 	return markdown, nil
 }
 
+// htmlToMarkdown strips boilerplate and flattens tables and deep nesting
+// according to the clean options, then converts the page to markdown
+func (t *WebFetchTool) htmlToMarkdown(htmlContent string) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return "", err
+	}
+	cleanHTML(doc, t.cleanOptions)
+
+	converter := md.NewConverter("", true, nil)
+	if t.cleanOptions.FlattenTables {
+		converter.AddRules(tableRule)
+	}
+	markdown := converter.Convert(doc.Selection)
+
+	// Cleaning can leave runs of blank lines behind
+	return blankLinesRegex.ReplaceAllString(markdown, "\n\n"), nil
+}
+
+var blankLinesRegex = regexp.MustCompile(`\n\s*\n(\s*\n)+`)
+
 func (t *WebFetchTool) extractTextContent(html string) string {
 	// Simple text extraction as fallback
 	// Remove script and style tags
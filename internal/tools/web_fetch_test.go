@@ -248,3 +248,58 @@ func TestWebFetchToolCacheCleanup(t *testing.T) {
 		t.Error("Expected tool to be created")
 	}
 }
+
+func TestWebFetchCleansBoilerplateAndTables(t *testing.T) {
+	var rows strings.Builder
+	for i := 1; i <= 20; i++ {
+		fmt.Fprintf(&rows, "<tr><td>item-%d</td><td>%d</td></tr>", i, i*10)
+	}
+	page := `<html><body>
+		<nav><a href="/">Home</a><a href="/pricing">Pricing</a></nav>
+		<div class="site-sidebar">Related links</div>
+		<main>
+			<h1>Release notes</h1>
+			<div class="menu-item">Soup of the day</div>
+			<span id="share-price">Shares closed at 42</span>
+			<form><label>Quantity</label><input name="qty"></form>
+			<ul><li>level 1<ul><li>level 2<ul><li>level 3<ul><li>level 4</li></ul></li></ul></li></ul></li></ul>
+			<table><tr><th>Name</th><th>Value</th></tr>` + rows.String() + `</table>
+		</main>
+		<footer>Copyright 2024</footer>
+	</body></html>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	tool := NewWebFetchTool(nil)
+	content, err := tool.fetchContent(server.URL)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+
+	for _, boilerplate := range []string{"Pricing", "Related links", "Copyright"} {
+		if strings.Contains(content, boilerplate) {
+			t.Errorf("expected %q to be stripped, got:\n%s", boilerplate, content)
+		}
+	}
+	for _, kept := range []string{"Release notes", "Soup of the day", "Shares closed at 42", "Quantity"} {
+		if !strings.Contains(content, kept) {
+			t.Errorf("expected %q to be kept, got:\n%s", kept, content)
+		}
+	}
+	if !strings.Contains(content, "Name | Value") || !strings.Contains(content, "item-20 | 200") {
+		t.Errorf("expected the table to be flattened into rows, got:\n%s", content)
+	}
+	if strings.Contains(content, "|---") || strings.Contains(content, "<table") {
+		t.Errorf("expected no markdown or HTML table syntax, got:\n%s", content)
+	}
+	if strings.Contains(content, "- level 4") {
+		t.Errorf("expected lists beyond the nesting limit to be flattened, got:\n%s", content)
+	}
+	if !strings.Contains(content, "level 4") {
+		t.Errorf("flattened lists should keep their text, got:\n%s", content)
+	}
+}
@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultSearchResults is how many results web_search returns by default
+const DefaultSearchResults = 5
+
+// maxSearchResults caps the results a single web_search call may request
+const maxSearchResults = 20
+
+// SearchResult is a single hit returned by a search backend
+type SearchResult struct {
+	Title   string
+	URL     string
+	Snippet string
+}
+
+// SearchProvider is a web search backend
+type SearchProvider interface {
+	Name() string
+	Search(ctx context.Context, query string, limit int) ([]SearchResult, error)
+}
+
+// WebSearchTool finds pages so the agent can follow up with web_fetch
+type WebSearchTool struct {
+	provider   SearchProvider
+	maxResults int
+}
+
+// NewWebSearchTool creates a web_search tool backed by provider. maxResults
+// <= 0 uses DefaultSearchResults.
+func NewWebSearchTool(provider SearchProvider, maxResults int) *WebSearchTool {
+	if maxResults <= 0 {
+		maxResults = DefaultSearchResults
+	}
+	if maxResults > maxSearchResults {
+		maxResults = maxSearchResults
+	}
+	return &WebSearchTool{provider: provider, maxResults: maxResults}
+}
+
+func (t *WebSearchTool) Name() string {
+	return "web_search"
+}
+
+func (t *WebSearchTool) Description() string {
+	return "Search the web and return the top results (title, URL, snippet). Use web_fetch to read a result"
+}
+
+func (t *WebSearchTool) ReadOnly() bool {
+	return true
+}
+
+func (t *WebSearchTool) GetParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"query": map[string]interface{}{
+				"type":        "string",
+				"description": "The search query",
+			},
+			"max_results": map[string]interface{}{
+				"type":        "integer",
+				"description": fmt.Sprintf("Number of results to return (default and maximum %d)", t.maxResults),
+			},
+		},
+		"required": []string{"query"},
+	}
+}
+
+func (t *WebSearchTool) Execute(args map[string]interface{}) (*ToolResult, error) {
+	query, _ := args["query"].(string)
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, requiredArg("query")
+	}
+
+	limit := t.maxResults
+	if n, ok := args["max_results"].(float64); ok && n > 0 && int(n) < limit {
+		limit = int(n)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	results, err := t.provider.Search(ctx, query, limit)
+	if err != nil {
+		return nil, timeoutError(fmt.Errorf("%s search failed: %w", t.provider.Name(), err))
+	}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	var llmContent, display strings.Builder
+	fmt.Fprintf(&llmContent, "Search results for %q (%d):\n", query, len(results))
+	fmt.Fprintf(&display, "🔎 **Searched %s** for `%s` (%d results)\n\n", t.provider.Name(), query, len(results))
+	if len(results) == 0 {
+		llmContent.WriteString("No results found.\n")
+	}
+	for i, result := range results {
+		fmt.Fprintf(&llmContent, "\n%d. %s\n   %s\n   %s\n", i+1, result.Title, result.URL, result.Snippet)
+		fmt.Fprintf(&display, "%d. [%s](%s)\n", i+1, result.Title, result.URL)
+	}
+
+	return &ToolResult{
+		LLMContent:    llmContent.String(),
+		ReturnDisplay: display.String(),
+		Error:         nil,
+	}, nil
+}
@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// mockSearchProvider returns scripted results and records the requested limit
+type mockSearchProvider struct {
+	results   []SearchResult
+	lastLimit int
+}
+
+func (m *mockSearchProvider) Name() string { return "Mock" }
+
+func (m *mockSearchProvider) Search(ctx context.Context, query string, limit int) ([]SearchResult, error) {
+	m.lastLimit = limit
+	return m.results, nil
+}
+
+func TestWebSearchToolReturnsCappedResults(t *testing.T) {
+	provider := &mockSearchProvider{}
+	for i := 1; i <= 5; i++ {
+		provider.results = append(provider.results, SearchResult{
+			Title:   fmt.Sprintf("Result %d", i),
+			URL:     fmt.Sprintf("https://example.com/%d", i),
+			Snippet: fmt.Sprintf("snippet %d", i),
+		})
+	}
+
+	tool := NewWebSearchTool(provider, 3)
+	result, err := tool.Execute(map[string]interface{}{"query": "go generics"})
+	if err != nil {
+		t.Fatalf("web_search failed: %v", err)
+	}
+
+	if provider.lastLimit != 3 {
+		t.Errorf("expected the provider to be asked for 3 results, got %d", provider.lastLimit)
+	}
+	if !strings.Contains(result.LLMContent, "1. Result 1\n   https://example.com/1\n   snippet 1") {
+		t.Errorf("expected title, URL and snippet, got %q", result.LLMContent)
+	}
+	if strings.Contains(result.LLMContent, "Result 4") {
+		t.Errorf("expected results beyond the cap to be dropped, got %q", result.LLMContent)
+	}
+	if !strings.Contains(result.ReturnDisplay, "Searched Mock") {
+		t.Errorf("expected the backend name in the display, got %q", result.ReturnDisplay)
+	}
+
+	// A smaller per-call limit is honoured, a larger one is not
+	if _, err := tool.Execute(map[string]interface{}{"query": "go", "max_results": float64(10)}); err != nil {
+		t.Fatal(err)
+	}
+	if provider.lastLimit != 3 {
+		t.Errorf("expected max_results above the cap to be ignored, got %d", provider.lastLimit)
+	}
+}
+
+func TestBraveSearchParsesResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Subscription-Token") != "key" || r.URL.Query().Get("q") != "agenticode" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"web": map[string]interface{}{
+				"results": []map[string]string{{"title": "AgentiCode", "url": "https://github.com/trknhr/agenticode", "description": "An AI coding agent"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	provider := &braveSearch{apiKey: "key", endpoint: server.URL}
+	results, err := provider.Search(context.Background(), "agenticode", 5)
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].URL != "https://github.com/trknhr/agenticode" || results[0].Snippet != "An AI coding agent" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
+
+func TestSerpAPISearchErrorsHideTheKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	provider := &serpAPISearch{apiKey: "secret-key", endpoint: server.URL}
+	_, err := provider.Search(context.Background(), "agenticode", 5)
+	if err == nil {
+		t.Fatal("expected a connection error")
+	}
+	if strings.Contains(err.Error(), "secret-key") || !strings.Contains(err.Error(), server.URL) {
+		t.Errorf("expected the error to name only the endpoint, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+const (
+	// DefaultMaxWriteBytes is the largest content write_file accepts
+	DefaultMaxWriteBytes = 10 << 20
+	// DefaultConfirmWriteBytes is the size above which a write needs an
+	// explicit yes from the user
+	DefaultConfirmWriteBytes = 1 << 20
+)
+
+var (
+	maxWriteBytes     atomic.Int64
+	confirmWriteBytes atomic.Int64
+)
+
+func init() {
+	maxWriteBytes.Store(DefaultMaxWriteBytes)
+	confirmWriteBytes.Store(DefaultConfirmWriteBytes)
+}
+
+// SetWriteLimits sets how large a write_file call may be: content over max
+// bytes is rejected (security.max_write_bytes) and content over confirm
+// bytes runs only when the user approves it at the prompt
+// (security.confirm_write_bytes). Zero turns a limit off.
+func SetWriteLimits(max, confirm int64) {
+	maxWriteBytes.Store(max)
+	confirmWriteBytes.Store(confirm)
+}
+
+// checkWriteSize rejects content larger than the write limit
+func checkWriteSize(path string, size int) error {
+	if max := maxWriteBytes.Load(); max > 0 && int64(size) > max {
+		return validationError("refusing to write %d bytes to %s: the limit is %d bytes (security.max_write_bytes); generate less content or split it across files", size, path, max)
+	}
+	return nil
+}
+
+// largeWriteWarning explains why a write needs explicit confirmation, or
+// returns "" when it is small enough
+func largeWriteWarning(size int) string {
+	if confirm := confirmWriteBytes.Load(); confirm > 0 && int64(size) > confirm {
+		return fmt.Sprintf("writes %d bytes, more than the %d bytes that can be approved automatically", size, confirm)
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteFileSizeLimit(t *testing.T) {
+	SetWriteLimits(100, 0)
+	defer SetWriteLimits(DefaultMaxWriteBytes, DefaultConfirmWriteBytes)
+
+	dir := t.TempDir()
+	under := filepath.Join(dir, "under.txt")
+	if _, err := NewWriteFileTool().Execute(map[string]interface{}{"path": under, "content": strings.Repeat("a", 100)}); err != nil {
+		t.Fatalf("expected a write at the limit to succeed, got %v", err)
+	}
+	if data, _ := os.ReadFile(under); len(data) != 100 {
+		t.Errorf("expected 100 bytes written, got %d", len(data))
+	}
+
+	over := filepath.Join(dir, "over.txt")
+	_, err := NewWriteFileTool().Execute(map[string]interface{}{"path": over, "content": strings.Repeat("a", 101)})
+	if !errors.Is(err, ErrValidation) || !strings.Contains(err.Error(), "security.max_write_bytes") {
+		t.Fatalf("expected the write over the limit to be rejected, got %v", err)
+	}
+	if _, err := os.Stat(over); !os.IsNotExist(err) {
+		t.Error("expected nothing to be written over the limit")
+	}
+}
+
+func TestLargeWritesNeedConfirmation(t *testing.T) {
+	SetWriteLimits(0, 10)
+	defer SetWriteLimits(DefaultMaxWriteBytes, DefaultConfirmWriteBytes)
+
+	tool := NewWriteFileTool()
+	if warning := tool.DangerousCall(map[string]interface{}{"content": "0123456789"}); warning != "" {
+		t.Errorf("expected no warning at the threshold, got %q", warning)
+	}
+	if warning := tool.DangerousCall(map[string]interface{}{"content": "0123456789!"}); !strings.Contains(warning, "writes 11 bytes") {
+		t.Errorf("expected a warning above the threshold, got %q", warning)
+	}
+}
+
+func TestMultiEditCreationHonoursWriteLimits(t *testing.T) {
+	SetWriteLimits(10, 5)
+	defer SetWriteLimits(DefaultMaxWriteBytes, DefaultConfirmWriteBytes)
+
+	tool := NewMultiEditTool()
+	create := func(content string) map[string]interface{} {
+		path := filepath.Join(t.TempDir(), "new.txt")
+		return map[string]interface{}{"file_path": path, "edits": []interface{}{
+			map[string]interface{}{"old_string": "", "new_string": content},
+		}}
+	}
+
+	if warning := tool.DangerousCall(create("012345")); !strings.Contains(warning, "writes 6 bytes") {
+		t.Errorf("expected a large creation to need confirmation, got %q", warning)
+	}
+	if warning := tool.DangerousCall(create("0123")); warning != "" {
+		t.Errorf("expected no warning below the threshold, got %q", warning)
+	}
+
+	args := create(strings.Repeat("a", 11))
+	_, err := tool.Execute(args)
+	if !errors.Is(err, ErrValidation) || !strings.Contains(err.Error(), "security.max_write_bytes") {
+		t.Fatalf("expected the creation over the limit to be rejected, got %v", err)
+	}
+	if _, err := os.Stat(args["file_path"].(string)); !os.IsNotExist(err) {
+		t.Error("expected nothing to be written over the limit")
+	}
+}